@@ -3,9 +3,11 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/spf13/cobra"
@@ -54,15 +56,26 @@ func main() {
 		})
 	}
 
+	rootCmd.AddCommand(&cobra.Command{
+		Use:          "lint-templates",
+		Short:        "Render every template and report the ones that fail",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLintTemplates(cmd.OutOrStdout())
+		},
+	})
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
-func runTUI(initialTab string) error {
+// loadService reads the chezit config and builds the chezmoi service.
+func loadService() (chezitconfig.Config, *chezmoi.Service, error) {
 	cfg, err := chezitconfig.Load()
 	if err != nil {
-		return fmt.Errorf("error loading config: %w", err)
+		return cfg, nil, fmt.Errorf("error loading config: %w", err)
 	}
 
 	client := chezmoi.New(
@@ -71,9 +84,52 @@ func runTUI(initialTab string) error {
 	)
 	tp, err := client.TargetPath()
 	if err != nil {
-		return fmt.Errorf("could not determine chezmoi target path: %w", err)
+		return cfg, nil, fmt.Errorf("could not determine chezmoi target path: %w", err)
+	}
+	allowlist := make([]chezmoi.SecretAllowRule, 0, len(cfg.SecretAllowlist))
+	for _, rule := range cfg.SecretAllowlist {
+		allowlist = append(allowlist, chezmoi.SecretAllowRule(rule))
+	}
+	svc := chezmoi.NewService(client, cfg.Mode, tp,
+		chezmoi.WithBackupRetention(chezmoi.BackupRetention{
+			MaxRuns: cfg.BackupKeepRuns,
+			MaxAge:  time.Duration(cfg.BackupMaxAgeDays) * 24 * time.Hour,
+		}),
+		chezmoi.WithSecretAllowlist(allowlist),
+	)
+	return cfg, svc, nil
+}
+
+// runLintTemplates prints each template that fails to render as
+// "source:line:column: message (target)" and fails if there are any.
+// Encrypted templates are listed as skipped.
+func runLintTemplates(out io.Writer) error {
+	_, svc, err := loadService()
+	if err != nil {
+		return err
+	}
+	report, err := svc.LintTemplates()
+	if err != nil {
+		return err
+	}
+	for _, target := range report.Skipped {
+		_, _ = fmt.Fprintf(out, "skipped encrypted template (%s)\n", target)
+	}
+	for _, issue := range report.Issues {
+		_, _ = fmt.Fprintf(out, "%s: %s (%s)\n", issue.Location(), issue.Message, issue.Target)
+	}
+	if len(report.Issues) > 0 {
+		return fmt.Errorf("%d of %d templates failed to render", len(report.Issues), report.Checked)
+	}
+	_, _ = fmt.Fprintf(out, "%d templates render cleanly\n", report.Checked)
+	return nil
+}
+
+func runTUI(initialTab string) error {
+	cfg, svc, err := loadService()
+	if err != nil {
+		return err
 	}
-	svc := chezmoi.NewService(client, cfg.Mode, tp)
 
 	iconMode, err := tui.ParseIconMode(cfg.Icons)
 	if err != nil {
@@ -102,6 +158,7 @@ func runTUI(initialTab string) error {
 		Service:       svc,
 		EscBehavior:   tui.EscQuit,
 		CommitPresets: cfg.CommitPresets,
+		DataProfiles:  cfg.DataProfiles,
 		PanelMode:     cfg.Panel,
 		IconMode:      iconMode,
 		InitialTab:    initialTab,
@@ -112,7 +169,11 @@ func runTUI(initialTab string) error {
 	model := tui.NewModel(opts)
 	p := tea.NewProgram(model)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if m, ok := final.(tui.Model); ok {
+		m.Close()
+	}
+	if err != nil {
 		return fmt.Errorf("error: %w", err)
 	}
 	return nil
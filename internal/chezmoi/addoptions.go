@@ -0,0 +1,50 @@
+package chezmoi
+
+import (
+	"os/exec"
+	"path/filepath"
+)
+
+const addOptionsFile = "add-options.json"
+
+// AddCmd returns the interactive command for an add with opts.Prompt,
+// after the same checks Add makes. It returns nil in read-only mode.
+func (s *Service) AddCmd(path string, opts AddOptions) (*exec.Cmd, error) {
+	if s.policy.IsReadOnly() {
+		return nil, nil
+	}
+	if err := s.policy.ValidateTargetPath(path); err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if !opts.Encrypt && !opts.AllowSecrets {
+		if findings := s.secrets.scanPath(path, s.TargetPath(), opts); len(findings) > 0 {
+			return nil, &SecretsFoundError{Findings: findings}
+		}
+	}
+	return s.client.AddCmd(path, opts), nil
+}
+
+// AddOptionsFor returns the options last remembered for adding files in
+// dir, or the zero options if there are none.
+func (s *Service) AddOptionsFor(dir string) (AddOptions, error) {
+	saved, err := readStateFile[map[string]AddOptions](s.dataDir, addOptionsFile)
+	if err != nil {
+		return AddOptions{}, err
+	}
+	return saved[filepath.Clean(dir)], nil
+}
+
+// RememberAddOptions saves opts as the options for adding files in dir.
+func (s *Service) RememberAddOptions(dir string, opts AddOptions) error {
+	_, err := updateStateFile(s.dataDir, addOptionsFile, func(saved map[string]AddOptions) map[string]AddOptions {
+		if saved == nil {
+			saved = make(map[string]AddOptions)
+		}
+		saved[filepath.Clean(dir)] = opts
+		return saved
+	})
+	return err
+}
@@ -0,0 +1,109 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestAddOptionsArgs(t *testing.T) {
+	opts := AddOptions{
+		Template:         true,
+		Create:           true,
+		TemplateSymlinks: true,
+		Secrets:          "error",
+		Prompt:           true,
+		Filter:           EntryFilter{Include: []EntryType{EntryFiles}, Exclude: []EntryType{EntryEncrypted}},
+		AllowSecrets:     true,
+	}
+	want := []string{
+		"--template", "--create", "--template-symlinks", "--secrets=error", "--prompt",
+		"--include=files", "--exclude=encrypted",
+	}
+	if got := opts.args(); !slices.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestAddOptionsValidate(t *testing.T) {
+	tests := []struct {
+		opts AddOptions
+		want string
+	}{
+		{AddOptions{Encrypt: true, Create: true, Secrets: "warning"}, ""},
+		{AddOptions{Template: true, AutoTemplate: true}, "only one of"},
+		{AddOptions{Follow: true, TemplateSymlinks: true}, "--follow"},
+		{AddOptions{Secrets: "loud"}, `got "loud"`},
+		{AddOptions{Filter: EntryFilter{Include: []EntryType{"sockets"}}}, `unknown entry type "sockets"`},
+		{AddOptions{Filter: EntryFilter{Include: []EntryType{EntryFiles}, Exclude: []EntryType{EntryFiles}}}, "both included and excluded"},
+	}
+	for _, tt := range tests {
+		err := tt.opts.Validate()
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.opts, err, tt.want)
+		}
+	}
+}
+
+func TestClientAddWithOptionsRejectsPrompt(t *testing.T) {
+	c := New(WithBinaryPath(writeFakeChezmoiBinary(t, `echo "unexpected command: $*" >&2; exit 1`)))
+	if err := c.AddWithOptions("/home/test/.bashrc", AddOptions{Prompt: true}); err == nil || !strings.Contains(err.Error(), "terminal") {
+		t.Fatalf("expected --prompt refused, got %v", err)
+	}
+	cmd := c.AddCmd("/home/test/.bashrc", AddOptions{Prompt: true})
+	if got := cmd.Args[len(cmd.Args)-4:]; !slices.Equal(got, []string{"--force", "--prompt", "--", "/home/test/.bashrc"}) {
+		t.Fatalf("unexpected args %q", cmd.Args)
+	}
+}
+
+func TestServiceRemembersAddOptionsPerDirectory(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "chezit")
+	svc := NewService(New(), chezitconfig.ModeReadOnly, "/home/test", WithDataDir(dataDir))
+
+	opts, err := svc.AddOptionsFor("/home/test/.config")
+	if err != nil || !reflect.DeepEqual(opts, AddOptions{}) {
+		t.Fatalf("expected no remembered options, got %+v, %v", opts, err)
+	}
+	saved := AddOptions{Template: true, Secrets: "ignore", AllowSecrets: true, Filter: EntryFilter{Exclude: []EntryType{EntryScripts}}}
+	if err := svc.RememberAddOptions("/home/test/.config/", saved); err != nil {
+		t.Fatalf("RememberAddOptions: %v", err)
+	}
+	if err := svc.RememberAddOptions("/home/test", AddOptions{Encrypt: true}); err != nil {
+		t.Fatalf("RememberAddOptions: %v", err)
+	}
+
+	opts, err = svc.AddOptionsFor("/home/test/.config")
+	if err != nil {
+		t.Fatalf("AddOptionsFor: %v", err)
+	}
+	saved.AllowSecrets = false // skipping the secret scan is never remembered
+	if !reflect.DeepEqual(opts, saved) {
+		t.Fatalf("got %+v, want %+v", opts, saved)
+	}
+	if opts, _ := svc.AddOptionsFor("/home/test"); !opts.Encrypt || opts.Template {
+		t.Fatalf("expected each directory remembered separately, got %+v", opts)
+	}
+}
+
+func TestServiceRememberAddOptionsReplacesCorruptFile(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, addOptionsFile), []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(New(), chezitconfig.ModeReadOnly, "/home/test", WithDataDir(dataDir))
+
+	if _, err := svc.AddOptionsFor("/home/test"); err == nil {
+		t.Fatal("expected an error for corrupt options")
+	}
+	if err := svc.RememberAddOptions("/home/test", AddOptions{Template: true}); err != nil {
+		t.Fatalf("RememberAddOptions: %v", err)
+	}
+	if opts, err := svc.AddOptionsFor("/home/test"); err != nil || !opts.Template {
+		t.Fatalf("expected the corrupt file replaced, got %+v, %v", opts, err)
+	}
+}
@@ -0,0 +1,336 @@
+package chezmoi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// AddPreview is what Add would write to the source state for a target.
+type AddPreview struct {
+	Target string // absolute target path
+	IsDir  bool
+	// SourcePath is the new source entry, relative to the source directory,
+	// e.g. "dot_config/private_app/executable_run.tmpl".
+	SourcePath string
+	// Attributes are the prefixes and suffixes of the entry's own name, in
+	// order, e.g. ["private_", "dot_", ".tmpl"].
+	Attributes []string
+	// NewDirs are the parent directories chezmoi creates in the source
+	// state, relative to the source directory, outermost first.
+	NewDirs []string
+	// Substitutions are the template variables --autotemplate puts in
+	// place of data values, in the order they are replaced.
+	Substitutions []AutoTemplateSubstitution
+	// Diff is a unified diff from the target to the source --autotemplate
+	// writes; "" when nothing is replaced.
+	Diff string
+}
+
+// AutoTemplateSubstitution is a template data value replaced with its
+// variable, e.g. "me@example.com" with "{{ .email }}".
+type AutoTemplateSubstitution struct {
+	Variable string // e.g. "chezmoi.hostname"
+	Value    string
+	Count    int // occurrences replaced
+}
+
+// PreviewAdd works out the source entry Add(path, opts) would create,
+// without writing anything. With opts.AutoTemplate it also shows which
+// values become template variables.
+func (s *Service) PreviewAdd(path string, opts AddOptions) (AddPreview, error) {
+	if err := s.policy.ValidateTargetPath(path); err != nil {
+		return AddPreview{}, err
+	}
+	if err := opts.Validate(); err != nil {
+		return AddPreview{}, err
+	}
+	stat := os.Lstat
+	if opts.Follow {
+		stat = os.Stat
+	}
+	info, err := stat(path)
+	if err != nil {
+		return AddPreview{}, err
+	}
+	rel, err := filepath.Rel(s.TargetPath(), path)
+	if err != nil || rel == "." {
+		return AddPreview{}, ErrOutsideTarget
+	}
+
+	preview := AddPreview{Target: path, IsDir: info.IsDir()}
+	parent, newDirs, err := s.addParentSourceDir(filepath.Dir(path))
+	if err != nil {
+		return AddPreview{}, err
+	}
+	preview.NewDirs = newDirs
+
+	if info.IsDir() {
+		preview.Attributes = sourceDirAttributes(info, opts)
+	} else {
+		encryptedSuffix := ""
+		if opts.Encrypt {
+			enc, err := s.Encryption()
+			if err != nil {
+				return AddPreview{}, err
+			}
+			encryptedSuffix = enc.Suffix
+		}
+		preview.Attributes = sourceFileAttributes(info, opts, encryptedSuffix)
+		if info.Mode()&fs.ModeSymlink != 0 && opts.TemplateSymlinks && !slices.Contains(preview.Attributes, suffixTemplate) {
+			if link, err := os.Readlink(path); err == nil && pathWithinAny(filepath.Clean(link), []string{s.TargetPath()}) {
+				preview.Attributes = append(preview.Attributes, suffixTemplate)
+			}
+		}
+	}
+	preview.SourcePath = joinSourcePath(parent, sourceEntryName(info.Name(), preview.Attributes))
+
+	if opts.AutoTemplate && info.Mode().IsRegular() {
+		if err := s.previewAutoTemplate(&preview, rel); err != nil {
+			return AddPreview{}, err
+		}
+	}
+	return preview, nil
+}
+
+// addParentSourceDir returns the source path of the nearest managed
+// ancestor of dir, relative to the source directory, and the source paths
+// of the directories below it that chezmoi add would create.
+func (s *Service) addParentSourceDir(dir string) (string, []string, error) {
+	target := s.TargetPath()
+	var missing []fs.FileInfo
+	parent := ""
+	for ; dir != target && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if sources, err := s.client.SourcePaths([]string{dir}); err == nil {
+			sourceDir, err := s.client.SourceDir()
+			if err != nil {
+				return "", nil, err
+			}
+			if parent, err = filepath.Rel(sourceDir, sources[0]); err != nil {
+				return "", nil, err
+			}
+			parent = filepath.ToSlash(parent)
+			break
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return "", nil, err
+		}
+		missing = append(missing, info)
+	}
+
+	var newDirs []string
+	for _, info := range slices.Backward(missing) {
+		parent = joinSourcePath(parent, sourceEntryName(info.Name(), sourceDirAttributes(info, AddOptions{})))
+		newDirs = append(newDirs, parent)
+	}
+	return parent, newDirs, nil
+}
+
+// sourceFileAttributes returns the attributes chezmoi gives a file or
+// symlink added with opts, in source name order.
+func sourceFileAttributes(info fs.FileInfo, opts AddOptions, encryptedSuffix string) []string {
+	var attrs []string
+	perm := info.Mode().Perm()
+	if info.Mode()&fs.ModeSymlink != 0 {
+		attrs = append(attrs, prefixSymlink)
+	} else {
+		if opts.Create {
+			attrs = append(attrs, prefixCreate)
+		}
+		if opts.Encrypt {
+			attrs = append(attrs, prefixEncrypted)
+		}
+		if perm&0o077 == 0 {
+			attrs = append(attrs, prefixPrivate)
+		}
+		if perm&0o222 == 0 {
+			attrs = append(attrs, prefixReadonly)
+		}
+		if info.Size() == 0 && !opts.Create {
+			attrs = append(attrs, prefixEmpty)
+		}
+		if perm&0o111 != 0 {
+			attrs = append(attrs, prefixExecutable)
+		}
+	}
+	attrs = append(attrs, nameAttributes(info.Name(), false)...)
+	if opts.Template || opts.AutoTemplate {
+		attrs = append(attrs, suffixTemplate)
+	}
+	if opts.Encrypt && encryptedSuffix != "" {
+		attrs = append(attrs, encryptedSuffix)
+	}
+	if !opts.Template && !opts.AutoTemplate && !opts.Encrypt && hasReservedSuffix(info.Name()) {
+		attrs = append(attrs, suffixLiteral)
+	}
+	return attrs
+}
+
+// sourceDirAttributes returns the attributes chezmoi gives a directory
+// added with opts, in source name order.
+func sourceDirAttributes(info fs.FileInfo, opts AddOptions) []string {
+	var attrs []string
+	perm := info.Mode().Perm()
+	if opts.Exact {
+		attrs = append(attrs, prefixExact)
+	}
+	if perm&0o077 == 0 {
+		attrs = append(attrs, prefixPrivate)
+	}
+	if perm&0o222 == 0 {
+		attrs = append(attrs, prefixReadonly)
+	}
+	return append(attrs, nameAttributes(info.Name(), true)...)
+}
+
+// nameAttributes returns dot_ for hidden names and literal_ for names that
+// would otherwise be read as attributes.
+func nameAttributes(name string, dir bool) []string {
+	if strings.HasPrefix(name, ".") {
+		return []string{prefixDot}
+	}
+	for _, prefix := range reservedPrefixes(dir) {
+		if strings.HasPrefix(name, prefix) {
+			return []string{prefixLiteral}
+		}
+	}
+	return nil
+}
+
+func reservedPrefixes(dir bool) []string {
+	if dir {
+		return []string{prefixRemove, prefixExternal, prefixExact, prefixPrivate, prefixReadonly, prefixDot, prefixLiteral}
+	}
+	return []string{
+		prefixCreate, prefixModify, prefixRemove, prefixRun, prefixSymlink,
+		prefixEncrypted, prefixPrivate, prefixReadonly, prefixEmpty, prefixExecutable,
+		prefixDot, prefixLiteral,
+	}
+}
+
+func hasReservedSuffix(name string) bool {
+	return strings.HasSuffix(name, suffixTemplate) || strings.HasSuffix(name, suffixLiteral)
+}
+
+// sourceEntryName builds a source name from the target name and the
+// attributes returned by sourceFileAttributes or sourceDirAttributes.
+func sourceEntryName(name string, attrs []string) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		if strings.HasSuffix(attr, "_") {
+			b.WriteString(attr)
+		}
+	}
+	if slices.Contains(attrs, prefixDot) {
+		name = strings.TrimPrefix(name, ".")
+	}
+	b.WriteString(name)
+	for _, attr := range attrs {
+		if strings.HasPrefix(attr, ".") {
+			b.WriteString(attr)
+		}
+	}
+	return b.String()
+}
+
+func joinSourcePath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// previewAutoTemplate fills in the substitutions and diff for a file added
+// with --autotemplate.
+func (s *Service) previewAutoTemplate(preview *AddPreview, rel string) error {
+	contents, err := os.ReadFile(preview.Target)
+	if err != nil {
+		return err
+	}
+	out, err := s.client.DataJSON()
+	if err != nil {
+		return err
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		return fmt.Errorf("chezmoi data: %w", err)
+	}
+	templated, subs := autoTemplate(string(contents), data)
+	preview.Substitutions = subs
+	if templated != string(contents) {
+		preview.Diff = udiff.Unified("a/"+filepath.ToSlash(rel), "b/"+preview.SourcePath, string(contents), templated)
+	}
+	return nil
+}
+
+// autoTemplate replaces template data values in contents with their
+// variables the way chezmoi add --autotemplate does: longest values first,
+// and only where a value does not start or end inside a word.
+func autoTemplate(contents string, data map[string]any) (string, []AutoTemplateSubstitution) {
+	var vars []AutoTemplateSubstitution
+	collectTemplateVariables(&vars, "", data)
+	slices.SortFunc(vars, func(a, b AutoTemplateSubstitution) int {
+		if len(a.Value) != len(b.Value) {
+			return len(b.Value) - len(a.Value)
+		}
+		return strings.Compare(a.Variable, b.Variable)
+	})
+
+	var subs []AutoTemplateSubstitution
+	for _, v := range vars {
+		if v.Value == "" {
+			continue
+		}
+		replacement := "{{ ." + v.Variable + " }}"
+		index := strings.Index(contents, v.Value)
+		for index != -1 && index != len(contents) {
+			if !inWord(contents, index) && !inWord(contents, index+len(v.Value)) {
+				contents = contents[:index] + replacement + contents[index+len(v.Value):]
+				index += len(replacement)
+				v.Count++
+			} else {
+				index++
+			}
+			next := strings.Index(contents[index:], v.Value)
+			if next == -1 {
+				break
+			}
+			index += next
+		}
+		if v.Count > 0 {
+			subs = append(subs, v)
+		}
+	}
+	return contents, subs
+}
+
+func collectTemplateVariables(vars *[]AutoTemplateSubstitution, parent string, data map[string]any) {
+	for name, value := range data {
+		variable := name
+		if parent != "" {
+			variable = parent + "." + name
+		}
+		switch value := value.(type) {
+		case string:
+			*vars = append(*vars, AutoTemplateSubstitution{Variable: variable, Value: value})
+		case map[string]any:
+			collectTemplateVariables(vars, variable, value)
+		}
+	}
+}
+
+// inWord reports whether splitting s at i would split a word.
+func inWord(s string, i int) bool {
+	return i > 0 && i < len(s) && isWordByte(s[i-1]) && isWordByte(s[i])
+}
+
+func isWordByte(b byte) bool {
+	return '0' <= b && b <= '9' || 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z'
+}
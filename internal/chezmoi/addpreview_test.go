@@ -0,0 +1,130 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+const fakeAddPreviewBody = `
+case "$1" in
+source-path)
+	if [ $# -eq 1 ]; then echo "$SRC"; exit 0; fi
+	if [ "$2" = "$TARGET/.config" ]; then echo "$SRC/dot_config"; exit 0; fi
+	echo "chezmoi: $2: not managed" >&2; exit 1
+	;;
+data) echo '{"email":"me@example.com","chezmoi":{"hostname":"box","os":"linux"}}' ;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`
+
+func newAddPreviewService(t *testing.T) (*Service, string) {
+	t.Helper()
+	src, target := t.TempDir(), t.TempDir()
+	t.Setenv("SRC", src)
+	t.Setenv("TARGET", target)
+	return newFakeService(t, chezitconfig.ModeWrite, target, fakeAddPreviewBody), target
+}
+
+func TestServicePreviewAddSourceName(t *testing.T) {
+	svc, target := newAddPreviewService(t)
+	script := filepath.Join(target, ".config", "app", "run.sh")
+	writeTestFile(t, script, "#!/bin/sh\n", 0o755)
+	if err := os.Chmod(filepath.Dir(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := svc.PreviewAdd(script, AddOptions{Template: true})
+	if err != nil {
+		t.Fatalf("PreviewAdd: %v", err)
+	}
+	if preview.SourcePath != "dot_config/private_app/executable_run.sh.tmpl" {
+		t.Errorf("unexpected source path %q", preview.SourcePath)
+	}
+	if !slices.Equal(preview.NewDirs, []string{"dot_config/private_app"}) {
+		t.Errorf("unexpected new dirs %v", preview.NewDirs)
+	}
+	if !slices.Equal(preview.Attributes, []string{"executable_", ".tmpl"}) {
+		t.Errorf("unexpected attributes %v", preview.Attributes)
+	}
+
+	netrc := filepath.Join(target, ".netrc")
+	writeTestFile(t, netrc, "machine example.com\n", 0o600)
+	preview, err = svc.PreviewAdd(netrc, AddOptions{})
+	if err != nil || preview.SourcePath != "private_dot_netrc" || preview.NewDirs != nil {
+		t.Fatalf("unexpected preview %+v, %v", preview, err)
+	}
+
+	hushlogin := filepath.Join(target, ".hushlogin")
+	writeTestFile(t, hushlogin, "", 0o644)
+	preview, err = svc.PreviewAdd(hushlogin, AddOptions{Create: true})
+	if err != nil || preview.SourcePath != "create_dot_hushlogin" {
+		t.Fatalf("expected create_ in place of empty_, got %+v, %v", preview, err)
+	}
+}
+
+func TestServicePreviewAddAutoTemplate(t *testing.T) {
+	svc, target := newAddPreviewService(t)
+	gitconfig := filepath.Join(target, ".gitconfig")
+	writeTestFile(t, gitconfig, "[user]\n\temail = me@example.com\n[host \"box\"]\n\tpath = sandbox\n", 0o644)
+
+	preview, err := svc.PreviewAdd(gitconfig, AddOptions{AutoTemplate: true})
+	if err != nil {
+		t.Fatalf("PreviewAdd: %v", err)
+	}
+	if preview.SourcePath != "dot_gitconfig.tmpl" {
+		t.Errorf("unexpected source path %q", preview.SourcePath)
+	}
+	want := []AutoTemplateSubstitution{
+		{Variable: "email", Value: "me@example.com", Count: 1},
+		{Variable: "chezmoi.hostname", Value: "box", Count: 1},
+	}
+	if !slices.Equal(preview.Substitutions, want) {
+		t.Errorf("unexpected substitutions %+v", preview.Substitutions)
+	}
+	for _, line := range []string{"+\temail = {{ .email }}", "+[host \"{{ .chezmoi.hostname }}\"]", "b/dot_gitconfig.tmpl"} {
+		if !strings.Contains(preview.Diff, line) {
+			t.Errorf("expected %q in the diff:\n%s", line, preview.Diff)
+		}
+	}
+	if strings.Contains(preview.Diff, "+\tpath") {
+		t.Errorf("expected values inside words left alone:\n%s", preview.Diff)
+	}
+}
+
+func TestSourceEntryNameRoundTrips(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs []string
+		want  string
+	}{
+		{".bashrc", nameAttributes(".bashrc", false), "dot_bashrc"},
+		{"run_me", nameAttributes("run_me", false), "literal_run_me"},
+		{"dot_file", nameAttributes("dot_file", false), "literal_dot_file"},
+		{"exact_dir", nameAttributes("exact_dir", true), "literal_exact_dir"},
+	}
+	for _, tt := range tests {
+		got := sourceEntryName(tt.name, tt.attrs)
+		if got != tt.want {
+			t.Errorf("sourceEntryName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	target := t.TempDir()
+	for _, name := range []string{"notes.tmpl", "run_me", ".x.literal"} {
+		path := filepath.Join(target, name)
+		writeTestFile(t, path, "x", 0o644)
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		source := sourceEntryName(name, sourceFileAttributes(info, AddOptions{}, ""))
+		if got := ParseSourceFileName(source); got.TargetName != name || got.Template {
+			t.Errorf("%q encodes as %q, which decodes to %+v", name, source, got)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package chezmoi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/go-udiff"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+const (
+	backupManifestName = "manifest.json"
+	backupFilesDir     = "files"
+	backupRunIDLayout  = "20060102-150405.000000"
+	backupDirMode      = fs.FileMode(0o700)
+)
+
+// BackupRetention bounds the backup store. Zero values mean no limit.
+type BackupRetention struct {
+	MaxRuns int
+	MaxAge  time.Duration
+}
+
+// DefaultBackupRetention keeps the last 20 runs for up to 30 days.
+var DefaultBackupRetention = BackupRetention{MaxRuns: 20, MaxAge: 30 * 24 * time.Hour}
+
+// BackupFile is one target file saved by a backup run.
+type BackupFile struct {
+	Path string      `json:"path"`
+	Mode fs.FileMode `json:"mode"`
+	Size int64       `json:"size"`
+	// DirModes are the modes of the directories between the target
+	// directory and the file, outermost first, so a restore can recreate
+	// missing ones as they were.
+	DirModes []fs.FileMode `json:"dir_modes,omitempty"`
+}
+
+// BackupRun is a set of target files saved before one overwrite, such as a
+// force apply or a restore.
+type BackupRun struct {
+	ID    string       `json:"id"`
+	Time  time.Time    `json:"time"`
+	Label string       `json:"label"`
+	Files []BackupFile `json:"files"`
+}
+
+// BackupStore keeps timestamped copies of target files. Each run is a
+// directory holding a manifest and the files laid out relative to the
+// target directory:
+//
+//	<dir>/<run-id>/manifest.json
+//	<dir>/<run-id>/files/.bashrc
+//
+// The copies may hold secrets, so every directory in the store is created
+// private to the user.
+type BackupStore struct {
+	dir        string
+	targetPath string
+	retention  BackupRetention
+	now        func() time.Time
+}
+
+func NewBackupStore(dir, targetPath string, retention BackupRetention) *BackupStore {
+	return &BackupStore{dir: dir, targetPath: targetPath, retention: retention, now: time.Now}
+}
+
+func (b *BackupStore) Dir() string {
+	return b.dir
+}
+
+// Snapshot copies the regular files among paths into a new run. Paths that
+// do not exist, directories, and symlinks are skipped, since there is no
+// content to lose. No run is created when nothing was copied; the returned
+// run then has no files. Old runs are pruned afterwards.
+func (b *BackupStore) Snapshot(label string, paths []string) (BackupRun, error) {
+	run, err := b.snapshot(label, paths)
+	if err != nil || len(run.Files) == 0 {
+		return run, err
+	}
+	return run, b.prune(run.ID)
+}
+
+// snapshot is Snapshot without pruning.
+func (b *BackupStore) snapshot(label string, paths []string) (BackupRun, error) {
+	run := BackupRun{Time: b.now(), Label: label}
+	run.ID = run.Time.Format(backupRunIDLayout)
+	runDir := filepath.Join(b.dir, run.ID)
+
+	for _, path := range paths {
+		rel, err := b.relPath(path)
+		if err != nil {
+			return BackupRun{}, err
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return BackupRun{}, fmt.Errorf("backup %s: %w", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		dirModes, err := b.dirModes(rel)
+		if err != nil {
+			_ = os.RemoveAll(runDir)
+			return BackupRun{}, fmt.Errorf("backup %s: %w", path, err)
+		}
+		dst := filepath.Join(runDir, backupFilesDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), backupDirMode); err != nil {
+			_ = os.RemoveAll(runDir)
+			return BackupRun{}, fmt.Errorf("backup %s: %w", path, err)
+		}
+		if err := copyFile(path, dst, 0o600); err != nil {
+			_ = os.RemoveAll(runDir)
+			return BackupRun{}, fmt.Errorf("backup %s: %w", path, err)
+		}
+		run.Files = append(run.Files, BackupFile{Path: path, Mode: info.Mode().Perm(), Size: info.Size(), DirModes: dirModes})
+	}
+	if len(run.Files) == 0 {
+		return run, nil
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		_ = os.RemoveAll(runDir)
+		return BackupRun{}, fmt.Errorf("backup manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, backupManifestName), data, 0o600); err != nil {
+		_ = os.RemoveAll(runDir)
+		return BackupRun{}, fmt.Errorf("backup manifest: %w", err)
+	}
+	return run, nil
+}
+
+// Runs returns the stored runs, newest first. Directories without a
+// readable manifest are ignored.
+func (b *BackupStore) Runs() ([]BackupRun, error) {
+	dirEntries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backups: %w", err)
+	}
+	var runs []BackupRun
+	for _, e := range dirEntries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(b.dir, e.Name(), backupManifestName))
+		if err != nil {
+			continue
+		}
+		var run BackupRun
+		if json.Unmarshal(data, &run) != nil || run.ID != e.Name() {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	slices.SortFunc(runs, func(a, b BackupRun) int { return strings.Compare(b.ID, a.ID) })
+	return runs, nil
+}
+
+// FilePath returns where run stored its copy of target.
+func (b *BackupStore) FilePath(run BackupRun, target string) (string, error) {
+	if _, ok := run.file(target); !ok {
+		return "", fmt.Errorf("%s is not in backup %s", target, run.ID)
+	}
+	rel, err := b.relPath(target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(b.dir, run.ID, backupFilesDir, rel), nil
+}
+
+// Diff returns a unified diff from the backed-up copy of target to the file
+// currently on disk. A missing target diffs against empty content.
+func (b *BackupStore) Diff(run BackupRun, target string) (string, error) {
+	saved, err := b.FilePath(run, target)
+	if err != nil {
+		return "", err
+	}
+	old, err := os.ReadFile(saved)
+	if err != nil {
+		return "", fmt.Errorf("read backup: %w", err)
+	}
+	current, err := os.ReadFile(target)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("read target: %w", err)
+	}
+	rel, _ := b.relPath(target)
+	rel = filepath.ToSlash(rel)
+	return udiff.Unified("a/"+rel, "b/"+rel, string(old), string(current)), nil
+}
+
+// Restore writes the backed-up copy of target back to disk with its saved
+// mode. The current file is snapshotted first so a restore can itself be
+// undone; that run is returned and has no files when there was nothing to
+// save. Old runs are pruned only once the copy is back, since run itself
+// may be the oldest one kept.
+func (b *BackupStore) Restore(run BackupRun, target string) (BackupRun, error) {
+	file, ok := run.file(target)
+	if !ok {
+		return BackupRun{}, fmt.Errorf("%s is not in backup %s", target, run.ID)
+	}
+	saved, err := b.FilePath(run, target)
+	if err != nil {
+		return BackupRun{}, err
+	}
+	before, err := b.snapshot("before restore of "+run.ID, []string{target})
+	if err != nil {
+		return BackupRun{}, err
+	}
+	if err := b.restoreParents(target, file.DirModes); err != nil {
+		return before, fmt.Errorf("restore %s: %w", target, err)
+	}
+	if err := copyFile(saved, target, file.Mode); err != nil {
+		return before, fmt.Errorf("restore %s: %w", target, err)
+	}
+	if len(before.Files) == 0 {
+		return before, nil
+	}
+	return before, b.prune(before.ID)
+}
+
+// Prune removes runs beyond the retention policy.
+func (b *BackupStore) Prune() error {
+	return b.prune("")
+}
+
+// prune removes runs beyond the retention policy, never removing keep.
+func (b *BackupStore) prune(keep string) error {
+	runs, err := b.Runs()
+	if err != nil {
+		return err
+	}
+	cutoff := time.Time{}
+	if b.retention.MaxAge > 0 {
+		cutoff = b.now().Add(-b.retention.MaxAge)
+	}
+	for i, run := range runs {
+		if run.ID == keep {
+			continue
+		}
+		tooMany := b.retention.MaxRuns > 0 && i >= b.retention.MaxRuns
+		tooOld := !cutoff.IsZero() && run.Time.Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(b.dir, run.ID)); err != nil {
+			return fmt.Errorf("prune backup %s: %w", run.ID, err)
+		}
+	}
+	return nil
+}
+
+// relPath returns target relative to the target directory, rejecting
+// anything outside it.
+func (b *BackupStore) relPath(target string) (string, error) {
+	if err := NewPolicy(chezitconfig.ModeReadOnly, b.targetPath).ValidateTargetPath(target); err != nil {
+		return "", fmt.Errorf("%s: %w", target, err)
+	}
+	rel, err := filepath.Rel(b.targetPath, filepath.Clean(target))
+	if err != nil || rel == "." {
+		return "", fmt.Errorf("%s: %w", target, ErrOutsideTarget)
+	}
+	return rel, nil
+}
+
+// dirModes returns the modes of the directories leading to rel inside the
+// target directory, outermost first.
+func (b *BackupStore) dirModes(rel string) ([]fs.FileMode, error) {
+	var modes []fs.FileMode
+	dir := b.targetPath
+	for _, name := range strings.Split(filepath.Dir(rel), string(filepath.Separator)) {
+		if name == "." {
+			break
+		}
+		dir = filepath.Join(dir, name)
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		modes = append(modes, info.Mode().Perm())
+	}
+	return modes, nil
+}
+
+// restoreParents recreates the missing directories leading to target with
+// the modes recorded by dirModes. Directories a manifest has no mode for,
+// such as ones written before modes were recorded, are made private.
+func (b *BackupStore) restoreParents(target string, modes []fs.FileMode) error {
+	rel, err := b.relPath(target)
+	if err != nil {
+		return err
+	}
+	dir := b.targetPath
+	for i, name := range strings.Split(filepath.Dir(rel), string(filepath.Separator)) {
+		if name == "." {
+			break
+		}
+		dir = filepath.Join(dir, name)
+		if _, err := os.Stat(dir); err == nil {
+			continue
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		mode := backupDirMode
+		if i < len(modes) {
+			mode = modes[i]
+		}
+		if err := os.Mkdir(dir, mode); err != nil {
+			return err
+		}
+		// Mkdir applies the umask; the recorded mode is what was there.
+		if err := os.Chmod(dir, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r BackupRun) file(target string) (BackupFile, bool) {
+	for _, f := range r.Files {
+		if f.Path == target {
+			return f, true
+		}
+	}
+	return BackupFile{}, false
+}
+
+// copyFile writes src to dst through a temporary file in dst's directory,
+// so dst is never left half-written.
+func copyFile(src, dst string, mode fs.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(dst, data, mode)
+}
+
+// writeFileAtomic writes data to dst through a temporary file in dst's
+// directory, so dst is never left half-written. The directory must exist.
+func writeFileAtomic(dst string, data []byte, mode fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".chezit-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
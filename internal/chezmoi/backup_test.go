@@ -0,0 +1,257 @@
+package chezmoi
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func newTestBackupStore(t *testing.T, retention BackupRetention) (*BackupStore, string) {
+	t.Helper()
+	target := t.TempDir()
+	store := NewBackupStore(filepath.Join(t.TempDir(), "backups"), target, retention)
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return store, target
+}
+
+func writeTestFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackupStoreSnapshotAndRestore(t *testing.T) {
+	store, target := newTestBackupStore(t, BackupRetention{})
+	bashrc := filepath.Join(target, ".bashrc")
+	sshConfig := filepath.Join(target, ".ssh", "config")
+	writeTestFile(t, bashrc, "local edit\n", 0o644)
+	writeTestFile(t, sshConfig, "Host *\n", 0o600)
+
+	run, err := store.Snapshot("apply --force", []string{bashrc, sshConfig, filepath.Join(target, ".missing")})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if len(run.Files) != 2 {
+		t.Fatalf("expected 2 files (missing target skipped), got %+v", run.Files)
+	}
+
+	runs, err := store.Runs()
+	if err != nil || len(runs) != 1 || runs[0].ID != run.ID || runs[0].Label != "apply --force" {
+		t.Fatalf("unexpected runs %+v, err %v", runs, err)
+	}
+
+	writeTestFile(t, bashrc, "from source\n", 0o644)
+	diff, err := store.Diff(run, bashrc)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if !strings.Contains(diff, "-local edit") || !strings.Contains(diff, "+from source") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+
+	before, err := store.Restore(run, bashrc)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if data, _ := os.ReadFile(bashrc); string(data) != "local edit\n" {
+		t.Fatalf("expected restored content, got %q", data)
+	}
+	if len(before.Files) != 1 || before.Files[0].Path != bashrc {
+		t.Fatalf("expected the overwritten file saved before restore, got %+v", before.Files)
+	}
+
+	if _, err := store.Restore(run, sshConfig); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if info, _ := os.Stat(sshConfig); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected saved mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestBackupStoreKeepsDirectoriesPrivate(t *testing.T) {
+	store, target := newTestBackupStore(t, BackupRetention{})
+	sshDir := filepath.Join(target, ".ssh")
+	sshConfig := filepath.Join(sshDir, "config")
+	writeTestFile(t, sshConfig, "Host *\n", 0o600)
+	if err := os.Chmod(sshDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	perm := func(path string) os.FileMode {
+		t.Helper()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Mode().Perm()
+	}
+
+	run, err := store.Snapshot("apply --force", []string{sshConfig})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	for _, dir := range []string{store.Dir(), filepath.Join(store.Dir(), run.ID, backupFilesDir, ".ssh")} {
+		if got := perm(dir); got != 0o700 {
+			t.Fatalf("expected %s private to the user, got %v", dir, got)
+		}
+	}
+
+	// The restore recreates the removed directory with its recorded mode.
+	if err := os.RemoveAll(sshDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Restore(run, sshConfig); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := perm(sshDir); got != 0o750 {
+		t.Fatalf("expected .ssh recreated with mode 0750, got %v", got)
+	}
+
+	// Without a recorded mode the directory is made private.
+	if err := os.RemoveAll(sshDir); err != nil {
+		t.Fatal(err)
+	}
+	run.Files[0].DirModes = nil
+	if _, err := store.Restore(run, sshConfig); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := perm(sshDir); got != 0o700 {
+		t.Fatalf("expected .ssh recreated with mode 0700, got %v", got)
+	}
+}
+
+func TestBackupStoreSnapshotWithoutFilesCreatesNoRun(t *testing.T) {
+	store, target := newTestBackupStore(t, BackupRetention{})
+
+	run, err := store.Snapshot("apply --force", []string{filepath.Join(target, ".missing")})
+	if err != nil || len(run.Files) != 0 {
+		t.Fatalf("expected empty run, got %+v, err %v", run, err)
+	}
+	if _, err := os.Stat(store.Dir()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no backup directory, got err %v", err)
+	}
+}
+
+func TestBackupStoreRejectsPathsOutsideTarget(t *testing.T) {
+	store, _ := newTestBackupStore(t, BackupRetention{})
+
+	if _, err := store.Snapshot("apply", []string{"/etc/passwd"}); !errors.Is(err, ErrOutsideTarget) {
+		t.Fatalf("expected ErrOutsideTarget, got %v", err)
+	}
+}
+
+func TestBackupStorePrunesByCountAndAge(t *testing.T) {
+	store, target := newTestBackupStore(t, BackupRetention{MaxRuns: 2})
+	path := filepath.Join(target, ".bashrc")
+	writeTestFile(t, path, "x\n", 0o644)
+
+	var ids []string
+	for range 3 {
+		run, err := store.Snapshot("apply", []string{path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, run.ID)
+	}
+	runs, _ := store.Runs()
+	if len(runs) != 2 || runs[0].ID != ids[2] || runs[1].ID != ids[1] {
+		t.Fatalf("expected the two newest runs kept, got %+v", runs)
+	}
+
+	store.retention = BackupRetention{MaxAge: time.Hour}
+	now := store.now()
+	store.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if err := store.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if runs, _ := store.Runs(); len(runs) != 0 {
+		t.Fatalf("expected expired runs pruned, got %+v", runs)
+	}
+}
+
+func TestBackupStoreRestoresFromOldestKeptRun(t *testing.T) {
+	store, target := newTestBackupStore(t, BackupRetention{MaxRuns: 2})
+	path := filepath.Join(target, ".bashrc")
+	writeTestFile(t, path, "first\n", 0o644)
+	oldest, err := store.Snapshot("apply", []string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, path, "second\n", 0o644)
+	if _, err := store.Snapshot("apply", []string{path}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, path, "third\n", 0o644)
+	before, err := store.Restore(oldest, path)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first\n" {
+		t.Fatalf("expected the oldest run's content restored, got %q", data)
+	}
+	runs, _ := store.Runs()
+	if len(runs) != 2 || runs[0].ID != before.ID {
+		t.Fatalf("expected retention applied after the restore, got %+v", runs)
+	}
+}
+
+func TestServiceBackupTargetsSavesChangingFiles(t *testing.T) {
+	target := t.TempDir()
+	bashrc := filepath.Join(target, ".bashrc")
+	zshrc := filepath.Join(target, ".zshrc")
+	writeTestFile(t, bashrc, "edited\n", 0o644)
+	writeTestFile(t, zshrc, "clean\n", 0o644)
+	writeTestFile(t, filepath.Join(target, "install.sh"), "#!/bin/sh\n", 0o755)
+
+	svc := newFakeService(t, chezitconfig.ModeWrite, target, `
+case "$1" in
+status)
+	printf 'MM %s/.bashrc\nM  %s/.zshrc\n R %s/install.sh\n' "$TARGET" "$TARGET" "$TARGET"
+	;;
+*)
+	echo "unexpected command: $*" >&2
+	exit 1
+	;;
+esac
+`, WithBackupDir(filepath.Join(t.TempDir(), "backups")))
+	t.Setenv("TARGET", target)
+
+	run, err := svc.BackupTargets("apply --force", nil)
+	if err != nil {
+		t.Fatalf("BackupTargets: %v", err)
+	}
+	if len(run.Files) != 1 || run.Files[0].Path != bashrc {
+		t.Fatalf("expected only .bashrc saved, got %+v", run.Files)
+	}
+
+	run, err = svc.BackupTargets("apply --force", []string{zshrc})
+	if err != nil || len(run.Files) != 0 {
+		t.Fatalf("expected nothing saved outside the scope, got %+v, err %v", run.Files, err)
+	}
+}
+
+func TestServiceBackupReadOnly(t *testing.T) {
+	svc := NewService(New(WithBinaryPath("/bin/true")), chezitconfig.ModeReadOnly, "/home/test",
+		WithBackupDir(t.TempDir()))
+
+	if _, err := svc.BackupTargets("apply", nil); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	if _, err := svc.RestoreBackup(BackupRun{}, "/home/test/.bashrc"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package chezmoi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// Attribute names chattr understands, for the attributes chezit edits.
+const (
+	AttrEncrypted  = "encrypted"
+	AttrPrivate    = "private"
+	AttrReadonly   = "readonly"
+	AttrEmpty      = "empty"
+	AttrExecutable = "executable"
+	AttrTemplate   = "template"
+	AttrExact      = "exact"
+)
+
+// SourceEntry is a managed target with its decoded source name.
+type SourceEntry struct {
+	Target     string // absolute target path
+	SourcePath string // absolute source path
+	Name       SourceName
+}
+
+// EditableAttributes returns the attributes chattr can toggle on the
+// entry, in source name order. Scripts, symlinks, and remove_ entries have
+// none.
+func (e SourceEntry) EditableAttributes() []string {
+	switch e.Name.Kind {
+	case SourceKindDir:
+		return []string{AttrExact, AttrPrivate, AttrReadonly}
+	case SourceKindFile, SourceKindCreate:
+		return []string{AttrEncrypted, AttrPrivate, AttrReadonly, AttrEmpty, AttrExecutable, AttrTemplate}
+	case SourceKindModify:
+		return []string{AttrEncrypted, AttrPrivate, AttrReadonly, AttrExecutable, AttrTemplate}
+	}
+	return nil
+}
+
+// HasAttribute reports whether the entry's source name sets attr.
+func (e SourceEntry) HasAttribute(attr string) bool {
+	switch attr {
+	case AttrEncrypted:
+		return e.Name.Encrypted
+	case AttrPrivate:
+		return e.Name.Private
+	case AttrReadonly:
+		return e.Name.Readonly
+	case AttrEmpty:
+		return e.Name.Empty
+	case AttrExecutable:
+		return e.Name.Executable
+	case AttrTemplate:
+		return e.Name.Template
+	case AttrExact:
+		return e.Name.Exact
+	}
+	return false
+}
+
+// ChattrModifiers returns the chattr modifiers that turn the entry's
+// attributes into want, e.g. ["+private", "-executable"]. Attributes the
+// entry cannot have are ignored.
+func (e SourceEntry) ChattrModifiers(want []string) []string {
+	var modifiers []string
+	for _, attr := range e.EditableAttributes() {
+		on := slices.Contains(want, attr)
+		switch {
+		case on && !e.HasAttribute(attr):
+			modifiers = append(modifiers, "+"+attr)
+		case !on && e.HasAttribute(attr):
+			modifiers = append(modifiers, "-"+attr)
+		}
+	}
+	return modifiers
+}
+
+// Chattr runs `chezmoi chattr` with modifiers on a target.
+func (c *Client) Chattr(target string, modifiers []string) error {
+	output, err := c.run("chattr", "--", strings.Join(modifiers, ","), target)
+	if err != nil {
+		return fmt.Errorf("chezmoi chattr: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// SourceEntry decodes the source name of a managed target.
+func (s *Service) SourceEntry(target string) (SourceEntry, error) {
+	if err := s.policy.ValidateTargetPath(target); err != nil {
+		return SourceEntry{}, err
+	}
+	sources, err := s.client.SourcePaths([]string{target})
+	if err != nil {
+		return SourceEntry{}, err
+	}
+	info, err := os.Stat(sources[0])
+	if err != nil {
+		return SourceEntry{}, err
+	}
+	entry := SourceEntry{Target: target, SourcePath: sources[0]}
+	if info.IsDir() {
+		entry.Name = ParseSourceDirName(filepath.Base(sources[0]))
+	} else {
+		entry.Name = ParseSourceFileName(filepath.Base(sources[0]))
+	}
+	return entry, nil
+}
+
+// Chattr changes the attributes of a managed target's source entry.
+func (s *Service) Chattr(target string, modifiers []string) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	if err := s.policy.ValidateTargetPath(target); err != nil {
+		return err
+	}
+	if len(modifiers) == 0 {
+		return nil
+	}
+	return s.client.Chattr(target, modifiers)
+}
+
+// PreviewTemplateToggle returns a unified diff from the target's current
+// contents to the contents chezmoi would write once the template
+// attribute is set (template true) or cleared. With the attribute set the
+// source is rendered against the current data, so template errors surface
+// here rather than on the next apply.
+func (s *Service) PreviewTemplateToggle(entry SourceEntry, template bool) (string, error) {
+	current, err := s.client.CatTarget(entry.Target)
+	if err != nil {
+		return "", err
+	}
+	source, err := s.sourcePlaintext(entry)
+	if err != nil {
+		return "", err
+	}
+	next := source
+	if template {
+		next, err = s.renderText(source)
+		if err != nil {
+			return "", err
+		}
+	}
+	name := filepath.Base(entry.Target)
+	return udiff.Unified("a/"+name, "b/"+name, current, next), nil
+}
+
+// sourcePlaintext reads a source file, decrypting it if needed.
+func (s *Service) sourcePlaintext(entry SourceEntry) (string, error) {
+	if entry.Name.Encrypted {
+		plaintext, err := s.client.Decrypt(entry.SourcePath)
+		return string(plaintext), err
+	}
+	data, err := os.ReadFile(entry.SourcePath)
+	return string(data), err
+}
+
+// renderText renders text as a template file, which unlike
+// ExecuteTemplate has no argument length limit.
+func (s *Service) renderText(text string) (string, error) {
+	f, err := os.CreateTemp("", "chezit-template-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return s.client.ExecuteTemplateFile(f.Name(), "")
+}
@@ -0,0 +1,75 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestSourceEntryChattrModifiers(t *testing.T) {
+	entry := SourceEntry{Name: ParseSourceFileName("private_executable_dot_zshrc")}
+	if got := entry.ChattrModifiers([]string{AttrPrivate, AttrTemplate, AttrExact}); !slices.Equal(got, []string{"-executable", "+template"}) {
+		t.Fatalf("unexpected modifiers %q", got)
+	}
+
+	dir := SourceEntry{Name: ParseSourceDirName("exact_dot_config")}
+	if got := dir.ChattrModifiers([]string{AttrPrivate, AttrTemplate}); !slices.Equal(got, []string{"-exact", "+private"}) {
+		t.Fatalf("unexpected dir modifiers %q", got)
+	}
+	if got := (SourceEntry{Name: ParseSourceFileName("symlink_dot_vimrc")}).EditableAttributes(); got != nil {
+		t.Fatalf("expected symlinks to have no editable attributes, got %q", got)
+	}
+}
+
+const fakeChattrBody = `
+case "$1" in
+source-path) echo "$SRC/executable_dot_greet" ;;
+cat) printf 'hello {{ .name }}\n' ;;
+execute-template) sed 's/{{ .name }}/world/' "$3" ;;
+chattr) echo "$@" > "$SRC/chattr-args" ;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`
+
+func TestServiceChattrPreviewsTemplate(t *testing.T) {
+	src := t.TempDir()
+	t.Setenv("SRC", src)
+	writeTestFile(t, filepath.Join(src, "executable_dot_greet"), "hello {{ .name }}\n", 0o644)
+	svc := newFakeService(t, chezitconfig.ModeWrite, "/home/test", fakeChattrBody)
+
+	entry, err := svc.SourceEntry("/home/test/.greet")
+	if err != nil {
+		t.Fatalf("SourceEntry: %v", err)
+	}
+	if entry.Name.Kind != SourceKindFile || !entry.HasAttribute(AttrExecutable) || entry.HasAttribute(AttrTemplate) {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	diff, err := svc.PreviewTemplateToggle(entry, true)
+	if err != nil {
+		t.Fatalf("PreviewTemplateToggle: %v", err)
+	}
+	if !strings.Contains(diff, "-hello {{ .name }}") || !strings.Contains(diff, "+hello world") {
+		t.Fatalf("expected the rendered template in the diff:\n%s", diff)
+	}
+
+	if err := svc.Chattr(entry.Target, entry.ChattrModifiers([]string{AttrTemplate})); err != nil {
+		t.Fatalf("Chattr: %v", err)
+	}
+	args, err := os.ReadFile(filepath.Join(src, "chattr-args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(args)); got != "chattr -- -executable,+template /home/test/.greet" {
+		t.Fatalf("unexpected chattr args %q", got)
+	}
+
+	readOnly := newFakeService(t, chezitconfig.ModeReadOnly, "/home/test", fakeChattrBody)
+	if err := readOnly.Chattr(entry.Target, []string{"+private"}); err == nil {
+		t.Fatal("expected chattr refused in read-only mode")
+	}
+}
@@ -1,6 +1,8 @@
 package chezmoi
 
 import (
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -165,3 +167,155 @@ func TestParseGitPorcelainQuotedPaths(t *testing.T) {
 		t.Errorf("expected unquoted path, got %q", staged[0].Path)
 	}
 }
+
+func TestParseGitNameStatusZ(t *testing.T) {
+	files := ParseGitNameStatusZ("M\x00dot_bashrc\x00A\x00dot_config/new file\x00D\x00dot_old\x00")
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d: %+v", len(files), files)
+	}
+	want := []GitFile{
+		{Path: "dot_bashrc", StatusCode: "M"},
+		{Path: "dot_config/new file", StatusCode: "A"},
+		{Path: "dot_old", StatusCode: "D"},
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("files[%d] = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+}
+
+func TestParseApplyPlan(t *testing.T) {
+	input := `diff --git a/.bashrc b/.bashrc
+index 1111111..2222222 100644
+--- a/.bashrc
++++ b/.bashrc
+@@ -1 +1 @@
+-old
++new
+diff --git a/.config/new.toml b/.config/new.toml
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/.config/new.toml
+@@ -0,0 +1 @@
++key = 1
+diff --git a/.old b/.old
+deleted file mode 100644
+index 4444444..0000000
+--- a/.old
++++ /dev/null
+diff --git a/bin/tool b/bin/tool
+old mode 100644
+new mode 100755
+`
+	entries := ParseApplyPlan(input, "/home/user")
+	want := []struct {
+		path   string
+		action ApplyPlanAction
+	}{
+		{"/home/user/.bashrc", PlanModify},
+		{"/home/user/.config/new.toml", PlanCreate},
+		{"/home/user/.old", PlanDelete},
+		{"/home/user/bin/tool", PlanChmod},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+	for i, w := range want {
+		if entries[i].Path != w.path || entries[i].Action != w.action {
+			t.Errorf("entries[%d] = %s %s, want %s %s", i, entries[i].Action, entries[i].Path, w.action, w.path)
+		}
+	}
+	if !strings.HasPrefix(entries[0].Diff, "diff --git a/.bashrc") || !strings.HasSuffix(entries[0].Diff, "+new") {
+		t.Errorf("unexpected diff section for .bashrc: %q", entries[0].Diff)
+	}
+}
+
+func TestParseApplyPlanEmpty(t *testing.T) {
+	if entries := ParseApplyPlan("", "/home/user"); len(entries) != 0 {
+		t.Fatalf("expected no entries, got %+v", entries)
+	}
+}
+
+func TestParseApplyResult(t *testing.T) {
+	input := `diff --git a/.bashrc b/.bashrc
+index 1111111..2222222 100644
+--- a/.bashrc
++++ b/.bashrc
+@@ -1 +1 @@
+-old
++new
+.zshrc has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] s
+diff --git a/install.sh b/install.sh
+new file mode 100755
+index 0000000..3333333
+--- /dev/null
++++ b/install.sh
+@@ -0,0 +1 @@
++echo hi
+.gitconfig has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] o
+diff --git a/.gitconfig b/.gitconfig
+@@ -1 +1 @@
+-a
++b
+chezmoi: .vimrc: template: dot_vimrc.tmpl:3: map has no entry for key "email"
+chezmoi: interrupted
+`
+	result := ParseApplyResult(input, "/home/user", []string{"/home/user/install.sh"})
+	want := []ApplyResultEntry{
+		{Path: "/home/user/.bashrc", Outcome: ApplyWritten, Action: PlanModify},
+		{Path: "/home/user/install.sh", Outcome: ApplyScriptRan, Action: PlanScript},
+		{Path: "/home/user/.gitconfig", Outcome: ApplyWritten, Action: PlanModify},
+		{Path: "/home/user/.zshrc", Outcome: ApplySkipped, Reason: "changed since chezmoi last wrote it"},
+		{Path: "/home/user/.vimrc", Outcome: ApplyFailed, Reason: `template: dot_vimrc.tmpl:3: map has no entry for key "email"`},
+	}
+	if len(result.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(result.Entries), result.Entries)
+	}
+	for i := range want {
+		if result.Entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, result.Entries[i], want[i])
+		}
+	}
+	if len(result.Errors) != 1 || result.Errors[0] != "interrupted" {
+		t.Errorf("unexpected errors %q", result.Errors)
+	}
+	if result.Count(ApplyWritten) != 2 || result.Count(ApplySkipped) != 1 {
+		t.Errorf("unexpected counts: written=%d skipped=%d", result.Count(ApplyWritten), result.Count(ApplySkipped))
+	}
+}
+
+func TestParseApplyResultDiffThenSkip(t *testing.T) {
+	input := `.zshrc has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] d
+diff --git a/.zshrc b/.zshrc
+@@ -1 +1 @@
+-local
++source
+.zshrc has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] s
+.bashrc has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] diff
+diff --git a/.bashrc b/.bashrc
+@@ -1 +1 @@
+-local
++source
+.bashrc has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] overwrite
+diff --git a/.bashrc b/.bashrc
+@@ -1 +1 @@
+-local
++source
+`
+	result := ParseApplyResult(input, "/home/user", nil)
+	want := []ApplyResultEntry{
+		{Path: "/home/user/.bashrc", Outcome: ApplyWritten, Action: PlanModify},
+		{Path: "/home/user/.zshrc", Outcome: ApplySkipped, Reason: "changed since chezmoi last wrote it"},
+	}
+	if !slices.Equal(result.Entries, want) {
+		t.Fatalf("entries = %+v, want %+v", result.Entries, want)
+	}
+}
+
+func TestParseApplyResultEmpty(t *testing.T) {
+	if result := ParseApplyResult("", "/home/user", nil); !result.Empty() {
+		t.Fatalf("expected empty result, got %+v", result)
+	}
+}
@@ -3,15 +3,20 @@
 package chezmoi
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -101,19 +106,104 @@ func (c *Client) command(args ...string) *exec.Cmd {
 }
 
 func (c *Client) cmd(args ...string) (*exec.Cmd, context.CancelFunc) {
+	return c.cmdContext(context.Background(), args...)
+}
+
+// cmdContext is cmd for a command that is also killed when ctx is done.
+func (c *Client) cmdContext(ctx context.Context, args ...string) (*exec.Cmd, context.CancelFunc) {
 	allArgs := append(c.baseFlags(), args...)
-	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
 	cmd := exec.CommandContext(ctx, c.binary(), allArgs...)
 	cmd.Stdin = nil
 	return cmd, cancel
 }
 
 func (c *Client) run(args ...string) ([]byte, error) {
-	cmd, cancel := c.cmd(args...)
+	return c.runContext(context.Background(), args...)
+}
+
+func (c *Client) runContext(ctx context.Context, args ...string) ([]byte, error) {
+	cmd, cancel := c.cmdContext(ctx, args...)
 	defer cancel()
 	return cmd.CombinedOutput()
 }
 
+// applyToTimeout bounds ApplyTo, which renders every template.
+const applyToTimeout = 10 * time.Minute
+
+// runUntil is runContext without Timeout, for commands that may run far
+// longer; the caller bounds them through ctx.
+func (c *Client) runUntil(ctx context.Context, args ...string) ([]byte, error) {
+	allArgs := append(c.baseFlags(), args...)
+	cmd := exec.CommandContext(ctx, c.binary(), allArgs...)
+	cmd.Stdin = nil
+	return cmd.CombinedOutput()
+}
+
+// runStdout is run with input, unless nil, on stdin and with stdout kept
+// apart from stderr, for output that must not have warnings mixed into it.
+func (c *Client) runStdout(input []byte, args ...string) (stdout, stderr []byte, err error) {
+	cmd, cancel := c.cmd(args...)
+	defer cancel()
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+	stdout, err = cmd.Output()
+	return stdout, errBuf.Bytes(), err
+}
+
+// streamWaitDelay bounds how long a cancelled command may keep its output
+// pipes open, e.g. through a child process that outlived it.
+const streamWaitDelay = 2 * time.Second
+
+// stream is the streaming variant of run: each stdout and stderr line is
+// sent to out as it is written instead of after exit. There is no
+// Timeout; the command is killed when ctx is done, in which case the
+// context's error is returned. out is not closed.
+func (c *Client) stream(ctx context.Context, out chan<- OutputLine, args ...string) error {
+	allArgs := append(c.baseFlags(), args...)
+	cmd := exec.CommandContext(ctx, c.binary(), allArgs...)
+	cmd.Stdin = nil
+	cmd.WaitDelay = streamWaitDelay
+
+	stdoutR, stdoutW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go scanOutputLines(&wg, stdoutR, false, out)
+	go scanOutputLines(&wg, stderrR, true, out)
+
+	err := cmd.Start()
+	if err == nil {
+		err = cmd.Wait()
+	}
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	wg.Wait()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// scanOutputLines forwards r to out line by line. Whatever cannot be split
+// into lines is drained so the writer never blocks.
+func scanOutputLines(wg *sync.WaitGroup, r io.Reader, stderr bool, out chan<- OutputLine) {
+	defer wg.Done()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		out <- OutputLine{Text: strings.TrimRight(scanner.Text(), "\r"), Stderr: stderr}
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
 func IsAvailable() bool {
 	return IsAvailableAt("chezmoi")
 }
@@ -162,14 +252,28 @@ func (c *Client) Diff(filePath string) (string, error) {
 
 // AddOptions maps to `chezmoi add` flags.
 type AddOptions struct {
-	Encrypt      bool // --encrypt
-	Template     bool // --template
-	AutoTemplate bool // --autotemplate
-	Exact        bool // --exact (directories only)
-	NoRecursive  bool // --recursive=false (directories only)
-}
-
-// Validate rejects mutually exclusive flag combinations.
+	Encrypt          bool        `json:"encrypt,omitempty"`           // --encrypt
+	Template         bool        `json:"template,omitempty"`          // --template
+	AutoTemplate     bool        `json:"autotemplate,omitempty"`      // --autotemplate
+	Exact            bool        `json:"exact,omitempty"`             // --exact (directories only)
+	NoRecursive      bool        `json:"no_recursive,omitempty"`      // --recursive=false (directories only)
+	Follow           bool        `json:"follow,omitempty"`            // --follow
+	Create           bool        `json:"create,omitempty"`            // --create
+	TemplateSymlinks bool        `json:"template_symlinks,omitempty"` // --template-symlinks
+	Secrets          string      `json:"secrets,omitempty"`           // --secrets: "ignore", "warning", or "error"; "" keeps chezmoi's setting
+	Prompt           bool        `json:"prompt,omitempty"`            // --prompt; needs a terminal, see Service.AddCmd
+	Filter           EntryFilter `json:"filter,omitzero"`             // --include and --exclude
+
+	// AllowSecrets skips the secret scan Service.Add runs on plain-text
+	// files. It is not passed to chezmoi, nor remembered.
+	AllowSecrets bool `json:"-"`
+}
+
+// AddSecretsModes are the values --secrets accepts.
+var AddSecretsModes = []string{"ignore", "warning", "error"}
+
+// Validate rejects mutually exclusive flag combinations and unknown
+// values.
 func (o AddOptions) Validate() error {
 	exclusive := 0
 	if o.Encrypt {
@@ -184,6 +288,22 @@ func (o AddOptions) Validate() error {
 	if exclusive > 1 {
 		return errors.New("invalid add options: only one of --encrypt, --template, --autotemplate may be specified")
 	}
+	if o.Follow && o.TemplateSymlinks {
+		return errors.New("invalid add options: --follow adds symlink targets, so --template-symlinks has nothing to template")
+	}
+	if o.Secrets != "" && !slices.Contains(AddSecretsModes, o.Secrets) {
+		return fmt.Errorf("invalid add options: --secrets must be one of %s, got %q", strings.Join(AddSecretsModes, ", "), o.Secrets)
+	}
+	for _, t := range slices.Concat(o.Filter.Include, o.Filter.Exclude) {
+		if !slices.Contains(AllEntryTypes(), t) {
+			return fmt.Errorf("invalid add options: unknown entry type %q", t)
+		}
+	}
+	for _, t := range o.Filter.Include {
+		if slices.Contains(o.Filter.Exclude, t) {
+			return fmt.Errorf("invalid add options: entry type %q is both included and excluded", t)
+		}
+	}
 	return nil
 }
 
@@ -204,7 +324,22 @@ func (o AddOptions) args() []string {
 	if o.NoRecursive {
 		flags = append(flags, "--recursive=false")
 	}
-	return flags
+	if o.Follow {
+		flags = append(flags, "--follow")
+	}
+	if o.Create {
+		flags = append(flags, "--create")
+	}
+	if o.TemplateSymlinks {
+		flags = append(flags, "--template-symlinks")
+	}
+	if o.Secrets != "" {
+		flags = append(flags, "--secrets="+o.Secrets)
+	}
+	if o.Prompt {
+		flags = append(flags, "--prompt")
+	}
+	return append(flags, entryFilterArgs(o.Filter)...)
 }
 
 // Add runs `chezmoi add --force`.
@@ -224,16 +359,28 @@ func (c *Client) AddWithOptions(filePath string, opts AddOptions) error {
 	if err := opts.Validate(); err != nil {
 		return err
 	}
-	args := []string{"add", "--force"}
-	args = append(args, opts.args()...)
-	args = append(args, "--", filePath)
-	output, err := c.run(args...)
+	if opts.Prompt {
+		return errors.New("chezmoi add: --prompt needs a terminal")
+	}
+	output, err := c.run(addArgs(filePath, opts)...)
 	if err != nil {
 		return fmt.Errorf("chezmoi add: %s: %w", strings.TrimSpace(string(output)), err)
 	}
 	return nil
 }
 
+// AddCmd returns `chezmoi add` with the flags from opts, for adds that
+// prompt and so need a terminal.
+func (c *Client) AddCmd(filePath string, opts AddOptions) *exec.Cmd {
+	return c.command(addArgs(filePath, opts)...)
+}
+
+func addArgs(filePath string, opts AddOptions) []string {
+	args := []string{"add", "--force"}
+	args = append(args, opts.args()...)
+	return append(args, "--", filePath)
+}
+
 // DumpConfigJSON runs `chezmoi dump-config --format=json`.
 func (c *Client) DumpConfigJSON() (string, error) {
 	output, err := c.run("dump-config", "--format=json")
@@ -369,20 +516,23 @@ func (c *Client) ApplyRefreshCmd() *exec.Cmd {
 	return c.command("apply", "--refresh-externals")
 }
 
+// The interactive apply commands pass --verbose so that output captured
+// from them can be parsed with ParseApplyResult.
+
 func (c *Client) ApplyCmd(filePath string) *exec.Cmd {
-	return c.command("apply", filePath)
+	return c.command("apply", "--verbose", filePath)
 }
 
 func (c *Client) ApplyAllCmd() *exec.Cmd {
-	return c.command("apply")
+	return c.command("apply", "--verbose")
 }
 
 func (c *Client) ApplyForceCmd(filePath string) *exec.Cmd {
-	return c.command("apply", "--force", filePath)
+	return c.command("apply", "--verbose", "--force", filePath)
 }
 
 func (c *Client) ApplyAllForceCmd() *exec.Cmd {
-	return c.command("apply", "--force")
+	return c.command("apply", "--verbose", "--force")
 }
 
 func (c *Client) ApplyDryRunCmd() *exec.Cmd {
@@ -393,6 +543,39 @@ func (c *Client) ApplyRefreshDryRunCmd() *exec.Cmd {
 	return c.command("apply", "--refresh-externals", "--dry-run", "-v")
 }
 
+// ApplyPlan runs `chezmoi apply --dry-run --verbose --force` and returns the
+// diff of every change apply would make. --force stops chezmoi prompting
+// about targets changed since the last write; nothing is written.
+func (c *Client) ApplyPlan() (string, error) {
+	output, err := c.run("apply", "--dry-run", "--verbose", "--force")
+	if err != nil {
+		return "", fmt.Errorf("chezmoi apply --dry-run: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
+
+// ApplyTargets runs `chezmoi apply --verbose --force` for the given target
+// paths. The output is returned even on error so that the targets written
+// before the failure can still be reported.
+func (c *Client) ApplyTargets(paths []string) (string, error) {
+	args := append([]string{"apply", "--verbose", "--force", "--"}, paths...)
+	output, err := c.run(args...)
+	if err != nil {
+		return string(output), fmt.Errorf("chezmoi apply: %s: %w", lastLine(string(output)), err)
+	}
+	return string(output), nil
+}
+
+// lastLine returns the last non-empty line of output, which is where
+// chezmoi prints its error after any verbose diff.
+func lastLine(output string) string {
+	output = strings.TrimSpace(output)
+	if idx := strings.LastIndex(output, "\n"); idx >= 0 {
+		return strings.TrimSpace(output[idx+1:])
+	}
+	return output
+}
+
 func (c *Client) UpdateCmd() *exec.Cmd {
 	return c.command("update")
 }
@@ -415,6 +598,25 @@ func (c *Client) CatTarget(filePath string) (string, error) {
 	return string(output), nil
 }
 
+// Decrypt runs `chezmoi decrypt` on path and returns the plaintext.
+func (c *Client) Decrypt(path string) ([]byte, error) {
+	output, stderr, err := c.runStdout(nil, "decrypt", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("chezmoi decrypt: %s: %w", strings.TrimSpace(string(stderr)), err)
+	}
+	return output, nil
+}
+
+// Encrypt runs `chezmoi encrypt` with plaintext on stdin and returns the
+// ciphertext for the recipients in the config.
+func (c *Client) Encrypt(plaintext []byte) ([]byte, error) {
+	output, stderr, err := c.runStdout(plaintext, "encrypt")
+	if err != nil {
+		return nil, fmt.Errorf("chezmoi encrypt: %s: %w", strings.TrimSpace(string(stderr)), err)
+	}
+	return output, nil
+}
+
 func (c *Client) applyEditorEnv(cmd *exec.Cmd) {
 	if c.Editor != "" {
 		cmd.Env = append(os.Environ(), "EDITOR="+c.Editor)
@@ -555,7 +757,12 @@ func (c *Client) EditConfigCmd() *exec.Cmd {
 }
 
 func (c *Client) GitLog() (string, error) {
-	output, err := c.run("git", "--", "log", "--oneline", "-20")
+	return c.GitLogLimit(20)
+}
+
+// GitLogLimit returns the n most recent source commits in --oneline form.
+func (c *Client) GitLogLimit(n int) (string, error) {
+	output, err := c.run("git", "--", "log", "--oneline", "-n", strconv.Itoa(n))
 	if err != nil {
 		return "", fmt.Errorf("chezmoi git log: %s: %w", strings.TrimSpace(string(output)), err)
 	}
@@ -588,8 +795,36 @@ func (c *Client) GitLogIncoming() (string, error) {
 	return string(output), nil
 }
 
-func (c *Client) GitFetch() error {
-	output, err := c.run("git", "--", "fetch")
+// GitDiffIncomingNames returns `git diff --name-status -z` output for changes
+// between HEAD and upstream. Returns "" if no upstream.
+func (c *Client) GitDiffIncomingNames() (string, error) {
+	output, err := c.run("git", "--", "diff", "--name-status", "-z", "--no-renames", "HEAD...@{upstream}")
+	if err != nil {
+		out := strings.TrimSpace(string(output))
+		if strings.Contains(out, "no upstream") || strings.Contains(out, "unknown revision") {
+			return "", nil
+		}
+		return "", fmt.Errorf("chezmoi git diff incoming: %s: %w", out, err)
+	}
+	return string(output), nil
+}
+
+// GitDiffIncoming runs `chezmoi git diff HEAD...@{upstream}` for a single
+// path relative to the working tree root.
+func (c *Client) GitDiffIncoming(path string) (string, error) {
+	output, err := c.run("git", "--", "diff", "HEAD...@{upstream}", "--", path)
+	if err != nil {
+		out := string(output)
+		if out != "" && !strings.HasPrefix(out, "error:") && !strings.HasPrefix(out, "fatal:") {
+			return out, nil
+		}
+		return "", fmt.Errorf("chezmoi git diff incoming: %s: %w", strings.TrimSpace(out), err)
+	}
+	return string(output), nil
+}
+
+func (c *Client) GitFetch(ctx context.Context) error {
+	output, err := c.runContext(ctx, "git", "--", "fetch")
 	if err != nil {
 		return fmt.Errorf("chezmoi git fetch: %s: %w", strings.TrimSpace(string(output)), err)
 	}
@@ -635,6 +870,175 @@ func (c *Client) GitPull() error {
 	return nil
 }
 
+// GitHead returns the full hash of the source repo HEAD.
+func (c *Client) GitHead() (string, error) {
+	output, err := c.run("git", "--", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("chezmoi git rev-parse: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	head := strings.TrimSpace(string(output))
+	if !isValidGitHash(head) {
+		return "", fmt.Errorf("chezmoi git rev-parse: %w: %q", ErrInvalidHash, head)
+	}
+	return head, nil
+}
+
+// GitReflog returns recent HEAD reflog entries as "<hash>\t<subject>" lines,
+// newest first.
+func (c *Client) GitReflog() (string, error) {
+	output, err := c.run("git", "--", "reflog", "-n", "100", "--format=%H%x09%gs")
+	if err != nil {
+		return "", fmt.Errorf("chezmoi git reflog: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
+
+// GitCountCommits returns how many commits are reachable from HEAD but not
+// from hash.
+func (c *Client) GitCountCommits(hash string) (int, error) {
+	if !isValidGitHash(hash) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidHash, hash)
+	}
+	output, err := c.run("git", "--", "rev-list", "--count", hash+"..HEAD")
+	if err != nil {
+		return 0, fmt.Errorf("chezmoi git rev-list: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("chezmoi git rev-list: %w", err)
+	}
+	return n, nil
+}
+
+// GitResetHard runs `chezmoi git reset --hard` to a commit. Validates hash
+// format first.
+func (c *Client) GitResetHard(hash string) error {
+	if !isValidGitHash(hash) {
+		return fmt.Errorf("%w: %q", ErrInvalidHash, hash)
+	}
+	output, err := c.run("git", "--", "reset", "--hard", hash)
+	if err != nil {
+		return fmt.Errorf("chezmoi git reset --hard: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// GitWorktreeAdd checks commit out, detached, into a new worktree at dir.
+// Validates hash format first.
+func (c *Client) GitWorktreeAdd(dir, hash string) error {
+	if !isValidGitHash(hash) {
+		return fmt.Errorf("%w: %q", ErrInvalidHash, hash)
+	}
+	output, err := c.run("git", "--", "worktree", "add", "--detach", dir, hash)
+	if err != nil {
+		return fmt.Errorf("chezmoi git worktree add: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// GitWorktreeRemove removes the worktree at dir, discarding anything in it.
+func (c *Client) GitWorktreeRemove(dir string) error {
+	output, err := c.run("git", "--", "worktree", "remove", "--force", dir)
+	if err != nil {
+		return fmt.Errorf("chezmoi git worktree remove: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ApplyTo runs `chezmoi apply` into destDir, keeping persistent state in
+// stateFile so the real state is never read or written. sourceDir
+// overrides the source directory unless empty. Scripts and externals are
+// skipped and failing targets do not stop the rest. The output is
+// returned even on error. A full apply can far outlast Timeout, so it is
+// bounded by ctx and applyToTimeout instead.
+func (c *Client) ApplyTo(ctx context.Context, sourceDir, destDir, stateFile string) (string, error) {
+	args := []string{"apply", "--destination=" + destDir, "--persistent-state=" + stateFile}
+	if sourceDir != "" {
+		args = append(args, "--source="+sourceDir)
+	}
+	args = append(args, "--force", "--keep-going",
+		"--exclude="+joinEntryTypes([]EntryType{EntryScripts, EntryExternals}))
+	ctx, cancel := context.WithTimeout(ctx, applyToTimeout)
+	defer cancel()
+	output, err := c.runUntil(ctx, args...)
+	if err != nil {
+		return string(output), fmt.Errorf("chezmoi apply: %s: %w", lastLine(string(output)), err)
+	}
+	return string(output), nil
+}
+
+// SourcePaths runs `chezmoi source-path` for targets, returning one source
+// path per target in the same order.
+func (c *Client) SourcePaths(targets []string) ([]string, error) {
+	output, err := c.run(append([]string{"source-path"}, targets...)...)
+	if err != nil {
+		return nil, fmt.Errorf("chezmoi source-path: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	paths := parseLines(output)
+	if len(paths) != len(targets) {
+		return nil, fmt.Errorf("chezmoi source-path: got %d paths for %d targets", len(paths), len(targets))
+	}
+	return paths, nil
+}
+
+// ExecuteTemplateFile renders the template at path with
+// `chezmoi execute-template --file`. dataFile, unless empty, is passed as
+// --override-data-file to merge extra data over the template data.
+func (c *Client) ExecuteTemplateFile(path, dataFile string) (string, error) {
+	args := []string{"execute-template", "--file"}
+	if dataFile != "" {
+		args = append(args, "--override-data-file="+dataFile)
+	}
+	output, err := c.run(append(args, path)...)
+	if err != nil {
+		return "", fmt.Errorf("chezmoi execute-template: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
+
+// ExecuteTemplate renders text as a template with
+// `chezmoi execute-template`, using the current config and data.
+func (c *Client) ExecuteTemplate(text string) (string, error) {
+	output, err := c.run("execute-template", "--", text)
+	if err != nil {
+		return "", fmt.Errorf("chezmoi execute-template: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
+
+// StateDump runs `chezmoi state dump --format=json` for the persistent
+// state, keyed by bucket and then by key.
+func (c *Client) StateDump() (map[string]map[string]json.RawMessage, error) {
+	output, err := c.run("state", "dump", "--format=json")
+	if err != nil {
+		return nil, fmt.Errorf("chezmoi state dump: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	var state map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(output, &state); err != nil {
+		return nil, fmt.Errorf("chezmoi state dump: %w", err)
+	}
+	return state, nil
+}
+
+// StateDelete runs `chezmoi state delete` for one key in bucket.
+func (c *Client) StateDelete(bucket, key string) error {
+	output, err := c.run("state", "delete", "--bucket="+bucket, "--key="+key)
+	if err != nil {
+		return fmt.Errorf("chezmoi state delete: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// StateDeleteBucket runs `chezmoi state delete-bucket` for bucket and
+// every key in it.
+func (c *Client) StateDeleteBucket(bucket string) error {
+	output, err := c.run("state", "delete-bucket", "--bucket="+bucket)
+	if err != nil {
+		return fmt.Errorf("chezmoi state delete-bucket: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
 func (c *Client) Data() (string, error) {
 	output, err := c.run("data", "--format=yaml")
 	if err != nil {
@@ -660,6 +1064,16 @@ func (c *Client) Verify() error {
 	return nil
 }
 
+// GitDiffStaged runs `chezmoi git diff --cached` for the whole index, with
+// no context lines.
+func (c *Client) GitDiffStaged() (string, error) {
+	output, err := c.run("git", "--", "diff", "--cached", "--unified=0", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return "", fmt.Errorf("chezmoi git diff: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
+
 // GitDiff runs `chezmoi git diff` for a file. Uses --cached when staged is true.
 func (c *Client) GitDiff(path string, staged bool) (string, error) {
 	args := []string{"git", "--", "diff"}
@@ -687,6 +1101,53 @@ func (c *Client) ReAddAll() (string, error) {
 	return string(output), nil
 }
 
+// StreamReAddAll runs `chezmoi re-add --force`, sending output to out as
+// it is written.
+func (c *Client) StreamReAddAll(ctx context.Context, out chan<- OutputLine) error {
+	if err := c.stream(ctx, out, "re-add", "--force"); err != nil {
+		return fmt.Errorf("chezmoi re-add: %w", err)
+	}
+	return nil
+}
+
+// StreamRefreshExternals runs `chezmoi apply --refresh-externals --force
+// --include=externals`, re-downloading externals and applying only them.
+func (c *Client) StreamRefreshExternals(ctx context.Context, out chan<- OutputLine) error {
+	if err := c.stream(ctx, out, "apply", "--refresh-externals", "--force", "--include=externals"); err != nil {
+		return fmt.Errorf("chezmoi apply --refresh-externals: %w", err)
+	}
+	return nil
+}
+
+// StreamRefreshExternal runs `chezmoi apply --refresh-externals --force`
+// for target alone, re-downloading the external written there.
+func (c *Client) StreamRefreshExternal(ctx context.Context, out chan<- OutputLine, target string) error {
+	if err := c.stream(ctx, out, "apply", "--refresh-externals", "--force", target); err != nil {
+		return fmt.Errorf("chezmoi apply --refresh-externals: %w", err)
+	}
+	return nil
+}
+
+// StreamDoctor runs `chezmoi doctor`, sending its report to out line by
+// line. Like Doctor, a non-zero exit after a report is not an error since
+// doctor exits non-zero whenever a check fails.
+func (c *Client) StreamDoctor(ctx context.Context, out chan<- OutputLine) error {
+	err := c.stream(ctx, out, "doctor")
+	var exitErr *exec.ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		return nil
+	}
+	return fmt.Errorf("chezmoi doctor: %w", err)
+}
+
+// StreamVerify runs `chezmoi verify`, sending any output to out.
+func (c *Client) StreamVerify(ctx context.Context, out chan<- OutputLine) error {
+	if err := c.stream(ctx, out, "verify"); err != nil {
+		return fmt.Errorf("chezmoi verify: %w", err)
+	}
+	return nil
+}
+
 // StatusText runs `chezmoi status` and returns raw output.
 func (c *Client) StatusText() (string, error) {
 	output, err := c.run("status")
@@ -731,8 +1192,8 @@ func (c *Client) EditConfigTemplateCmd() *exec.Cmd {
 }
 
 // Archive runs `chezmoi archive --output=<path>`. Format is auto-detected from extension.
-func (c *Client) Archive(outputPath string) error {
-	_, err := c.run("archive", "--output="+outputPath)
+func (c *Client) Archive(ctx context.Context, outputPath string) error {
+	_, err := c.runContext(ctx, "archive", "--output="+outputPath)
 	if err != nil {
 		return fmt.Errorf("chezmoi archive: %w", err)
 	}
@@ -1,12 +1,15 @@
 package chezmoi
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestClientBaseFlagsContents(t *testing.T) {
@@ -95,10 +98,10 @@ func TestCommandConstructorsIncludeConfigFlag(t *testing.T) {
 		want []string
 	}{
 		{name: "ApplyRefreshCmd", cmd: client.ApplyRefreshCmd(), want: []string{"--config", "/tmp/custom.toml", "apply", "--refresh-externals"}},
-		{name: "ApplyCmd", cmd: client.ApplyCmd("/tmp/file"), want: []string{"--config", "/tmp/custom.toml", "apply", "/tmp/file"}},
-		{name: "ApplyAllCmd", cmd: client.ApplyAllCmd(), want: []string{"--config", "/tmp/custom.toml", "apply"}},
-		{name: "ApplyForceCmd", cmd: client.ApplyForceCmd("/tmp/file"), want: []string{"--config", "/tmp/custom.toml", "apply", "--force", "/tmp/file"}},
-		{name: "ApplyAllForceCmd", cmd: client.ApplyAllForceCmd(), want: []string{"--config", "/tmp/custom.toml", "apply", "--force"}},
+		{name: "ApplyCmd", cmd: client.ApplyCmd("/tmp/file"), want: []string{"--config", "/tmp/custom.toml", "apply", "--verbose", "/tmp/file"}},
+		{name: "ApplyAllCmd", cmd: client.ApplyAllCmd(), want: []string{"--config", "/tmp/custom.toml", "apply", "--verbose"}},
+		{name: "ApplyForceCmd", cmd: client.ApplyForceCmd("/tmp/file"), want: []string{"--config", "/tmp/custom.toml", "apply", "--verbose", "--force", "/tmp/file"}},
+		{name: "ApplyAllForceCmd", cmd: client.ApplyAllForceCmd(), want: []string{"--config", "/tmp/custom.toml", "apply", "--verbose", "--force"}},
 		{name: "ApplyDryRunCmd", cmd: client.ApplyDryRunCmd(), want: []string{"--config", "/tmp/custom.toml", "apply", "--dry-run", "-v"}},
 		{name: "ApplyRefreshDryRunCmd", cmd: client.ApplyRefreshDryRunCmd(), want: []string{"--config", "/tmp/custom.toml", "apply", "--refresh-externals", "--dry-run", "-v"}},
 		{name: "UpdateCmd", cmd: client.UpdateCmd(), want: []string{"--config", "/tmp/custom.toml", "update"}},
@@ -187,6 +190,86 @@ esac
 	}
 }
 
+func TestClientStreamSendsLinesAsWritten(t *testing.T) {
+	binaryPath := writeFakeChezmoiClientBinary(t, `
+case "$1" in
+re-add)
+	printf 'one\ntwo\n'
+	echo "warning: three" >&2
+	;;
+*)
+	echo "unexpected command: $*" >&2
+	exit 1
+	;;
+esac
+`)
+
+	client := New(WithBinaryPath(binaryPath))
+	out := make(chan OutputLine, 8)
+	if err := client.StreamReAddAll(context.Background(), out); err != nil {
+		t.Fatalf("StreamReAddAll returned unexpected error: %v", err)
+	}
+	close(out)
+
+	var stdout []string
+	var stderr []string
+	for line := range out {
+		if line.Stderr {
+			stderr = append(stderr, line.Text)
+		} else {
+			stdout = append(stdout, line.Text)
+		}
+	}
+	if strings.Join(stdout, ",") != "one,two" {
+		t.Fatalf("unexpected stdout lines %q", stdout)
+	}
+	if len(stderr) != 1 || stderr[0] != "warning: three" {
+		t.Fatalf("unexpected stderr lines %q", stderr)
+	}
+}
+
+func TestClientStreamCancelStopsCommand(t *testing.T) {
+	binaryPath := writeFakeChezmoiClientBinary(t, `
+echo started
+exec sleep 30
+`)
+
+	client := New(WithBinaryPath(binaryPath))
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan OutputLine, 8)
+	done := make(chan error, 1)
+	go func() { done <- client.StreamReAddAll(ctx, out) }()
+
+	if line := <-out; line.Text != "started" {
+		t.Fatalf("unexpected first line %q", line.Text)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("stream did not stop after cancel")
+	}
+}
+
+func TestClientStreamDoctorIgnoresFailedChecks(t *testing.T) {
+	binaryPath := writeFakeChezmoiClientBinary(t, `
+echo "failed  config-file  missing"
+exit 1
+`)
+
+	client := New(WithBinaryPath(binaryPath))
+	out := make(chan OutputLine, 8)
+	if err := client.StreamDoctor(context.Background(), out); err != nil {
+		t.Fatalf("expected doctor report without error, got %v", err)
+	}
+	if line := <-out; !strings.Contains(line.Text, "config-file") {
+		t.Fatalf("unexpected line %q", line.Text)
+	}
+}
+
 func TestClientIgnoredUsesResolvedTargetPath(t *testing.T) {
 	binaryPath := writeFakeChezmoiClientBinary(t, `
 case "$1" in
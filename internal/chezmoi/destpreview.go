@@ -0,0 +1,348 @@
+package chezmoi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// DestPreviewStatus is how a target in a DestPreview compares to the
+// current destination.
+type DestPreviewStatus uint8
+
+const (
+	PreviewSame     DestPreviewStatus = iota
+	PreviewAdded                      // in the preview, missing from the destination
+	PreviewModified                   // in the preview with different content
+	PreviewRemoved                    // managed now, not in the preview
+)
+
+// DestPreviewEntry is one target in a DestPreview.
+type DestPreviewEntry struct {
+	Path   string // target path in the real destination
+	Status DestPreviewStatus
+}
+
+// DestPreview is a destination applied into a temporary directory, either
+// from the current source (a sandbox apply) or from an older source commit
+// (time travel). Close it with CloseDestPreview.
+type DestPreview struct {
+	Commit  string // source commit previewed; "" for the current source
+	Dir     string // temporary directory holding the preview
+	DestDir string // preview destination inside Dir
+	Entries []DestPreviewEntry
+	// Warnings holds chezmoi's output when some targets failed to apply,
+	// e.g. templates needing secrets or data the commit predates.
+	Warnings string
+}
+
+// PreviewPath returns where target was written in the preview destination.
+func (p DestPreview) PreviewPath(target, targetPath string) (string, error) {
+	rel, err := filepath.Rel(targetPath, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", ErrOutsideTarget
+	}
+	return filepath.Join(p.DestDir, rel), nil
+}
+
+// Counts returns how many entries were added, modified, and removed.
+func (p DestPreview) Counts() (added, modified, removed int) {
+	for _, entry := range p.Entries {
+		switch entry.Status {
+		case PreviewAdded:
+			added++
+		case PreviewModified:
+			modified++
+		case PreviewRemoved:
+			removed++
+		}
+	}
+	return added, modified, removed
+}
+
+// timeTravelCommitLimit bounds how far back the commit picker reaches.
+const timeTravelCommitLimit = 200
+
+// TimeTravelCommits returns the source commits that can be previewed,
+// newest first.
+func (s *Service) TimeTravelCommits() ([]GitCommit, error) {
+	output, err := s.client.GitLogLimit(timeTravelCommitLimit)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGitLogOneline(output), nil
+}
+
+// SandboxApply applies the current source state into a temporary
+// destination with its own persistent state, leaving the real destination
+// and chezmoi state untouched. Scripts and externals are skipped, but the
+// configured hooks still run, so it is refused in read-only mode.
+func (s *Service) SandboxApply(ctx context.Context) (DestPreview, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return DestPreview{}, err
+	}
+	return s.newDestPreview("", func(preview *DestPreview) (string, error) {
+		return s.client.ApplyTo(ctx, "", preview.DestDir, filepath.Join(preview.Dir, "chezmoistate.boltdb"))
+	})
+}
+
+// TimeTravel previews the destination at commit without touching the real
+// source files, destination, or chezmoi state. The commit is checked out
+// into a temporary worktree, which git records under the source repo's
+// .git/worktrees until it is removed again, and applied like
+// SandboxApply; targets managed now but missing at the commit are
+// reported as removed. It is refused in read-only mode.
+func (s *Service) TimeTravel(ctx context.Context, commit string) (DestPreview, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return DestPreview{}, err
+	}
+	if !isValidGitHash(commit) {
+		return DestPreview{}, fmt.Errorf("%w: %q", ErrInvalidHash, commit)
+	}
+	sourceRel, err := s.sourceWithinGitRoot()
+	if err != nil {
+		return DestPreview{}, err
+	}
+	return s.newDestPreview(commit, func(preview *DestPreview) (output string, err error) {
+		worktree := filepath.Join(preview.Dir, "source")
+		if err := s.client.GitWorktreeAdd(worktree, commit); err != nil {
+			return "", err
+		}
+		// Deferred so the worktree is unregistered from the source repo
+		// however apply ends.
+		defer func() {
+			if removeErr := s.client.GitWorktreeRemove(worktree); removeErr != nil {
+				output, err = "", removeErr
+			}
+		}()
+		return s.client.ApplyTo(
+			ctx, filepath.Join(worktree, sourceRel), preview.DestDir, filepath.Join(preview.Dir, "chezmoistate.boltdb"),
+		)
+	})
+}
+
+// sourceWithinGitRoot returns the source directory relative to the root of
+// its git repo, so the same directory can be found inside a worktree or in
+// the paths git reports.
+func (s *Service) sourceWithinGitRoot() (string, error) {
+	root, err := s.client.GitRoot()
+	if err != nil {
+		return "", err
+	}
+	source, err := s.client.SourceDir()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(source); err == nil {
+		source = resolved
+	}
+	rel, err := filepath.Rel(root, source)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("source %s is outside its git repo %s", source, root)
+	}
+	return rel, nil
+}
+
+// newDestPreview creates the temporary directories, runs apply to fill the
+// preview destination, and compares the result with the current targets.
+// apply returns chezmoi's output; an error with some targets written is
+// kept as a warning. The temporary directory is removed on failure.
+func (s *Service) newDestPreview(commit string, apply func(*DestPreview) (string, error)) (DestPreview, error) {
+	dir, err := os.MkdirTemp("", "chezit-preview-")
+	if err != nil {
+		return DestPreview{}, fmt.Errorf("dest preview: %w", err)
+	}
+	preview := DestPreview{Commit: commit, Dir: dir, DestDir: filepath.Join(dir, "home")}
+	if err := s.fillDestPreview(&preview, apply); err != nil {
+		_ = os.RemoveAll(dir)
+		return DestPreview{}, err
+	}
+	return preview, nil
+}
+
+func (s *Service) fillDestPreview(preview *DestPreview, apply func(*DestPreview) (string, error)) error {
+	if err := os.Mkdir(preview.DestDir, 0o700); err != nil {
+		return fmt.Errorf("dest preview: %w", err)
+	}
+	output, applyErr := apply(preview)
+
+	entries, err := s.destPreviewEntries(preview.DestDir)
+	if err != nil {
+		return err
+	}
+	if applyErr != nil {
+		if len(entries) == 0 {
+			return applyErr
+		}
+		preview.Warnings = strings.TrimSpace(output)
+		if preview.Warnings == "" {
+			preview.Warnings = applyErr.Error()
+		}
+	}
+
+	if preview.Commit != "" {
+		// Targets managed now but absent at the commit would be removed by
+		// going back to it.
+		managed, err := s.client.ManagedWithFilter(EntryFilter{Exclude: []EntryType{EntryScripts, EntryExternals}})
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			seen[entry.Path] = true
+		}
+		for _, path := range managed {
+			if seen[path] {
+				continue
+			}
+			if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+				entries = append(entries, DestPreviewEntry{Path: path, Status: PreviewRemoved})
+			}
+		}
+	}
+	slices.SortFunc(entries, func(a, b DestPreviewEntry) int { return strings.Compare(a.Path, b.Path) })
+	preview.Entries = entries
+	return nil
+}
+
+// destPreviewEntries compares every file and symlink under destDir with
+// the matching target.
+func (s *Service) destPreviewEntries(destDir string) ([]DestPreviewEntry, error) {
+	targetPath := s.TargetPath()
+	var entries []DestPreviewEntry
+	err := filepath.WalkDir(destDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(destDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(targetPath, rel)
+		status, err := compareDestPreviewTarget(path, target)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, DestPreviewEntry{Path: target, Status: status})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dest preview: %w", err)
+	}
+	return entries, nil
+}
+
+func compareDestPreviewTarget(preview, target string) (DestPreviewStatus, error) {
+	previewInfo, err := os.Lstat(preview)
+	if err != nil {
+		return 0, err
+	}
+	targetInfo, err := os.Lstat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return PreviewAdded, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if previewInfo.Mode().Type() != targetInfo.Mode().Type() {
+		return PreviewModified, nil
+	}
+	if previewInfo.Mode()&fs.ModeSymlink != 0 {
+		previewLink, err := os.Readlink(preview)
+		if err != nil {
+			return 0, err
+		}
+		targetLink, err := os.Readlink(target)
+		if err != nil {
+			return 0, err
+		}
+		if previewLink != targetLink {
+			return PreviewModified, nil
+		}
+		return PreviewSame, nil
+	}
+	if previewInfo.Size() != targetInfo.Size() || previewInfo.Mode().Perm() != targetInfo.Mode().Perm() {
+		return PreviewModified, nil
+	}
+	previewData, err := os.ReadFile(preview)
+	if err != nil {
+		return 0, err
+	}
+	targetData, err := os.ReadFile(target)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(previewData, targetData) {
+		return PreviewModified, nil
+	}
+	return PreviewSame, nil
+}
+
+// DestPreviewDiff returns a unified diff from the current target to the
+// target in the preview.
+func (s *Service) DestPreviewDiff(preview DestPreview, target string) (string, error) {
+	previewFile, err := preview.PreviewPath(target, s.TargetPath())
+	if err != nil {
+		return "", err
+	}
+	current, err := readDestPreviewFile(target)
+	if err != nil {
+		return "", fmt.Errorf("read target: %w", err)
+	}
+	previewed, err := readDestPreviewFile(previewFile)
+	if err != nil {
+		return "", fmt.Errorf("read preview: %w", err)
+	}
+	rel, _ := filepath.Rel(s.TargetPath(), target)
+	rel = filepath.ToSlash(rel)
+	return udiff.Unified("a/"+rel, "b/"+rel, current, previewed), nil
+}
+
+// readDestPreviewFile reads a file, or a symlink's destination, returning
+// "" when path does not exist.
+func readDestPreviewFile(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		return "symlink to " + link + "\n", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// CloseDestPreview deletes a preview's temporary files.
+func (s *Service) CloseDestPreview(preview DestPreview) error {
+	if preview.Dir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(preview.Dir); err != nil {
+		return fmt.Errorf("dest preview: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package chezmoi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+// newDestPreviewService fakes a source repo that renders .bashrc and
+// .profile plus a new .config/vim/vimrc, with .zshrc managed now but not
+// rendered; a previewed commit renders the same files.
+func newDestPreviewService(t *testing.T, target string) *Service {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("ROOT", root)
+	t.Setenv("TARGET", target)
+	return newFakeService(t, chezitconfig.ModeWrite, target, `
+case "$1" in
+git)
+	case "$3 $4" in
+	"rev-parse --show-toplevel") echo "$ROOT" ;;
+	"worktree add") mkdir -p "$6/home" ;;
+	"worktree remove") rm -rf "$6" ;;
+	*) echo "unexpected git: $*" >&2; exit 1 ;;
+	esac
+	;;
+source-path) echo "$ROOT/home" ;;
+apply)
+	for arg in "$@"; do
+		case "$arg" in
+		--source=*) [ -d "${arg#--source=}" ] || { echo "missing source" >&2; exit 1; } ;;
+		--destination=*) dest="${arg#--destination=}" ;;
+		esac
+	done
+	echo "old bashrc" > "$dest/.bashrc"
+	echo "profile" > "$dest/.profile"
+	mkdir -p "$dest/.config/vim"
+	echo "vimrc" > "$dest/.config/vim/vimrc"
+	;;
+managed) printf '%s\n' "$TARGET/.bashrc" "$TARGET/.profile" "$TARGET/.zshrc" ;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+}
+
+func TestServiceTimeTravelComparesWithCurrentTargets(t *testing.T) {
+	target := t.TempDir()
+	for name, content := range map[string]string{".bashrc": "new bashrc\n", ".profile": "profile\n", ".zshrc": "zshrc\n"} {
+		if err := os.WriteFile(filepath.Join(target, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := newDestPreviewService(t, target)
+
+	preview, err := svc.TimeTravel(context.Background(), "abc1234")
+	if err != nil {
+		t.Fatalf("TimeTravel: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.CloseDestPreview(preview) })
+
+	want := []DestPreviewEntry{
+		{Path: filepath.Join(target, ".bashrc"), Status: PreviewModified},
+		{Path: filepath.Join(target, ".config/vim/vimrc"), Status: PreviewAdded},
+		{Path: filepath.Join(target, ".profile"), Status: PreviewSame},
+		{Path: filepath.Join(target, ".zshrc"), Status: PreviewRemoved},
+	}
+	if len(preview.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), preview.Entries)
+	}
+	for i := range want {
+		if preview.Entries[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, preview.Entries[i], want[i])
+		}
+	}
+	if added, modified, removed := preview.Counts(); added != 1 || modified != 1 || removed != 1 {
+		t.Fatalf("unexpected counts %d/%d/%d", added, modified, removed)
+	}
+	if _, err := os.Stat(filepath.Join(preview.Dir, "source")); !os.IsNotExist(err) {
+		t.Fatalf("expected the worktree removed after applying, got %v", err)
+	}
+
+	diff, err := svc.DestPreviewDiff(preview, filepath.Join(target, ".bashrc"))
+	if err != nil {
+		t.Fatalf("DestPreviewDiff: %v", err)
+	}
+	if !strings.Contains(diff, "-new bashrc") || !strings.Contains(diff, "+old bashrc") {
+		t.Fatalf("expected a diff from current to the commit, got:\n%s", diff)
+	}
+	diff, err = svc.DestPreviewDiff(preview, filepath.Join(target, ".zshrc"))
+	if err != nil || !strings.Contains(diff, "-zshrc") {
+		t.Fatalf("expected a removal diff, got %q (%v)", diff, err)
+	}
+
+	if err := svc.CloseDestPreview(preview); err != nil {
+		t.Fatalf("CloseDestPreview: %v", err)
+	}
+	if _, err := os.Stat(preview.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the preview removed, got %v", err)
+	}
+}
+
+func TestServiceDestPreviewsRefusedReadOnly(t *testing.T) {
+	svc := NewService(New(WithBinaryPath("/bin/true")), chezitconfig.ModeReadOnly, "/home/test")
+	if _, err := svc.TimeTravel(context.Background(), "abc1234"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected time travel refused in read-only mode, got %v", err)
+	}
+	if _, err := svc.SandboxApply(context.Background()); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected sandbox apply refused in read-only mode, got %v", err)
+	}
+}
+
+func TestApplyToOutlastsClientTimeout(t *testing.T) {
+	binary := writeFakeChezmoiBinary(t, `sleep 0.3; echo applied`)
+	client := New(WithBinaryPath(binary), WithTimeout(50*time.Millisecond))
+
+	output, err := client.ApplyTo(context.Background(), "", t.TempDir(), filepath.Join(t.TempDir(), "state.boltdb"))
+	if err != nil || strings.TrimSpace(output) != "applied" {
+		t.Fatalf("expected the apply to finish past the client timeout, got %q, %v", output, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.ApplyTo(ctx, "", t.TempDir(), filepath.Join(t.TempDir(), "state.boltdb")); err == nil {
+		t.Fatal("expected the caller's context to stop the apply")
+	}
+}
+
+func TestServiceTimeTravelRejectsInvalidHash(t *testing.T) {
+	svc := NewService(New(WithBinaryPath("/bin/true")), chezitconfig.ModeWrite, "/home/test")
+	if _, err := svc.TimeTravel(context.Background(), "--help"); !errors.Is(err, ErrInvalidHash) {
+		t.Fatal("expected an invalid hash to be rejected")
+	}
+}
+
+func TestServiceSandboxApplyUsesCurrentSource(t *testing.T) {
+	target := t.TempDir()
+	if err := os.WriteFile(filepath.Join(target, ".profile"), []byte("profile\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc := newDestPreviewService(t, target)
+
+	preview, err := svc.SandboxApply(context.Background())
+	if err != nil {
+		t.Fatalf("SandboxApply: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.CloseDestPreview(preview) })
+
+	if preview.Commit != "" {
+		t.Fatalf("expected no commit for a sandbox, got %q", preview.Commit)
+	}
+	// Without a commit nothing is reported as removed.
+	if added, modified, removed := preview.Counts(); added != 2 || modified != 0 || removed != 0 {
+		t.Fatalf("unexpected counts %d/%d/%d: %+v", added, modified, removed, preview.Entries)
+	}
+	if _, err := os.Stat(filepath.Join(target, ".bashrc")); !os.IsNotExist(err) {
+		t.Fatalf("expected the real destination untouched, got %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(preview.DestDir, ".bashrc")); err != nil || string(data) != "old bashrc\n" {
+		t.Fatalf("expected the rendered file in the sandbox, got %q (%v)", data, err)
+	}
+}
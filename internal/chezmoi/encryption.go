@@ -0,0 +1,197 @@
+package chezmoi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Encryption is the encryption chezmoi is configured with.
+type Encryption struct {
+	Tool       string   // "age" or "gpg"
+	Suffix     string   // appended to encrypted file names, e.g. ".age"
+	Recipients []string // who new ciphertext is encrypted for
+}
+
+type encryptionToolConfig struct {
+	Recipient  string   `json:"recipient"`
+	Recipients []string `json:"recipients"`
+	Suffix     string   `json:"suffix"`
+}
+
+// Encryption reads the encryption tool, suffix, and recipients from the
+// config. It returns ErrNoEncryption when none is configured.
+func (s *Service) Encryption() (Encryption, error) {
+	out, err := s.client.DumpConfigJSON()
+	if err != nil {
+		return Encryption{}, err
+	}
+	var config struct {
+		Encryption string               `json:"encryption"`
+		Age        encryptionToolConfig `json:"age"`
+		GPG        encryptionToolConfig `json:"gpg"`
+	}
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		return Encryption{}, fmt.Errorf("chezmoi dump-config: %w", err)
+	}
+	var tool encryptionToolConfig
+	enc := Encryption{Tool: config.Encryption}
+	switch enc.Tool {
+	case "age":
+		tool, enc.Suffix = config.Age, ".age"
+	case "gpg":
+		tool, enc.Suffix = config.GPG, ".asc"
+	default:
+		return Encryption{}, ErrNoEncryption
+	}
+	if tool.Suffix != "" {
+		enc.Suffix = tool.Suffix
+	}
+	if tool.Recipient != "" {
+		enc.Recipients = append(enc.Recipients, tool.Recipient)
+	}
+	enc.Recipients = append(enc.Recipients, tool.Recipients...)
+	return enc, nil
+}
+
+// EncryptFile writes path encrypted for the configured recipients next to
+// it, with the encryption suffix appended, and returns the new file. It
+// never overwrites an existing file.
+func (s *Service) EncryptFile(path string) (string, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return "", err
+	}
+	if err := s.policy.ValidateTargetPath(path); err != nil {
+		return "", err
+	}
+	enc, err := s.Encryption()
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := s.client.Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+	output := path + enc.Suffix
+	return output, writeNewFile(output, ciphertext)
+}
+
+// DecryptFile writes the plaintext of path next to it, without the
+// encryption suffix or, when it has none, with ".decrypted" appended, and
+// returns the new file. It never overwrites an existing file.
+func (s *Service) DecryptFile(path string) (string, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return "", err
+	}
+	if err := s.policy.ValidateTargetPath(path); err != nil {
+		return "", err
+	}
+	output := path + ".decrypted"
+	if enc, err := s.Encryption(); err == nil {
+		if trimmed, ok := strings.CutSuffix(path, enc.Suffix); ok && !strings.HasSuffix(trimmed, string(filepath.Separator)) {
+			output = trimmed
+		}
+	}
+	plaintext, err := s.client.Decrypt(path)
+	if err != nil {
+		return "", err
+	}
+	return output, writeNewFile(output, plaintext)
+}
+
+// writeNewFile creates path, readable only by the owner, with data. It
+// fails if path exists.
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// StreamReencryptAll decrypts every encrypted source file and encrypts it
+// again for the recipients now in the config, so that a new age recipient
+// takes effect without re-adding each target. One line per file is sent
+// to out. Files that fail are reported and skipped.
+func (s *Service) StreamReencryptAll(ctx context.Context, out chan<- OutputLine) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	enc, err := s.Encryption()
+	if err != nil {
+		return err
+	}
+	targets, err := s.client.ManagedWithFilter(EntryFilter{Include: []EntryType{EntryEncrypted}})
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		out <- OutputLine{Text: "no encrypted files in the source state"}
+		return nil
+	}
+	sources, err := s.client.SourcePaths(targets)
+	if err != nil {
+		return err
+	}
+	recipients := strings.Join(enc.Recipients, ", ")
+	if recipients == "" {
+		recipients = "the configured " + enc.Tool + " identity"
+	}
+	out <- OutputLine{Text: fmt.Sprintf("re-encrypting %d files for %s", len(sources), recipients)}
+
+	failed := 0
+	for i, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := targets[i]
+		if rel, err := filepath.Rel(s.TargetPath(), name); err == nil {
+			name = rel
+		}
+		progress := fmt.Sprintf("[%d/%d] %s", i+1, len(sources), name)
+		if err := s.reencryptFile(source); err != nil {
+			failed++
+			out <- OutputLine{Text: progress + ": " + err.Error(), Stderr: true}
+			continue
+		}
+		out <- OutputLine{Text: progress}
+	}
+	if failed > 0 {
+		return fmt.Errorf("re-encrypt: %d of %d files failed", failed, len(sources))
+	}
+	return nil
+}
+
+// reencryptFile replaces the encrypted source file with the same plaintext
+// encrypted for the current recipients, keeping its permissions.
+func (s *Service) reencryptFile(source string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+	plaintext, err := s.client.Decrypt(source)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := s.client.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(source, ciphertext, info.Mode().Perm())
+}
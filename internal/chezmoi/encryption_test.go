@@ -0,0 +1,107 @@
+package chezmoi
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+// fakeEncryptionBody "encrypts" by prefixing NEW: and "decrypts" by
+// dropping any uppercase prefix, failing on files that contain BAD.
+const fakeEncryptionBody = `
+case "$1" in
+dump-config) echo '{"encryption":"age","age":{"recipient":"age1new","suffix":".age"}}' ;;
+managed) printf '%s\n' "$TARGET/.secret" "$TARGET/.token" ;;
+source-path) printf '%s\n' "$SRC/encrypted_dot_secret.age" "$SRC/encrypted_dot_token.age" ;;
+encrypt) printf 'NEW:'; cat ;;
+decrypt)
+	if grep -q BAD "$3"; then echo "no identity matched" >&2; exit 1; fi
+	sed 's/^[A-Z]*://' "$3"
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`
+
+func TestServiceStreamReencryptAll(t *testing.T) {
+	src, target := t.TempDir(), t.TempDir()
+	secret := filepath.Join(src, "encrypted_dot_secret.age")
+	writeTestFile(t, secret, "OLD:hello", 0o600)
+	writeTestFile(t, filepath.Join(src, "encrypted_dot_token.age"), "BAD", 0o600)
+	t.Setenv("SRC", src)
+	t.Setenv("TARGET", target)
+	svc := newFakeService(t, chezitconfig.ModeWrite, target, fakeEncryptionBody)
+
+	out := make(chan OutputLine, 16)
+	err := svc.StreamReencryptAll(context.Background(), out)
+	close(out)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 files failed") {
+		t.Fatalf("expected one failure reported, got %v", err)
+	}
+	var lines []string
+	for line := range out {
+		lines = append(lines, line.Text)
+	}
+	want := []string{
+		"re-encrypting 2 files for age1new",
+		"[1/2] .secret",
+		"[2/2] .token: chezmoi decrypt: no identity matched: exit status 1",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected progress:\n%s", strings.Join(lines, "\n"))
+	}
+
+	data, err := os.ReadFile(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "NEW:hello" {
+		t.Errorf("expected the source re-encrypted, got %q", data)
+	}
+	if info, err := os.Stat(secret); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the mode kept, got %v %v", info.Mode(), err)
+	}
+}
+
+func TestServiceEncryptAndDecryptFile(t *testing.T) {
+	target := t.TempDir()
+	notes := filepath.Join(target, "notes.txt")
+	writeTestFile(t, notes, "hi", 0o644)
+	svc := newFakeService(t, chezitconfig.ModeWrite, target, fakeEncryptionBody)
+
+	encrypted, err := svc.EncryptFile(notes)
+	if err != nil {
+		t.Fatalf("EncryptFile: %v", err)
+	}
+	if data, _ := os.ReadFile(encrypted); encrypted != notes+".age" || string(data) != "NEW:hi" {
+		t.Fatalf("unexpected encrypted file %s: %q", encrypted, data)
+	}
+	if _, err := svc.EncryptFile(notes); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("expected the existing .age file kept, got %v", err)
+	}
+
+	if _, err := svc.DecryptFile(encrypted); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("expected the existing plaintext kept, got %v", err)
+	}
+	if err := os.Remove(notes); err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := svc.DecryptFile(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptFile: %v", err)
+	}
+	info, err := os.Stat(decrypted)
+	if data, _ := os.ReadFile(decrypted); decrypted != notes || string(data) != "hi" || err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("unexpected decrypted file %s: %q", decrypted, data)
+	}
+
+	readOnly := NewService(New(WithBinaryPath("chezmoi")), chezitconfig.ModeReadOnly, target)
+	if _, err := readOnly.EncryptFile(notes); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+}
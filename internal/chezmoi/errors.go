@@ -9,4 +9,8 @@ var (
 	ErrPathEmpty     = errors.New("path is empty")
 	ErrPathNotAbs    = errors.New("path must be absolute")
 	ErrInvalidHash   = errors.New("invalid git commit hash")
+	ErrNoUpdate      = errors.New("no update to undo")
+	ErrSourceDirty   = errors.New("source has uncommitted changes")
+	ErrInvalidData   = errors.New("invalid template data overlay")
+	ErrNoEncryption  = errors.New("no encryption configured")
 )
@@ -0,0 +1,241 @@
+package chezmoi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// External is an entry defined in a .chezmoiexternal file, with when chezmoi
+// last downloaded it.
+type External struct {
+	Target        string        // target path the external is written to
+	Type          string        // "file", "archive", "archive-file", or "git-repo"
+	URL           string        // first URL chezmoi tries
+	RefreshPeriod time.Duration // how long a download is reused; 0 until --refresh-externals
+	Definition    string        // .chezmoiexternal file the entry is in
+	LastFetched   time.Time     // cache or clone modification time; zero when never fetched
+	Err           error         // Definition could not be read; only Definition is set
+}
+
+// RefreshDue reports whether the next apply downloads e again because its
+// refresh period has passed.
+func (e External) RefreshDue(now time.Time) bool {
+	return e.RefreshPeriod > 0 && !e.LastFetched.IsZero() && now.Sub(e.LastFetched) > e.RefreshPeriod
+}
+
+// externalDef is the part of a .chezmoiexternal entry chezit shows.
+type externalDef struct {
+	Type          string   `json:"type" yaml:"type"`
+	URL           string   `json:"url" yaml:"url"`
+	URLs          []string `json:"urls" yaml:"urls"`
+	RefreshPeriod string   `json:"refreshPeriod" yaml:"refreshPeriod"`
+}
+
+// Externals reads every .chezmoiexternal.<format> file and .chezmoiexternals
+// directory in the source state and lists the entries they define, sorted
+// by target. Templated definitions are rendered first. A definition that
+// cannot be read or parsed is listed as a single entry with Err set.
+func (s *Service) Externals() ([]External, error) {
+	sourceDir, err := s.client.SourceDir()
+	if err != nil {
+		return nil, err
+	}
+	cacheDir := s.externalCacheDir()
+
+	var externals []External
+	err = filepath.WalkDir(sourceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		targetDir, ok := externalTargetDir(sourceDir, p)
+		if !ok {
+			return nil
+		}
+		defs, err := s.readExternalDefs(p)
+		if err != nil {
+			externals = append(externals, External{Definition: p, Err: err})
+			return nil
+		}
+		for _, name := range slices.Sorted(maps.Keys(defs)) {
+			target := filepath.Join(s.TargetPath(), filepath.FromSlash(targetDir), filepath.FromSlash(name))
+			externals = append(externals, newExternal(defs[name], target, p, cacheDir))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(externals, func(a, b External) int {
+		return strings.Compare(a.Target, b.Target)
+	})
+	return externals, nil
+}
+
+// externalTargetDir reports whether the source file p defines externals and
+// returns the directory, relative to the destination, its entries are
+// relative to: the target of the directory holding the .chezmoiexternal
+// file or .chezmoiexternals directory.
+func externalTargetDir(sourceDir, p string) (string, bool) {
+	rel, err := filepath.Rel(sourceDir, p)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	dir, name := path.Split(rel)
+	if !strings.HasPrefix(name, ".chezmoiexternal.") {
+		i := strings.Index("/"+rel, "/.chezmoiexternals/")
+		if i < 0 {
+			return "", false
+		}
+		dir = rel[:i]
+	}
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		return "", true
+	}
+	target, ok := TargetRelPath(dir)
+	return target, ok
+}
+
+// readExternalDefs parses a definition file by its extension, rendering it
+// first when it is a template.
+func (s *Service) readExternalDefs(p string) (map[string]externalDef, error) {
+	name := filepath.Base(p)
+	var text string
+	if base, ok := strings.CutSuffix(name, ".tmpl"); ok {
+		name = base
+		out, err := s.client.ExecuteTemplateFile(p, "")
+		if err != nil {
+			return nil, err
+		}
+		text = out
+	} else {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+
+	defs := map[string]externalDef{}
+	switch ext := path.Ext(name); ext {
+	case ".json", ".jsonc":
+		if err := json.Unmarshal([]byte(text), &defs); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal([]byte(text), &defs); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	case ".toml":
+		// chezmoi's own TOML parser converts it, so chezit needs none.
+		out, err := s.client.ExecuteTemplate("{{ " + strconv.Quote(text) + " | fromToml | toJson }}")
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(out), &defs); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown format %q", name, ext)
+	}
+	return defs, nil
+}
+
+// newExternal describes def, defined in definition and written to target.
+func newExternal(def externalDef, target, definition, cacheDir string) External {
+	e := External{
+		Target:     target,
+		Type:       def.Type,
+		URL:        def.URL,
+		Definition: definition,
+	}
+	if e.URL == "" && len(def.URLs) > 0 {
+		e.URL = def.URLs[0]
+	}
+	if d, err := time.ParseDuration(def.RefreshPeriod); err == nil {
+		e.RefreshPeriod = d
+	}
+	if e.Type == "git-repo" {
+		e.LastFetched = gitRepoFetchTime(target)
+	} else {
+		e.LastFetched = externalCacheTime(cacheDir, append([]string{def.URL}, def.URLs...))
+	}
+	return e
+}
+
+// externalCacheTime returns when chezmoi last downloaded any of urls into
+// its cache, which it keys by the SHA-256 of the URL.
+func externalCacheTime(cacheDir string, urls []string) time.Time {
+	var last time.Time
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		sum := sha256.Sum256([]byte(url))
+		info, err := os.Stat(filepath.Join(cacheDir, "external", hex.EncodeToString(sum[:])))
+		if err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last
+}
+
+// gitRepoFetchTime returns when the git-repo external at target was last
+// pulled, or cloned if it never was.
+func gitRepoFetchTime(target string) time.Time {
+	for _, name := range []string{"FETCH_HEAD", ""} {
+		if info, err := os.Stat(filepath.Join(target, ".git", name)); err == nil {
+			return info.ModTime()
+		}
+	}
+	return time.Time{}
+}
+
+// externalCacheDir returns chezmoi's cache directory from its config,
+// falling back to the default.
+func (s *Service) externalCacheDir() string {
+	var config struct {
+		CacheDir string `json:"cacheDir"`
+	}
+	if out, err := s.client.DumpConfigJSON(); err == nil && json.Unmarshal([]byte(out), &config) == nil && config.CacheDir != "" {
+		return config.CacheDir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "chezmoi")
+}
+
+// StreamRefreshExternal re-downloads the external at target and applies
+// it, sending the output to out.
+func (s *Service) StreamRefreshExternal(ctx context.Context, out chan<- OutputLine, target string) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	if err := s.policy.ValidateTargetPath(target); err != nil {
+		return err
+	}
+	return s.client.StreamRefreshExternal(ctx, out, target)
+}
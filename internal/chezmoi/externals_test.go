@@ -0,0 +1,74 @@
+package chezmoi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestServiceExternals(t *testing.T) {
+	src, target, cache := t.TempDir(), t.TempDir(), t.TempDir()
+	writeTestFile(t, filepath.Join(src, ".chezmoiexternal.toml"), "[\".oh-my-zsh\"]\n", 0o644)
+	writeTestFile(t, filepath.Join(src, "dot_config", ".chezmoiexternals", "nvim.yaml.tmpl"),
+		"nvim:\n  type: git-repo\n  url: https://github.com/example/nvim.git\n", 0o644)
+	writeTestFile(t, filepath.Join(src, "private_dot_local", ".chezmoiexternal.json"), "{not json", 0o644)
+	writeTestFile(t, filepath.Join(target, ".config", "nvim", ".git", "FETCH_HEAD"), "", 0o644)
+
+	// The archive was downloaded 10 days ago and refreshes weekly.
+	url := "https://github.com/ohmyzsh/ohmyzsh/archive/master.tar.gz"
+	sum := sha256.Sum256([]byte(url))
+	cached := filepath.Join(cache, "external", hex.EncodeToString(sum[:]))
+	writeTestFile(t, cached, "", 0o644)
+	fetched := time.Now().Add(-240 * time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(cached, fetched, fetched); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SRC", src)
+	t.Setenv("CACHE", cache)
+	t.Setenv("TOMLJSON", `{".oh-my-zsh":{"type":"archive","url":"`+url+`","refreshPeriod":"168h"}}`)
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, target, `
+case "$1" in
+source-path) echo "$SRC" ;;
+dump-config) echo "{\"cacheDir\": \"$CACHE\"}" ;;
+execute-template)
+	case "$2" in
+	--file) cat "$3" ;;
+	*) echo "$TOMLJSON" ;;
+	esac
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+
+	externals, err := svc.Externals()
+	if err != nil {
+		t.Fatalf("Externals: %v", err)
+	}
+	if len(externals) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", externals)
+	}
+	broken, nvim, omz := externals[0], externals[1], externals[2]
+
+	if broken.Err == nil || !strings.HasSuffix(broken.Definition, ".chezmoiexternal.json") {
+		t.Errorf("expected the broken definition listed with its error, got %+v", broken)
+	}
+	if nvim.Target != filepath.Join(target, ".config", "nvim") || nvim.Type != "git-repo" || nvim.LastFetched.IsZero() {
+		t.Errorf("unexpected git-repo external %+v", nvim)
+	}
+	if nvim.RefreshDue(time.Now()) {
+		t.Error("expected no refresh due without a refresh period")
+	}
+	if omz.Target != filepath.Join(target, ".oh-my-zsh") || omz.Type != "archive" || omz.URL != url {
+		t.Errorf("unexpected archive external %+v", omz)
+	}
+	if omz.RefreshPeriod != 168*time.Hour || !omz.LastFetched.Equal(fetched) || !omz.RefreshDue(time.Now()) {
+		t.Errorf("expected the archive fetched 10 days ago and due, got %+v", omz)
+	}
+}
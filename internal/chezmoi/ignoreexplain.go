@@ -0,0 +1,367 @@
+package chezmoi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IgnoreLineState says how a .chezmoiignore line bears on a path.
+type IgnoreLineState int
+
+const (
+	IgnoreLineInert   IgnoreLineState = iota // blank, comment, or template action
+	IgnoreLineSkipped                        // left out by a template conditional with the current data
+	IgnoreLineNoMatch                        // rendered, but matches neither the path nor a parent
+	IgnoreLineMatch                          // ignores the path or a parent
+	IgnoreLineNegated                        // "!" pattern matching the path or a parent, which keeps it
+)
+
+// IgnoreRuleLine is one line of a .chezmoiignore file, evaluated against
+// a path.
+type IgnoreRuleLine struct {
+	Number   int    // 1-based
+	Text     string // as written, before rendering
+	Patterns []string
+	State    IgnoreLineState
+	Matched  string // the path or parent the line matched, relative to the destination
+}
+
+// IgnoreRuleFile is a .chezmoiignore file that applies to a path.
+type IgnoreRuleFile struct {
+	Path        string // absolute
+	Dir         string // target directory its patterns are relative to, "" for the root
+	Lines       []IgnoreRuleLine
+	RenderError string // the template did not render; lines are not evaluated
+}
+
+// IgnoreExplanation describes why a target is or is not ignored: by which
+// .chezmoiignore lines, and whether an entry filter hides it instead.
+type IgnoreExplanation struct {
+	Target    string
+	Rel       string // slash-separated, relative to the destination
+	Files     []IgnoreRuleFile
+	IgnoredAt string // the path or parent the rules ignore, "" if none
+
+	Filter  EntryFilter
+	Managed int // managed entries at or under Target, counted when Filter is set
+	Hidden  int // of those, how many Filter leaves out
+}
+
+// Ignored reports whether the rules ignore the path.
+func (e IgnoreExplanation) Ignored() bool { return e.IgnoredAt != "" }
+
+// ExplainIgnore renders every .chezmoiignore that applies to target with
+// the current template data and evaluates its patterns against target and
+// its parents. A non-zero filter is also checked for hiding managed
+// entries at or under target.
+func (s *Service) ExplainIgnore(target string, filter EntryFilter) (IgnoreExplanation, error) {
+	if err := s.policy.ValidateTargetPath(target); err != nil {
+		return IgnoreExplanation{}, err
+	}
+	rel, err := filepath.Rel(s.TargetPath(), target)
+	if err != nil || rel == "." {
+		return IgnoreExplanation{}, ErrOutsideTarget
+	}
+	e := IgnoreExplanation{Target: target, Rel: filepath.ToSlash(rel), Filter: filter}
+
+	sourceDir, err := s.client.SourceDir()
+	if err != nil {
+		return IgnoreExplanation{}, err
+	}
+	files, err := findIgnoreFiles(sourceDir, e.Rel)
+	if err != nil {
+		return IgnoreExplanation{}, err
+	}
+	for _, f := range files {
+		e.Files = append(e.Files, s.evaluateIgnoreFile(f, e.Rel))
+	}
+	e.IgnoredAt = ignoredAt(e.Files, e.Rel)
+
+	if !filter.IsZero() {
+		all, err := s.client.Managed()
+		if err != nil {
+			return IgnoreExplanation{}, err
+		}
+		shown, err := s.client.ManagedWithFilter(filter)
+		if err != nil {
+			return IgnoreExplanation{}, err
+		}
+		under := func(p string) bool { return p == target || hasPathPrefix(p, target) }
+		e.Managed = countFunc(all, under)
+		e.Hidden = e.Managed - countFunc(shown, under)
+	}
+	return e, nil
+}
+
+// findIgnoreFiles returns the .chezmoiignore files in the source directory
+// whose target directory is rel or one of its parents, root first.
+func findIgnoreFiles(sourceDir, rel string) ([]IgnoreRuleFile, error) {
+	var files []IgnoreRuleFile
+	err := filepath.WalkDir(sourceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		sourceRel, _ := filepath.Rel(sourceDir, p)
+		if d.IsDir() {
+			if sourceRel == "." {
+				return nil
+			}
+			dir, ok := targetDirRelPath(filepath.ToSlash(sourceRel))
+			if !ok || !(dir == rel || strings.HasPrefix(rel, dir+"/")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != ignoreFileName {
+			return nil
+		}
+		dir := ""
+		if sourceDir := filepath.Dir(sourceRel); sourceDir != "." {
+			dir, _ = targetDirRelPath(filepath.ToSlash(sourceDir))
+		}
+		files = append(files, IgnoreRuleFile{Path: p, Dir: dir})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("find %s files: %w", ignoreFileName, err)
+	}
+	return files, nil
+}
+
+// targetDirRelPath maps a slash-separated source directory path to its
+// target path, or false if chezmoi does not read it as a directory.
+func targetDirRelPath(sourceRel string) (string, bool) {
+	segments := strings.Split(sourceRel, "/")
+	target := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+		sn := ParseSourceDirName(segment)
+		if sn.Kind != SourceKindDir || sn.External || sn.TargetName == "" {
+			return "", false
+		}
+		target = append(target, sn.TargetName)
+	}
+	return strings.Join(target, "/"), true
+}
+
+// ignoreLineMarker tags the end of each source line so rendered patterns
+// can be traced back to the line that produced them.
+var ignoreLineMarker = regexp.MustCompile("\x1e([0-9]+)\x1e")
+
+// templateAction matches a template action on a single line.
+var templateAction = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// evaluateIgnoreFile renders f and marks each line with how it bears on
+// rel.
+func (s *Service) evaluateIgnoreFile(f IgnoreRuleFile, rel string) IgnoreRuleFile {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		f.RenderError = err.Error()
+		return f
+	}
+	source := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	// Mark lines outside template actions; a line inside a multi-line
+	// action is left unmarked so the marker is not parsed as template code.
+	var b strings.Builder
+	depth := 0
+	inert := make([]bool, len(source))
+	for i, line := range source {
+		inAction := depth > 0
+		depth = max(depth+strings.Count(line, "{{")-strings.Count(line, "}}"), 0)
+		rest := templateAction.ReplaceAllString(line, "")
+		if j := strings.IndexByte(rest, '#'); j >= 0 {
+			rest = rest[:j]
+		}
+		inert[i] = inAction || depth > 0 || strings.TrimSpace(rest) == ""
+		b.WriteString(line)
+		if depth == 0 {
+			fmt.Fprintf(&b, "\x1e%d\x1e", i)
+		}
+		b.WriteByte('\n')
+	}
+
+	f.Lines = make([]IgnoreRuleLine, len(source))
+	for i, line := range source {
+		f.Lines[i] = IgnoreRuleLine{Number: i + 1, Text: line, State: IgnoreLineSkipped}
+		if inert[i] {
+			f.Lines[i].State = IgnoreLineInert
+		}
+	}
+
+	rendered, err := s.renderText(b.String())
+	if err != nil {
+		f.RenderError = err.Error()
+		for i := range f.Lines {
+			f.Lines[i].State = IgnoreLineInert
+		}
+		return f
+	}
+	from := 0
+	for _, m := range ignoreLineMarker.FindAllStringSubmatchIndex(rendered, -1) {
+		text := rendered[from:m[0]]
+		from = m[1]
+		i, _ := strconv.Atoi(rendered[m[2]:m[3]])
+		if i < len(f.Lines) {
+			f.Lines[i].Patterns = append(f.Lines[i].Patterns, ignorePatterns(text)...)
+		}
+	}
+
+	for i := range f.Lines {
+		line := &f.Lines[i]
+		if len(line.Patterns) == 0 {
+			continue
+		}
+		line.State = IgnoreLineNoMatch
+		for _, pattern := range line.Patterns {
+			negated, p := splitIgnorePattern(f.Dir, pattern)
+			matched, ok := matchIgnoreParents(p, rel)
+			if !ok {
+				continue
+			}
+			line.Matched = matched
+			line.State = IgnoreLineMatch
+			if negated {
+				line.State = IgnoreLineNegated
+			}
+			break
+		}
+	}
+	return f
+}
+
+// ignorePatterns returns the patterns in rendered .chezmoiignore text the
+// way chezmoi reads them: "#" starts a comment and blank lines are skipped.
+func ignorePatterns(text string) []string {
+	var patterns []string
+	for line := range strings.SplitSeq(text, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns
+}
+
+// splitIgnorePattern strips a leading "!" and joins the pattern to the
+// directory of the .chezmoiignore it came from.
+func splitIgnorePattern(dir, pattern string) (negated bool, joined string) {
+	pattern, negated = strings.CutPrefix(pattern, "!")
+	if dir != "" {
+		pattern = dir + "/" + pattern
+	}
+	return negated, pattern
+}
+
+// matchIgnoreParents returns the shortest of rel and its parents that
+// pattern matches.
+func matchIgnoreParents(pattern, rel string) (string, bool) {
+	names := strings.Split(rel, "/")
+	for n := 1; n <= len(names); n++ {
+		if matchIgnoreExact(pattern, names[:n]) {
+			return strings.Join(names[:n], "/"), true
+		}
+	}
+	return "", false
+}
+
+// ignoredAt applies chezmoi's rule to rel and each parent, shortest first:
+// an entry is ignored if an include pattern matches it and no "!" pattern
+// does. An ignored parent ignores everything under it.
+func ignoredAt(files []IgnoreRuleFile, rel string) string {
+	var include, exclude []string
+	for _, f := range files {
+		for _, line := range f.Lines {
+			for _, pattern := range line.Patterns {
+				if negated, p := splitIgnorePattern(f.Dir, pattern); negated {
+					exclude = append(exclude, p)
+				} else {
+					include = append(include, p)
+				}
+			}
+		}
+	}
+	names := strings.Split(rel, "/")
+	for n := 1; n <= len(names); n++ {
+		matches := func(p string) bool { return matchIgnoreExact(p, names[:n]) }
+		if slices.ContainsFunc(include, matches) && !slices.ContainsFunc(exclude, matches) {
+			return strings.Join(names[:n], "/")
+		}
+	}
+	return ""
+}
+
+func countFunc(paths []string, f func(string) bool) int {
+	n := 0
+	for _, p := range paths {
+		if f(p) {
+			n++
+		}
+	}
+	return n
+}
+
+// hasPathPrefix reports whether child is strictly under root.
+func hasPathPrefix(child, root string) bool {
+	return strings.HasPrefix(child, root) && len(child) > len(root) && child[len(root)] == filepath.Separator
+}
+
+// matchIgnoreExact reports whether pattern matches exactly the path made
+// of names.
+func matchIgnoreExact(pattern string, names []string) bool {
+	for _, alt := range expandBraces(pattern) {
+		if matchSegments(strings.Split(alt, "/"), names) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandBraces expands {a,b} alternatives in pattern, which path.Match
+// does not support.
+func expandBraces(pattern string) []string {
+	start, depth := -1, 0
+	var commas []int
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			if depth == 0 {
+				start, commas = i, nil
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth > 0 {
+				continue
+			}
+			var out []string
+			from := start + 1
+			for _, end := range append(commas, i) {
+				out = append(out, expandBraces(pattern[:start]+pattern[from:end]+pattern[i+1:])...)
+				from = end + 1
+			}
+			return out
+		}
+	}
+	return []string{pattern}
+}
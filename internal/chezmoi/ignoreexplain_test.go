@@ -0,0 +1,96 @@
+package chezmoi
+
+import (
+	"path/filepath"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+// fakeExplainBody renders templates by dropping "ne" blocks, as if the
+// condition were false, and every other action.
+const fakeExplainBody = `
+case "$1" in
+source-path) echo "$SRC" ;;
+execute-template) sed -e '/{{ if ne/,/{{ end }}/d' -e 's/{{[^}]*}}//g' "$3" ;;
+managed) case "$*" in *--include=*) ;; *) echo /home/test/.bashrc ;; esac ;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`
+
+func newExplainService(t *testing.T) *Service {
+	t.Helper()
+	src := t.TempDir()
+	t.Setenv("SRC", src)
+	writeTestFile(t, filepath.Join(src, ".chezmoiignore"), `# caches
+.cache
+{{ if ne .chezmoi.os "linux" }}
+.config/linux-only
+{{ end }}
+!.cache/keep
+*.log # logs
+`, 0o644)
+	writeTestFile(t, filepath.Join(src, "dot_config", ".chezmoiignore"), "nvim/lazy-lock.json\n", 0o644)
+	writeTestFile(t, filepath.Join(src, "dot_local", ".chezmoiignore"), "*\n", 0o644)
+	return newFakeService(t, chezitconfig.ModeReadOnly, "/home/test", fakeExplainBody)
+}
+
+func TestServiceExplainIgnoreMarksLines(t *testing.T) {
+	svc := newExplainService(t)
+
+	e, err := svc.ExplainIgnore("/home/test/.cache/keep/x", EntryFilter{})
+	if err != nil {
+		t.Fatalf("ExplainIgnore: %v", err)
+	}
+	if e.Rel != ".cache/keep/x" || e.IgnoredAt != ".cache" {
+		t.Fatalf("expected .cache ignored, got %+v", e)
+	}
+	if len(e.Files) != 1 {
+		t.Fatalf("expected only the root .chezmoiignore, got %+v", e.Files)
+	}
+	want := []struct {
+		state   IgnoreLineState
+		matched string
+	}{
+		{IgnoreLineInert, ""},
+		{IgnoreLineMatch, ".cache"},
+		{IgnoreLineInert, ""},
+		{IgnoreLineSkipped, ""},
+		{IgnoreLineInert, ""},
+		{IgnoreLineNegated, ".cache/keep"},
+		{IgnoreLineNoMatch, ""},
+	}
+	lines := e.Files[0].Lines
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), lines)
+	}
+	for i, w := range want {
+		if lines[i].State != w.state || lines[i].Matched != w.matched {
+			t.Errorf("line %d %q: got state %d matched %q, want %d %q", i+1, lines[i].Text, lines[i].State, lines[i].Matched, w.state, w.matched)
+		}
+	}
+	if got := lines[6].Patterns; len(got) != 1 || got[0] != "*.log" {
+		t.Errorf("expected the comment stripped from the pattern, got %q", got)
+	}
+}
+
+func TestServiceExplainIgnoreNegatedAndNested(t *testing.T) {
+	svc := newExplainService(t)
+
+	e, err := svc.ExplainIgnore("/home/test/.config/nvim/lazy-lock.json", EntryFilter{})
+	if err != nil {
+		t.Fatalf("ExplainIgnore: %v", err)
+	}
+	if len(e.Files) != 2 || e.Files[1].Dir != ".config" || e.IgnoredAt != ".config/nvim/lazy-lock.json" {
+		t.Fatalf("expected the nested .chezmoiignore to ignore the path, got %+v", e)
+	}
+
+	// A managed path the filter leaves out is hidden, not ignored.
+	e, err = svc.ExplainIgnore("/home/test/.bashrc", EntryFilter{Include: []EntryType{EntryTemplates}})
+	if err != nil {
+		t.Fatalf("ExplainIgnore: %v", err)
+	}
+	if e.Ignored() || e.Managed != 1 || e.Hidden != 1 {
+		t.Fatalf("expected .bashrc hidden by the filter rather than ignored, got %+v", e)
+	}
+}
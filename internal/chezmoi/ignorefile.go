@@ -0,0 +1,194 @@
+package chezmoi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const ignoreFileName = ".chezmoiignore"
+
+// IgnoreCondition limits an ignore pattern to machines where a template
+// data value matches, e.g. chezmoi.os "darwin". The zero value ignores
+// everywhere.
+type IgnoreCondition struct {
+	Variable string // e.g. "chezmoi.os" or "chezmoi.hostname"
+	Value    string
+	Negate   bool // ignore where the value differs instead
+}
+
+// IsZero reports whether the condition applies everywhere.
+func (c IgnoreCondition) IsZero() bool { return c.Variable == "" }
+
+// template returns the opening action of the conditional.
+func (c IgnoreCondition) template() string {
+	op := "eq"
+	if c.Negate {
+		op = "ne"
+	}
+	return fmt.Sprintf("{{ if %s .%s %q }}", op, c.Variable, c.Value)
+}
+
+// IgnoreMachine is the template data .chezmoiignore conditions are
+// offered from.
+type IgnoreMachine struct {
+	OS       string // chezmoi.os
+	Hostname string // chezmoi.hostname
+}
+
+// EscapeIgnorePattern returns a .chezmoiignore pattern matching the
+// slash-separated target path rel and nothing else. Glob and template
+// characters are escaped. "#" starts a comment wherever it appears and
+// surrounding spaces are trimmed, so those cannot be escaped and match
+// any single character instead.
+func EscapeIgnorePattern(rel string) string {
+	var b strings.Builder
+	for i, r := range rel {
+		switch {
+		case strings.ContainsRune(`*?[]{}\`, r):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '!' && i == 0:
+			b.WriteString(`\!`)
+		case r == '#', r == ' ' && (i == 0 || i == len(rel)-1):
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IgnorePathPattern returns the pattern that ignores exactly target.
+func (s *Service) IgnorePathPattern(target string) (string, error) {
+	if err := s.policy.ValidateTargetPath(target); err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(s.TargetPath(), target)
+	if err != nil || rel == "." {
+		return "", ErrOutsideTarget
+	}
+	return EscapeIgnorePattern(filepath.ToSlash(rel)), nil
+}
+
+// SuggestIgnorePattern returns a starting pattern for ignoring entries
+// like the slash-separated target path rel: files with the same extension
+// in the same directory, or anything with the same name.
+func SuggestIgnorePattern(rel string, isDir bool) string {
+	dir, name := path.Split(rel)
+	if ext := path.Ext(name); !isDir && ext != "" && ext != name {
+		return EscapeIgnorePattern(dir) + "*" + EscapeIgnorePattern(ext)
+	}
+	return "**/" + EscapeIgnorePattern(name)
+}
+
+// MatchIgnorePattern reports whether pattern matches the slash-separated
+// target path rel or one of its parent directories, with "**" matching
+// any number of directories.
+func MatchIgnorePattern(pattern, rel string) bool {
+	_, ok := matchIgnoreParents(pattern, rel)
+	return ok
+}
+
+func matchSegments(pattern, names []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(pattern[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], names[0]); err != nil || !ok {
+			return false
+		}
+		pattern, names = pattern[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// ValidateIgnorePattern rejects patterns .chezmoiignore would read
+// differently from how they are written.
+func ValidateIgnorePattern(pattern string) error {
+	switch {
+	case strings.TrimSpace(pattern) == "":
+		return errors.New("pattern is empty")
+	case strings.ContainsAny(pattern, "\r\n"):
+		return errors.New("pattern must be a single line")
+	case strings.Contains(pattern, "#"):
+		return errors.New(`"#" starts a comment in .chezmoiignore; use "?" to match it`)
+	case strings.Contains(pattern, "{{"):
+		return errors.New(`.chezmoiignore is a template; escape "{" as "\{"`)
+	case strings.HasPrefix(pattern, "/"):
+		return errors.New("patterns are relative to the destination directory")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	return nil
+}
+
+// IgnoreMachine returns the current machine's values for the conditions
+// an ignore pattern can be wrapped in.
+func (s *Service) IgnoreMachine() (IgnoreMachine, error) {
+	out, err := s.client.DataJSON()
+	if err != nil {
+		return IgnoreMachine{}, err
+	}
+	var data struct {
+		Chezmoi IgnoreMachine `json:"chezmoi"`
+	}
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		return IgnoreMachine{}, fmt.Errorf("chezmoi data: %w", err)
+	}
+	return data.Chezmoi, nil
+}
+
+// AppendIgnorePattern appends pattern to .chezmoiignore in the source
+// directory, creating it if needed, wrapped in cond unless it is zero.
+func (s *Service) AppendIgnorePattern(pattern string, cond IgnoreCondition) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	if err := ValidateIgnorePattern(pattern); err != nil {
+		return err
+	}
+	sourceDir, err := s.client.SourceDir()
+	if err != nil {
+		return err
+	}
+	ignorePath := filepath.Join(sourceDir, ignoreFileName)
+	existing, err := os.ReadFile(ignorePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("read %s: %w", ignoreFileName, err)
+	}
+
+	var b strings.Builder
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	if cond.IsZero() {
+		b.WriteString(pattern + "\n")
+	} else {
+		b.WriteString(cond.template() + "\n" + pattern + "\n{{ end }}\n")
+	}
+
+	f, err := os.OpenFile(ignorePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", ignoreFileName, err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", ignoreFileName, err)
+	}
+	return f.Close()
+}
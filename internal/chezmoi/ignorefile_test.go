@@ -0,0 +1,148 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestEscapeIgnorePattern(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{".config/nvim", ".config/nvim"},
+		{"notes [draft].md", `notes \[draft\].md`},
+		{"*star?", `\*star\?`},
+		{"!bang", `\!bang`},
+		{"a!b", "a!b"},
+		{"{{ x }}", `\{\{ x \}\}`},
+		{`back\slash`, `back\\slash`},
+		{"tag#1", "tag?1"},
+		{" padded ", "?padded?"},
+	}
+	for _, tt := range tests {
+		got := EscapeIgnorePattern(tt.rel)
+		if got != tt.want {
+			t.Errorf("EscapeIgnorePattern(%q) = %q, want %q", tt.rel, got, tt.want)
+		}
+		if err := ValidateIgnorePattern(got); err != nil {
+			t.Errorf("EscapeIgnorePattern(%q) = %q is invalid: %v", tt.rel, got, err)
+		}
+		if !MatchIgnorePattern(got, tt.rel) {
+			t.Errorf("EscapeIgnorePattern(%q) = %q does not match it", tt.rel, got)
+		}
+	}
+}
+
+func TestSuggestIgnorePattern(t *testing.T) {
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  string
+	}{
+		{".cache/app/log.txt", false, ".cache/app/*.txt"},
+		{"build.log", false, "*.log"},
+		{".bashrc", false, "**/.bashrc"},
+		{".config/node_modules", true, "**/node_modules"},
+		{".config/app.d", true, "**/app.d"},
+	}
+	for _, tt := range tests {
+		if got := SuggestIgnorePattern(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("SuggestIgnorePattern(%q, %v) = %q, want %q", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestMatchIgnorePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"**/node_modules", "src/app/node_modules", true},
+		{"**/node_modules", "node_modules/pkg/index.js", true},
+		{".cache/*.txt", ".cache/log.txt", true},
+		{".cache/*.txt", ".cache/sub/log.txt", false},
+		{".config", ".config/nvim/init.lua", true},
+		{".config", ".configs", false},
+	}
+	for _, tt := range tests {
+		if got := MatchIgnorePattern(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("MatchIgnorePattern(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestValidateIgnorePattern(t *testing.T) {
+	for _, pattern := range []string{"", "  ", "a\nb", "tag#1", "{{ .x }}", "/abs", "[unclosed"} {
+		if err := ValidateIgnorePattern(pattern); err == nil {
+			t.Errorf("expected %q to be rejected", pattern)
+		}
+	}
+}
+
+const fakeIgnoreBody = `
+case "$1" in
+source-path) echo "$SRC" ;;
+data) echo '{"chezmoi":{"os":"linux","hostname":"box"}}' ;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`
+
+func TestServiceAppendIgnorePattern(t *testing.T) {
+	src := t.TempDir()
+	t.Setenv("SRC", src)
+	ignorePath := filepath.Join(src, ".chezmoiignore")
+	writeTestFile(t, ignorePath, "README.md", 0o644)
+	svc := newFakeService(t, chezitconfig.ModeWrite, "/home/test", fakeIgnoreBody)
+
+	machine, err := svc.IgnoreMachine()
+	if err != nil {
+		t.Fatalf("IgnoreMachine: %v", err)
+	}
+	if machine != (IgnoreMachine{OS: "linux", Hostname: "box"}) {
+		t.Fatalf("unexpected machine %+v", machine)
+	}
+
+	pattern, err := svc.IgnorePathPattern("/home/test/.cache/[tmp]")
+	if err != nil {
+		t.Fatalf("IgnorePathPattern: %v", err)
+	}
+	if err := svc.AppendIgnorePattern(pattern, IgnoreCondition{}); err != nil {
+		t.Fatalf("AppendIgnorePattern: %v", err)
+	}
+	if err := svc.AppendIgnorePattern("**/*.log", IgnoreCondition{Variable: "chezmoi.os", Value: machine.OS, Negate: true}); err != nil {
+		t.Fatalf("AppendIgnorePattern: %v", err)
+	}
+	got, err := os.ReadFile(ignorePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "README.md\n.cache/\\[tmp\\]\n{{ if ne .chezmoi.os \"linux\" }}\n**/*.log\n{{ end }}\n"
+	if string(got) != want {
+		t.Fatalf("unexpected .chezmoiignore:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := svc.IgnorePathPattern("/elsewhere/.bashrc"); err == nil {
+		t.Fatal("expected a path outside the destination to be refused")
+	}
+	if err := svc.AppendIgnorePattern("a#b", IgnoreCondition{}); err == nil {
+		t.Fatal("expected an invalid pattern to be refused")
+	}
+	readOnly := newFakeService(t, chezitconfig.ModeReadOnly, "/home/test", fakeIgnoreBody)
+	if err := readOnly.AppendIgnorePattern("*.log", IgnoreCondition{}); err == nil {
+		t.Fatal("expected append refused in read-only mode")
+	}
+}
+
+func TestMatchIgnorePatternExpandsBraces(t *testing.T) {
+	if !MatchIgnorePattern(".{bash,zsh}rc", ".zshrc") || !MatchIgnorePattern("{a,b/{c,d}}/*.txt", "b/d/x.txt") {
+		t.Fatal("expected brace alternatives to match")
+	}
+	if MatchIgnorePattern(`\{a,b\}`, "a") {
+		t.Fatal("expected escaped braces to be literal")
+	}
+}
@@ -1,6 +1,9 @@
 package chezmoi
 
-import "strings"
+import (
+	"path/filepath"
+	"strings"
+)
 
 // ParseStatus parses `chezmoi status` output.
 func ParseStatus(output string) []FileStatus {
@@ -70,3 +73,189 @@ func ParseGitLogOneline(output string) []GitCommit {
 	}
 	return commits
 }
+
+// ParseReflogUpdateOrigin finds the commit HEAD pointed at before the most
+// recent pull in `git reflog --format=%H%x09%gs` output. A pull can leave
+// several entries (a rebase pull logs start, pick, and finish), so all
+// consecutive pull entries are skipped. It returns false when the reflog
+// has no pull, or none with an entry before it.
+func ParseReflogUpdateOrigin(output string) (string, bool) {
+	inPull := false
+	for line := range strings.SplitSeq(output, "\n") {
+		hash, subject, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || hash == "" {
+			continue
+		}
+		isPull := strings.HasPrefix(subject, "pull")
+		switch {
+		case isPull:
+			inPull = true
+		case inPull:
+			return hash, true
+		}
+	}
+	return "", false
+}
+
+// ParseGitNameStatusZ parses `git diff --name-status -z --no-renames` output.
+func ParseGitNameStatusZ(output string) []GitFile {
+	var files []GitFile
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		code := strings.TrimSpace(fields[i])
+		path := fields[i+1]
+		if code == "" || path == "" {
+			continue
+		}
+		files = append(files, GitFile{Path: path, StatusCode: code[:1]})
+	}
+	return files
+}
+
+// ParseApplyPlan parses the git-format diff printed by
+// `chezmoi apply --dry-run --verbose` into one entry per target. Paths in
+// the diff are relative to targetPath.
+func ParseApplyPlan(output, targetPath string) []ApplyPlanEntry {
+	var entries []ApplyPlanEntry
+	var current *ApplyPlanEntry
+	var body strings.Builder
+	hasHunk := false
+	modeChange := false
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if modeChange && !hasHunk && current.Action == PlanModify {
+			current.Action = PlanChmod
+		}
+		current.Diff = strings.TrimRight(body.String(), "\n")
+		entries = append(entries, *current)
+		current = nil
+	}
+
+	for line := range strings.SplitSeq(output, "\n") {
+		if rest, ok := strings.CutPrefix(line, "diff --git "); ok {
+			flush()
+			body.Reset()
+			hasHunk = false
+			modeChange = false
+			current = &ApplyPlanEntry{Path: filepath.Join(targetPath, diffGitTargetPath(rest)), Action: PlanModify}
+		}
+		if current == nil {
+			continue
+		}
+		body.WriteString(line)
+		body.WriteString("\n")
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			current.Action = PlanCreate
+		case strings.HasPrefix(line, "deleted file mode"):
+			current.Action = PlanDelete
+		case strings.HasPrefix(line, "old mode"):
+			modeChange = true
+		case strings.HasPrefix(line, "@@"):
+			hasHunk = true
+		}
+	}
+	flush()
+	return entries
+}
+
+// applyChangedPrompt ends chezmoi's prompt about a target that was modified
+// since chezmoi last wrote it.
+const applyChangedPrompt = " has changed since chezmoi last wrote it?"
+
+// ParseApplyResult parses the output of `chezmoi apply --verbose` into a
+// per-target result. Targets in the verbose diff were written, or ran if
+// listed in scripts; targets chezmoi prompted about but never wrote were
+// skipped, even if their diff was shown at the prompt; `chezmoi: <target>: <reason>` lines are failures. Paths in the
+// output are relative to targetPath.
+func ParseApplyResult(output, targetPath string, scripts []string) ApplyResult {
+	var result ApplyResult
+	index := make(map[string]int)
+	set := func(entry ApplyResultEntry) {
+		if i, ok := index[entry.Path]; ok {
+			result.Entries[i] = entry
+			return
+		}
+		index[entry.Path] = len(result.Entries)
+		result.Entries = append(result.Entries, entry)
+	}
+	resolve := func(path string) string {
+		if filepath.IsAbs(path) {
+			return filepath.Clean(path)
+		}
+		return filepath.Join(targetPath, path)
+	}
+
+	isScript := make(map[string]bool, len(scripts))
+	for _, path := range scripts {
+		isScript[path] = true
+	}
+	// Answering "diff" at a prompt prints the target's diff, so the last
+	// answer decides whether a diff means the target was written.
+	declined := make(map[string]bool)
+	for line := range strings.SplitSeq(output, "\n") {
+		before, after, ok := strings.Cut(strings.TrimSpace(line), applyChangedPrompt)
+		if !ok {
+			continue
+		}
+		if i := strings.LastIndex(after, "]"); i >= 0 {
+			after = after[i+1:]
+		}
+		declined[resolve(strings.TrimSpace(before))] = applyPromptDeclined(after)
+	}
+	for _, planned := range ParseApplyPlan(output, targetPath) {
+		if declined[planned.Path] {
+			continue
+		}
+		entry := ApplyResultEntry{Path: planned.Path, Outcome: ApplyWritten, Action: planned.Action}
+		if isScript[planned.Path] {
+			entry.Outcome = ApplyScriptRan
+			entry.Action = PlanScript
+		}
+		set(entry)
+	}
+
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimSpace(line)
+		if before, _, ok := strings.Cut(line, applyChangedPrompt); ok {
+			path := resolve(strings.TrimSpace(before))
+			if _, written := index[path]; !written {
+				set(ApplyResultEntry{Path: path, Outcome: ApplySkipped, Reason: "changed since chezmoi last wrote it"})
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "chezmoi: ")
+		if !ok {
+			continue
+		}
+		target, reason, ok := strings.Cut(rest, ": ")
+		if !ok || target == "" || strings.ContainsAny(target, " \t") {
+			result.Errors = append(result.Errors, rest)
+			continue
+		}
+		set(ApplyResultEntry{Path: resolve(target), Outcome: ApplyFailed, Reason: reason})
+	}
+	return result
+}
+
+// applyPromptDeclined reports whether the answer echoed after an
+// overwrite prompt leaves the target unwritten. chezmoi also accepts each
+// choice by its first letter.
+func applyPromptDeclined(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "s", "skip", "q", "quit":
+		return true
+	}
+	return false
+}
+
+// diffGitTargetPath extracts the "b/" path from a `diff --git a/x b/x` header.
+func diffGitTargetPath(header string) string {
+	if idx := strings.LastIndex(header, " b/"); idx >= 0 {
+		return header[idx+3:]
+	}
+	return strings.TrimPrefix(header, "a/")
+}
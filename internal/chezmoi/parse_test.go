@@ -87,6 +87,56 @@ func TestParseGitLogOneline(t *testing.T) {
 	}
 }
 
+func TestParseReflogUpdateOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "fast-forward pull",
+			input:  "bbb2222\tpull: Fast-forward\naaa1111\tcommit: add zshrc\n",
+			want:   "aaa1111",
+			wantOK: true,
+		},
+		{
+			name: "rebase pull logs several entries",
+			input: "ccc3333\tpull --autostash --rebase (finish): returning to refs/heads/main\n" +
+				"ccc3333\tpull --autostash --rebase (pick): local tweak\n" +
+				"bbb2222\tpull --autostash --rebase (start): checkout bbb2222\n" +
+				"aaa1111\tcommit: local tweak\n",
+			want:   "aaa1111",
+			wantOK: true,
+		},
+		{
+			name:   "commits after the pull are skipped",
+			input:  "ddd4444\tcommit: later\nbbb2222\tpull: Fast-forward\naaa1111\tclone: from origin\n",
+			want:   "aaa1111",
+			wantOK: true,
+		},
+		{
+			name:   "no pull",
+			input:  "bbb2222\tcommit: b\naaa1111\tcommit (initial): a\n",
+			wantOK: false,
+		},
+		{
+			name:   "pull is the oldest entry",
+			input:  "bbb2222\tpull: Fast-forward\n",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseReflogUpdateOrigin(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("ParseReflogUpdateOrigin() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestIsValidGitHash(t *testing.T) {
 	tests := []struct {
 		name  string
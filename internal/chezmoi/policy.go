@@ -66,13 +66,23 @@ func (p Policy) AvailableCommands(hasEditSource, hasEditConfig bool) []CommandAv
 				Command: "chezmoi apply", Category: "apply",
 				Available: true, SupportsDryRun: true,
 			},
+			CommandAvailability{
+				Label: "Apply Plan", Description: "Review planned changes and apply selected targets",
+				Command: "chezmoi apply --dry-run --verbose", Category: "apply",
+				Available: true,
+			},
 			CommandAvailability{
 				Label: "Update", Description: "Pull from remote and apply",
 				Command: "chezmoi update", Category: "apply",
 				Available: true,
 			},
 			CommandAvailability{
-				Label: "Refresh Externals", Description: "Re-download external files and apply",
+				Label: "Undo Update", Description: "Reset source to before the last update or pull, then review apply",
+				Command: "chezmoi git -- reset --hard <commit>", Category: "apply",
+				Available: true,
+			},
+			CommandAvailability{
+				Label: "Refresh Externals", Description: "Re-download and apply external files in the background",
 				Command: "chezmoi apply --refresh-externals", Category: "apply",
 				Available: true, SupportsDryRun: true,
 			},
@@ -81,11 +91,34 @@ func (p Policy) AvailableCommands(hasEditSource, hasEditConfig bool) []CommandAv
 				Command: "chezmoi re-add", Category: "apply",
 				Available: true,
 			},
+			CommandAvailability{
+				Label: "Re-Encrypt All", Description: "Re-encrypt every encrypted source for the recipients in the config",
+				Command: "chezmoi decrypt <source> | chezmoi encrypt", Category: "apply",
+				Available: true,
+			},
+			CommandAvailability{
+				Label: "Backups", Description: "Browse and restore files saved before force applies",
+				Command: "~/.local/share/chezit/backups", Category: "apply",
+				Available: true,
+			},
 			CommandAvailability{
 				Label: "Init", Description: "Interactive chezmoi init",
 				Command: "chezmoi init", Category: "apply",
 				Available: true,
 			},
+			// The previews apply into a temporary directory, but chezmoi
+			// still runs hooks, and time travel registers a git worktree in
+			// the source repo.
+			CommandAvailability{
+				Label: "Time Travel", Description: "Preview the destination at an older source commit",
+				Command: "chezmoi apply --source <worktree> --destination <tmp>", Category: "apply",
+				Available: true,
+			},
+			CommandAvailability{
+				Label: "Sandbox Apply", Description: "Apply the source into a temporary directory and browse the result",
+				Command: "chezmoi apply --destination <tmp>", Category: "apply",
+				Available: true,
+			},
 		)
 	}
 
@@ -125,6 +158,31 @@ func (p Policy) AvailableCommands(hasEditSource, hasEditConfig bool) []CommandAv
 			Command: "git log --oneline -20", Category: "info",
 			Available: true,
 		},
+		CommandAvailability{
+			Label: "What-If Data", Description: "Render templates with overridden data and diff against now",
+			Command: "chezmoi execute-template --override-data-file <overlay>", Category: "info",
+			Available: true,
+		},
+		CommandAvailability{
+			Label: "Lint Templates", Description: "Render every template and list the ones that fail",
+			Command: "chezmoi execute-template --file <template>", Category: "info",
+			Available: true,
+		},
+		CommandAvailability{
+			Label: "Data Dependencies", Description: "Show which templates use each data key or secret manager",
+			Command: "chezmoi managed --include=templates", Category: "info",
+			Available: true,
+		},
+		CommandAvailability{
+			Label: "Scripts", Description: "List run_ scripts with their order and last run; reset run state",
+			Command: "chezmoi state dump", Category: "info",
+			Available: true,
+		},
+		CommandAvailability{
+			Label: "Externals", Description: "List .chezmoiexternal entries with their URL and last fetch; refresh one",
+			Command: "chezmoi apply --refresh-externals <target>", Category: "info",
+			Available: true,
+		},
 		CommandAvailability{
 			Label: "Archive", Description: "Create backup archive of target state",
 			Command: "chezmoi archive --output=<path>", Category: "info",
@@ -75,7 +75,7 @@ func TestAvailableCommandsReadOnly(t *testing.T) {
 	}
 
 	// Mutations must be hidden in read-only mode.
-	forbidden := []string{"Apply", "Update", "Refresh Externals", "Re-Add All", "Init", "Edit Source"}
+	forbidden := []string{"Apply", "Apply Plan", "Update", "Refresh Externals", "Re-Add All", "Re-Encrypt All", "Backups", "Undo Update", "Init", "Time Travel", "Sandbox Apply", "Edit Source"}
 	for _, label := range forbidden {
 		if labels[label] {
 			t.Fatalf("read-only mode should not include %q", label)
@@ -83,7 +83,7 @@ func TestAvailableCommandsReadOnly(t *testing.T) {
 	}
 
 	// Read-only info commands must still be visible.
-	required := []string{"Status", "Diff All", "Doctor", "Verify", "Data", "Cat Config", "Git Log", "Archive"}
+	required := []string{"Status", "Diff All", "Doctor", "Verify", "Data", "Cat Config", "Git Log", "What-If Data", "Lint Templates", "Data Dependencies", "Scripts", "Externals", "Archive"}
 	for _, label := range required {
 		if !labels[label] {
 			t.Errorf("read-only mode should include %q", label)
@@ -100,7 +100,7 @@ func TestAvailableCommandsWriteMode(t *testing.T) {
 		labels[cmd.Label] = true
 	}
 
-	expected := []string{"Apply", "Update", "Refresh Externals", "Git Log", "Edit Source", "Edit Config", "Edit Config Template", "Archive"}
+	expected := []string{"Apply", "Update", "Undo Update", "Refresh Externals", "Backups", "Git Log", "Edit Source", "Edit Config", "Edit Config Template", "Archive"}
 	for _, label := range expected {
 		if !labels[label] {
 			t.Errorf("expected command %q to be present", label)
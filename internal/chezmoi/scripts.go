@@ -0,0 +1,197 @@
+package chezmoi
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Persistent state buckets chezmoi records script runs in.
+const (
+	scriptStateBucket = "scriptState" // run_once scripts, keyed by the SHA-256 of their contents
+	entryStateBucket  = "entryState"  // entries, including run_onchange scripts, keyed by target path
+)
+
+// Script is a run_ script in the source state, with what chezmoi's
+// persistent state records about its last run.
+type Script struct {
+	Target   string     // target path chezmoi knows the script by
+	Name     string     // Target relative to the destination directory
+	Source   string     // file in the source directory
+	Attrs    SourceName // run_ attributes decoded from the source name
+	Order    int        // 1-based position in which apply runs the script
+	Rendered string     // the script as apply runs it, after templating
+	Err      error      // rendering failed
+	LastRun  time.Time  // when a run was last recorded; zero when never
+	Current  bool       // run_once or run_onchange already ran with these contents, so apply skips it
+
+	contentsKey string // hex SHA-256 of Rendered
+}
+
+// Kind returns "once", "onchange", or "always".
+func (sc Script) Kind() string {
+	switch {
+	case sc.Attrs.Once:
+		return "once"
+	case sc.Attrs.OnChange:
+		return "onchange"
+	default:
+		return "always"
+	}
+}
+
+// Phase returns "before" or "after" for scripts run before or after the
+// files are updated, or "" for scripts run in order with them.
+func (sc Script) Phase() string {
+	switch {
+	case sc.Attrs.Before:
+		return "before"
+	case sc.Attrs.After:
+		return "after"
+	default:
+		return ""
+	}
+}
+
+// phaseRank orders scripts the way apply runs them.
+func (sc Script) phaseRank() int {
+	switch {
+	case sc.Attrs.Before:
+		return 0
+	case sc.Attrs.After:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// scriptStateRecord is a scriptState bucket value.
+type scriptStateRecord struct {
+	Name  string    `json:"name"`
+	RunAt time.Time `json:"runAt"`
+}
+
+// entryStateRecord is the part of an entryState bucket value that says
+// which contents a run_onchange script last ran with.
+type entryStateRecord struct {
+	ContentsSHA256 string `json:"contentsSHA256"`
+}
+
+// Scripts lists the run_ scripts, both in .chezmoiscripts and alongside
+// other entries, in the order apply runs them: before_ scripts, then
+// scripts run with the files, then after_ scripts, each by name. Every
+// script is rendered so its run state can be matched against the
+// contents chezmoi recorded.
+func (s *Service) Scripts() ([]Script, error) {
+	targets, err := s.client.ManagedWithFilter(EntryFilter{Include: []EntryType{EntryScripts}})
+	if err != nil || len(targets) == 0 {
+		return nil, err
+	}
+	sources, err := s.client.SourcePaths(targets)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.client.StateDump()
+	if err != nil {
+		return nil, err
+	}
+
+	type render struct {
+		out string
+		err error
+	}
+	renders := renderTemplates(targets, sources, func(target, _ string) render {
+		out, err := s.client.CatTarget(target)
+		return render{out: out, err: err}
+	})
+	scripts := make([]Script, len(targets))
+	for i, target := range targets {
+		sc := Script{
+			Target:   target,
+			Name:     s.scriptName(target),
+			Source:   sources[i],
+			Attrs:    ParseSourceFileName(filepath.Base(sources[i])),
+			Rendered: renders[i].out,
+			Err:      renders[i].err,
+		}
+		sum := sha256.Sum256([]byte(sc.Rendered))
+		sc.contentsKey = hex.EncodeToString(sum[:])
+		sc.LastRun, sc.Current = scriptRunState(sc, state)
+		scripts[i] = sc
+	}
+	slices.SortStableFunc(scripts, func(a, b Script) int {
+		return cmp.Or(a.phaseRank()-b.phaseRank(), strings.Compare(a.Name, b.Name))
+	})
+	for i := range scripts {
+		scripts[i].Order = i + 1
+	}
+	return scripts, nil
+}
+
+// scriptName returns target relative to the destination directory, the
+// name chezmoi records script runs under.
+func (s *Service) scriptName(target string) string {
+	rel, err := filepath.Rel(s.TargetPath(), target)
+	if err != nil {
+		return filepath.Base(target)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// scriptRunState finds when sc last ran and whether apply would skip it.
+// A run_once script is skipped once any script with the same contents has
+// run; a run_onchange script while its contents match its entry state.
+func scriptRunState(sc Script, state map[string]map[string]json.RawMessage) (lastRun time.Time, current bool) {
+	for key, raw := range state[scriptStateBucket] {
+		var rec scriptStateRecord
+		if json.Unmarshal(raw, &rec) != nil {
+			continue
+		}
+		if rec.Name == sc.Name && rec.RunAt.After(lastRun) {
+			lastRun = rec.RunAt
+		}
+		if key == sc.contentsKey && sc.Attrs.Once && sc.Err == nil {
+			current = true
+		}
+	}
+	if sc.Attrs.OnChange && sc.Err == nil {
+		var rec entryStateRecord
+		if raw, ok := state[entryStateBucket][sc.Target]; ok && json.Unmarshal(raw, &rec) == nil {
+			current = rec.ContentsSHA256 == sc.contentsKey
+		}
+	}
+	return lastRun, current
+}
+
+// ResetScriptState deletes what chezmoi recorded about script's runs, so
+// the next apply runs it again: its scriptState records, the record for
+// its current contents, and for run_onchange scripts its entry state.
+func (s *Service) ResetScriptState(script Script) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	state, err := s.client.StateDump()
+	if err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(state[scriptStateBucket])) {
+		var rec scriptStateRecord
+		_ = json.Unmarshal(state[scriptStateBucket][key], &rec)
+		if rec.Name != script.Name && key != script.contentsKey {
+			continue
+		}
+		if err := s.client.StateDelete(scriptStateBucket, key); err != nil {
+			return err
+		}
+	}
+	if _, ok := state[entryStateBucket][script.Target]; ok && script.Attrs.OnChange {
+		return s.client.StateDelete(entryStateBucket, script.Target)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package chezmoi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func contentsSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// newScriptsService fakes chezmoi with four scripts and a persistent state
+// in which setup.sh and brew.sh ran with their current contents and
+// install.sh ran with older contents. State deletions are logged to the
+// returned file.
+func newScriptsService(t *testing.T, mode chezitconfig.Mode) (*Service, string, string) {
+	t.Helper()
+	target := t.TempDir()
+	log := filepath.Join(t.TempDir(), "state.log")
+	state := `{
+  "entryState": {
+    "` + target + `/brew.sh": {"type": "script", "contentsSHA256": "` + contentsSHA256("brew bundle\n") + `"},
+    "` + target + `/.bashrc": {"type": "file"}
+  },
+  "scriptState": {
+    "` + contentsSHA256("echo setup\n") + `": {"name": "setup.sh", "runAt": "2026-03-01T10:00:00Z"},
+    "` + contentsSHA256("echo old install\n") + `": {"name": "install.sh", "runAt": "2026-02-01T10:00:00Z"}
+  }
+}`
+	t.Setenv("TARGET", target)
+	t.Setenv("STATE", state)
+	t.Setenv("LOG", log)
+	svc := newFakeService(t, mode, target, `
+case "$1" in
+managed) printf '%s\n' "$TARGET/brew.sh" "$TARGET/cleanup.sh" "$TARGET/install.sh" "$TARGET/setup.sh" ;;
+source-path)
+	shift
+	for target in "$@"; do
+		case "$target" in
+		*/brew.sh) echo "/src/.chezmoiscripts/run_onchange_brew.sh.tmpl" ;;
+		*/cleanup.sh) echo "/src/.chezmoiscripts/run_after_cleanup.sh" ;;
+		*/install.sh) echo "/src/.chezmoiscripts/run_once_install.sh" ;;
+		*/setup.sh) echo "/src/.chezmoiscripts/run_once_before_setup.sh" ;;
+		esac
+	done
+	;;
+state)
+	case "$2" in
+	dump) printf '%s\n' "$STATE" ;;
+	delete) echo "$*" >> "$LOG" ;;
+	esac
+	;;
+cat)
+	case "$2" in
+	*/brew.sh) echo "brew bundle" ;;
+	*/cleanup.sh) echo "template error" >&2; exit 1 ;;
+	*/install.sh) echo "echo install" ;;
+	*/setup.sh) echo "echo setup" ;;
+	esac
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+	return svc, target, log
+}
+
+func TestServiceScriptsOrderAndRunState(t *testing.T) {
+	svc, target, _ := newScriptsService(t, chezitconfig.ModeReadOnly)
+
+	scripts, err := svc.Scripts()
+	if err != nil {
+		t.Fatalf("Scripts: %v", err)
+	}
+	var names []string
+	for _, sc := range scripts {
+		names = append(names, sc.Name)
+	}
+	if got := strings.Join(names, " "); got != "setup.sh brew.sh install.sh cleanup.sh" {
+		t.Fatalf("unexpected order %q", got)
+	}
+
+	setup, brew, install, cleanup := scripts[0], scripts[1], scripts[2], scripts[3]
+	if setup.Kind() != "once" || setup.Phase() != "before" || setup.Order != 1 {
+		t.Errorf("unexpected setup.sh %+v", setup)
+	}
+	if !setup.Current || !setup.LastRun.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected setup.sh to have run with its contents, got %+v", setup)
+	}
+	if brew.Kind() != "onchange" || !brew.Attrs.Template || !brew.Current {
+		t.Errorf("expected brew.sh current from its entry state, got %+v", brew)
+	}
+	if install.Current || install.LastRun.IsZero() {
+		t.Errorf("expected install.sh to have run with older contents, got %+v", install)
+	}
+	if cleanup.Kind() != "always" || cleanup.Phase() != "after" || cleanup.Err == nil {
+		t.Errorf("expected cleanup.sh to fail rendering, got %+v", cleanup)
+	}
+	if brew.Target != filepath.Join(target, "brew.sh") || install.Rendered != "echo install\n" {
+		t.Errorf("unexpected target or contents: %+v %+v", brew, install)
+	}
+}
+
+func TestServiceResetScriptState(t *testing.T) {
+	svc, _, log := newScriptsService(t, chezitconfig.ModeWrite)
+	scripts, err := svc.Scripts()
+	if err != nil {
+		t.Fatalf("Scripts: %v", err)
+	}
+
+	if err := svc.ResetScriptState(scripts[0]); err != nil {
+		t.Fatalf("reset setup.sh: %v", err)
+	}
+	if err := svc.ResetScriptState(scripts[1]); err != nil {
+		t.Fatalf("reset brew.sh: %v", err)
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "state delete --bucket=scriptState --key=" + contentsSHA256("echo setup\n") + "\n" +
+		"state delete --bucket=entryState --key=" + scripts[1].Target + "\n"
+	if string(data) != want {
+		t.Fatalf("got deletions:\n%s\nwant:\n%s", data, want)
+	}
+
+	readOnly, _, _ := newScriptsService(t, chezitconfig.ModeReadOnly)
+	if err := readOnly.ResetScriptState(scripts[0]); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+}
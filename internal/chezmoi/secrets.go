@@ -0,0 +1,319 @@
+package chezmoi
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SecretFinding is a line that looks like it holds a secret in plain text.
+type SecretFinding struct {
+	Path string // file scanned; for a staged diff, relative to the source repository
+	Line int    // 1-based line number
+	Rule string // ID of the matching rule, e.g. "private-key"
+	Text string // the line, trimmed, with the secret masked
+}
+
+func (f SecretFinding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.Path, f.Line, f.Rule, f.Text)
+}
+
+// SecretsFoundError is returned by Add and GitCommit when the content they
+// would store looks like it holds secrets in plain text.
+type SecretsFoundError struct {
+	Findings []SecretFinding
+}
+
+func (e *SecretsFoundError) Error() string {
+	noun := "lines look"
+	if len(e.Findings) == 1 {
+		noun = "line looks"
+	}
+	return fmt.Sprintf("%d %s like secrets, first %s:%d (%s): add encrypted or move the values into template data",
+		len(e.Findings), noun, e.Findings[0].Path, e.Findings[0].Line, e.Findings[0].Rule)
+}
+
+// SecretAllowRule exempts findings from the secret scan. A finding is
+// allowed when every field set in the rule matches it.
+type SecretAllowRule struct {
+	Rule    string // rule ID, e.g. "high-entropy"
+	Path    string // glob matched against the path relative to the target or source directory; "**" matches any number of directories
+	Pattern string // regular expression matched against the line
+}
+
+// secretRule flags lines matching pattern. With minEntropy set, the
+// pattern's first group must also look random: at least minEntropy bits
+// of Shannon entropy per character.
+type secretRule struct {
+	id         string
+	pattern    *regexp.Regexp
+	minEntropy float64
+}
+
+var secretRules = []secretRule{
+	{id: "private-key", pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`)},
+	{id: "aws-access-key", pattern: regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{id: "github-token", pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{id: "gitlab-token", pattern: regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})`)},
+	{id: "slack-token", pattern: regexp.MustCompile(`\b(xox[abposr]-[A-Za-z0-9-]{10,})`)},
+	{id: "stripe-key", pattern: regexp.MustCompile(`\b([rs]k_live_[A-Za-z0-9]{20,})`)},
+	{id: "google-api-key", pattern: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`)},
+	{id: "npm-token", pattern: regexp.MustCompile(`\b(npm_[A-Za-z0-9]{36})\b`)},
+	{id: "age-identity", pattern: regexp.MustCompile(`\b(AGE-SECRET-KEY-1[0-9A-Z]{58})\b`)},
+	{id: "api-key", pattern: regexp.MustCompile(`\b(sk-(?:proj-|ant-)?[A-Za-z0-9_-]{32,})`)},
+	{
+		// A random-looking value assigned to something named like a secret.
+		// Template actions never match, so templated values pass.
+		id:         "high-entropy",
+		pattern:    regexp.MustCompile(`(?i)(?:password|passwd|secret|token|api[_-]?key|access[_-]?key)[\w.-]*["']?\s*[:=]\s*["']?([A-Za-z0-9+/=_.~!@#$%^&*-]{16,})`),
+		minEntropy: 3.5,
+	},
+}
+
+// secretScanMaxBytes skips files too large to be dotfiles worth scanning.
+const secretScanMaxBytes = 1 << 20
+
+type secretAllow struct {
+	rule    string
+	path    string
+	pattern *regexp.Regexp
+}
+
+// secretScanner finds secrets with secretRules, minus allowlisted findings.
+type secretScanner struct {
+	allow []secretAllow
+}
+
+// newSecretScanner compiles rules. A pattern that does not compile makes
+// its rule match nothing; the config validates patterns before this.
+func newSecretScanner(rules []SecretAllowRule) secretScanner {
+	var sc secretScanner
+	for _, r := range rules {
+		a := secretAllow{rule: r.Rule, path: r.Path}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				continue
+			}
+			a.pattern = re
+		}
+		sc.allow = append(sc.allow, a)
+	}
+	return sc
+}
+
+// allowed reports whether an allowlist rule exempts rule's match on line
+// in a file known by any of paths.
+func (sc secretScanner) allowed(rule, line string, paths []string) bool {
+	for _, a := range sc.allow {
+		if a.rule != "" && a.rule != rule {
+			continue
+		}
+		if a.pattern != nil && !a.pattern.MatchString(line) {
+			continue
+		}
+		if a.path != "" && !matchesAnyPath(a.path, paths) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// matchesAnyPath reports whether glob matches one of the slash-separated
+// paths. "*" stays within a directory; a "**" segment spans any number.
+func matchesAnyPath(glob string, paths []string) bool {
+	pattern := strings.Split(glob, "/")
+	for _, p := range paths {
+		if matchSegments(pattern, strings.Split(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanLine checks one line against every rule and returns the first
+// finding that is not allowlisted.
+func (sc secretScanner) scanLine(line string, paths []string) (rule, masked string, found bool) {
+	for _, r := range secretRules {
+		m := r.pattern.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		if r.minEntropy > 0 && shannonEntropy(line[m[2]:m[3]]) < r.minEntropy {
+			continue
+		}
+		if sc.allowed(r.id, line, paths) {
+			continue
+		}
+		masked = line
+		if len(m) > 2 && m[2] >= 0 {
+			masked = line[:m[2]] + maskSecret(line[m[2]:m[3]]) + line[m[3]:]
+		}
+		return r.id, strings.TrimSpace(masked), true
+	}
+	return "", "", false
+}
+
+// maskSecret keeps the first four characters of secret, enough to tell
+// which key it is.
+func maskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return secret[:4] + "****"
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len([]rune(s)))
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// scanContent scans the lines of a file shown as name and allowlisted by
+// paths. Binary content is skipped.
+func (sc secretScanner) scanContent(name string, paths []string, content []byte) []SecretFinding {
+	if bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0 {
+		return nil
+	}
+	var findings []SecretFinding
+	for i, line := range strings.Split(string(content), "\n") {
+		if rule, text, ok := sc.scanLine(line, paths); ok {
+			findings = append(findings, SecretFinding{Path: name, Line: i + 1, Rule: rule, Text: text})
+		}
+	}
+	return findings
+}
+
+// scanPath scans the file at p, or the regular files under it when it is
+// a directory (only its direct children with opts.NoRecursive), as chezmoi
+// add would read them: with opts.Follow a symlink at p is scanned as what
+// it points to. Paths are allowlisted relative to targetDir.
+func (sc secretScanner) scanPath(p, targetDir string, opts AddOptions) []SecretFinding {
+	root := p
+	if opts.Follow {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			root = resolved
+		}
+	}
+	var findings []SecretFinding
+	_ = filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		// Report files under the path that was added, not the link target.
+		if rel, err := filepath.Rel(root, file); err == nil {
+			file = filepath.Join(p, rel)
+		}
+		if d.IsDir() {
+			if file != p && (opts.NoRecursive || d.Name() == ".git") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > secretScanMaxBytes {
+			return nil
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil
+		}
+		var paths []string
+		if rel, err := filepath.Rel(targetDir, file); err == nil {
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		findings = append(findings, sc.scanContent(file, paths, content)...)
+		return nil
+	})
+	return findings
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// scanDiff scans the lines a unified diff adds. Files are allowlisted by
+// their path in the diff and the target path it maps to; sourceRel is the
+// source directory relative to the repo root the diff's paths start from,
+// which differs from "." when .chezmoiroot is set.
+//
+// Hunk bodies are consumed by the line counts in their headers, so an
+// added line whose content starts with "++ " is scanned, not taken for
+// the next file's header.
+func (sc secretScanner) scanDiff(diff, sourceRel string) []SecretFinding {
+	var findings []SecretFinding
+	var file string
+	var paths []string
+	line, oldLeft, newLeft := 0, 0, 0
+	for l := range strings.SplitSeq(diff, "\n") {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(l, "+"):
+				newLeft--
+				if file == "" {
+					break
+				}
+				if rule, text, ok := sc.scanLine(l[1:], paths); ok {
+					findings = append(findings, SecretFinding{Path: file, Line: line, Rule: rule, Text: text})
+				}
+				line++
+			case strings.HasPrefix(l, "-"):
+				oldLeft--
+			case strings.HasPrefix(l, `\`):
+				// "\ No newline at end of file" belongs to the line before.
+			default:
+				oldLeft--
+				newLeft--
+				line++
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(l, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(l, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+			paths = []string{file}
+			rel, ok := file, true
+			if sourceRel != "." {
+				rel, ok = strings.CutPrefix(file, sourceRel+"/")
+			}
+			if target, isTarget := TargetRelPath(rel); ok && isTarget {
+				paths = append(paths, target)
+			}
+		case strings.HasPrefix(l, "@@ "):
+			if m := hunkHeaderRe.FindStringSubmatch(l); m != nil {
+				line, _ = strconv.Atoi(m[2])
+				oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[3])
+			}
+		}
+	}
+	return findings
+}
+
+// hunkCount parses a hunk header's line count, which is 1 when omitted.
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
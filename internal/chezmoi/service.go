@@ -1,10 +1,15 @@
 package chezmoi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	chezitconfig "github.com/daptify14/chezit/internal/config"
@@ -13,14 +18,62 @@ import (
 // Service wraps a Client with policy enforcement and use-case orchestration.
 // It is the primary interface consumed by the TUI.
 type Service struct {
-	client *Client
-	policy Policy
+	client  *Client
+	policy  Policy
+	backups *BackupStore
+	secrets secretScanner
+	dataDir string
 }
 
-func NewService(client *Client, mode chezitconfig.Mode, targetPath string) *Service {
+// ServiceOption configures a Service.
+type ServiceOption func(*serviceConfig)
+
+type serviceConfig struct {
+	dataDir         string
+	backupDir       string
+	backupRetention BackupRetention
+	secretAllowlist []SecretAllowRule
+}
+
+// WithDataDir overrides where chezit keeps its own state, such as backups
+// and the commit recorded before an update.
+func WithDataDir(dir string) ServiceOption {
+	return func(c *serviceConfig) { c.dataDir = dir }
+}
+
+// WithBackupDir overrides where pre-apply backups are stored.
+func WithBackupDir(dir string) ServiceOption {
+	return func(c *serviceConfig) { c.backupDir = dir }
+}
+
+// WithBackupRetention sets how many backup runs are kept, and for how long.
+func WithBackupRetention(r BackupRetention) ServiceOption {
+	return func(c *serviceConfig) { c.backupRetention = r }
+}
+
+// WithSecretAllowlist exempts findings from the secret scan run by Add and
+// GitCommit.
+func WithSecretAllowlist(rules []SecretAllowRule) ServiceOption {
+	return func(c *serviceConfig) { c.secretAllowlist = rules }
+}
+
+func NewService(client *Client, mode chezitconfig.Mode, targetPath string, opts ...ServiceOption) *Service {
+	cfg := serviceConfig{
+		dataDir:         chezitDataDir(),
+		backupRetention: DefaultBackupRetention,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.backupDir == "" {
+		cfg.backupDir = filepath.Join(cfg.dataDir, "backups")
+	}
 	return &Service{
-		client: client,
-		policy: NewPolicy(mode, targetPath),
+		client:  client,
+		policy:  NewPolicy(mode, targetPath),
+		backups: NewBackupStore(cfg.backupDir, targetPath, cfg.backupRetention),
+		secrets: newSecretScanner(cfg.secretAllowlist),
+		dataDir: cfg.dataDir,
 	}
 }
 
@@ -66,6 +119,20 @@ func (s *Service) GitStatus() (staged, unstaged []GitFile, err error) {
 	return s.client.GitStatusFiles()
 }
 
+// StateDump returns chezmoi's persistent state, keyed by bucket and then
+// by key.
+func (s *Service) StateDump() (map[string]map[string]json.RawMessage, error) {
+	return s.client.StateDump()
+}
+
+func (s *Service) StreamDoctor(ctx context.Context, out chan<- OutputLine) error {
+	return s.client.StreamDoctor(ctx, out)
+}
+
+func (s *Service) StreamVerify(ctx context.Context, out chan<- OutputLine) error {
+	return s.client.StreamVerify(ctx, out)
+}
+
 func (s *Service) GitDiff(path string, staged bool) (string, error) {
 	return s.client.GitDiff(path, staged)
 }
@@ -73,10 +140,13 @@ func (s *Service) GitLog() (string, error)             { return s.client.GitLog(
 func (s *Service) GitLogUnpushed() (string, error)     { return s.client.GitLogUnpushed() }
 func (s *Service) GitLogIncoming() (string, error)     { return s.client.GitLogIncoming() }
 func (s *Service) GitShow(hash string) (string, error) { return s.client.GitShow(hash) }
+func (s *Service) GitDiffIncoming(path string) (string, error) {
+	return s.client.GitDiffIncoming(path)
+}
 
 // GitFetch is allowed in read-only mode — fetch only updates remote-tracking refs.
-func (s *Service) GitFetch() error {
-	return s.client.GitFetch()
+func (s *Service) GitFetch(ctx context.Context) error {
+	return s.client.GitFetch(ctx)
 }
 
 func (s *Service) GitPull() error {
@@ -86,6 +156,78 @@ func (s *Service) GitPull() error {
 	return s.client.GitPull()
 }
 
+// --- Update undo ---
+
+const updateOriginFile = "update-origin.json"
+
+// RecordUpdateOrigin saves the source HEAD so the update or pull about to
+// run can be undone.
+func (s *Service) RecordUpdateOrigin() error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	head, err := s.client.GitHead()
+	if err != nil {
+		return err
+	}
+	return writeStateFile(s.dataDir, updateOriginFile, UpdateOrigin{Commit: head, Time: time.Now()})
+}
+
+// LastUpdateOrigin returns the commit undoing the last update resets to.
+// The commit recorded before the update is preferred; when there is none,
+// or HEAD has not moved since, the commit before the last pull in the
+// reflog is used. It returns ErrNoUpdate when neither applies.
+func (s *Service) LastUpdateOrigin() (UpdateOrigin, error) {
+	head, err := s.client.GitHead()
+	if err != nil {
+		return UpdateOrigin{}, err
+	}
+	origin, err := readStateFile[UpdateOrigin](s.dataDir, updateOriginFile)
+	if err != nil && !errors.Is(err, errCorruptState) {
+		return UpdateOrigin{}, err
+	}
+	if !isValidGitHash(origin.Commit) || origin.Commit == head {
+		reflog, err := s.client.GitReflog()
+		if err != nil {
+			return UpdateOrigin{}, err
+		}
+		commit, ok := ParseReflogUpdateOrigin(reflog)
+		if !ok || commit == head {
+			return UpdateOrigin{}, ErrNoUpdate
+		}
+		origin = UpdateOrigin{Commit: commit, FromReflog: true}
+	}
+	origin.Dropped, err = s.client.GitCountCommits(origin.Commit)
+	if err != nil {
+		return UpdateOrigin{}, err
+	}
+	return origin, nil
+}
+
+// UndoUpdate hard-resets the source to commit, the origin of the last
+// update. It refuses when the source has uncommitted changes, which the
+// reset would discard. The destination is left alone; apply afterwards to
+// roll it back too.
+func (s *Service) UndoUpdate(commit string) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	staged, unstaged, err := s.client.GitStatusFiles()
+	if err != nil {
+		return err
+	}
+	if len(staged) > 0 || len(unstaged) > 0 {
+		return ErrSourceDirty
+	}
+	if err := s.client.GitResetHard(commit); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dataDir, updateOriginFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("clear update origin: %w", err)
+	}
+	return nil
+}
+
 // --- Aggregated read operations ---
 
 // LoadStatus combines chezmoi status with git status (skips git in read-only mode).
@@ -108,6 +250,80 @@ func (s *Service) LoadStatus() (StatusSnapshot, error) {
 	return snap, nil
 }
 
+// IncomingFiles lists files changed between HEAD and upstream, mapped from
+// source names to target paths. Returns nil when there is no upstream.
+func (s *Service) IncomingFiles() ([]IncomingFile, error) {
+	raw, err := s.client.GitDiffIncomingNames()
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	changed := ParseGitNameStatusZ(raw)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	gitRoot, err := s.client.GitRoot()
+	if err != nil {
+		return nil, err
+	}
+	sourceDir, err := s.client.SourceDir()
+	if err != nil {
+		return nil, err
+	}
+	return mapIncomingFiles(changed, gitRoot, sourceDir, s.policy.TargetPath()), nil
+}
+
+// ApplyPlan parses an apply dry-run into per-target entries. Scripts are
+// identified from `chezmoi status`, since the diff alone cannot tell them
+// apart from regular files.
+func (s *Service) ApplyPlan() ([]ApplyPlanEntry, error) {
+	raw, err := s.client.ApplyPlan()
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.client.Status()
+	if err != nil {
+		return nil, err
+	}
+	entries := ParseApplyPlan(raw, s.policy.TargetPath())
+
+	scripts := make(map[string]bool)
+	for _, f := range files {
+		if f.SourceStatus == 'R' || f.DestStatus == 'R' {
+			scripts[f.Path] = true
+		}
+	}
+	for i := range entries {
+		if scripts[entries[i].Path] {
+			entries[i].Action = PlanScript
+			delete(scripts, entries[i].Path)
+		}
+	}
+	for _, f := range files {
+		if scripts[f.Path] {
+			entries = append(entries, ApplyPlanEntry{Path: f.Path, Action: PlanScript})
+		}
+	}
+	return entries, nil
+}
+
+// mapIncomingFiles resolves each working-tree-relative path to a target path.
+// Paths outside the source directory (e.g. a README next to .chezmoiroot) and
+// entries without a target keep an empty TargetPath.
+func mapIncomingFiles(changed []GitFile, gitRoot, sourceDir, targetPath string) []IncomingFile {
+	files := make([]IncomingFile, 0, len(changed))
+	for _, f := range changed {
+		incoming := IncomingFile{SourcePath: f.Path, StatusCode: f.StatusCode}
+		rel, err := filepath.Rel(sourceDir, filepath.Join(gitRoot, filepath.FromSlash(f.Path)))
+		if err == nil && targetPath != "" {
+			if targetRel, ok := TargetRelPath(filepath.ToSlash(rel)); ok {
+				incoming.TargetPath = filepath.Join(targetPath, filepath.FromSlash(targetRel))
+			}
+		}
+		files = append(files, incoming)
+	}
+	return files
+}
+
 func (s *Service) LoadInfo(req LoadInfoRequest) (InfoSnapshot, error) {
 	var content string
 	var err error
@@ -151,6 +367,48 @@ func (s *Service) ReAddAll() (string, error) {
 	return s.client.ReAddAll()
 }
 
+// StreamReAddAll is ReAddAll with output streamed to out as it is written.
+func (s *Service) StreamReAddAll(ctx context.Context, out chan<- OutputLine) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	return s.client.StreamReAddAll(ctx, out)
+}
+
+// ApplyTargets applies only the given targets, each of which must be inside
+// the target directory. It returns the verbose apply output, also on error.
+func (s *Service) ApplyTargets(paths []string) (string, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", ErrPathEmpty
+	}
+	for _, path := range paths {
+		if err := s.policy.ValidateTargetPath(path); err != nil {
+			return "", err
+		}
+	}
+	return s.client.ApplyTargets(paths)
+}
+
+// DeleteStateKey removes key from bucket in chezmoi's persistent state.
+func (s *Service) DeleteStateKey(bucket, key string) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	return s.client.StateDelete(bucket, key)
+}
+
+// DeleteStateBucket removes bucket and all its keys from chezmoi's
+// persistent state.
+func (s *Service) DeleteStateBucket(bucket string) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	return s.client.StateDeleteBucket(bucket)
+}
+
 func (s *Service) Forget(path string) error {
 	if err := s.policy.CheckMutation(); err != nil {
 		return err
@@ -158,7 +416,10 @@ func (s *Service) Forget(path string) error {
 	return s.client.Forget(path)
 }
 
-// Add also validates that path is within the target directory.
+// Add also validates that path is within the target directory. Unless the
+// file is added encrypted or opts.AllowSecrets is set, it returns a
+// *SecretsFoundError, adding nothing, when path looks like it holds
+// secrets.
 func (s *Service) Add(path string, opts AddOptions) error {
 	if err := s.policy.CheckMutation(); err != nil {
 		return err
@@ -166,6 +427,11 @@ func (s *Service) Add(path string, opts AddOptions) error {
 	if err := s.policy.ValidateTargetPath(path); err != nil {
 		return err
 	}
+	if !opts.Encrypt && !opts.AllowSecrets {
+		if findings := s.secrets.scanPath(path, s.TargetPath(), opts); len(findings) > 0 {
+			return &SecretsFoundError{Findings: findings}
+		}
+	}
 	return s.client.AddWithOptions(path, opts)
 }
 
@@ -211,7 +477,29 @@ func (s *Service) GitSoftReset() error {
 	return s.client.GitSoftReset()
 }
 
+// GitCommit returns a *SecretsFoundError, committing nothing, when the
+// staged changes look like they add secrets.
 func (s *Service) GitCommit(msg string) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	diff, err := s.client.GitDiffStaged()
+	if err != nil {
+		return err
+	}
+	sourceRel, err := s.sourceWithinGitRoot()
+	if err != nil {
+		return err
+	}
+	if findings := s.secrets.scanDiff(diff, filepath.ToSlash(sourceRel)); len(findings) > 0 {
+		return &SecretsFoundError{Findings: findings}
+	}
+	return s.client.Commit(msg)
+}
+
+// GitCommitAllowingSecrets commits without the secret scan, for when the
+// findings GitCommit reported are known to be safe.
+func (s *Service) GitCommitAllowingSecrets(msg string) error {
 	if err := s.policy.CheckMutation(); err != nil {
 		return err
 	}
@@ -262,6 +550,15 @@ func (s *Service) ApplyRefreshCmd() *exec.Cmd {
 	return s.client.ApplyRefreshCmd()
 }
 
+// StreamRefreshExternals re-downloads externals and applies them without
+// prompting, streaming output to out.
+func (s *Service) StreamRefreshExternals(ctx context.Context, out chan<- OutputLine) error {
+	if err := s.policy.CheckMutation(); err != nil {
+		return err
+	}
+	return s.client.StreamRefreshExternals(ctx, out)
+}
+
 func (s *Service) ApplyDryRunCmd() *exec.Cmd {
 	return s.client.ApplyDryRunCmd()
 }
@@ -312,12 +609,12 @@ func (s *Service) EditConfigTemplateCmd() *exec.Cmd {
 
 // Archive creates a timestamped tar.gz of the target state. Returns the output path.
 // Not gated by read-only: archiving is a read operation.
-func (s *Service) Archive() (string, error) {
+func (s *Service) Archive(ctx context.Context) (string, error) {
 	outputPath, err := s.archiveOutputPath()
 	if err != nil {
 		return "", err
 	}
-	if err := s.client.Archive(outputPath); err != nil {
+	if err := s.client.Archive(ctx, outputPath); err != nil {
 		return "", err
 	}
 	return outputPath, nil
@@ -333,11 +630,82 @@ func (s *Service) archiveOutputPath() (string, error) {
 }
 
 func (s *Service) ArchiveOutputDir() string {
+	return filepath.Join(chezitDataDir(), "archives")
+}
+
+// chezitDataDir is where chezit keeps archives, backups, and other state.
+func chezitDataDir() string {
 	dataDir, err := os.UserHomeDir()
 	if err != nil {
 		dataDir = os.TempDir()
 	}
-	return filepath.Join(dataDir, ".local", "share", "chezit", "archives")
+	return filepath.Join(dataDir, ".local", "share", "chezit")
+}
+
+// --- Backup operations ---
+
+func (s *Service) BackupOutputDir() string {
+	return s.backups.Dir()
+}
+
+// BackupTargets snapshots the target files an apply of paths would
+// overwrite, or of everything when paths is empty. Only files chezmoi
+// status reports as changing are saved; scripts are skipped.
+func (s *Service) BackupTargets(label string, paths []string) (BackupRun, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return BackupRun{}, err
+	}
+	files, err := s.client.Status()
+	if err != nil {
+		return BackupRun{}, err
+	}
+	var targets []string
+	for _, f := range files {
+		if f.DestStatus == ' ' || f.SourceStatus == 'R' || f.DestStatus == 'R' {
+			continue
+		}
+		if len(paths) > 0 && !pathWithinAny(f.Path, paths) {
+			continue
+		}
+		targets = append(targets, f.Path)
+	}
+	return s.backups.Snapshot(label, targets)
+}
+
+// BackupRuns lists stored runs, newest first, after pruning any that fall
+// outside the retention policy.
+func (s *Service) BackupRuns() ([]BackupRun, error) {
+	if err := s.backups.Prune(); err != nil {
+		return nil, err
+	}
+	return s.backups.Runs()
+}
+
+func (s *Service) BackupDiff(run BackupRun, path string) (string, error) {
+	return s.backups.Diff(run, path)
+}
+
+// RestoreBackup writes a backed-up file over its target, first snapshotting
+// the current file. It returns that snapshot.
+func (s *Service) RestoreBackup(run BackupRun, path string) (BackupRun, error) {
+	if err := s.policy.CheckMutation(); err != nil {
+		return BackupRun{}, err
+	}
+	if err := s.policy.ValidateTargetPath(path); err != nil {
+		return BackupRun{}, err
+	}
+	return s.backups.Restore(run, path)
+}
+
+// pathWithinAny reports whether path is one of roots or inside one of them.
+func pathWithinAny(path string, roots []string) bool {
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Service) AvailableCommands() []CommandAvailability {
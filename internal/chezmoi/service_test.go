@@ -1,6 +1,8 @@
 package chezmoi
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -91,6 +93,18 @@ func TestServiceReadOnlyBlocksMutations(t *testing.T) {
 	if err := svc.GitPull(); err == nil {
 		t.Fatal("expected GitPull to return error in read-only mode")
 	}
+	if err := svc.StreamRefreshExternals(context.Background(), nil); err == nil {
+		t.Fatal("expected StreamRefreshExternals to return error in read-only mode")
+	}
+	if err := svc.StreamReAddAll(context.Background(), nil); err == nil {
+		t.Fatal("expected StreamReAddAll to return error in read-only mode")
+	}
+	if err := svc.RecordUpdateOrigin(); err == nil {
+		t.Fatal("expected RecordUpdateOrigin to return error in read-only mode")
+	}
+	if err := svc.UndoUpdate("abc1234"); err == nil {
+		t.Fatal("expected UndoUpdate to return error in read-only mode")
+	}
 }
 
 func TestServiceInteractiveCmdsNilInReadOnly(t *testing.T) {
@@ -144,7 +158,7 @@ esac
 	client := New(WithBinaryPath(binaryPath))
 	svc := NewService(client, chezitconfig.ModeReadOnly, "/home/test")
 
-	outputPath, err := svc.Archive()
+	outputPath, err := svc.Archive(context.Background())
 	if err != nil {
 		t.Fatalf("Archive should not be blocked in read-only mode, got: %v", err)
 	}
@@ -187,11 +201,243 @@ esac
 	client := New(WithBinaryPath(binaryPath))
 	svc := NewService(client, chezitconfig.ModeReadOnly, "/home/test")
 
-	if err := svc.GitFetch(); err != nil {
+	if err := svc.GitFetch(context.Background()); err != nil {
 		t.Fatalf("expected GitFetch to succeed in read-only mode, got: %v", err)
 	}
 }
 
+func TestServiceIncomingFilesMapsSourceNames(t *testing.T) {
+	binaryPath := writeFakeChezmoiBinary(t, `
+case "$1" in
+git)
+	case "$3" in
+	diff) printf 'M\000home/dot_bashrc\000A\000home/run_once_setup.sh\000M\000README.md\000' ;;
+	rev-parse) printf '/src\n' ;;
+	esac
+	;;
+source-path)
+	printf '/src/home\n'
+	;;
+*)
+	echo "unexpected command: $*" >&2
+	exit 1
+	;;
+esac
+`)
+
+	client := New(WithBinaryPath(binaryPath))
+	svc := NewService(client, chezitconfig.ModeReadOnly, "/home/test")
+
+	files, err := svc.IncomingFiles()
+	if err != nil {
+		t.Fatalf("IncomingFiles returned unexpected error: %v", err)
+	}
+	want := []IncomingFile{
+		{SourcePath: "home/dot_bashrc", TargetPath: "/home/test/.bashrc", StatusCode: "M"},
+		{SourcePath: "home/run_once_setup.sh", StatusCode: "A"},
+		{SourcePath: "README.md", StatusCode: "M"},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, got %d: %+v", len(want), len(files), files)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("files[%d] = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+}
+
+func TestServiceIncomingFilesNoUpstream(t *testing.T) {
+	binaryPath := writeFakeChezmoiBinary(t, `
+echo "fatal: no upstream configured for branch 'main'" >&2
+exit 128
+`)
+
+	client := New(WithBinaryPath(binaryPath))
+	svc := NewService(client, chezitconfig.ModeWrite, "/home/test")
+
+	files, err := svc.IncomingFiles()
+	if err != nil {
+		t.Fatalf("expected no error without upstream, got: %v", err)
+	}
+	if files != nil {
+		t.Fatalf("expected nil files, got %+v", files)
+	}
+}
+
+func TestServiceApplyPlanMarksScripts(t *testing.T) {
+	binaryPath := writeFakeChezmoiBinary(t, `
+case "$1" in
+apply)
+	printf 'diff --git a/.bashrc b/.bashrc\n@@ -1 +1 @@\n-a\n+b\ndiff --git a/install.sh b/install.sh\nnew file mode 100755\n'
+	;;
+status)
+	printf ' M /home/test/.bashrc\n R /home/test/install.sh\n R /home/test/.chezmoiscripts/run.sh\n'
+	;;
+*)
+	echo "unexpected command: $*" >&2
+	exit 1
+	;;
+esac
+`)
+
+	client := New(WithBinaryPath(binaryPath))
+	svc := NewService(client, chezitconfig.ModeWrite, "/home/test")
+
+	entries, err := svc.ApplyPlan()
+	if err != nil {
+		t.Fatalf("ApplyPlan returned unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Action != PlanModify {
+		t.Errorf("expected .bashrc to be modify, got %s", entries[0].Action)
+	}
+	if entries[1].Action != PlanScript || entries[2].Action != PlanScript {
+		t.Errorf("expected scripts from status, got %+v", entries[1:])
+	}
+}
+
+func TestServiceApplyTargetsValidatesPaths(t *testing.T) {
+	client := New(WithBinaryPath("/bin/true"))
+
+	readOnly := NewService(client, chezitconfig.ModeReadOnly, "/home/test")
+	if _, err := readOnly.ApplyTargets([]string{"/home/test/.bashrc"}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	svc := NewService(client, chezitconfig.ModeWrite, "/home/test")
+	if _, err := svc.ApplyTargets(nil); !errors.Is(err, ErrPathEmpty) {
+		t.Fatalf("expected ErrPathEmpty, got %v", err)
+	}
+	if _, err := svc.ApplyTargets([]string{"/etc/passwd"}); !errors.Is(err, ErrOutsideTarget) {
+		t.Fatalf("expected ErrOutsideTarget, got %v", err)
+	}
+	if _, err := svc.ApplyTargets([]string{"/home/test/.bashrc"}); err != nil {
+		t.Fatalf("expected apply to succeed, got %v", err)
+	}
+}
+
+func TestServiceDeleteState(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "state.log")
+	t.Setenv("LOG", log)
+	binaryPath := writeFakeChezmoiBinary(t, `echo "$*" >> "$LOG"`)
+	client := New(WithBinaryPath(binaryPath))
+
+	readOnly := NewService(client, chezitconfig.ModeReadOnly, "/home/test")
+	if err := readOnly.DeleteStateKey("entryState", "/home/test/.bashrc"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	if err := readOnly.DeleteStateBucket("scriptState"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	svc := NewService(client, chezitconfig.ModeWrite, "/home/test")
+	if err := svc.DeleteStateKey("entryState", "/home/test/.bashrc"); err != nil {
+		t.Fatalf("DeleteStateKey: %v", err)
+	}
+	if err := svc.DeleteStateBucket("scriptState"); err != nil {
+		t.Fatalf("DeleteStateBucket: %v", err)
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "state delete --bucket=entryState --key=/home/test/.bashrc\n" +
+		"state delete-bucket --bucket=scriptState\n"
+	if string(data) != want {
+		t.Fatalf("got commands:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+// newGitSourceService fakes the git commands behind update undo. HEAD and
+// the porcelain status live in files under $STATE so resets are visible to
+// later calls.
+func newGitSourceService(t *testing.T, head, reflog string) (svc *Service, state string) {
+	t.Helper()
+	state = t.TempDir()
+	if err := os.WriteFile(filepath.Join(state, "head"), []byte(head+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(state, "reflog"), []byte(reflog), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STATE", state)
+	svc = newFakeService(t, chezitconfig.ModeWrite, "/home/test", `
+case "$1 $3" in
+"git rev-parse") cat "$STATE/head" ;;
+"git reflog") cat "$STATE/reflog" ;;
+"git rev-list") echo 2 ;;
+"git status") cat "$STATE/status" 2>/dev/null || true ;;
+"git reset") echo "$5" > "$STATE/head" ;;
+*)
+	echo "unexpected command: $*" >&2
+	exit 1
+	;;
+esac
+`, WithDataDir(t.TempDir()))
+	return svc, state
+}
+
+func TestServiceUndoUpdateUsesRecordedOrigin(t *testing.T) {
+	svc, state := newGitSourceService(t, "aaa1111", "")
+
+	if err := svc.RecordUpdateOrigin(); err != nil {
+		t.Fatalf("RecordUpdateOrigin: %v", err)
+	}
+	if _, err := svc.LastUpdateOrigin(); !errors.Is(err, ErrNoUpdate) {
+		t.Fatalf("expected ErrNoUpdate while HEAD has not moved, got %v", err)
+	}
+
+	// The update moves HEAD forward.
+	if err := os.WriteFile(filepath.Join(state, "head"), []byte("bbb2222\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	origin, err := svc.LastUpdateOrigin()
+	if err != nil {
+		t.Fatalf("LastUpdateOrigin: %v", err)
+	}
+	if origin.Commit != "aaa1111" || origin.FromReflog || origin.Dropped != 2 || origin.Time.IsZero() {
+		t.Fatalf("unexpected origin %+v", origin)
+	}
+
+	if err := os.WriteFile(filepath.Join(state, "status"), []byte(" M dot_bashrc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UndoUpdate(origin.Commit); !errors.Is(err, ErrSourceDirty) {
+		t.Fatalf("expected ErrSourceDirty, got %v", err)
+	}
+	if err := os.Remove(filepath.Join(state, "status")); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UndoUpdate(origin.Commit); err != nil {
+		t.Fatalf("UndoUpdate: %v", err)
+	}
+	if head, _ := os.ReadFile(filepath.Join(state, "head")); strings.TrimSpace(string(head)) != "aaa1111" {
+		t.Fatalf("expected HEAD reset to aaa1111, got %q", head)
+	}
+}
+
+func TestServiceLastUpdateOriginFallsBackToReflog(t *testing.T) {
+	svc, _ := newGitSourceService(t, "bbb2222", "bbb2222\tpull: Fast-forward\naaa1111\tcommit: a\n")
+
+	origin, err := svc.LastUpdateOrigin()
+	if err != nil {
+		t.Fatalf("LastUpdateOrigin: %v", err)
+	}
+	if origin.Commit != "aaa1111" || !origin.FromReflog {
+		t.Fatalf("expected reflog origin aaa1111, got %+v", origin)
+	}
+}
+
+// newFakeService returns a Service on target whose chezmoi is a fake
+// binary running body, written by writeFakeChezmoiBinary.
+func newFakeService(t *testing.T, mode chezitconfig.Mode, target, body string, opts ...ServiceOption) *Service {
+	t.Helper()
+	return NewService(New(WithBinaryPath(writeFakeChezmoiBinary(t, body))), mode, target, opts...)
+}
+
 func writeFakeChezmoiBinary(t *testing.T, body string) string {
 	t.Helper()
 
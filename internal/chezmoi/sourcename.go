@@ -0,0 +1,199 @@
+package chezmoi
+
+import (
+	"path"
+	"strings"
+)
+
+// SourceKind is the kind of target state entry encoded by a source name.
+type SourceKind int
+
+const (
+	SourceKindFile SourceKind = iota
+	SourceKindCreate
+	SourceKindModify
+	SourceKindRemove
+	SourceKindScript
+	SourceKindSymlink
+	SourceKindDir
+)
+
+// SourceName is a decoded chezmoi source state file or directory name.
+type SourceName struct {
+	Kind       SourceKind
+	TargetName string // name in the destination directory, e.g. ".bashrc"
+
+	Encrypted  bool
+	Private    bool
+	Readonly   bool
+	Empty      bool
+	Executable bool
+	Exact      bool // directories only
+	External   bool // directories only
+	Template   bool
+
+	// Script timing attributes (SourceKindScript only).
+	Once     bool
+	OnChange bool
+	Before   bool
+	After    bool
+}
+
+// Prefix and suffix attributes, in the order chezmoi requires them.
+const (
+	prefixCreate     = "create_"
+	prefixModify     = "modify_"
+	prefixRemove     = "remove_"
+	prefixRun        = "run_"
+	prefixSymlink    = "symlink_"
+	prefixEncrypted  = "encrypted_"
+	prefixPrivate    = "private_"
+	prefixReadonly   = "readonly_"
+	prefixEmpty      = "empty_"
+	prefixExecutable = "executable_"
+	prefixExact      = "exact_"
+	prefixExternal   = "external_"
+	prefixOnce       = "once_"
+	prefixOnChange   = "onchange_"
+	prefixBefore     = "before_"
+	prefixAfter      = "after_"
+	prefixDot        = "dot_"
+	prefixLiteral    = "literal_"
+
+	suffixTemplate = ".tmpl"
+	suffixLiteral  = ".literal"
+)
+
+// encryptedSuffixes are stripped from encrypted_ files (age and gpg defaults).
+var encryptedSuffixes = []string{".age", ".asc"}
+
+// ParseSourceFileName decodes the attributes of a source state file name.
+func ParseSourceFileName(name string) SourceName {
+	sn := SourceName{Kind: SourceKindFile}
+	rest := name
+
+	switch {
+	case consumePrefix(&rest, prefixCreate):
+		sn.Kind = SourceKindCreate
+	case consumePrefix(&rest, prefixModify):
+		sn.Kind = SourceKindModify
+	case consumePrefix(&rest, prefixRemove):
+		sn.Kind = SourceKindRemove
+	case consumePrefix(&rest, prefixRun):
+		sn.Kind = SourceKindScript
+	case consumePrefix(&rest, prefixSymlink):
+		sn.Kind = SourceKindSymlink
+	}
+
+	literal := consumePrefix(&rest, prefixLiteral)
+	if !literal {
+		switch sn.Kind {
+		case SourceKindScript:
+			sn.Once = consumePrefix(&rest, prefixOnce)
+			if !sn.Once {
+				sn.OnChange = consumePrefix(&rest, prefixOnChange)
+			}
+			sn.Before = consumePrefix(&rest, prefixBefore)
+			if !sn.Before {
+				sn.After = consumePrefix(&rest, prefixAfter)
+			}
+		case SourceKindFile, SourceKindCreate, SourceKindModify:
+			sn.Encrypted = consumePrefix(&rest, prefixEncrypted)
+			sn.Private = consumePrefix(&rest, prefixPrivate)
+			sn.Readonly = consumePrefix(&rest, prefixReadonly)
+			if sn.Kind != SourceKindModify {
+				sn.Empty = consumePrefix(&rest, prefixEmpty)
+			}
+			sn.Executable = consumePrefix(&rest, prefixExecutable)
+		}
+		literal = consumePrefix(&rest, prefixLiteral)
+	}
+	if !literal && sn.Kind != SourceKindScript && consumePrefix(&rest, prefixDot) {
+		rest = "." + rest
+	}
+
+	if !consumeSuffix(&rest, suffixLiteral) {
+		sn.Template = consumeSuffix(&rest, suffixTemplate)
+		if sn.Encrypted {
+			for _, suffix := range encryptedSuffixes {
+				if consumeSuffix(&rest, suffix) {
+					break
+				}
+			}
+		}
+	}
+
+	sn.TargetName = rest
+	return sn
+}
+
+// ParseSourceDirName decodes the attributes of a source state directory name.
+func ParseSourceDirName(name string) SourceName {
+	sn := SourceName{Kind: SourceKindDir}
+	rest := name
+
+	if !consumePrefix(&rest, prefixLiteral) {
+		if consumePrefix(&rest, prefixRemove) {
+			sn.Kind = SourceKindRemove
+		}
+		sn.External = consumePrefix(&rest, prefixExternal)
+		sn.Exact = consumePrefix(&rest, prefixExact)
+		sn.Private = consumePrefix(&rest, prefixPrivate)
+		sn.Readonly = consumePrefix(&rest, prefixReadonly)
+		if !consumePrefix(&rest, prefixLiteral) && consumePrefix(&rest, prefixDot) {
+			rest = "." + rest
+		}
+	}
+
+	sn.TargetName = rest
+	return sn
+}
+
+// TargetRelPath maps a slash-separated path relative to the source directory
+// to the corresponding path relative to the destination directory. It returns
+// false for entries that have no target: special .chezmoi* files, scripts,
+// and anything chezmoi ignores because its name begins with a dot.
+func TargetRelPath(sourceRel string) (string, bool) {
+	sourceRel = path.Clean(strings.TrimPrefix(sourceRel, "./"))
+	if sourceRel == "." || sourceRel == "" || strings.HasPrefix(sourceRel, "../") {
+		return "", false
+	}
+
+	segments := strings.Split(sourceRel, "/")
+	target := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+		var sn SourceName
+		if i == len(segments)-1 {
+			sn = ParseSourceFileName(segment)
+			if sn.Kind == SourceKindScript {
+				return "", false
+			}
+		} else {
+			sn = ParseSourceDirName(segment)
+		}
+		if sn.TargetName == "" {
+			return "", false
+		}
+		target = append(target, sn.TargetName)
+	}
+	return strings.Join(target, "/"), true
+}
+
+func consumePrefix(s *string, prefix string) bool {
+	if rest, ok := strings.CutPrefix(*s, prefix); ok {
+		*s = rest
+		return true
+	}
+	return false
+}
+
+func consumeSuffix(s *string, suffix string) bool {
+	if rest, ok := strings.CutSuffix(*s, suffix); ok && rest != "" {
+		*s = rest
+		return true
+	}
+	return false
+}
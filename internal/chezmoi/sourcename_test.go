@@ -0,0 +1,68 @@
+package chezmoi
+
+import "testing"
+
+func TestParseSourceFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want SourceName
+	}{
+		{"dot_bashrc", SourceName{Kind: SourceKindFile, TargetName: ".bashrc"}},
+		{"private_executable_dot_script.sh.tmpl", SourceName{Kind: SourceKindFile, TargetName: ".script.sh", Private: true, Executable: true, Template: true}},
+		{"encrypted_private_dot_netrc.age", SourceName{Kind: SourceKindFile, TargetName: ".netrc", Encrypted: true, Private: true}},
+		{"create_empty_dot_hushlogin", SourceName{Kind: SourceKindCreate, TargetName: ".hushlogin", Empty: true}},
+		{"modify_dot_config.json", SourceName{Kind: SourceKindModify, TargetName: ".config.json"}},
+		{"remove_dot_old", SourceName{Kind: SourceKindRemove, TargetName: ".old"}},
+		{"symlink_dot_vimrc.tmpl", SourceName{Kind: SourceKindSymlink, TargetName: ".vimrc", Template: true}},
+		{"run_once_before_install.sh", SourceName{Kind: SourceKindScript, TargetName: "install.sh", Once: true, Before: true}},
+		{"run_onchange_after_reload.sh.tmpl", SourceName{Kind: SourceKindScript, TargetName: "reload.sh", OnChange: true, After: true, Template: true}},
+		{"literal_dot_keep.tmpl", SourceName{Kind: SourceKindFile, TargetName: "dot_keep", Template: true}},
+		{"dot_file.tmpl.literal", SourceName{Kind: SourceKindFile, TargetName: ".file.tmpl"}},
+		{"private_literal_dot_x", SourceName{Kind: SourceKindFile, TargetName: "dot_x", Private: true}},
+	}
+	for _, tt := range tests {
+		if got := ParseSourceFileName(tt.name); got != tt.want {
+			t.Errorf("ParseSourceFileName(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSourceDirName(t *testing.T) {
+	tests := []struct {
+		name string
+		want SourceName
+	}{
+		{"dot_config", SourceName{Kind: SourceKindDir, TargetName: ".config"}},
+		{"exact_private_dot_ssh", SourceName{Kind: SourceKindDir, TargetName: ".ssh", Exact: true, Private: true}},
+		{"external_dot_oh-my-zsh", SourceName{Kind: SourceKindDir, TargetName: ".oh-my-zsh", External: true}},
+		{"remove_dot_cache", SourceName{Kind: SourceKindRemove, TargetName: ".cache"}},
+		{"literal_exact_dir", SourceName{Kind: SourceKindDir, TargetName: "exact_dir"}},
+	}
+	for _, tt := range tests {
+		if got := ParseSourceDirName(tt.name); got != tt.want {
+			t.Errorf("ParseSourceDirName(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTargetRelPath(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+		ok     bool
+	}{
+		{"dot_bashrc", ".bashrc", true},
+		{"dot_config/nvim/init.lua.tmpl", ".config/nvim/init.lua", true},
+		{"exact_dot_ssh/private_config", ".ssh/config", true},
+		{".chezmoiignore", "", false},
+		{".chezmoiscripts/run_once_install.sh", "", false},
+		{"run_after_reload.sh", "", false},
+		{"../outside", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := TargetRelPath(tt.source)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("TargetRelPath(%q) = (%q, %v), want (%q, %v)", tt.source, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package chezmoi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// errCorruptState is wrapped by readStateFile when a state file exists but
+// does not decode.
+var errCorruptState = errors.New("corrupt state file")
+
+// readStateFile decodes the JSON state file name in dataDir, chezit's own
+// state kept beside archives and backups. A missing file is the zero value.
+func readStateFile[T any](dataDir, name string) (T, error) {
+	var v T
+	data, err := os.ReadFile(filepath.Join(dataDir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return v, fmt.Errorf("read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		var zero T
+		return zero, fmt.Errorf("read %s: %w: %w", name, errCorruptState, err)
+	}
+	return v, nil
+}
+
+// writeStateFile saves v as the JSON state file name in dataDir. State is
+// chezit's own, so it is written in read-only mode too.
+func writeStateFile(dataDir, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return fmt.Errorf("save %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, name), data, 0o600); err != nil {
+		return fmt.Errorf("save %s: %w", name, err)
+	}
+	return nil
+}
+
+// updateStateFile reads the state file name, applies update, and writes
+// the result back. A corrupt file is started over rather than failing
+// every later update; any other read error is returned.
+func updateStateFile[T any](dataDir, name string, update func(T) T) (T, error) {
+	v, err := readStateFile[T](dataDir, name)
+	if err != nil && !errors.Is(err, errCorruptState) {
+		return v, err
+	}
+	v = update(v)
+	if err := writeStateFile(dataDir, name, v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
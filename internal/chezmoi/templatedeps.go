@@ -0,0 +1,436 @@
+package chezmoi
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// TemplateDeps is what a template target reads: template data keys and
+// secret-manager functions, including those read by the templates it
+// includes from .chezmoitemplates.
+type TemplateDeps struct {
+	Target  string
+	Source  string
+	Keys    []string // data keys such as ".email" or ".chezmoi.os", sorted; "." is all data
+	Secrets []string // secret-manager functions called, sorted
+	Err     error    // the template could not be parsed; Keys and Secrets are empty
+}
+
+// DataIndex maps template data keys and secret-manager functions to the
+// templates that use them.
+type DataIndex struct {
+	Templates []TemplateDeps // in target order
+}
+
+// DataIndexEntry is one key or secret-manager function and its direct users.
+type DataIndexEntry struct {
+	Key     string   // ".email", or a function name such as "bitwarden" when Secret
+	Secret  bool     // Key is a secret-manager function
+	Targets []string // templates that use Key as written
+}
+
+// Entries lists every data key, sorted, followed by every secret-manager
+// function, sorted.
+func (ix DataIndex) Entries() []DataIndexEntry {
+	keys := map[string][]string{}
+	secrets := map[string][]string{}
+	for _, t := range ix.Templates {
+		for _, k := range t.Keys {
+			keys[k] = append(keys[k], t.Target)
+		}
+		for _, fn := range t.Secrets {
+			secrets[fn] = append(secrets[fn], t.Target)
+		}
+	}
+	entries := make([]DataIndexEntry, 0, len(keys)+len(secrets))
+	for _, k := range slices.Sorted(maps.Keys(keys)) {
+		entries = append(entries, DataIndexEntry{Key: k, Targets: keys[k]})
+	}
+	for _, fn := range slices.Sorted(maps.Keys(secrets)) {
+		entries = append(entries, DataIndexEntry{Key: fn, Secret: true, Targets: secrets[fn]})
+	}
+	return entries
+}
+
+// DataIndexUser is a template whose output changes with an entry.
+type DataIndexUser struct {
+	Target string
+	Via    []string // keys the template reads that overlap the entry
+}
+
+// Direct reports whether the template reads the entry's key as written.
+func (u DataIndexUser) Direct(entry DataIndexEntry) bool {
+	return slices.Contains(u.Via, entry.Key)
+}
+
+// Users returns the templates whose output changes with entry: those
+// using its key, a key inside it, or a map containing it. A template
+// reading ".git" uses ".git.email", and one reading ".git.email" changes
+// when ".git" is replaced.
+func (ix DataIndex) Users(entry DataIndexEntry) []DataIndexUser {
+	var users []DataIndexUser
+	for _, t := range ix.Templates {
+		var via []string
+		if entry.Secret {
+			if slices.Contains(t.Secrets, entry.Key) {
+				via = []string{entry.Key}
+			}
+		} else {
+			for _, k := range t.Keys {
+				if dataKeysOverlap(k, entry.Key) {
+					via = append(via, k)
+				}
+			}
+		}
+		if len(via) > 0 {
+			users = append(users, DataIndexUser{Target: t.Target, Via: via})
+		}
+	}
+	return users
+}
+
+// DepsOf returns the dependencies of the template at target.
+func (ix DataIndex) DepsOf(target string) (TemplateDeps, bool) {
+	for _, t := range ix.Templates {
+		if t.Target == target {
+			return t, true
+		}
+	}
+	return TemplateDeps{}, false
+}
+
+// dataKeysOverlap reports whether a and b are the same key or one is inside
+// the other.
+func dataKeysOverlap(a, b string) bool {
+	if a == "." || b == "." || a == b {
+		return true
+	}
+	return strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// secretFunctions are chezmoi's template functions that read from a
+// password or secret manager.
+var secretFunctions = map[string]bool{
+	"awsSecretsManager": true, "awsSecretsManagerRaw": true,
+	"azureKeyVault": true,
+	"bitwarden":     true, "bitwardenAttachment": true, "bitwardenAttachmentByRef": true, "bitwardenFields": true, "bitwardenSecrets": true,
+	"dashlaneNote": true, "dashlanePassword": true,
+	"doppler": true, "dopplerProjectJSON": true,
+	"ejsonDecrypt": true, "ejsonDecryptWithKey": true,
+	"gopass": true, "gopassRaw": true,
+	"hcpVaultSecret": true, "hcpVaultSecretJson": true,
+	"keepassxc": true, "keepassxcAttachment": true, "keepassxcAttribute": true,
+	"keeper": true, "keeperDataFields": true, "keeperFindPassword": true,
+	"keyring":  true,
+	"lastpass": true, "lastpassRaw": true,
+	"onepassword": true, "onepasswordDetailsFields": true, "onepasswordDocument": true, "onepasswordItemFields": true, "onepasswordRead": true,
+	"pass": true, "passFields": true, "passRaw": true,
+	"passhole": true,
+	"rbw":      true, "rbwFields": true,
+	"secret": true, "secretJSON": true,
+	"vault": true,
+}
+
+// TemplateDataIndex scans every template target, other than scripts and
+// encrypted templates, for the data keys and secret-manager functions it
+// uses. Templates are parsed, not executed, so the scan is fast and never
+// prompts for secrets. Inside
+// {{ range }} only the ranged-over key is recorded, and keys read from the
+// result of a function call are not seen.
+func (s *Service) TemplateDataIndex() (DataIndex, error) {
+	targets, sources, _, err := s.templateSources()
+	if err != nil {
+		return DataIndex{}, err
+	}
+	var named map[string]templateScan
+	if sourceDir, err := s.client.SourceDir(); err == nil {
+		named = scanNamedTemplates(filepath.Join(sourceDir, ".chezmoitemplates"))
+	}
+
+	ix := DataIndex{Templates: make([]TemplateDeps, len(targets))}
+	for i, target := range targets {
+		deps := TemplateDeps{Target: target, Source: sources[i]}
+		data, err := os.ReadFile(sources[i])
+		if err != nil {
+			deps.Err = err
+			ix.Templates[i] = deps
+			continue
+		}
+		scan := scanTemplate(filepath.Base(sources[i]), string(data))
+		if scan.err != nil {
+			deps.Err = scan.err
+			ix.Templates[i] = deps
+			continue
+		}
+		keys, secrets := map[string]bool{}, map[string]bool{}
+		scan.collect(named, ".", keys, secrets, map[string]bool{})
+		deps.Keys = slices.Sorted(maps.Keys(keys))
+		deps.Secrets = slices.Sorted(maps.Keys(secrets))
+		ix.Templates[i] = deps
+	}
+	return ix, nil
+}
+
+// templateScan is what one template file uses directly.
+type templateScan struct {
+	keys     map[string]bool
+	secrets  map[string]bool
+	includes []templateInclude
+	err      error
+}
+
+// templateInclude is a {{ template }} or includeTemplate call; key is the
+// data passed as dot, or "" when it is not a data key.
+type templateInclude struct {
+	name string
+	key  string
+}
+
+// collect adds the keys and secrets used by scan, invoked with the data at
+// key as dot, and those of the named templates it includes. When key is ""
+// dot is not template data, and only secrets are collected.
+func (scan templateScan) collect(named map[string]templateScan, key string, keys, secrets, visiting map[string]bool) {
+	for k := range scan.keys {
+		if abs := joinDataKey(key, k); abs != "" {
+			keys[abs] = true
+		}
+	}
+	for fn := range scan.secrets {
+		secrets[fn] = true
+	}
+	for _, inc := range scan.includes {
+		sub, ok := named[inc.name]
+		if !ok || visiting[inc.name] || sub.err != nil {
+			continue
+		}
+		visiting[inc.name] = true
+		sub.collect(named, joinDataKey(key, inc.key), keys, secrets, visiting)
+		delete(visiting, inc.name)
+	}
+}
+
+// joinDataKey resolves k, a key relative to dot, where dot is the data key
+// dot holds. Either being "" means unknown, and so is the result.
+func joinDataKey(dot, k string) string {
+	switch {
+	case dot == "" || k == "":
+		return ""
+	case dot == ".":
+		return k
+	case k == ".":
+		return dot
+	}
+	return dot + k
+}
+
+// scanNamedTemplates scans the templates in a .chezmoitemplates directory,
+// keyed by their slash-separated path relative to it.
+func scanNamedTemplates(dir string) map[string]templateScan {
+	named := map[string]templateScan{}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		named[filepath.ToSlash(rel)] = scanTemplate(d.Name(), string(data))
+		return nil
+	})
+	return named
+}
+
+// delimiterDirective matches chezmoi's per-file delimiter directive, e.g.
+// `chezmoi:template:left-delimiter="[[" right-delimiter="]]"`.
+var delimiterDirective = regexp.MustCompile(`chezmoi:template:.*?(left|right)-delimiter=("(?:[^"\\]|\\.)*"|\S+)(?:\s+(left|right)-delimiter=("(?:[^"\\]|\\.)*"|\S+))?`)
+
+// templateDelimiters returns the delimiters set by a directive in text, or
+// "" for the default.
+func templateDelimiters(text string) (left, right string) {
+	for _, match := range delimiterDirective.FindAllStringSubmatch(text, -1) {
+		for i := 1; i+1 < len(match); i += 2 {
+			value := match[i+1]
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+			switch match[i] {
+			case "left":
+				left = value
+			case "right":
+				right = value
+			}
+		}
+	}
+	return left, right
+}
+
+// scanTemplate parses text and records the data keys, secret-manager
+// calls, and named templates it uses. Unknown functions are allowed so
+// chezmoi's own functions parse.
+func scanTemplate(name, text string) templateScan {
+	scan := templateScan{keys: map[string]bool{}, secrets: map[string]bool{}}
+	left, right := templateDelimiters(text)
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	treeSet := map[string]*parse.Tree{}
+	if _, err := tree.Parse(text, left, right, treeSet); err != nil {
+		scan.err = err
+		return scan
+	}
+	w := templateWalker{scan: &scan, vars: map[string]string{}}
+	for _, t := range treeSet {
+		w.walk(t.Root, ".")
+	}
+	return scan
+}
+
+// templateWalker records what a parse tree uses. Keys are relative to the
+// data the template is executed with, which is both its initial dot and $.
+// The dot passed down is the key dot holds at that point: "." at the top,
+// ".git" inside {{ with .git }}, and "" when it is not a data key.
+type templateWalker struct {
+	scan *templateScan
+	vars map[string]string // variable → key it holds
+}
+
+func (w templateWalker) walk(node parse.Node, dot string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		w.walkPipe(n.Pipe, dot)
+	case *parse.IfNode:
+		w.walkPipe(n.Pipe, dot)
+		w.walk(n.List, dot)
+		w.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		w.walkPipe(n.Pipe, dot)
+		w.walk(n.List, w.pipeKey(n.Pipe, dot))
+		w.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		// The range key itself is recorded; elements are not tracked, so
+		// neither dot nor the loop variables hold a key inside the body.
+		w.walkPipe(n.Pipe, dot)
+		for _, v := range n.Pipe.Decl {
+			delete(w.vars, v.Ident[0])
+		}
+		w.walk(n.List, "")
+		w.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		key := w.pipeKey(n.Pipe, dot)
+		if key == "" {
+			w.walkPipe(n.Pipe, dot)
+		}
+		w.scan.includes = append(w.scan.includes, templateInclude{name: n.Name, key: key})
+	}
+}
+
+func (w templateWalker) walkPipe(pipe *parse.PipeNode, dot string) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		w.walkCommand(cmd, dot)
+	}
+	// Remember variables holding a data key, such as {{ $git := .git }}.
+	if len(pipe.Decl) > 0 {
+		if key := w.pipeKey(pipe, dot); key != "" {
+			w.vars[pipe.Decl[len(pipe.Decl)-1].Ident[0]] = key
+		}
+	}
+}
+
+func (w templateWalker) walkCommand(cmd *parse.CommandNode, dot string) {
+	if len(cmd.Args) == 0 {
+		return
+	}
+	if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok {
+		if secretFunctions[ident.Ident] {
+			w.scan.secrets[ident.Ident] = true
+		}
+		if ident.Ident == "includeTemplate" && len(cmd.Args) >= 2 {
+			if name, ok := cmd.Args[1].(*parse.StringNode); ok {
+				inc := templateInclude{name: name.Text}
+				if len(cmd.Args) >= 3 {
+					inc.key = w.argKey(cmd.Args[2], dot)
+					if inc.key == "" {
+						w.walkArg(cmd.Args[2], dot)
+					}
+				}
+				w.scan.includes = append(w.scan.includes, inc)
+				return
+			}
+		}
+	}
+	for _, arg := range cmd.Args {
+		w.walkArg(arg, dot)
+	}
+}
+
+func (w templateWalker) walkArg(arg parse.Node, dot string) {
+	switch n := arg.(type) {
+	case *parse.PipeNode:
+		w.walkPipe(n, dot)
+	case *parse.ChainNode:
+		if key := w.argKey(n, dot); key != "" {
+			w.scan.keys[key] = true
+		} else if pipe, ok := n.Node.(*parse.PipeNode); ok {
+			w.walkPipe(pipe, dot)
+		}
+	default:
+		if key := w.argKey(arg, dot); key != "" {
+			w.scan.keys[key] = true
+		}
+	}
+}
+
+// argKey returns the data key an argument reads, relative to the template's
+// dot, or "" when it is not one.
+func (w templateWalker) argKey(arg parse.Node, dot string) string {
+	switch n := arg.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return joinDataKey(dot, "."+strings.Join(n.Ident, "."))
+	case *parse.VariableNode:
+		base := w.vars[n.Ident[0]]
+		if n.Ident[0] == "$" {
+			base = "."
+		}
+		if len(n.Ident) == 1 {
+			return base
+		}
+		return joinDataKey(base, "."+strings.Join(n.Ident[1:], "."))
+	case *parse.ChainNode:
+		return joinDataKey(w.argKey(n.Node, dot), "."+strings.Join(n.Field, "."))
+	case *parse.PipeNode:
+		return w.pipeKey(n, dot)
+	}
+	return ""
+}
+
+// pipeKey returns the data key a pipeline evaluates to when it is a single
+// field, variable, or dot, or "" otherwise.
+func (w templateWalker) pipeKey(pipe *parse.PipeNode, dot string) string {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return ""
+	}
+	return w.argKey(pipe.Cmds[0].Args[0], dot)
+}
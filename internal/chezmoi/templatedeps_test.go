@@ -0,0 +1,165 @@
+package chezmoi
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestScanTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantKeys    []string
+		wantSecrets []string
+	}{
+		{
+			name:     "fields and chezmoi keys",
+			text:     `email = {{ .email }}{{ if eq .chezmoi.os "darwin" }}mac{{ end }}`,
+			wantKeys: []string{".chezmoi.os", ".email"},
+		},
+		{
+			name:     "with changes dot and $ stays at the root",
+			text:     `{{ with .git }}{{ .name }} {{ $.email }}{{ end }}`,
+			wantKeys: []string{".email", ".git", ".git.name"},
+		},
+		{
+			name:     "range records only the ranged key",
+			text:     `{{ range $k, $v := .hosts }}{{ $k }} {{ .addr }}{{ end }}`,
+			wantKeys: []string{".hosts"},
+		},
+		{
+			name:     "variables",
+			text:     `{{ $git := .git }}{{ $git.email }}{{ $.chezmoi.hostname }}`,
+			wantKeys: []string{".chezmoi.hostname", ".git", ".git.email"},
+		},
+		{
+			name:        "secret functions",
+			text:        `{{ (bitwarden "item" "github").login.password }} {{ onepasswordRead "op://x" | trim }}`,
+			wantSecrets: []string{"bitwarden", "onepasswordRead"},
+		},
+		{
+			name:     "custom delimiters",
+			text:     "# chezmoi:template:left-delimiter=\"[[\" right-delimiter=\"]]\"\n[[ .email ]] {{ .ignored }}",
+			wantKeys: []string{".email"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scan := scanTemplate("t", tt.text)
+			if scan.err != nil {
+				t.Fatalf("scan: %v", scan.err)
+			}
+			if got := slices.Sorted(maps.Keys(scan.keys)); !slices.Equal(got, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", got, tt.wantKeys)
+			}
+			if got := slices.Sorted(maps.Keys(scan.secrets)); !slices.Equal(got, tt.wantSecrets) {
+				t.Errorf("secrets = %v, want %v", got, tt.wantSecrets)
+			}
+		})
+	}
+}
+
+func TestDataIndexUsers(t *testing.T) {
+	ix := DataIndex{Templates: []TemplateDeps{
+		{Target: "/home/test/.gitconfig", Keys: []string{".git.email", ".git.name"}},
+		{Target: "/home/test/.ssh/config", Keys: []string{".git"}, Secrets: []string{"bitwarden"}},
+		{Target: "/home/test/.zshrc", Keys: []string{".chezmoi.os"}},
+	}}
+
+	entries := ix.Entries()
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Key)
+	}
+	if want := []string{".chezmoi.os", ".git", ".git.email", ".git.name", "bitwarden"}; !slices.Equal(got, want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	if !entries[4].Secret || entries[0].Secret {
+		t.Fatalf("expected only bitwarden marked secret: %+v", entries)
+	}
+
+	targets := func(users []DataIndexUser) []string {
+		var out []string
+		for _, u := range users {
+			out = append(out, u.Target)
+		}
+		return out
+	}
+	email := DataIndexEntry{Key: ".git.email"}
+	users := ix.Users(email)
+	if got := targets(users); !slices.Equal(got, []string{"/home/test/.gitconfig", "/home/test/.ssh/config"}) {
+		t.Fatalf("users of .git.email = %v", got)
+	}
+	if !users[0].Direct(email) || users[1].Direct(email) || !slices.Equal(users[1].Via, []string{".git"}) {
+		t.Errorf("expected .gitconfig direct and .ssh/config via .git: %+v", users)
+	}
+	if got := targets(ix.Users(DataIndexEntry{Key: ".git"})); len(got) != 2 {
+		t.Errorf("users of .git = %v", got)
+	}
+	if got := targets(ix.Users(DataIndexEntry{Key: "bitwarden", Secret: true})); !slices.Equal(got, []string{"/home/test/.ssh/config"}) {
+		t.Errorf("users of bitwarden = %v", got)
+	}
+	if got := targets(ix.Users(DataIndexEntry{Key: ".chezmoi"})); !slices.Equal(got, []string{"/home/test/.zshrc"}) {
+		t.Errorf("users of .chezmoi = %v", got)
+	}
+}
+
+func TestServiceTemplateDataIndexFollowsIncludes(t *testing.T) {
+	target := t.TempDir()
+	src := t.TempDir()
+	files := map[string]string{
+		"dot_gitconfig.tmpl":          `{{ template "user" .git }}{{ includeTemplate "signing" . }}`,
+		"dot_zshrc.tmpl":              "{{ if eq .chezmoi.os \"darwin\" }}brew{{ end }}\n{{ includeTemplate \"token\" }}\n",
+		"dot_broken.tmpl":             "{{ if }}",
+		".chezmoitemplates/user":      `name = {{ .name }}`,
+		".chezmoitemplates/signing":   `{{ .signingkey }}`,
+		".chezmoitemplates/token":     `{{ bitwarden "item" "gh" }}`,
+		".chezmoitemplates/unrelated": `{{ .unused }}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("TARGET", target)
+	t.Setenv("SRC", src)
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, target, `
+case "$1" in
+managed) printf '%s\n' "$TARGET/.broken" "$TARGET/.gitconfig" "$TARGET/.zshrc" ;;
+source-path)
+	shift
+	[ $# -eq 0 ] && { echo "$SRC"; exit 0; }
+	for target in "$@"; do echo "$SRC/dot_$(basename "$target" | cut -c2-).tmpl"; done
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+
+	ix, err := svc.TemplateDataIndex()
+	if err != nil {
+		t.Fatalf("TemplateDataIndex: %v", err)
+	}
+	if len(ix.Templates) != 3 {
+		t.Fatalf("expected 3 templates, got %+v", ix.Templates)
+	}
+	if broken, _ := ix.DepsOf(filepath.Join(target, ".broken")); broken.Err == nil {
+		t.Error("expected a parse error for .broken")
+	}
+	git, ok := ix.DepsOf(filepath.Join(target, ".gitconfig"))
+	if !ok || !slices.Equal(git.Keys, []string{".git.name", ".signingkey"}) {
+		t.Errorf("unexpected .gitconfig deps %+v", git)
+	}
+	zsh, _ := ix.DepsOf(filepath.Join(target, ".zshrc"))
+	if !slices.Equal(zsh.Keys, []string{".chezmoi.os"}) || !slices.Equal(zsh.Secrets, []string{"bitwarden"}) {
+		t.Errorf("unexpected .zshrc deps %+v", zsh)
+	}
+}
@@ -0,0 +1,36 @@
+package chezmoi
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	templateHistoryFile  = "template-history.json"
+	templateHistoryLimit = 50
+)
+
+// ExecuteTemplate renders text as a template against the current config and
+// data. Nothing is written, so it is allowed in read-only mode.
+func (s *Service) ExecuteTemplate(text string) (string, error) {
+	return s.client.ExecuteTemplate(text)
+}
+
+// TemplateHistory returns the expressions saved with AddTemplateHistory,
+// most recent first. A missing history file is an empty history.
+func (s *Service) TemplateHistory() ([]string, error) {
+	return readStateFile[[]string](s.dataDir, templateHistoryFile)
+}
+
+// AddTemplateHistory moves expr to the front of the saved history, keeping
+// the most recent templateHistoryLimit entries, and returns the new history.
+func (s *Service) AddTemplateHistory(expr string) ([]string, error) {
+	if strings.TrimSpace(expr) == "" {
+		return s.TemplateHistory()
+	}
+	return updateStateFile(s.dataDir, templateHistoryFile, func(history []string) []string {
+		history = slices.DeleteFunc(history, func(h string) bool { return h == expr })
+		history = append([]string{expr}, history...)
+		return history[:min(len(history), templateHistoryLimit)]
+	})
+}
@@ -0,0 +1,91 @@
+package chezmoi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestServiceExecuteTemplatePassesTextAfterDoubleDash(t *testing.T) {
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, "/home/test", `
+[ "$1" = execute-template ] && [ "$2" = -- ] || { echo "unexpected command: $*" >&2; exit 1; }
+printf '%s' "$3" | sed 's/{{ .chezmoi.os }}/linux/'
+`)
+
+	got, err := svc.ExecuteTemplate("-os: {{ .chezmoi.os }}")
+	if err != nil {
+		t.Fatalf("ExecuteTemplate: %v", err)
+	}
+	if got != "-os: linux" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestServiceTemplateHistoryKeepsMostRecentFirst(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "chezit")
+	svc := NewService(New(), chezitconfig.ModeReadOnly, "/home/test", WithDataDir(dataDir))
+
+	history, err := svc.TemplateHistory()
+	if err != nil || len(history) != 0 {
+		t.Fatalf("expected an empty history, got %v, %v", history, err)
+	}
+	for _, expr := range []string{"{{ .chezmoi.os }}", "{{ .email }}", "  ", "{{ .chezmoi.os }}"} {
+		if _, err := svc.AddTemplateHistory(expr); err != nil {
+			t.Fatalf("AddTemplateHistory(%q): %v", expr, err)
+		}
+	}
+	history, err = svc.TemplateHistory()
+	if err != nil {
+		t.Fatalf("TemplateHistory: %v", err)
+	}
+	if want := []string{"{{ .chezmoi.os }}", "{{ .email }}"}; !slices.Equal(history, want) {
+		t.Fatalf("got %q, want %q", history, want)
+	}
+
+	for i := range templateHistoryLimit + 5 {
+		if _, err := svc.AddTemplateHistory(fmt.Sprintf("{{ %d }}", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, _ = svc.TemplateHistory()
+	if len(history) != templateHistoryLimit || history[0] != fmt.Sprintf("{{ %d }}", templateHistoryLimit+4) {
+		t.Fatalf("expected the newest %d entries, got %d starting %q", templateHistoryLimit, len(history), history[0])
+	}
+}
+
+func TestServiceAddTemplateHistoryReplacesCorruptFile(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, templateHistoryFile), []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(New(), chezitconfig.ModeReadOnly, "/home/test", WithDataDir(dataDir))
+
+	if _, err := svc.TemplateHistory(); err == nil {
+		t.Fatal("expected an error for a corrupt history")
+	}
+	history, err := svc.AddTemplateHistory("{{ .email }}")
+	if err != nil || !slices.Equal(history, []string{"{{ .email }}"}) {
+		t.Fatalf("expected the corrupt history replaced, got %q, %v", history, err)
+	}
+}
+
+func TestServiceAddTemplateHistoryKeepsUnreadableFile(t *testing.T) {
+	dataDir := t.TempDir()
+	// A directory in the file's place fails to read, unlike bad JSON.
+	if err := os.Mkdir(filepath.Join(dataDir, templateHistoryFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(New(), chezitconfig.ModeReadOnly, "/home/test", WithDataDir(dataDir))
+
+	if _, err := svc.AddTemplateHistory("{{ .email }}"); err == nil || errors.Is(err, errCorruptState) {
+		t.Fatalf("expected the read error returned, got %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dataDir, templateHistoryFile)); err != nil || !info.IsDir() {
+		t.Fatalf("expected the unreadable entry left alone, got %v", err)
+	}
+}
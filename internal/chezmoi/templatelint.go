@@ -0,0 +1,115 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TemplateLintIssue is a template that failed to render.
+type TemplateLintIssue struct {
+	Target  string // target path of the failing template
+	Source  string // file the error points at: the target's source or a template it includes
+	Line    int    // 1-based; 0 when the error has no position
+	Column  int    // 1-based; 0 when unknown
+	Message string
+}
+
+// Location returns the issue position as "source:line:column", leaving out
+// the parts that are unknown.
+func (i TemplateLintIssue) Location() string {
+	loc := i.Source
+	if i.Line > 0 {
+		loc += ":" + strconv.Itoa(i.Line)
+		if i.Column > 0 {
+			loc += ":" + strconv.Itoa(i.Column)
+		}
+	}
+	return loc
+}
+
+// TemplateLintReport is the result of LintTemplates.
+type TemplateLintReport struct {
+	Checked int      // templates rendered
+	Skipped []string // encrypted template targets, which are not rendered
+	Issues  []TemplateLintIssue
+}
+
+// templateErrorPattern matches a Go template error position and message,
+// e.g. `template: dot_gitconfig.tmpl:12:5: executing ... map has no entry`.
+var templateErrorPattern = regexp.MustCompile(`template: (\S+?):(\d+)(?::(\d+))?: (.+)`)
+
+// exitStatusSuffix is the exec error appended to chezmoi's output.
+var exitStatusSuffix = regexp.MustCompile(`: exit status \d+$`)
+
+// LintTemplates renders every template target, other than scripts, on its
+// own with `chezmoi execute-template`, so one broken template is reported
+// with its position instead of failing everything that loads the source
+// state. Encrypted templates are listed in Skipped instead. Nothing is
+// written, so it is allowed in read-only mode.
+func (s *Service) LintTemplates() (TemplateLintReport, error) {
+	targets, sources, encrypted, err := s.templateSources()
+	if err != nil {
+		return TemplateLintReport{}, err
+	}
+	results := renderTemplates(targets, sources, func(target, source string) error {
+		_, err := s.client.ExecuteTemplateFile(source, "")
+		return err
+	})
+
+	report := TemplateLintReport{Checked: len(targets), Skipped: encrypted}
+	var sourceDir string
+	for i, renderErr := range results {
+		if renderErr == nil {
+			continue
+		}
+		issue := parseTemplateError(renderErr.Error())
+		issue.Target = targets[i]
+		if issue.Source != "" && !filepath.IsAbs(issue.Source) && filepath.Base(sources[i]) != issue.Source {
+			// A relative name that is not the target's own source is an
+			// included template.
+			if sourceDir == "" {
+				sourceDir, _ = s.client.SourceDir()
+			}
+			issue.Source = resolveIncludedTemplate(sourceDir, issue.Source)
+		}
+		if issue.Source == "" || !filepath.IsAbs(issue.Source) {
+			issue.Source = sources[i]
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+	return report, nil
+}
+
+// parseTemplateError extracts the template name, position, and message from
+// a failed render. Source holds the template name as written in the error.
+func parseTemplateError(text string) TemplateLintIssue {
+	match := templateErrorPattern.FindStringSubmatch(text)
+	if match == nil {
+		return TemplateLintIssue{Message: exitStatusSuffix.ReplaceAllString(strings.TrimSpace(text), "")}
+	}
+	issue := TemplateLintIssue{
+		Source:  match[1],
+		Message: exitStatusSuffix.ReplaceAllString(strings.TrimSpace(match[4]), ""),
+	}
+	issue.Line, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		issue.Column, _ = strconv.Atoi(match[3])
+	}
+	return issue
+}
+
+// resolveIncludedTemplate returns the path of a template named name in the
+// source's .chezmoitemplates directory, or "" when there is none.
+func resolveIncludedTemplate(sourceDir, name string) string {
+	if sourceDir == "" {
+		return ""
+	}
+	path := filepath.Join(sourceDir, ".chezmoitemplates", name)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
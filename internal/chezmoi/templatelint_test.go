@@ -0,0 +1,110 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestParseTemplateError(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want TemplateLintIssue
+	}{
+		{
+			name: "executing error with column",
+			text: `chezmoi execute-template: chezmoi: template: dot_gitconfig.tmpl:3:7: executing "dot_gitconfig.tmpl" at <.email>: map has no entry for key "email": exit status 1`,
+			want: TemplateLintIssue{
+				Source: "dot_gitconfig.tmpl", Line: 3, Column: 7,
+				Message: `executing "dot_gitconfig.tmpl" at <.email>: map has no entry for key "email"`,
+			},
+		},
+		{
+			name: "parse error without column",
+			text: `chezmoi execute-template: chezmoi: template: /src/dot_zshrc.tmpl:12: function "nope" not defined: exit status 1`,
+			want: TemplateLintIssue{Source: "/src/dot_zshrc.tmpl", Line: 12, Message: `function "nope" not defined`},
+		},
+		{
+			name: "no position",
+			text: "chezmoi execute-template: chezmoi: open /src/missing.tmpl: no such file or directory: exit status 1",
+			want: TemplateLintIssue{Message: "chezmoi execute-template: chezmoi: open /src/missing.tmpl: no such file or directory"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTemplateError(tt.text); got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceLintTemplatesReportsEachFailure(t *testing.T) {
+	target := t.TempDir()
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, ".chezmoitemplates"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, ".chezmoitemplates", "header"), []byte("{{ nope }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TARGET", target)
+	t.Setenv("SRC", src)
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, target, `
+case "$1" in
+managed) printf '%s\n' "$TARGET/.bashrc" "$TARGET/.gitconfig" "$TARGET/.zshrc" ;;
+source-path)
+	shift
+	[ $# -eq 0 ] && { echo "$SRC"; exit 0; }
+	for target in "$@"; do echo "$SRC/dot_$(basename "$target" | cut -c2-).tmpl"; done
+	;;
+execute-template)
+	for arg in "$@"; do path="$arg"; done
+	case "$path" in
+	*dot_gitconfig.tmpl)
+		echo 'chezmoi: template: dot_gitconfig.tmpl:3:7: executing "dot_gitconfig.tmpl" at <.email>: map has no entry for key "email"' >&2
+		exit 1
+		;;
+	*dot_zshrc.tmpl)
+		echo 'chezmoi: template: header:1: function "nope" not defined' >&2
+		exit 1
+		;;
+	*) echo "ok" ;;
+	esac
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+
+	report, err := svc.LintTemplates()
+	if err != nil {
+		t.Fatalf("LintTemplates: %v", err)
+	}
+	if report.Checked != 3 || len(report.Issues) != 2 {
+		t.Fatalf("expected 3 checked with 2 issues, got %+v", report)
+	}
+	want := []TemplateLintIssue{
+		{
+			Target: filepath.Join(target, ".gitconfig"), Source: filepath.Join(src, "dot_gitconfig.tmpl"),
+			Line: 3, Column: 7, Message: `executing "dot_gitconfig.tmpl" at <.email>: map has no entry for key "email"`,
+		},
+		{
+			Target: filepath.Join(target, ".zshrc"), Source: filepath.Join(src, ".chezmoitemplates", "header"),
+			Line: 1, Message: `function "nope" not defined`,
+		},
+	}
+	for i := range want {
+		if report.Issues[i] != want[i] {
+			t.Errorf("issue %d: got %+v, want %+v", i, report.Issues[i], want[i])
+		}
+	}
+	if loc := report.Issues[0].Location(); loc != filepath.Join(src, "dot_gitconfig.tmpl")+":3:7" {
+		t.Errorf("unexpected location %q", loc)
+	}
+	if loc := report.Issues[1].Location(); loc != filepath.Join(src, ".chezmoitemplates", "header")+":1" {
+		t.Errorf("unexpected location %q", loc)
+	}
+}
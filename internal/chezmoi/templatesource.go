@@ -0,0 +1,31 @@
+package chezmoi
+
+import (
+	"fmt"
+	"os"
+)
+
+// TemplateSource is a template target's raw source next to its rendered
+// output.
+type TemplateSource struct {
+	Path     string // template file in the source directory
+	Source   string // template text as written
+	Rendered string // output of `chezmoi cat`
+}
+
+// TemplateSourceOf reads the template behind target and renders it.
+func (s *Service) TemplateSourceOf(target string) (TemplateSource, error) {
+	paths, err := s.client.SourcePaths([]string{target})
+	if err != nil {
+		return TemplateSource{}, err
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		return TemplateSource{}, fmt.Errorf("read template source: %w", err)
+	}
+	rendered, err := s.client.CatTarget(target)
+	if err != nil {
+		return TemplateSource{}, err
+	}
+	return TemplateSource{Path: paths[0], Source: string(data), Rendered: rendered}, nil
+}
@@ -0,0 +1,38 @@
+package chezmoi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestServiceTemplateSourceOf(t *testing.T) {
+	src := t.TempDir()
+	sourcePath := filepath.Join(src, "dot_gitconfig.tmpl")
+	if err := os.WriteFile(sourcePath, []byte("[user]\n  email = {{ .email }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOURCE_PATH", sourcePath)
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, "/home/test", `
+case "$1" in
+source-path) echo "$SOURCE_PATH" ;;
+cat) printf '[user]\n  email = me@example.com\n' ;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+
+	got, err := svc.TemplateSourceOf("/home/test/.gitconfig")
+	if err != nil {
+		t.Fatalf("TemplateSourceOf: %v", err)
+	}
+	want := TemplateSource{
+		Path:     sourcePath,
+		Source:   "[user]\n  email = {{ .email }}\n",
+		Rendered: "[user]\n  email = me@example.com\n",
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
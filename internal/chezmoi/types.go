@@ -3,6 +3,7 @@ package chezmoi
 import (
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // EntryType values map to chezmoi --include/--exclude flags.
@@ -27,14 +28,19 @@ func AllEntryTypes() []EntryType {
 }
 
 type EntryFilter struct {
-	Include []EntryType // chezmoi --include flags; empty means include all
-	Exclude []EntryType // chezmoi --exclude flags
+	Include []EntryType `json:"include,omitempty"` // chezmoi --include flags; empty means include all
+	Exclude []EntryType `json:"exclude,omitempty"` // chezmoi --exclude flags
 }
 
 func (f EntryFilter) IsZero() bool {
 	return len(f.Include) == 0 && len(f.Exclude) == 0
 }
 
+// String formats the filter as the chezmoi flags it maps to.
+func (f EntryFilter) String() string {
+	return strings.Join(entryFilterArgs(f), " ")
+}
+
 func entryFilterArgs(f EntryFilter) []string {
 	var args []string
 	if len(f.Include) > 0 {
@@ -100,6 +106,102 @@ type GitFile struct {
 	StatusCode string
 }
 
+// IncomingFile is a file changed between HEAD and its upstream in the source
+// repository, mapped to the target path it updates.
+type IncomingFile struct {
+	SourcePath string // path relative to the git working tree root
+	TargetPath string // absolute target path; empty for scripts and special files
+	StatusCode string // git name-status code: A, M, D, T
+}
+
+// ApplyPlanAction classifies a change in an apply dry-run.
+type ApplyPlanAction int
+
+const (
+	PlanModify ApplyPlanAction = iota
+	PlanCreate
+	PlanDelete
+	PlanChmod
+	PlanScript
+)
+
+// String returns the lowercase verb shown in apply plans.
+func (a ApplyPlanAction) String() string {
+	switch a {
+	case PlanCreate:
+		return "create"
+	case PlanDelete:
+		return "delete"
+	case PlanChmod:
+		return "chmod"
+	case PlanScript:
+		return "script"
+	default:
+		return "modify"
+	}
+}
+
+// ApplyPlanEntry is one target change parsed from `chezmoi apply --dry-run --verbose`.
+type ApplyPlanEntry struct {
+	Path   string // absolute target path
+	Action ApplyPlanAction
+	Diff   string // the entry's section of the dry-run diff
+}
+
+// ApplyOutcome is what happened to one target during an apply.
+type ApplyOutcome int
+
+const (
+	ApplyWritten ApplyOutcome = iota
+	ApplyScriptRan
+	ApplySkipped
+	ApplyFailed
+)
+
+// String returns the lowercase word shown in apply results.
+func (o ApplyOutcome) String() string {
+	switch o {
+	case ApplyScriptRan:
+		return "ran"
+	case ApplySkipped:
+		return "skipped"
+	case ApplyFailed:
+		return "failed"
+	default:
+		return "written"
+	}
+}
+
+// ApplyResultEntry is one target parsed from `chezmoi apply --verbose`.
+type ApplyResultEntry struct {
+	Path    string // absolute target path
+	Outcome ApplyOutcome
+	Action  ApplyPlanAction // what was written: create, modify, delete, or chmod
+	Reason  string          // why the target was skipped or failed
+}
+
+// ApplyResult is the per-target outcome of one apply run.
+type ApplyResult struct {
+	Entries []ApplyResultEntry
+	Errors  []string // failures not tied to a target
+}
+
+// Count returns how many entries had outcome o.
+func (r ApplyResult) Count(o ApplyOutcome) int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty reports whether the apply left nothing to report.
+func (r ApplyResult) Empty() bool {
+	return len(r.Entries) == 0 && len(r.Errors) == 0
+}
+
 type GitInfo struct {
 	Branch string
 	Ahead  int
@@ -113,6 +215,24 @@ type GitCommit struct {
 	Message string // first line of commit message
 }
 
+// UpdateOrigin is the source commit an update or pull started from, which
+// undoing the update resets to.
+type UpdateOrigin struct {
+	Commit string    `json:"commit"`
+	Time   time.Time `json:"time"`
+	// FromReflog is set when no recorded commit was usable and Commit was
+	// found in the reflog instead; Time is then zero.
+	FromReflog bool `json:"-"`
+	// Dropped is how many commits on HEAD the reset would discard.
+	Dropped int `json:"-"`
+}
+
+// OutputLine is one line written by a streamed chezmoi command.
+type OutputLine struct {
+	Text   string
+	Stderr bool
+}
+
 // InteractiveCmd wraps commands that require TTY (edit, apply, update).
 type InteractiveCmd struct {
 	Cmd *exec.Cmd
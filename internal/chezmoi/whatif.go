@@ -0,0 +1,156 @@
+package chezmoi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/aymanbagabas/go-udiff"
+	"gopkg.in/yaml.v3"
+)
+
+// WhatIfRender is a template target rendered with the current template data
+// and again with a data overlay merged in.
+type WhatIfRender struct {
+	Target  string // target path
+	Source  string // source template
+	Current string // rendered with the current data
+	WhatIf  string // rendered with the overlay
+	Diff    string // unified diff from Current to WhatIf; "" when equal
+	Err     error  // either rendering failed
+	Skipped bool   // an encrypted template, which is not rendered
+}
+
+// Changed reports whether the overlay changes the rendered target.
+func (r WhatIfRender) Changed() bool {
+	return r.Err == nil && !r.Skipped && r.Current != r.WhatIf
+}
+
+// ParseDataOverlay parses a YAML template data overlay. It must be a
+// non-empty mapping, e.g. "chezmoi:\n  os: darwin\n".
+func ParseDataOverlay(text string) (map[string]any, error) {
+	var data map[string]any
+	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: overlay is empty", ErrInvalidData)
+	}
+	return data, nil
+}
+
+// templateRenderWorkers bounds how many templates are rendered at once.
+const templateRenderWorkers = 4
+
+// templateSources returns the template targets, other than scripts, with
+// the source file of each. Encrypted templates are returned apart in
+// encrypted: their source files hold ciphertext, so they can be neither
+// rendered on their own nor scanned.
+func (s *Service) templateSources() (targets, sources, encrypted []string, err error) {
+	all, err := s.client.ManagedWithFilter(EntryFilter{
+		Include: []EntryType{EntryTemplates},
+		Exclude: []EntryType{EntryScripts},
+	})
+	if err != nil || len(all) == 0 {
+		return nil, nil, nil, err
+	}
+	targets, err = s.client.ManagedWithFilter(EntryFilter{
+		Include: []EntryType{EntryTemplates},
+		Exclude: []EntryType{EntryScripts, EntryEncrypted},
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, target := range all {
+		if !slices.Contains(targets, target) {
+			encrypted = append(encrypted, target)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, nil, encrypted, nil
+	}
+	sources, err = s.client.SourcePaths(targets)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return targets, sources, encrypted, nil
+}
+
+// renderTemplates calls render for each target and source, a few at a
+// time, and returns the results in target order.
+func renderTemplates[T any](targets, sources []string, render func(target, source string) T) []T {
+	results := make([]T, len(targets))
+	sem := make(chan struct{}, templateRenderWorkers)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = render(target, sources[i])
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// WhatIfTemplates renders every template target twice with
+// `chezmoi execute-template`: once with the current data and once with data
+// merged over it from a temporary --override-data-file. Script templates are
+// left out and encrypted ones follow the rest, marked Skipped. Nothing is written outside the temporary file, so it is allowed
+// in read-only mode.
+func (s *Service) WhatIfTemplates(data map[string]any) ([]WhatIfRender, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: overlay is empty", ErrInvalidData)
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	targets, sources, encrypted, err := s.templateSources()
+	if err != nil || len(targets)+len(encrypted) == 0 {
+		return nil, err
+	}
+
+	dataFile, err := os.CreateTemp("", "chezit-whatif-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("what-if: %w", err)
+	}
+	defer os.Remove(dataFile.Name())
+	_, err = dataFile.Write(encoded)
+	if closeErr := dataFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("what-if: %w", err)
+	}
+
+	renders := renderTemplates(targets, sources, func(target, source string) WhatIfRender {
+		return s.renderWhatIf(target, source, dataFile.Name())
+	})
+	for _, target := range encrypted {
+		renders = append(renders, WhatIfRender{Target: target, Skipped: true})
+	}
+	return renders, nil
+}
+
+func (s *Service) renderWhatIf(target, source, dataFile string) WhatIfRender {
+	r := WhatIfRender{Target: target, Source: source}
+	if r.Current, r.Err = s.client.ExecuteTemplateFile(source, ""); r.Err != nil {
+		return r
+	}
+	if r.WhatIf, r.Err = s.client.ExecuteTemplateFile(source, dataFile); r.Err != nil {
+		return r
+	}
+	if r.Current != r.WhatIf {
+		rel, err := filepath.Rel(s.TargetPath(), target)
+		if err != nil {
+			rel = filepath.Base(target)
+		}
+		rel = filepath.ToSlash(rel)
+		r.Diff = udiff.Unified("current/"+rel, "what-if/"+rel, r.Current, r.WhatIf)
+	}
+	return r
+}
@@ -0,0 +1,149 @@
+package chezmoi
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chezitconfig "github.com/daptify14/chezit/internal/config"
+)
+
+func TestParseDataOverlay(t *testing.T) {
+	data, err := ParseDataOverlay("chezmoi:\n  os: darwin\nemail: me@work.example\n")
+	if err != nil {
+		t.Fatalf("ParseDataOverlay: %v", err)
+	}
+	if data["email"] != "me@work.example" {
+		t.Fatalf("unexpected data %v", data)
+	}
+	if chezmoi, ok := data["chezmoi"].(map[string]any); !ok || chezmoi["os"] != "darwin" {
+		t.Fatalf("expected nested chezmoi data, got %v", data)
+	}
+
+	for _, text := range []string{"", "  \n", "- a\n- b\n", "chezmoi: [\n"} {
+		if _, err := ParseDataOverlay(text); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("ParseDataOverlay(%q): expected ErrInvalidData, got %v", text, err)
+		}
+	}
+}
+
+func TestServiceWhatIfTemplatesDiffsAgainstCurrentData(t *testing.T) {
+	target := t.TempDir()
+	t.Setenv("TARGET", target)
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, target, `
+case "$1" in
+managed)
+	case "$*" in
+	*--include=templates*--exclude=scripts*) ;;
+	*) echo "unexpected filter: $*" >&2; exit 1 ;;
+	esac
+	printf '%s\n' "$TARGET/.gitconfig" "$TARGET/.zshrc" "$TARGET/.ssh/config"
+	;;
+source-path)
+	shift
+	for target in "$@"; do echo "/src/$(basename "$target").tmpl"; done
+	;;
+execute-template)
+	data=""
+	for arg in "$@"; do
+		case "$arg" in
+		--override-data-file=*) data=$(cat "${arg#--override-data-file=}") ;;
+		esac
+		path="$arg"
+	done
+	case "$path" in
+	/src/.gitconfig.tmpl)
+		case "$data" in
+		*darwin*) echo "os = darwin" ;;
+		*) echo "os = linux" ;;
+		esac
+		;;
+	/src/.zshrc.tmpl) echo "same" ;;
+	/src/config.tmpl)
+		[ -z "$data" ] || { echo "map has no entry for key \"work\"" >&2; exit 1; }
+		echo "ssh"
+		;;
+	esac
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+
+	renders, err := svc.WhatIfTemplates(map[string]any{"chezmoi": map[string]any{"os": "darwin"}})
+	if err != nil {
+		t.Fatalf("WhatIfTemplates: %v", err)
+	}
+	if len(renders) != 3 {
+		t.Fatalf("expected 3 renders, got %+v", renders)
+	}
+
+	gitconfig := renders[0]
+	if gitconfig.Target != filepath.Join(target, ".gitconfig") || gitconfig.Source != "/src/.gitconfig.tmpl" {
+		t.Fatalf("unexpected render %+v", gitconfig)
+	}
+	if !gitconfig.Changed() || !strings.Contains(gitconfig.Diff, "-os = linux") || !strings.Contains(gitconfig.Diff, "+os = darwin") {
+		t.Fatalf("expected the overlay to change .gitconfig, got %+v", gitconfig)
+	}
+	if !strings.Contains(gitconfig.Diff, "what-if/.gitconfig") {
+		t.Fatalf("expected diff labels relative to the target, got:\n%s", gitconfig.Diff)
+	}
+
+	if zshrc := renders[1]; zshrc.Changed() || zshrc.Diff != "" || zshrc.Err != nil {
+		t.Fatalf("expected .zshrc unchanged, got %+v", zshrc)
+	}
+	if ssh := renders[2]; ssh.Err == nil || ssh.Changed() || !strings.Contains(ssh.Err.Error(), "no entry for key") {
+		t.Fatalf("expected the overlay rendering error reported, got %+v", ssh)
+	}
+}
+
+func TestServiceWhatIfTemplatesRejectsEmptyOverlay(t *testing.T) {
+	svc := NewService(New(WithBinaryPath("/bin/true")), chezitconfig.ModeReadOnly, "/home/test")
+	if _, err := svc.WhatIfTemplates(nil); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("expected ErrInvalidData, got %v", err)
+	}
+}
+
+func TestServiceTemplateRendersSkipEncryptedTemplates(t *testing.T) {
+	target := t.TempDir()
+	t.Setenv("TARGET", target)
+	svc := newFakeService(t, chezitconfig.ModeReadOnly, target, `
+case "$1" in
+managed)
+	case "$*" in
+	*--exclude=scripts,encrypted*) printf '%s\n' "$TARGET/.gitconfig" ;;
+	*) printf '%s\n' "$TARGET/.gitconfig" "$TARGET/.netrc" ;;
+	esac
+	;;
+source-path)
+	shift
+	for target in "$@"; do echo "/src/$(basename "$target").tmpl"; done
+	;;
+execute-template)
+	for arg in "$@"; do path="$arg"; done
+	case "$path" in
+	/src/.gitconfig.tmpl) echo "os = linux" ;;
+	*) echo "cannot render ciphertext: $path" >&2; exit 1 ;;
+	esac
+	;;
+*) echo "unexpected command: $*" >&2; exit 1 ;;
+esac
+`)
+	netrc := filepath.Join(target, ".netrc")
+
+	renders, err := svc.WhatIfTemplates(map[string]any{"work": true})
+	if err != nil {
+		t.Fatalf("WhatIfTemplates: %v", err)
+	}
+	if len(renders) != 2 || renders[0].Err != nil || renders[1].Target != netrc || !renders[1].Skipped || renders[1].Err != nil {
+		t.Fatalf("expected the encrypted template reported as skipped, got %+v", renders)
+	}
+
+	report, err := svc.LintTemplates()
+	if err != nil {
+		t.Fatalf("LintTemplates: %v", err)
+	}
+	if report.Checked != 1 || len(report.Issues) != 0 || len(report.Skipped) != 1 || report.Skipped[0] != netrc {
+		t.Fatalf("expected the encrypted template skipped, got %+v", report)
+	}
+}
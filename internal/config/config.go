@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -39,12 +41,32 @@ type Config struct {
 	ChezmoiConfig string   `yaml:"chezmoi_config_path"`
 	CommitPresets []string `yaml:"commit_presets"`
 	DiffBuiltin   bool     `yaml:"diff_builtin"`
+
+	// Pre-apply backup retention; 0 keeps runs without limit.
+	BackupKeepRuns   int `yaml:"backup_keep_runs"`
+	BackupMaxAgeDays int `yaml:"backup_max_age_days"`
+
+	// Named template data overlays offered by What-If Data, e.g. another
+	// machine's chezmoi.os and chezmoi.hostname.
+	DataProfiles map[string]map[string]any `yaml:"data_profiles"`
+
+	// Findings the secret scan before add and commit lets through.
+	SecretAllowlist []SecretAllowRule `yaml:"secret_allowlist"`
+}
+
+// SecretAllowRule matches the findings it allows on every field it sets.
+type SecretAllowRule struct {
+	Rule    string `yaml:"rule"`    // rule ID, e.g. "high-entropy"
+	Path    string `yaml:"path"`    // glob on the target or source path, e.g. ".config/**/config.toml"
+	Pattern string `yaml:"pattern"` // regular expression matched against the line
 }
 
 func Default() Config {
 	return Config{
-		Icons: "nerdfont",
-		Mode:  ModeWrite,
+		Icons:            "nerdfont",
+		Mode:             ModeWrite,
+		BackupKeepRuns:   20,
+		BackupMaxAgeDays: 30,
 	}
 }
 
@@ -80,6 +102,28 @@ func (c Config) Validate() error {
 	if _, err := ParseMode(string(c.Mode)); err != nil {
 		return err
 	}
+	if c.BackupKeepRuns < 0 {
+		return fmt.Errorf("invalid backup_keep_runs %d (must be 0 or more)", c.BackupKeepRuns)
+	}
+	if c.BackupMaxAgeDays < 0 {
+		return fmt.Errorf("invalid backup_max_age_days %d (must be 0 or more)", c.BackupMaxAgeDays)
+	}
+	for name, data := range c.DataProfiles {
+		if strings.TrimSpace(name) == "" || len(data) == 0 {
+			return fmt.Errorf("invalid data_profiles entry %q (needs a name and data)", name)
+		}
+	}
+	for i, rule := range c.SecretAllowlist {
+		if rule.Rule == "" && rule.Path == "" && rule.Pattern == "" {
+			return fmt.Errorf("invalid secret_allowlist entry %d (needs a rule, path, or pattern)", i+1)
+		}
+		if _, err := path.Match(rule.Path, ""); err != nil {
+			return fmt.Errorf("invalid secret_allowlist entry %d path %q: %w", i+1, rule.Path, err)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid secret_allowlist entry %d pattern: %w", i+1, err)
+		}
+	}
 	return nil
 }
 
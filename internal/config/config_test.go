@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -107,6 +108,111 @@ func TestLoadFromDiffBuiltinDefaultsFalse(t *testing.T) {
 	}
 }
 
+func TestLoadFromParsesBackupRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+backup_keep_runs: 5
+backup_max_age_days: 0
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if cfg.BackupKeepRuns != 5 || cfg.BackupMaxAgeDays != 0 {
+		t.Fatalf("unexpected backup retention: %d runs, %d days", cfg.BackupKeepRuns, cfg.BackupMaxAgeDays)
+	}
+}
+
+func TestLoadFromInvalidBackupRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+backup_keep_runs: -1
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := LoadFrom(path); err == nil {
+		t.Fatalf("expected error for negative backup_keep_runs")
+	}
+}
+
+func TestLoadFromParsesDataProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+data_profiles:
+  work-mac:
+    chezmoi:
+      os: darwin
+      hostname: work-mbp
+    email: me@work.example
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	profile := cfg.DataProfiles["work-mac"]
+	chezmoi, ok := profile["chezmoi"].(map[string]any)
+	if !ok || chezmoi["os"] != "darwin" || profile["email"] != "me@work.example" {
+		t.Fatalf("unexpected data profiles: %v", cfg.DataProfiles)
+	}
+}
+
+func TestLoadFromInvalidDataProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+data_profiles:
+  empty: {}
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := LoadFrom(path); err == nil {
+		t.Fatalf("expected error for an empty data profile")
+	}
+}
+
+func TestLoadFromParsesSecretAllowlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+secret_allowlist:
+  - rule: high-entropy
+    path: .config/app/*.toml
+  - pattern: EXAMPLE
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	want := []SecretAllowRule{
+		{Rule: "high-entropy", Path: ".config/app/*.toml"},
+		{Pattern: "EXAMPLE"},
+	}
+	if !reflect.DeepEqual(cfg.SecretAllowlist, want) {
+		t.Fatalf("unexpected secret allowlist: %+v", cfg.SecretAllowlist)
+	}
+}
+
+func TestLoadFromInvalidSecretAllowlist(t *testing.T) {
+	for _, entry := range []string{"{}", "{pattern: '('}", "{path: '['}"} {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("secret_allowlist:\n  - "+entry+"\n"), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if _, err := LoadFrom(path); err == nil {
+			t.Errorf("expected error for secret_allowlist entry %s", entry)
+		}
+	}
+}
+
 func TestNormalizeIconsTrimsAndLowercases(t *testing.T) {
 	cfg := Config{
 		Icons: "  NerdFont  ",
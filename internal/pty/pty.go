@@ -0,0 +1,8 @@
+// Package pty starts commands attached to a pseudo-terminal so their
+// interactive prompts can be driven from inside the TUI.
+package pty
+
+import "errors"
+
+// ErrUnsupported is returned on platforms without pseudo-terminal support.
+var ErrUnsupported = errors.New("pty: unsupported platform")
@@ -0,0 +1,38 @@
+package pty
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// open allocates a pseudo-terminal pair and returns the controlling side
+// along with the path of the terminal side.
+func open() (*os.File, string, error) {
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("pty: open /dev/ptmx: %w", err)
+	}
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+		_ = unix.Close(fd)
+		return nil, "", fmt.Errorf("pty: grant: %w", err)
+	}
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+		_ = unix.Close(fd)
+		return nil, "", fmt.Errorf("pty: unlock: %w", err)
+	}
+	// TIOCPTYGNAME fills a 128-byte buffer with the terminal side's path.
+	name := make([]byte, 128)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+		_ = unix.Close(fd)
+		return nil, "", fmt.Errorf("pty: ptsname: %w", errno)
+	}
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	return os.NewFile(uintptr(fd), "/dev/ptmx"), string(name), nil
+}
@@ -0,0 +1,26 @@
+//go:build !linux && !darwin
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Supported reports whether Start can allocate a pseudo-terminal here.
+const Supported = false
+
+// Start always fails with ErrUnsupported on this platform.
+func Start(*exec.Cmd, int, int) (*os.File, error) {
+	return nil, ErrUnsupported
+}
+
+// Kill always fails with ErrUnsupported on this platform.
+func Kill(*exec.Cmd) error {
+	return ErrUnsupported
+}
+
+// Resize always fails with ErrUnsupported on this platform.
+func Resize(*os.File, int, int) error {
+	return ErrUnsupported
+}
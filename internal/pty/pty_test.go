@@ -0,0 +1,82 @@
+//go:build linux || darwin
+
+package pty
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestStartAttachesTerminal(t *testing.T) {
+	cmd := exec.Command("sh", "-c", `test -t 0 && test -t 1 && printf 'tty ok'; read reply; printf ' got:%s' "$reply"`)
+	ptm, err := Start(cmd, 80, 24)
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	defer ptm.Close()
+
+	if _, err := ptm.WriteString("yes\r"); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	var out strings.Builder
+	buf := make([]byte, 1024)
+	for {
+		n, readErr := ptm.Read(buf)
+		out.Write(buf[:n])
+		if readErr != nil {
+			if readErr != io.EOF && !strings.Contains(readErr.Error(), "input/output error") {
+				t.Fatalf("read output: %v", readErr)
+			}
+			break
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("command failed: %v (output %q)", err, out.String())
+	}
+	if !strings.Contains(out.String(), "tty ok") {
+		t.Fatalf("expected command to see a terminal, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "got:yes") {
+		t.Fatalf("expected forwarded input, got %q", out.String())
+	}
+}
+
+func TestResizeSetsWindowSize(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 0.2; stty size")
+	ptm, err := Start(cmd, 80, 24)
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	defer ptm.Close()
+	if err := Resize(ptm, 100, 30); err != nil {
+		t.Fatalf("Resize returned error: %v", err)
+	}
+
+	out, _ := io.ReadAll(ptm)
+	_ = cmd.Wait()
+	if !strings.Contains(string(out), "30 100") {
+		t.Fatalf("expected resized window 30 100, got %q", out)
+	}
+}
+
+func TestKillEndsProcessGroup(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30 & wait")
+	ptm, err := Start(cmd, 80, 24)
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	defer ptm.Close()
+
+	if err := Kill(cmd); err != nil {
+		t.Fatalf("Kill returned error: %v", err)
+	}
+	// The backgrounded sleep holds the terminal open; reads only end once
+	// it is gone too.
+	_, _ = io.ReadAll(ptm)
+	if err := cmd.Wait(); err == nil {
+		t.Fatal("expected the killed command to report an error")
+	}
+}
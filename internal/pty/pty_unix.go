@@ -0,0 +1,79 @@
+//go:build linux || darwin
+
+package pty
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Supported reports whether Start can allocate a pseudo-terminal here.
+const Supported = true
+
+// Start runs cmd with its stdin, stdout, and stderr attached to a new
+// pseudo-terminal of the given size and returns the controlling side.
+// Reads from the returned file yield the command's output; writes are
+// delivered as keyboard input. The caller must Wait on cmd and close the
+// returned file.
+func Start(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	ptm, ptsName, err := open()
+	if err != nil {
+		return nil, err
+	}
+	if err := Resize(ptm, cols, rows); err != nil {
+		_ = ptm.Close()
+		return nil, err
+	}
+	pts, err := os.OpenFile(ptsName, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = ptm.Close()
+		return nil, fmt.Errorf("pty: open %s: %w", ptsName, err)
+	}
+	// The child only needs the terminal side; close our copy once it has
+	// started so reads from ptm end when the child exits.
+	defer pts.Close()
+
+	cmd.Stdin = pts
+	cmd.Stdout = pts
+	cmd.Stderr = pts
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	if err := cmd.Start(); err != nil {
+		_ = ptm.Close()
+		return nil, err
+	}
+	return ptm, nil
+}
+
+// Kill ends a command started by Start along with everything it spawned.
+// The command leads its own session, so its process group also holds any
+// scripts or sudo prompts it is waiting on.
+func Kill(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return unix.Kill(-cmd.Process.Pid, unix.SIGKILL)
+}
+
+// Resize sets the window size of the pseudo-terminal behind ptm.
+func Resize(ptm *os.File, cols, rows int) error {
+	ws := &unix.Winsize{Col: uint16(max(cols, 1)), Row: uint16(max(rows, 1))} //nolint:gosec // clamped terminal dimensions
+	raw, err := ptm.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ioctlErr error
+	if err := raw.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetWinsize(int(fd), unix.TIOCSWINSZ, ws)
+	}); err != nil {
+		return err
+	}
+	return ioctlErr
+}
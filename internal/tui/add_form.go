@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// Keys of the flags multi-select in the add form.
+const (
+	addFlagEncrypt          = "encrypt"
+	addFlagTemplate         = "template"
+	addFlagAutoTemplate     = "autotemplate"
+	addFlagCreate           = "create"
+	addFlagFollow           = "follow"
+	addFlagTemplateSymlinks = "template-symlinks"
+	addFlagPrompt           = "prompt"
+	addFlagExact            = "exact"
+	addFlagNoRecursive      = "no-recursive"
+	addFlagAllowSecrets     = "allow-secrets"
+)
+
+// addFormState holds the Add With Options form for one target.
+type addFormState struct {
+	form   *huh.Form
+	path   string
+	isDir  bool
+	values *addFormValues // bound to the form fields
+}
+
+// addFormValues are the form's field values; a pointer so the bindings
+// survive Model copies.
+type addFormValues struct {
+	flags   []string
+	secrets string
+	include []chezmoi.EntryType
+	exclude []chezmoi.EntryType
+}
+
+func newAddFormValues(opts chezmoi.AddOptions) *addFormValues {
+	v := &addFormValues{
+		secrets: opts.Secrets,
+		include: slices.Clone(opts.Filter.Include),
+		exclude: slices.Clone(opts.Filter.Exclude),
+	}
+	for flag, set := range map[string]bool{
+		addFlagEncrypt:          opts.Encrypt,
+		addFlagTemplate:         opts.Template,
+		addFlagAutoTemplate:     opts.AutoTemplate,
+		addFlagCreate:           opts.Create,
+		addFlagFollow:           opts.Follow,
+		addFlagTemplateSymlinks: opts.TemplateSymlinks,
+		addFlagPrompt:           opts.Prompt,
+		addFlagExact:            opts.Exact,
+		addFlagNoRecursive:      opts.NoRecursive,
+		addFlagAllowSecrets:     opts.AllowSecrets,
+	} {
+		if set {
+			v.flags = append(v.flags, flag)
+		}
+	}
+	return v
+}
+
+// options converts the form values back to AddOptions. Directory-only
+// flags are dropped for files.
+func (v *addFormValues) options(isDir bool) chezmoi.AddOptions {
+	has := func(flag string) bool { return slices.Contains(v.flags, flag) }
+	opts := chezmoi.AddOptions{
+		Encrypt:          has(addFlagEncrypt),
+		Template:         has(addFlagTemplate),
+		AutoTemplate:     has(addFlagAutoTemplate),
+		Create:           has(addFlagCreate),
+		Follow:           has(addFlagFollow),
+		TemplateSymlinks: has(addFlagTemplateSymlinks),
+		Prompt:           has(addFlagPrompt),
+		Secrets:          v.secrets,
+		AllowSecrets:     has(addFlagAllowSecrets),
+	}
+	if isDir {
+		opts.Exact = has(addFlagExact)
+		opts.NoRecursive = has(addFlagNoRecursive)
+		opts.Filter = chezmoi.EntryFilter{Include: slices.Clone(v.include), Exclude: slices.Clone(v.exclude)}
+	}
+	return opts
+}
+
+// addOptionsCmd loads the options last used in path's directory.
+func (m Model) addOptionsCmd(path string) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return addOptionsLoadedMsg{path: path, err: err}
+		}
+		opts, err := svc.AddOptionsFor(filepath.Dir(path))
+		return addOptionsLoadedMsg{path: path, isDir: info.IsDir(), opts: opts, err: err}
+	}
+}
+
+func (m Model) handleAddOptionsLoaded(msg addOptionsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		// Fall back to the defaults; the form is still usable.
+		m.ui.message = "Error: " + msg.err.Error()
+	}
+	m.view = AddFormScreen
+	m.actions.show = false
+	m.addForm = addFormState{path: msg.path, isDir: msg.isDir, values: newAddFormValues(msg.opts)}
+	m.addForm.form = m.buildAddForm()
+	return m, m.addForm.form.Init()
+}
+
+// buildAddForm creates the flag, --secrets, and (for directories) entry
+// type fields, each checked with AddOptions.Validate as it is left.
+func (m Model) buildAddForm() *huh.Form {
+	s := m.addForm
+	values, isDir := s.values, s.isDir
+	validateFlags := func([]string) error { return values.options(isDir).Validate() }
+	validateTypes := func([]chezmoi.EntryType) error { return values.options(isDir).Validate() }
+
+	flags := []huh.Option[string]{
+		huh.NewOption("Encrypt (--encrypt)", addFlagEncrypt),
+		huh.NewOption("Template (--template)", addFlagTemplate),
+		huh.NewOption("Auto template (--autotemplate)", addFlagAutoTemplate),
+		huh.NewOption("Create only if missing (--create)", addFlagCreate),
+		huh.NewOption("Follow symlinks (--follow)", addFlagFollow),
+		huh.NewOption("Template symlinks (--template-symlinks)", addFlagTemplateSymlinks),
+		huh.NewOption("Prompt per file (--prompt)", addFlagPrompt),
+	}
+	if isDir {
+		flags = append(flags,
+			huh.NewOption("Exact (--exact)", addFlagExact),
+			huh.NewOption("Shallow (--recursive=false)", addFlagNoRecursive),
+		)
+	}
+	flags = append(flags, huh.NewOption("Skip chezit secret scan", addFlagAllowSecrets))
+
+	secrets := []huh.Option[string]{huh.NewOption("chezmoi config", "")}
+	for _, mode := range chezmoi.AddSecretsModes {
+		secrets = append(secrets, huh.NewOption(mode, mode))
+	}
+
+	groups := []*huh.Group{
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Add "+shortenPath(s.path, m.targetPath)).
+				Options(flags...).
+				Height(len(flags)+1).
+				Value(&values.flags).
+				Validate(validateFlags),
+			huh.NewSelect[string]().
+				Title("chezmoi secret check (--secrets)").
+				Options(secrets...).
+				Value(&values.secrets),
+		),
+	}
+	if isDir {
+		types := make([]huh.Option[chezmoi.EntryType], 0, len(chezmoi.AllEntryTypes()))
+		for _, t := range chezmoi.AllEntryTypes() {
+			types = append(types, huh.NewOption(string(t), t))
+		}
+		groups = append(groups, huh.NewGroup(
+			huh.NewMultiSelect[chezmoi.EntryType]().
+				Title("Include entry types (--include)").
+				Description("None selected adds every type").
+				Options(types...).
+				Value(&values.include).
+				Validate(validateTypes),
+			huh.NewMultiSelect[chezmoi.EntryType]().
+				Title("Exclude entry types (--exclude)").
+				Options(types...).
+				Value(&values.exclude).
+				Validate(validateTypes),
+		))
+	}
+
+	return huh.NewForm(groups...).
+		WithTheme(huh.ThemeFunc(huh.ThemeCatppuccin)).
+		WithKeyMap(whatIfFormKeyMap()).
+		WithWidth(56).
+		WithShowHelp(false)
+}
+
+// handleAddFormUpdate routes messages to the add form. On submit it
+// remembers the options for the directory and shows the add preview.
+func (m Model) handleAddFormUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.addForm.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.addForm.form = f
+	}
+
+	switch m.addForm.form.State {
+	case huh.StateCompleted:
+		path, opts := m.addForm.path, m.addForm.values.options(m.addForm.isDir)
+		m.addForm = addFormState{}
+		m.view = StatusScreen
+		if err := opts.Validate(); err != nil {
+			m.ui.message = "Error: " + err.Error()
+			return m, nil
+		}
+		m.ui.busyAction = true
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.rememberAddOptionsCmd(path, opts), m.addPreviewCmd(path, opts))
+	case huh.StateAborted:
+		m.addForm = addFormState{}
+		m.view = StatusScreen
+		return m, nil
+	}
+	return m, cmd
+}
+
+// rememberAddOptionsCmd saves opts as the defaults for path's directory.
+func (m Model) rememberAddOptionsCmd(path string, opts chezmoi.AddOptions) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		return addOptionsSavedMsg{err: svc.RememberAddOptions(filepath.Dir(path), opts)}
+	}
+}
+
+// handleAddOptionsSaved reports a failure to remember the options; the add
+// itself goes ahead.
+func (m Model) handleAddOptionsSaved(msg addOptionsSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+	}
+	return m, nil
+}
+
+// renderAddFormScreen wraps the add form in a centered box.
+func (m Model) renderAddFormScreen() string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.Primary).
+		Padding(1, 2).
+		Width(60)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(m.addForm.form.View()))
+}
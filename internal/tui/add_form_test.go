@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func TestAddFormValidatesAndPreviews(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // remembered options live in the data dir
+	target := t.TempDir()
+	path := filepath.Join(target, ".bashrc")
+	if err := os.WriteFile(path, []byte("alias ll='ls -l'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(WithTarget(target), WithSize(120, 40))
+
+	m, _ = sendMsg(t, m, m.addOptionsCmd(path)())
+	if m.view != AddFormScreen || m.addForm.isDir {
+		t.Fatalf("expected the add form for a file, got view %v", m.view)
+	}
+	screen := ansi.Strip(m.renderAddFormScreen())
+	for _, want := range []string{"Add ~/.bashrc", "--create", "--secrets"} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("expected %q in the form, got:\n%s", want, screen)
+		}
+	}
+	if strings.Contains(screen, "--exact") {
+		t.Fatal("expected directory-only flags hidden for a file")
+	}
+
+	// Encrypt, then Template: the combination is rejected.
+	m, _ = sendKey(t, m, runeKey("x"))
+	m, _ = sendKey(t, m, runeKey("j"))
+	m, _ = sendKey(t, m, runeKey("x"))
+	if screen := ansi.Strip(m.renderAddFormScreen()); !strings.Contains(screen, "only one of") {
+		t.Fatalf("expected the validation error, got:\n%s", screen)
+	}
+	m, _ = sendKey(t, m, runeKey("k"))
+	m, _ = sendKey(t, m, runeKey("x"))
+
+	m, cmd := submitAddForm(t, m)
+	if m.view != StatusScreen || !m.ui.busyAction || cmd == nil {
+		t.Fatalf("expected the form submitted, got view %v", m.view)
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c != nil {
+			m, _ = sendMsg(t, m, c())
+		}
+	}
+	if m.view != DiffScreen || m.diff.previewAdd == nil || !m.diff.previewAdd.opts.Template {
+		t.Fatalf("expected the preview of a template add, got view %v", m.view)
+	}
+	if opts, err := m.service.AddOptionsFor(target); err != nil || !opts.Template || opts.Encrypt {
+		t.Fatalf("expected the options remembered for the directory, got %+v, %v", opts, err)
+	}
+}
+
+// submitAddForm presses Enter through every field, feeding the form its
+// own focus commands, until it closes.
+func submitAddForm(t *testing.T, m Model) (Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for range 10 {
+		if m.view != AddFormScreen {
+			break
+		}
+		m, cmd = sendKey(t, m, specialKey(tea.KeyEnter))
+		for cmd != nil && m.view == AddFormScreen {
+			msg := cmd()
+			if _, ok := msg.(tea.BatchMsg); ok {
+				break
+			}
+			m, cmd = sendMsg(t, m, msg)
+		}
+	}
+	return m, cmd
+}
+
+func TestAddFormValuesRoundTrip(t *testing.T) {
+	opts := chezmoi.AddOptions{
+		Encrypt:     true,
+		Exact:       true,
+		Secrets:     "error",
+		Filter:      chezmoi.EntryFilter{Exclude: []chezmoi.EntryType{chezmoi.EntryScripts}},
+		NoRecursive: true,
+	}
+	values := newAddFormValues(opts)
+	if got := values.options(true); !got.Encrypt || !got.Exact || !got.NoRecursive || got.Secrets != "error" ||
+		!slices.Equal(got.Filter.Exclude, opts.Filter.Exclude) {
+		t.Fatalf("unexpected options %+v", got)
+	}
+	if got := values.options(false); got.Exact || got.NoRecursive || got.Filter.Exclude != nil {
+		t.Fatalf("expected directory-only options dropped for a file, got %+v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// addPreviewRequest is an add waiting on its preview to be confirmed.
+type addPreviewRequest struct {
+	path string
+	opts chezmoi.AddOptions
+}
+
+// addAttributeHelp explains each source name attribute in the add preview.
+var addAttributeHelp = map[string]string{
+	"encrypted_":  "encrypted in the source state",
+	"private_":    "not readable by group or others",
+	"readonly_":   "not writable",
+	"create_":     "only created if missing, never overwritten",
+	"empty_":      "kept even though it is empty",
+	"executable_": "executable",
+	"symlink_":    "symlink",
+	"exact_":      "untracked files inside are removed on apply",
+	"dot_":        "name starts with a dot",
+	"literal_":    "rest of the name is not read as attributes",
+	".tmpl":       "rendered as a template",
+	".literal":    "suffix is part of the name",
+}
+
+// addPreviewCmd works out what adding path with opts would create, for
+// the user to confirm before anything is written.
+func (m Model) addPreviewCmd(path string, opts chezmoi.AddOptions) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		preview, err := svc.PreviewAdd(path, opts)
+		return addPreviewLoadedMsg{request: addPreviewRequest{path: path, opts: opts}, preview: preview, err: err}
+	}
+}
+
+func (m Model) handleAddPreviewLoaded(msg addPreviewLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	request := msg.request
+	lines := m.addPreviewLines(msg.preview, request.opts)
+	m.view = DiffScreen
+	m.diff.previewAdd = &request
+	m.diff.content = strings.Join(lines, "\n")
+	m.diff.path = msg.preview.Target
+	m.diff.rawLines = lines
+	m.diff.lines = lines
+	m.diff.pagerApplied = false
+	m.diff.resetViewport()
+	m.actions.show = false
+	return m, nil
+}
+
+// addPreviewLines describes the source entry an add creates, followed by
+// the --autotemplate diff.
+func (m Model) addPreviewLines(preview chezmoi.AddPreview, opts chezmoi.AddOptions) []string {
+	lines := []string{
+		"Target: " + shortenPath(preview.Target, m.targetPath),
+		"Source: " + preview.SourcePath,
+	}
+	for _, dir := range preview.NewDirs {
+		lines = append(lines, "New directory: "+dir)
+	}
+	if len(preview.Attributes) > 0 {
+		lines = append(lines, "", "Attributes:")
+		for _, attr := range preview.Attributes {
+			help := addAttributeHelp[attr]
+			if help == "" {
+				help = "encryption suffix"
+			}
+			lines = append(lines, fmt.Sprintf("  %-12s %s", attr, help))
+		}
+	}
+	if !opts.AutoTemplate {
+		return lines
+	}
+
+	lines = append(lines, "")
+	if len(preview.Substitutions) == 0 {
+		return append(lines, "No template data values found; the file is added as a template unchanged.")
+	}
+	lines = append(lines, "Template variables:")
+	for _, sub := range preview.Substitutions {
+		lines = append(lines, fmt.Sprintf("  {{ .%s }} ← %q ×%d", sub.Variable, sub.Value, sub.Count))
+	}
+	lines = append(lines, "")
+	return append(lines, strings.Split(strings.TrimRight(preview.Diff, "\n"), "\n")...)
+}
+
+// confirmAddPreview queues the add the preview was shown for. An add with
+// --prompt runs in the terminal pane instead, since chezmoi asks about
+// each file.
+func (m Model) confirmAddPreview() (tea.Model, tea.Cmd) {
+	request := *m.diff.previewAdd
+	m.diff.previewAdd = nil
+	m.diff.clear()
+	m.view = StatusScreen
+	if request.opts.Prompt {
+		cmd, err := m.service.AddCmd(request.path, request.opts)
+		if err != nil {
+			m.ui.message = "Error: " + err.Error()
+			return m, nil
+		}
+		return m, m.terminalExecCmd(chezmoiActionAdd, "chezmoi add --prompt", cmd, wrapWithPressEnter(cmd), "chezmoi: add not supported")
+	}
+	scope := writerJobScope(request.path)
+	return m, m.enqueueJob("add "+shortenPath(request.path, m.targetPath), chezmoiActionAdd, scope, m.addFileJob(request.path, request.opts))
+}
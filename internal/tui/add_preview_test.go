@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func TestAddPreviewConfirmQueuesAdd(t *testing.T) {
+	target := t.TempDir()
+	path := filepath.Join(target, ".bashrc")
+	if err := os.WriteFile(path, []byte("alias ll='ls -l'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(WithTarget(target), WithSize(120, 30))
+
+	m, _ = sendMsg(t, m, m.addPreviewCmd(path, chezmoi.AddOptions{Template: true})())
+	if m.view != DiffScreen || m.diff.previewAdd == nil {
+		t.Fatalf("expected the add preview, got view %v", m.view)
+	}
+	screen := ansi.Strip(m.renderDiffView())
+	for _, want := range []string{"Source: dot_bashrc.tmpl", "rendered as a template", "enter add"} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("expected %q in the preview, got:\n%s", want, screen)
+		}
+	}
+	if len(m.jobs.jobs) != 0 {
+		t.Fatal("expected nothing added before confirming")
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen || m.diff.previewAdd != nil || len(m.jobs.jobs) != 0 {
+		t.Fatal("expected Esc to cancel the add")
+	}
+
+	m, _ = sendMsg(t, m, m.addPreviewCmd(path, chezmoi.AddOptions{Template: true})())
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	if m.view != StatusScreen || len(m.jobs.jobs) != 1 || !strings.HasPrefix(m.jobs.jobs[0].label, "add ") {
+		t.Fatalf("expected Enter to queue the add, got %+v", m.jobs.jobs)
+	}
+}
+
+func TestAddPreviewLinesShowAutoTemplateSubstitutions(t *testing.T) {
+	m := newTestModel()
+	lines := m.addPreviewLines(chezmoi.AddPreview{
+		Target:     "/home/test/.gitconfig",
+		SourcePath: "dot_gitconfig.tmpl",
+		Attributes: []string{"dot_", ".tmpl"},
+		Substitutions: []chezmoi.AutoTemplateSubstitution{
+			{Variable: "email", Value: "me@example.com", Count: 2},
+		},
+		Diff: "--- a/.gitconfig\n+++ b/dot_gitconfig.tmpl\n@@ -1 +1 @@\n-email = me@example.com\n+email = {{ .email }}\n",
+	}, chezmoi.AddOptions{AutoTemplate: true})
+	text := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"Target: ~/.gitconfig",
+		"dot_         name starts with a dot",
+		`{{ .email }} ← "me@example.com" ×2`,
+		"+email = {{ .email }}",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in:\n%s", want, text)
+		}
+	}
+}
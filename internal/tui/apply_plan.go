@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// applyPlanItem is one reviewable row on the Apply Plan screen.
+type applyPlanItem struct {
+	entry    chezmoi.ApplyPlanEntry
+	excluded bool
+}
+
+// applyPlanState holds the parsed dry-run and per-entry selection.
+type applyPlanState struct {
+	items  []applyPlanItem
+	cursor int
+}
+
+// applyPlanActionOrder groups plan rows: files first, scripts last.
+var applyPlanActionOrder = []chezmoi.ApplyPlanAction{
+	chezmoi.PlanCreate,
+	chezmoi.PlanModify,
+	chezmoi.PlanDelete,
+	chezmoi.PlanChmod,
+	chezmoi.PlanScript,
+}
+
+func newApplyPlanState(entries []chezmoi.ApplyPlanEntry) applyPlanState {
+	items := make([]applyPlanItem, len(entries))
+	for i, entry := range entries {
+		items[i] = applyPlanItem{entry: entry}
+	}
+	slices.SortStableFunc(items, func(a, b applyPlanItem) int {
+		return slices.Index(applyPlanActionOrder, a.entry.Action) - slices.Index(applyPlanActionOrder, b.entry.Action)
+	})
+	return applyPlanState{items: items}
+}
+
+// includedPaths returns the target paths still ticked for apply.
+func (p applyPlanState) includedPaths() []string {
+	var paths []string
+	for _, item := range p.items {
+		if !item.excluded {
+			paths = append(paths, item.entry.Path)
+		}
+	}
+	return paths
+}
+
+// includedScripts returns the ticked entries that are scripts.
+func (p applyPlanState) includedScripts() []string {
+	var paths []string
+	for _, item := range p.items {
+		if !item.excluded && item.entry.Action == chezmoi.PlanScript {
+			paths = append(paths, item.entry.Path)
+		}
+	}
+	return paths
+}
+
+// countByAction returns how many entries have the given action.
+func (p applyPlanState) countByAction(action chezmoi.ApplyPlanAction) int {
+	count := 0
+	for _, item := range p.items {
+		if item.entry.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func (m Model) openApplyPlan() (tea.Model, tea.Cmd) {
+	if m.service.IsReadOnly() {
+		m.ui.message = actionUnavailableMessage("read-only mode")
+		return m, nil
+	}
+	m.ui.busyAction = true
+	m.ui.message = ""
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadApplyPlanCmd())
+}
+
+func (m Model) loadApplyPlanCmd() tea.Cmd {
+	gen := m.gen
+	return func() tea.Msg {
+		entries, err := m.service.ApplyPlan()
+		return applyPlanLoadedMsg{entries: entries, err: err, gen: gen}
+	}
+}
+
+func (m Model) applyPlanTargetsCmd(paths, scripts []string) tea.Cmd {
+	return func() tea.Msg {
+		// ApplyTargets forces past local edits, so save them first.
+		backup, err := m.service.BackupTargets("apply plan", paths)
+		if err != nil {
+			return applyPlanAppliedMsg{paths: paths, scripts: scripts, err: fmt.Errorf("backup failed, nothing applied: %w", err)}
+		}
+		output, err := m.service.ApplyTargets(paths)
+		return applyPlanAppliedMsg{paths: paths, scripts: scripts, output: output, backedUp: len(backup.Files), err: err}
+	}
+}
+
+// handleApplyPlanApplied opens the result summary, then finishes like any
+// other action.
+func (m Model) handleApplyPlanApplied(msg applyPlanAppliedMsg) (tea.Model, tea.Cmd) {
+	m.recordApplyResult(msg.output, msg.scripts)
+	if msg.err != nil {
+		return m.handleActionDone(chezmoiActionDoneMsg{action: chezmoiActionApplyPlan, err: msg.err})
+	}
+	noun := "targets"
+	if len(msg.paths) == 1 {
+		noun = "target"
+	}
+	message := fmt.Sprintf("applied %d %s", len(msg.paths), noun)
+	if msg.backedUp > 0 {
+		message += fmt.Sprintf(" (%d backed up)", msg.backedUp)
+	}
+	return m.handleActionDone(chezmoiActionDoneMsg{action: chezmoiActionApplyPlan, message: message})
+}
+
+func (m Model) handleApplyPlanLoaded(msg applyPlanLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.gen {
+		return m, nil
+	}
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if len(msg.entries) == 0 {
+		m.ui.message = "Nothing to apply — destination matches source"
+		return m, nil
+	}
+	m.plan = newApplyPlanState(msg.entries)
+	m.actions.show = false
+	m.view = ApplyPlanScreen
+	return m, nil
+}
+
+func (m Model) handleApplyPlanKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		m.view = StatusScreen
+		m.plan = applyPlanState{}
+		return m, nil
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.plan.cursor = moveCursorUp(m.plan.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.plan.cursor = moveCursorDown(m.plan.cursor, len(m.plan.items), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.plan.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.plan.cursor = max(0, len(m.plan.items)-1)
+	case key.Matches(msg, ChezApplyPlanKeys.Toggle):
+		if m.plan.cursor < len(m.plan.items) {
+			m.plan.items[m.plan.cursor].excluded = !m.plan.items[m.plan.cursor].excluded
+		}
+	case key.Matches(msg, ChezApplyPlanKeys.ToggleAll):
+		exclude := len(m.plan.includedPaths()) > 0
+		for i := range m.plan.items {
+			m.plan.items[i].excluded = exclude
+		}
+	case key.Matches(msg, ChezApplyPlanKeys.Diff):
+		return m.openApplyPlanEntryDiff()
+	case key.Matches(msg, ChezApplyPlanKeys.Apply):
+		paths := m.plan.includedPaths()
+		if len(paths) == 0 {
+			m.ui.message = "Nothing selected to apply"
+			return m, nil
+		}
+		scripts := m.plan.includedScripts()
+		m.view = StatusScreen
+		m.plan = applyPlanState{}
+		m.ui.busyAction = true
+		m.ui.message = ""
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.applyPlanTargetsCmd(paths, scripts))
+	}
+	return m, nil
+}
+
+// openApplyPlanEntryDiff shows the selected entry's dry-run diff in the diff view.
+func (m Model) openApplyPlanEntryDiff() (tea.Model, tea.Cmd) {
+	if m.plan.cursor >= len(m.plan.items) {
+		return m, nil
+	}
+	entry := m.plan.items[m.plan.cursor].entry
+	if strings.TrimSpace(entry.Diff) == "" {
+		m.ui.message = "No diff for " + shortenPath(entry.Path, m.targetPath)
+		return m, nil
+	}
+	m.view = DiffScreen
+	m.diff.fromApplyPlan = true
+	m.diff.sourceSection = changesSectionDrift
+	m.diff.content = entry.Diff
+	m.diff.path = entry.Path
+	m.diff.rawLines = strings.Split(entry.Diff, "\n")
+	m.diff.lines = m.diff.rawLines
+	m.diff.pagerApplied = false
+	m.diff.resetViewport()
+	return m, nil
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func testApplyPlanEntries() []chezmoi.ApplyPlanEntry {
+	return []chezmoi.ApplyPlanEntry{
+		{Path: "/home/test/install.sh", Action: chezmoi.PlanScript},
+		{Path: "/home/test/.bashrc", Action: chezmoi.PlanModify, Diff: "diff --git a/.bashrc b/.bashrc\n@@ -1 +1 @@\n-a\n+b"},
+		{Path: "/home/test/.config/new.toml", Action: chezmoi.PlanCreate},
+	}
+}
+
+func TestApplyPlanLoadedOpensScreenGroupedByAction(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, applyPlanLoadedMsg{entries: testApplyPlanEntries(), gen: m.gen})
+
+	if m.view != ApplyPlanScreen {
+		t.Fatalf("expected ApplyPlanScreen, got %v", m.view)
+	}
+	want := []chezmoi.ApplyPlanAction{chezmoi.PlanCreate, chezmoi.PlanModify, chezmoi.PlanScript}
+	for i, action := range want {
+		if m.plan.items[i].entry.Action != action {
+			t.Fatalf("items[%d] action = %s, want %s", i, m.plan.items[i].entry.Action, action)
+		}
+	}
+}
+
+func TestApplyPlanLoadedEmptyStaysOnStatus(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, applyPlanLoadedMsg{gen: m.gen})
+
+	if m.view != StatusScreen {
+		t.Fatalf("expected StatusScreen for empty plan, got %v", m.view)
+	}
+	if m.ui.message == "" {
+		t.Fatal("expected nothing-to-apply message")
+	}
+}
+
+func TestApplyPlanToggleExcludesEntry(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, applyPlanLoadedMsg{entries: testApplyPlanEntries(), gen: m.gen})
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	m, _ = sendKey(t, m, specialKey(tea.KeySpace))
+
+	paths := m.plan.includedPaths()
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 included paths, got %v", paths)
+	}
+	for _, p := range paths {
+		if p == "/home/test/.bashrc" {
+			t.Fatalf("expected .bashrc excluded, got %v", paths)
+		}
+	}
+
+	m, _ = sendKey(t, m, runeKey("a"))
+	if len(m.plan.includedPaths()) != 0 {
+		t.Fatal("expected toggle all to exclude every entry")
+	}
+	m, cmd := sendKey(t, m, runeKey("y"))
+	if cmd != nil {
+		t.Fatal("expected no apply cmd with nothing selected")
+	}
+	if m.view != ApplyPlanScreen {
+		t.Fatalf("expected to stay on plan, got %v", m.view)
+	}
+}
+
+func TestApplyPlanApplyReturnsToStatus(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, applyPlanLoadedMsg{entries: testApplyPlanEntries(), gen: m.gen})
+
+	m, cmd := sendKey(t, m, runeKey("y"))
+	if cmd == nil {
+		t.Fatal("expected apply cmd")
+	}
+	if m.view != StatusScreen || !m.ui.busyAction {
+		t.Fatalf("expected busy StatusScreen after apply, got view=%v busy=%v", m.view, m.ui.busyAction)
+	}
+}
+
+func TestApplyPlanEntryDiffEscReturnsToPlan(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, applyPlanLoadedMsg{entries: testApplyPlanEntries(), gen: m.gen})
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	if m.view != DiffScreen || !m.diff.fromApplyPlan {
+		t.Fatalf("expected plan entry diff, got view=%v", m.view)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != ApplyPlanScreen {
+		t.Fatalf("expected Esc to return to ApplyPlanScreen, got %v", m.view)
+	}
+}
+
+func TestApplyPlanReadOnlyBlocked(t *testing.T) {
+	m := newTestModel(WithReadOnly())
+	next, cmd := m.openApplyPlan()
+	if cmd != nil {
+		t.Fatal("expected no cmd in read-only mode")
+	}
+	if next.(Model).ui.message == "" {
+		t.Fatal("expected read-only message")
+	}
+}
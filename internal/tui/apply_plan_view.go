@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func (m Model) renderApplyPlanScreen() string {
+	var b strings.Builder
+
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Apply Plan")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	b.WriteString(activeTheme.DimText.Render("  " + m.applyPlanSummary()))
+	b.WriteString("\n\n")
+
+	maxWidth := m.effectiveWidth() - 2
+	start, end := visibleRange(len(m.plan.items), m.plan.cursor, m.applyPlanListHeight())
+	for i := start; i < end; i++ {
+		b.WriteString(m.renderApplyPlanRow(m.plan.items[i], i == m.plan.cursor, maxWidth))
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderApplyPlanStatusBar())
+}
+
+// applyPlanSummary counts entries per action, e.g. "2 create · 1 modify".
+func (m Model) applyPlanSummary() string {
+	var parts []string
+	for _, action := range applyPlanActionOrder {
+		if n := m.plan.countByAction(action); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, action))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m Model) renderApplyPlanRow(item applyPlanItem, selected bool, maxWidth int) string {
+	cursor := "  "
+	if selected {
+		cursor = "> "
+	}
+	check := "[x]"
+	if item.excluded {
+		check = "[ ]"
+	}
+	verb := fmt.Sprintf("%-6s", item.entry.Action)
+	icon := renderFileIcon(filepath.Base(item.entry.Path), false, selected, m.iconMode)
+	display := shortenPath(item.entry.Path, m.targetPath)
+
+	if selected {
+		content := visualTruncate(cursor+check+" "+verb+" "+icon+display, maxWidth)
+		return activeTheme.Selected.Width(maxWidth).Render(content)
+	}
+	content := cursor + check + " " + applyPlanActionStyle(item.entry.Action).Render(verb) + " " + icon + display
+	if item.excluded {
+		return activeTheme.DimText.Render(visualTruncate(cursor+check+" "+verb+" "+display, maxWidth))
+	}
+	return visualTruncate(content, maxWidth)
+}
+
+func applyPlanActionStyle(action chezmoi.ApplyPlanAction) lipgloss.Style {
+	switch action {
+	case chezmoi.PlanCreate:
+		return activeTheme.SuccessFg
+	case chezmoi.PlanDelete:
+		return activeTheme.DangerFg
+	case chezmoi.PlanChmod:
+		return activeTheme.AccentFg
+	case chezmoi.PlanScript:
+		return activeTheme.WarningFg
+	default:
+		return activeTheme.PrimaryFg
+	}
+}
+
+func (m Model) renderApplyPlanStatusBar() string {
+	status := fmt.Sprintf(" %d/%d selected for apply ", len(m.plan.includedPaths()), len(m.plan.items))
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | space include/exclude | a toggle all | enter diff | y apply selected | esc cancel")
+	return statusBar + "\n" + help
+}
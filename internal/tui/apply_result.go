@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// applyHighlightDuration is how long resolved targets stay visible in the
+// Status tab after the post-apply refresh.
+const applyHighlightDuration = 3 * time.Second
+
+// applyResultState holds the last parsed apply result for the summary
+// overlay and the brief highlight of the Status rows it resolved.
+type applyResultState struct {
+	result chezmoi.ApplyResult
+	show   bool
+	scroll int
+
+	// resolved are the written and ran entries. They are highlighted from
+	// the next status reload until highlightUntil.
+	resolved         []chezmoi.ApplyResultEntry
+	highlightPending bool
+	highlightUntil   time.Time
+}
+
+// highlighting reports whether resolved rows are currently shown.
+func (s applyResultState) highlighting(now time.Time) bool {
+	return len(s.resolved) > 0 && !s.highlightPending && now.Before(s.highlightUntil)
+}
+
+// recordApplyResult parses captured `chezmoi apply --verbose` output and
+// opens the summary overlay when there is anything to report.
+func (m *Model) recordApplyResult(output string, scripts []string) {
+	result := chezmoi.ParseApplyResult(output, m.targetPath, scripts)
+	if result.Empty() {
+		return
+	}
+	var resolved []chezmoi.ApplyResultEntry
+	for _, e := range result.Entries {
+		if e.Outcome == chezmoi.ApplyWritten || e.Outcome == chezmoi.ApplyScriptRan {
+			resolved = append(resolved, e)
+		}
+	}
+	m.applyResult = applyResultState{
+		result:           result,
+		show:             true,
+		resolved:         resolved,
+		highlightPending: len(resolved) > 0,
+	}
+}
+
+// statusScripts returns the target paths of scripts in the current status,
+// which is how apply output tells scripts from files.
+func (m Model) statusScripts() []string {
+	var scripts []string
+	for _, f := range m.status.files {
+		if f.SourceStatus == 'R' || f.DestStatus == 'R' {
+			scripts = append(scripts, f.Path)
+		}
+	}
+	return scripts
+}
+
+// startApplyHighlight begins the resolved-row highlight on the first status
+// reload after an apply, and schedules its end.
+func (m *Model) startApplyHighlight() tea.Cmd {
+	if !m.applyResult.highlightPending {
+		return nil
+	}
+	m.applyResult.highlightPending = false
+	m.applyResult.highlightUntil = time.Now().Add(applyHighlightDuration)
+	return tea.Tick(applyHighlightDuration, func(time.Time) tea.Msg {
+		return applyHighlightExpiredMsg{}
+	})
+}
+
+func (m Model) handleApplyHighlightExpired() (tea.Model, tea.Cmd) {
+	if m.applyResult.highlightPending || m.applyResult.highlighting(time.Now()) {
+		return m, nil
+	}
+	m.applyResult.resolved = nil
+	m.buildChangesRows()
+	m.status.changesCursor = min(m.status.changesCursor, max(0, len(m.status.changesRows)-1))
+	return m, nil
+}
+
+// resolvedRows returns highlight rows for resolved targets that no longer
+// appear in the drift list. They are left out while a filter is active.
+func (m Model) resolvedRows() []changesRow {
+	if !m.applyResult.highlighting(time.Now()) || m.filterInput.Value() != "" {
+		return nil
+	}
+	drifting := make(map[string]bool, len(m.status.filteredFiles))
+	for _, f := range m.status.filteredFiles {
+		drifting[f.Path] = true
+	}
+	var rows []changesRow
+	for i := range m.applyResult.resolved {
+		entry := &m.applyResult.resolved[i]
+		if drifting[entry.Path] {
+			continue
+		}
+		rows = append(rows, changesRow{section: changesSectionDrift, resolved: entry})
+	}
+	return rows
+}
+
+func (m Model) handleApplyResultKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	maxScroll := m.applyResultMaxScroll()
+	switch {
+	case key.Matches(msg, ChezApplyResultKeys.Close):
+		m.applyResult.show = false
+		m.applyResult.scroll = 0
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.applyResult.scroll = max(0, m.applyResult.scroll-1)
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.applyResult.scroll = min(maxScroll, m.applyResult.scroll+1)
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.applyResult.scroll = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.applyResult.scroll = maxScroll
+	}
+	return m, nil
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+	"github.com/daptify14/chezit/internal/pty"
+)
+
+const verboseApplyOutput = `diff --git a/.bashrc b/.bashrc
+@@ -1 +1 @@
+-old
++new
+.zshrc has changed since chezmoi last wrote it? [diff,overwrite,all-overwrite,skip,quit] s
+chezmoi: .vimrc: template: dot_vimrc.tmpl:3: map has no entry for key "email"
+`
+
+func TestTerminalApplyOpensResultOverlay(t *testing.T) {
+	m := newTestModel(WithSize(120, 40))
+	session, _ := newPipeTerminalSession(t)
+	m, _ = sendMsg(t, m, terminalStartedMsg{session: session})
+	m, _ = sendMsg(t, m, terminalOutputMsg{session: session, data: []byte(strings.ReplaceAll(verboseApplyOutput, "\n", "\r\n"))})
+	m, _ = sendMsg(t, m, terminalExitedMsg{session: session, err: errors.New("exit status 1")})
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if !m.applyResult.show {
+		t.Fatal("expected the apply result overlay after closing the pane")
+	}
+	rendered := ansi.Strip(m.renderApplyResultOverlay())
+	for _, want := range []string{"1 written · 1 skipped · 1 failed", "~/.bashrc", "modify", "~/.zshrc", "changed since chezmoi last wrote it", "~/.vimrc", `map has no entry for key "email"`} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected overlay to contain %q, got:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.applyResult.show {
+		t.Fatal("expected esc to close the overlay")
+	}
+}
+
+func TestTerminalApplyUsesScriptsFromApplyStart(t *testing.T) {
+	if !pty.Supported {
+		t.Skip("pseudo-terminals unsupported on this platform")
+	}
+	m := newTestModel(WithSize(120, 40), WithDriftFiles([]chezmoi.FileStatus{
+		{Path: "/home/test/install.sh", SourceStatus: ' ', DestStatus: 'R'},
+	}))
+	cmd := exec.Command("printf", `diff --git a/install.sh b/install.sh\nnew file mode 100755\n`)
+	msg := m.terminalExecCmd(chezmoiActionApplyAll, "chezmoi apply", cmd, cmd, "unsupported")()
+	m, next := sendMsg(t, m, msg)
+	for next != nil && m.term.running {
+		m, next = sendMsg(t, m, next())
+	}
+
+	// A status reload while the pane is open no longer lists the script.
+	m.status.files = nil
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if got := applyResultSummary(m.applyResult.result); got != "1 ran" {
+		t.Fatalf("expected the script recognized from the apply start, got %q", got)
+	}
+}
+
+func TestApplyResultHighlightsResolvedRowsAfterReload(t *testing.T) {
+	m := newTestModel(WithDriftFiles([]chezmoi.FileStatus{
+		{Path: "/home/test/.bashrc", SourceStatus: ' ', DestStatus: 'M'},
+		{Path: "/home/test/.zshrc", SourceStatus: ' ', DestStatus: 'M'},
+	}))
+	m.recordApplyResult(verboseApplyOutput, nil)
+	m.applyResult.show = false
+
+	// Only .zshrc still drifts after the apply.
+	m, cmd := sendMsg(t, m, chezmoiStatusLoadedMsg{
+		files: []chezmoi.FileStatus{{Path: "/home/test/.zshrc", SourceStatus: ' ', DestStatus: 'M'}},
+		gen:   m.gen,
+	})
+	if cmd == nil {
+		t.Fatal("expected a tick to end the highlight")
+	}
+	var resolved []string
+	for _, row := range m.status.changesRows {
+		if row.resolved != nil {
+			resolved = append(resolved, row.resolved.Path)
+		}
+	}
+	if len(resolved) != 1 || resolved[0] != "/home/test/.bashrc" {
+		t.Fatalf("expected .bashrc highlighted as resolved, got %v", resolved)
+	}
+	if out := ansi.Strip(m.renderChangesTabContentWidth(100)); !strings.Contains(out, "~/.bashrc  applied") {
+		t.Fatalf("expected resolved row to render, got:\n%s", out)
+	}
+
+	// Still within the highlight window: the tick is ignored.
+	m, _ = sendMsg(t, m, applyHighlightExpiredMsg{})
+	if len(m.applyResult.resolved) == 0 {
+		t.Fatal("expected highlight to survive an early tick")
+	}
+
+	m.applyResult.highlightUntil = time.Now().Add(-time.Second)
+	m, _ = sendMsg(t, m, applyHighlightExpiredMsg{})
+	for _, row := range m.status.changesRows {
+		if row.resolved != nil {
+			t.Fatal("expected resolved rows to be gone after the highlight")
+		}
+	}
+}
+
+func TestApplyPlanAppliedRecordsResult(t *testing.T) {
+	m := newTestModel()
+	m.ui.busyAction = true
+
+	m, _ = sendMsg(t, m, applyPlanAppliedMsg{
+		paths:   []string{"/home/test/.bashrc", "/home/test/install.sh"},
+		scripts: []string{"/home/test/install.sh"},
+		output:  "diff --git a/.bashrc b/.bashrc\n@@ -1 +1 @@\n-a\n+b\ndiff --git a/install.sh b/install.sh\nnew file mode 100755\n",
+	})
+	if !m.applyResult.show {
+		t.Fatal("expected result overlay")
+	}
+	if got := applyResultSummary(m.applyResult.result); got != "1 written · 1 ran" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	if m.ui.message != "applied 2 targets" {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+const applyResultFooter = "↑/↓ scroll | g/G top/bottom | esc close"
+
+func (m Model) renderApplyResultOverlay() string {
+	return buildHelpOverlay(m.width, m.height, m.applyResult.scroll, applyResultFooter, m.applyResultRows()...)
+}
+
+func (m Model) applyResultMaxScroll() int {
+	return helpOverlayMaxScroll(m.width, m.height, applyResultFooter, m.applyResultRows()...)
+}
+
+// applyResultRows lays the result out as overlay sections, one per outcome.
+func (m Model) applyResultRows() [][]HelpSection {
+	result := m.applyResult.result
+	rows := [][]HelpSection{{{
+		Title: "Apply Result",
+		Notes: []string{applyResultSummary(result)},
+	}}}
+
+	sections := []struct {
+		title   string
+		outcome chezmoi.ApplyOutcome
+	}{
+		{"Written", chezmoi.ApplyWritten},
+		{"Scripts Ran", chezmoi.ApplyScriptRan},
+		{"Skipped", chezmoi.ApplySkipped},
+		{"Failed", chezmoi.ApplyFailed},
+	}
+	for _, sec := range sections {
+		var entries []HelpEntry
+		for _, e := range result.Entries {
+			if e.Outcome != sec.outcome {
+				continue
+			}
+			desc := e.Reason
+			if e.Outcome == chezmoi.ApplyWritten {
+				desc = e.Action.String()
+			}
+			entries = append(entries, HelpEntry{Key: shortenPath(e.Path, m.targetPath), Desc: desc})
+		}
+		if len(entries) > 0 {
+			rows = append(rows, []HelpSection{{Title: sec.title, Entries: entries}})
+		}
+	}
+	if len(result.Errors) > 0 {
+		rows = append(rows, []HelpSection{{Title: "Errors", Notes: result.Errors}})
+	}
+	return rows
+}
+
+// applyResultSummary counts each outcome, e.g. "3 written · 1 skipped".
+func applyResultSummary(result chezmoi.ApplyResult) string {
+	var parts []string
+	for _, o := range []chezmoi.ApplyOutcome{chezmoi.ApplyWritten, chezmoi.ApplyScriptRan, chezmoi.ApplySkipped, chezmoi.ApplyFailed} {
+		if n := result.Count(o); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, o))
+		}
+	}
+	switch n := len(result.Errors); {
+	case n == 1:
+		parts = append(parts, "1 error")
+	case n > 1:
+		parts = append(parts, fmt.Sprintf("%d errors", n))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderResolvedRow renders a target the last apply resolved, shown briefly
+// in the drift section after it stops drifting.
+func (m Model) renderResolvedRow(e chezmoi.ApplyResultEntry, isSelected bool, maxWidth int) string {
+	cursor := "    "
+	if isSelected {
+		cursor = "  > "
+	}
+	label := "applied"
+	if e.Outcome == chezmoi.ApplyScriptRan {
+		label = "ran"
+	}
+	line := fmt.Sprintf("%s%-*s  %s  %s", cursor, chezmoiColStatus, "✓", shortenPath(e.Path, m.targetPath), label)
+	line = visualTruncate(line, maxWidth)
+	if isSelected {
+		return activeTheme.Selected.Width(maxWidth).Render(line)
+	}
+	return activeTheme.SuccessFg.Render(line)
+}
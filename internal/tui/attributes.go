@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// attrFormState holds the attributes form for one managed entry.
+type attrFormState struct {
+	form     *huh.Form
+	entry    chezmoi.SourceEntry
+	selected *[]string // bound to the toggles
+}
+
+// chattrRequest is an attribute change waiting on its template preview
+// to be confirmed.
+type chattrRequest struct {
+	entry     chezmoi.SourceEntry
+	modifiers []string
+}
+
+// attrSourceAffix maps chattr attribute names to the source name prefix or
+// suffix they set, to reuse the add preview's explanations.
+var attrSourceAffix = map[string]string{
+	chezmoi.AttrEncrypted:  "encrypted_",
+	chezmoi.AttrPrivate:    "private_",
+	chezmoi.AttrReadonly:   "readonly_",
+	chezmoi.AttrEmpty:      "empty_",
+	chezmoi.AttrExecutable: "executable_",
+	chezmoi.AttrTemplate:   ".tmpl",
+	chezmoi.AttrExact:      "exact_",
+}
+
+// sourceEntryCmd decodes the source name of a managed target for the
+// attributes form.
+func (m Model) sourceEntryCmd(path string) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		entry, err := svc.SourceEntry(path)
+		return sourceEntryLoadedMsg{path: path, entry: entry, err: err}
+	}
+}
+
+func (m Model) handleSourceEntryLoaded(msg sourceEntryLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if len(msg.entry.EditableAttributes()) == 0 {
+		m.ui.message = "No attributes to edit on " + filepath.Base(msg.entry.SourcePath)
+		return m, nil
+	}
+	var selected []string
+	for _, attr := range msg.entry.EditableAttributes() {
+		if msg.entry.HasAttribute(attr) {
+			selected = append(selected, attr)
+		}
+	}
+	m.view = AttributesScreen
+	m.actions.show = false
+	m.attrForm = attrFormState{entry: msg.entry, selected: &selected}
+	m.attrForm.form = m.buildAttrForm()
+	return m, m.attrForm.form.Init()
+}
+
+// buildAttrForm creates a toggle for each attribute chattr can change on
+// the entry, preset from its source name.
+func (m Model) buildAttrForm() *huh.Form {
+	entry := m.attrForm.entry
+	attrs := entry.EditableAttributes()
+	opts := make([]huh.Option[string], 0, len(attrs))
+	for _, attr := range attrs {
+		opts = append(opts, huh.NewOption(fmt.Sprintf("%-11s %s", attr, addAttributeHelp[attrSourceAffix[attr]]), attr))
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Attributes of " + shortenPath(entry.Target, m.targetPath)).
+				Description("Source: " + filepath.Base(entry.SourcePath)).
+				Options(opts...).
+				Height(len(opts) + 2).
+				Value(m.attrForm.selected),
+		),
+	).WithTheme(huh.ThemeFunc(huh.ThemeCatppuccin)).
+		WithKeyMap(whatIfFormKeyMap()).
+		WithWidth(56).
+		WithShowHelp(false)
+}
+
+// handleAttrFormUpdate routes messages to the attributes form. On submit a
+// template change is previewed first; other changes are applied at once.
+func (m Model) handleAttrFormUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.attrForm.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.attrForm.form = f
+	}
+
+	switch m.attrForm.form.State {
+	case huh.StateCompleted:
+		entry := m.attrForm.entry
+		request := chattrRequest{entry: entry, modifiers: entry.ChattrModifiers(*m.attrForm.selected)}
+		m.attrForm = attrFormState{}
+		m.view = StatusScreen
+		if len(request.modifiers) == 0 {
+			m.ui.message = "Attributes unchanged"
+			return m, nil
+		}
+		if slices.ContainsFunc(request.modifiers, isTemplateModifier) {
+			m.ui.busyAction = true
+			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.chattrPreviewCmd(request))
+		}
+		return m, m.enqueueChattr(request)
+	case huh.StateAborted:
+		m.attrForm = attrFormState{}
+		m.view = StatusScreen
+		return m, nil
+	}
+	return m, cmd
+}
+
+func isTemplateModifier(modifier string) bool {
+	return strings.TrimLeft(modifier, "+-") == chezmoi.AttrTemplate
+}
+
+// chattrPreviewCmd renders the entry the way it would be written once the
+// template attribute changes.
+func (m Model) chattrPreviewCmd(request chattrRequest) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		template := slices.Contains(request.modifiers, "+"+chezmoi.AttrTemplate)
+		diff, err := svc.PreviewTemplateToggle(request.entry, template)
+		return chattrPreviewLoadedMsg{request: request, diff: diff, err: err}
+	}
+}
+
+func (m Model) handleChattrPreviewLoaded(msg chattrPreviewLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	request := msg.request
+	lines := []string{
+		"Target: " + shortenPath(request.entry.Target, m.targetPath),
+		"Source: " + filepath.Base(request.entry.SourcePath),
+		"Change: " + strings.Join(request.modifiers, ", "),
+		"",
+	}
+	switch {
+	case msg.err != nil:
+		// Show the template error rather than fail: fixing the template
+		// after the change may be the plan.
+		lines = append(lines, "The source does not render as a template:")
+		lines = append(lines, strings.Split(strings.TrimRight(msg.err.Error(), "\n"), "\n")...)
+	case msg.diff == "":
+		lines = append(lines, "The target contents stay the same.")
+	default:
+		lines = append(lines, strings.Split(strings.TrimRight(msg.diff, "\n"), "\n")...)
+	}
+	m.view = DiffScreen
+	m.diff.previewChattr = &request
+	m.diff.content = strings.Join(lines, "\n")
+	m.diff.path = request.entry.Target
+	m.diff.rawLines = lines
+	m.diff.lines = lines
+	m.diff.pagerApplied = false
+	m.diff.resetViewport()
+	return m, nil
+}
+
+// confirmChattrPreview applies the attribute change the preview was shown
+// for.
+func (m Model) confirmChattrPreview() (tea.Model, tea.Cmd) {
+	request := *m.diff.previewChattr
+	m.diff.previewChattr = nil
+	m.diff.clear()
+	m.view = StatusScreen
+	return m, m.enqueueChattr(request)
+}
+
+func (m *Model) enqueueChattr(request chattrRequest) tea.Cmd {
+	target := request.entry.Target
+	label := "chattr " + strings.Join(request.modifiers, ",") + " " + shortenPath(target, m.targetPath)
+	return m.enqueueJob(label, chezmoiActionEditAttributes, writerJobScope(target), m.chattrJob(target, request.modifiers))
+}
+
+func (m Model) chattrJob(target string, modifiers []string) jobFunc {
+	mgr := m.service
+	return func(_ context.Context, out chan<- chezmoi.OutputLine) error {
+		if err := mgr.Chattr(target, modifiers); err != nil {
+			out <- chezmoi.OutputLine{Text: err.Error(), Stderr: true}
+			return errors.New(mapAddError(err))
+		}
+		return nil
+	}
+}
+
+// renderAttributesScreen wraps the attributes form in a centered box.
+func (m Model) renderAttributesScreen() string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.Primary).
+		Padding(1, 2).
+		Width(60)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(m.attrForm.form.View()))
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+var testGreetEntryLoaded = sourceEntryLoadedMsg{path: "/home/test/.greet", entry: chezmoi.SourceEntry{
+	Target:     "/home/test/.greet",
+	SourcePath: "/src/executable_dot_greet",
+	Name:       chezmoi.ParseSourceFileName("executable_dot_greet"),
+}}
+
+// submitAttrForm presses Enter and feeds the form its own commands until
+// it closes.
+func submitAttrForm(t *testing.T, m Model) (Model, tea.Cmd) {
+	t.Helper()
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEnter))
+	for cmd != nil && m.view == AttributesScreen {
+		msg := cmd()
+		if _, ok := msg.(tea.BatchMsg); ok {
+			break
+		}
+		m, cmd = sendMsg(t, m, msg)
+	}
+	return m, cmd
+}
+
+func TestAttributesFormPreviewsTemplateToggle(t *testing.T) {
+	m := newTestModel(WithSize(120, 40), WithLoaded(testGreetEntryLoaded))
+	if m.view != AttributesScreen {
+		t.Fatalf("expected the attributes form, got view %v", m.view)
+	}
+	screen := ansi.Strip(m.renderAttributesScreen())
+	for _, want := range []string{"Attributes of ~/.greet", "Source: executable_dot_greet", "template    rendered as a template"} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("expected %q in the form, got:\n%s", want, screen)
+		}
+	}
+	if strings.Contains(screen, "exact") {
+		t.Fatal("expected directory attributes hidden for a file")
+	}
+
+	// Toggle template, the last option.
+	for range 5 {
+		m, _ = sendKey(t, m, runeKey("j"))
+	}
+	m, _ = sendKey(t, m, runeKey("x"))
+	m, cmd := submitAttrForm(t, m)
+	if m.view != StatusScreen || !m.ui.busyAction || cmd == nil || len(m.jobs.jobs) != 0 {
+		t.Fatal("expected the template change previewed before running")
+	}
+
+	request := chattrRequest{entry: chezmoi.SourceEntry{Target: "/home/test/.greet", SourcePath: "/src/executable_dot_greet"}, modifiers: []string{"+template"}}
+	m, _ = sendMsg(t, m, chattrPreviewLoadedMsg{request: request, diff: "--- a/.greet\n+++ b/.greet\n@@ -1 +1 @@\n-hello {{ .name }}\n+hello world\n"})
+	if m.view != DiffScreen || m.diff.previewChattr == nil {
+		t.Fatalf("expected the chattr preview, got view %v", m.view)
+	}
+	if text := ansi.Strip(m.renderDiffView()); !strings.Contains(text, "+hello world") || !strings.Contains(text, "enter apply change") {
+		t.Fatalf("expected the rendered template in the preview, got:\n%s", text)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	if m.view != StatusScreen || len(m.jobs.jobs) != 1 || !strings.HasPrefix(m.jobs.jobs[0].label, "chattr +template ") {
+		t.Fatalf("expected Enter to queue the chattr, got %+v", m.jobs.jobs)
+	}
+}
+
+func TestAttributesFormAppliesOtherChangesDirectly(t *testing.T) {
+	m := newTestModel(WithSize(120, 40), WithLoaded(testGreetEntryLoaded))
+	// Clear executable, the fifth option, and set private, the second.
+	for range 4 {
+		m, _ = sendKey(t, m, runeKey("j"))
+	}
+	m, _ = sendKey(t, m, runeKey("x"))
+	for range 3 {
+		m, _ = sendKey(t, m, runeKey("k"))
+	}
+	m, _ = sendKey(t, m, runeKey("x"))
+	m, _ = submitAttrForm(t, m)
+	if m.view != StatusScreen || len(m.jobs.jobs) != 1 || !strings.HasPrefix(m.jobs.jobs[0].label, "chattr +private,-executable ") {
+		t.Fatalf("expected the chattr queued, got %+v", m.jobs.jobs)
+	}
+
+	m = newTestModel(WithSize(120, 40), WithLoaded(testGreetEntryLoaded))
+	m, _ = submitAttrForm(t, m)
+	if len(m.jobs.jobs) != 0 || m.ui.message != "Attributes unchanged" {
+		t.Fatalf("expected nothing to run, got %q", m.ui.message)
+	}
+}
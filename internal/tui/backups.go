@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// backupRow is one line on the Backups screen: a run header when file is
+// -1, otherwise one of the run's files.
+type backupRow struct {
+	run  int
+	file int
+}
+
+// backupsState holds the loaded backup runs for the Backups screen. The
+// cursor only rests on file rows.
+type backupsState struct {
+	runs   []chezmoi.BackupRun
+	rows   []backupRow
+	cursor int
+}
+
+func newBackupsState(runs []chezmoi.BackupRun) backupsState {
+	s := backupsState{runs: runs}
+	for i, run := range runs {
+		s.rows = append(s.rows, backupRow{run: i, file: -1})
+		for j := range run.Files {
+			s.rows = append(s.rows, backupRow{run: i, file: j})
+		}
+	}
+	s.cursor = s.nextFileRow(0, 1)
+	return s
+}
+
+// nextFileRow returns the first file row at or after from in direction
+// dir (1 or -1), or -1 if there is none.
+func (s backupsState) nextFileRow(from, dir int) int {
+	for i := from; i >= 0 && i < len(s.rows); i += dir {
+		if s.rows[i].file >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveCursor moves by step file rows, stopping at the first or last one.
+func (s *backupsState) moveCursor(step int) {
+	dir := 1
+	if step < 0 {
+		dir, step = -1, -step
+	}
+	for range step {
+		next := s.nextFileRow(s.cursor+dir, dir)
+		if next < 0 {
+			return
+		}
+		s.cursor = next
+	}
+}
+
+// selected returns the run and file under the cursor.
+func (s backupsState) selected() (chezmoi.BackupRun, chezmoi.BackupFile, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.rows) || s.rows[s.cursor].file < 0 {
+		return chezmoi.BackupRun{}, chezmoi.BackupFile{}, false
+	}
+	row := s.rows[s.cursor]
+	run := s.runs[row.run]
+	return run, run.Files[row.file], true
+}
+
+func (m Model) openBackups() (tea.Model, tea.Cmd) {
+	if m.service.IsReadOnly() {
+		m.ui.message = actionUnavailableMessage("read-only mode")
+		return m, nil
+	}
+	m.ui.busyAction = true
+	m.ui.message = ""
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadBackupsCmd())
+}
+
+func (m Model) loadBackupsCmd() tea.Cmd {
+	return func() tea.Msg {
+		runs, err := m.service.BackupRuns()
+		return backupsLoadedMsg{runs: runs, err: err}
+	}
+}
+
+func (m Model) handleBackupsLoaded(msg backupsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if m.view != BackupsScreen {
+		if len(msg.runs) == 0 {
+			m.ui.message = "No backups yet — force applies save overwritten files to " + m.service.BackupOutputDir()
+			return m, nil
+		}
+		m.actions.show = false
+		m.view = BackupsScreen
+		m.backups = newBackupsState(msg.runs)
+		return m, nil
+	}
+	// Reloaded after a restore: keep the cursor on the same file.
+	run, file, ok := m.backups.selected()
+	m.backups = newBackupsState(msg.runs)
+	if ok {
+		for i, row := range m.backups.rows {
+			if row.file >= 0 && m.backups.runs[row.run].ID == run.ID && m.backups.runs[row.run].Files[row.file].Path == file.Path {
+				m.backups.cursor = i
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// backupTargetsCmd snapshots what an apply of paths would overwrite, then
+// hands next back to run the apply. paths nil means everything.
+func (m Model) backupTargetsCmd(label string, paths []string, next tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		run, err := m.service.BackupTargets(label, paths)
+		return applyBackupDoneMsg{run: run, err: err, next: next}
+	}
+}
+
+func (m Model) handleApplyBackupDone(msg applyBackupDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.ui.message = "Error: backup failed, nothing applied: " + msg.err.Error()
+		return m, nil
+	}
+	switch n := len(msg.run.Files); {
+	case n == 1:
+		m.ui.message = "backed up 1 file"
+	case n > 1:
+		m.ui.message = fmt.Sprintf("backed up %d files", n)
+	}
+	return m, msg.next
+}
+
+func (m Model) handleBackupsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		m.view = StatusScreen
+		m.backups = backupsState{}
+		return m, nil
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.backups.moveCursor(-navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.backups.moveCursor(navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.backups.cursor = m.backups.nextFileRow(0, 1)
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.backups.cursor = m.backups.nextFileRow(len(m.backups.rows)-1, -1)
+	case key.Matches(msg, ChezBackupsKeys.Diff):
+		run, file, ok := m.backups.selected()
+		if !ok {
+			return m, nil
+		}
+		m.ui.busyAction = true
+		return m, func() tea.Msg {
+			content, err := m.service.BackupDiff(run, file.Path)
+			return backupDiffLoadedMsg{path: file.Path, content: content, err: err}
+		}
+	case key.Matches(msg, ChezBackupsKeys.Restore):
+		run, file, ok := m.backups.selected()
+		if !ok {
+			return m, nil
+		}
+		m.ui.busyAction = true
+		m.ui.message = ""
+		return m, func() tea.Msg {
+			before, err := m.service.RestoreBackup(run, file.Path)
+			return backupRestoredMsg{path: file.Path, before: before, err: err}
+		}
+	}
+	return m, nil
+}
+
+// handleBackupDiffLoaded shows a backup-vs-current diff in the diff view.
+func (m Model) handleBackupDiffLoaded(msg backupDiffLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if strings.TrimSpace(msg.content) == "" {
+		m.ui.message = shortenPath(msg.path, m.targetPath) + " matches the backup"
+		return m, nil
+	}
+	m.view = DiffScreen
+	m.diff.fromBackups = true
+	m.diff.sourceSection = changesSectionDrift
+	m.diff.content = msg.content
+	m.diff.path = msg.path
+	m.diff.rawLines = strings.Split(msg.content, "\n")
+	m.diff.lines = m.diff.rawLines
+	m.diff.pagerApplied = false
+	m.diff.resetViewport()
+	return m, nil
+}
+
+func (m Model) handleBackupRestored(msg backupRestoredMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m.ui.message = "restored " + shortenPath(msg.path, m.targetPath)
+	if len(msg.before.Files) > 0 {
+		m.ui.message += fmt.Sprintf(" (replaced copy saved as %s)", msg.before.ID)
+	}
+	m.panel.clearCache()
+	return m, tea.Batch(m.loadBackupsCmd(), m.postActionReloadCmds(), sendRefreshMsg())
+}
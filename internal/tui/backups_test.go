@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// newBackupsModel returns a model whose service reads backups from a temp
+// store holding one run with .bashrc and .zshrc.
+func newBackupsModel(t *testing.T) (Model, string) {
+	t.Helper()
+	target := t.TempDir()
+	dir := filepath.Join(t.TempDir(), "backups")
+	for name, content := range map[string]string{".bashrc": "saved bashrc\n", ".zshrc": "saved zshrc\n"} {
+		if err := os.WriteFile(filepath.Join(target, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := chezmoi.NewBackupStore(dir, target, chezmoi.BackupRetention{})
+	if _, err := store.Snapshot("chezmoi apply --force", []string{filepath.Join(target, ".bashrc"), filepath.Join(target, ".zshrc")}); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(WithTarget(target), WithServiceOptions(chezmoi.WithBackupDir(dir)), WithSize(100, 30))
+	m, _ = sendMsg(t, m, loadBackups(t, m))
+	return m, target
+}
+
+func loadBackups(t *testing.T, m Model) tea.Msg {
+	t.Helper()
+	msg := m.loadBackupsCmd()()
+	if loaded, ok := msg.(backupsLoadedMsg); !ok || loaded.err != nil {
+		t.Fatalf("unexpected load result %#v", msg)
+	}
+	return msg
+}
+
+func TestBackupsScreenListsRunsAndSkipsHeaders(t *testing.T) {
+	m, _ := newBackupsModel(t)
+	if m.view != BackupsScreen {
+		t.Fatalf("expected BackupsScreen, got %v", m.view)
+	}
+	rendered := ansi.Strip(m.renderBackupsScreen())
+	for _, want := range []string{"1 run · 2 files", "chezmoi apply --force", "~/.bashrc", "~/.zshrc", "0644"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	if m.backups.cursor != 1 {
+		t.Fatalf("expected cursor on the first file row, got %d", m.backups.cursor)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyUp))
+	if m.backups.cursor != 1 {
+		t.Fatal("expected the run header to be skipped")
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	if _, file, _ := m.backups.selected(); filepath.Base(file.Path) != ".zshrc" {
+		t.Fatalf("expected cursor to stop on the last file, got %s", file.Path)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected esc to leave the screen, got %v", m.view)
+	}
+}
+
+func TestBackupsDiffAndRestore(t *testing.T) {
+	m, target := newBackupsModel(t)
+	bashrc := filepath.Join(target, ".bashrc")
+	if err := os.WriteFile(bashrc, []byte("applied bashrc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEnter))
+	if cmd == nil {
+		t.Fatal("expected a diff command")
+	}
+	m, _ = sendMsg(t, m, cmd())
+	if m.view != DiffScreen || !m.diff.fromBackups {
+		t.Fatalf("expected backup diff view, got %v", m.view)
+	}
+	if diff := strings.Join(m.diff.rawLines, "\n"); !strings.Contains(diff, "-saved bashrc") || !strings.Contains(diff, "+applied bashrc") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != BackupsScreen {
+		t.Fatalf("expected esc to return to backups, got %v", m.view)
+	}
+
+	m, cmd = sendKey(t, m, runeKey("r"))
+	if cmd == nil {
+		t.Fatal("expected a restore command")
+	}
+	m, _ = sendMsg(t, m, cmd())
+	if data, _ := os.ReadFile(bashrc); string(data) != "saved bashrc\n" {
+		t.Fatalf("expected restored content, got %q", data)
+	}
+	if !strings.Contains(m.ui.message, "restored ~/.bashrc (replaced copy saved as") {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+
+	m, _ = sendMsg(t, m, loadBackups(t, m))
+	if len(m.backups.runs) != 2 || m.backups.runs[0].Label != "before restore of "+m.backups.runs[1].ID {
+		t.Fatalf("expected the replaced file saved as a new run, got %+v", m.backups.runs)
+	}
+	if _, file, _ := m.backups.selected(); file.Path != bashrc {
+		t.Fatalf("expected cursor to stay on .bashrc, got %s", file.Path)
+	}
+}
+
+func TestOpenBackupsWithoutRunsShowsMessage(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, backupsLoadedMsg{})
+	if m.view == BackupsScreen || !strings.Contains(m.ui.message, "No backups yet") {
+		t.Fatalf("expected empty-store message, got view %v message %q", m.view, m.ui.message)
+	}
+}
+
+func TestForceApplyBacksUpBeforeRunning(t *testing.T) {
+	m := newTestModel()
+	m.view = ConfirmScreen
+	m.overlays.confirmAction = chezmoiActionApplyAll
+	m.overlays.applyForce = true
+
+	result, cmd := m.executeApplyConfirm()
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("expected a backup command")
+	}
+	done, ok := cmd().(applyBackupDoneMsg)
+	if !ok {
+		t.Fatal("expected force apply to snapshot targets first")
+	}
+	if done.err != nil || done.next == nil {
+		t.Fatalf("expected a successful backup followed by the apply, got %+v", done)
+	}
+
+	m, next := sendMsg(t, m, applyBackupDoneMsg{err: errors.New("disk full"), next: done.next})
+	if next != nil || !strings.Contains(m.ui.message, "backup failed, nothing applied") {
+		t.Fatalf("expected a failed backup to cancel the apply, got message %q", m.ui.message)
+	}
+}
+
+func TestInteractiveApplySkipsBackup(t *testing.T) {
+	m := newTestModel()
+	m.view = ConfirmScreen
+	m.overlays.confirmAction = chezmoiActionApplyAll
+
+	_, cmd := m.executeApplyConfirm()
+	if cmd == nil {
+		t.Fatal("expected the apply command")
+	}
+	msg := cmd()
+	if started, ok := msg.(terminalStartedMsg); ok {
+		_ = started.session.ptm.Close()
+		_ = started.session.cmd.Wait()
+	}
+	if _, ok := msg.(applyBackupDoneMsg); ok {
+		t.Fatal("interactive apply prompts before overwriting and should not snapshot")
+	}
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+const backupTimeLayout = "2006-01-02 15:04:05"
+
+func (m Model) renderBackupsScreen() string {
+	var b strings.Builder
+
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Backups")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	b.WriteString(activeTheme.DimText.Render("  " + m.backupsSummary()))
+	b.WriteString("\n\n")
+
+	maxWidth := m.effectiveWidth() - 2
+	rows := m.backups.rows
+	start, end := visibleRange(len(rows), max(m.backups.cursor, 0), m.backupsListHeight())
+	for i := start; i < end; i++ {
+		row := rows[i]
+		run := m.backups.runs[row.run]
+		if row.file < 0 {
+			b.WriteString(renderBackupRunHeader(run, maxWidth))
+		} else {
+			b.WriteString(m.renderBackupFileRow(run.Files[row.file], i == m.backups.cursor, maxWidth))
+		}
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderBackupsStatusBar())
+}
+
+// backupsSummary counts runs and files, e.g. "3 runs · 7 files in <dir>".
+func (m Model) backupsSummary() string {
+	files := 0
+	for _, run := range m.backups.runs {
+		files += len(run.Files)
+	}
+	runNoun, fileNoun := "runs", "files"
+	if len(m.backups.runs) == 1 {
+		runNoun = "run"
+	}
+	if files == 1 {
+		fileNoun = "file"
+	}
+	return fmt.Sprintf("%d %s · %d %s in %s", len(m.backups.runs), runNoun, files, fileNoun, m.service.BackupOutputDir())
+}
+
+func renderBackupRunHeader(run chezmoi.BackupRun, maxWidth int) string {
+	line := fmt.Sprintf("  %s · %s", run.Time.Local().Format(backupTimeLayout), run.Label)
+	return activeTheme.AccentFg.Render(visualTruncate(line, maxWidth))
+}
+
+func (m Model) renderBackupFileRow(file chezmoi.BackupFile, selected bool, maxWidth int) string {
+	cursor := "    "
+	if selected {
+		cursor = "  > "
+	}
+	icon := renderFileIcon(filepath.Base(file.Path), false, selected, m.iconMode)
+	meta := fmt.Sprintf("  %s  %04o", humanSize(file.Size), file.Mode)
+	content := visualTruncate(cursor+icon+shortenPath(file.Path, m.targetPath)+meta, maxWidth)
+	if selected {
+		return activeTheme.Selected.Width(maxWidth).Render(content)
+	}
+	return content
+}
+
+func (m Model) renderBackupsStatusBar() string {
+	status := " Backups "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | enter/d diff against current | r restore | esc back")
+	return statusBar + "\n" + help
+}
@@ -47,6 +47,11 @@ func (m Model) executeChezmoiCommand(id chezmoiCommandID) (tea.Model, tea.Cmd) {
 		m.overlays.confirmAction = chezmoiActionReAdd
 		m.overlays.confirmLabel = "re-add all (overwrite source from destination)"
 		return m, nil
+	case chezmoiCmdReencryptAll:
+		m.view = ConfirmScreen
+		m.overlays.confirmAction = chezmoiActionReencryptAll
+		m.overlays.confirmLabel = "re-encrypt all (rewrite encrypted sources for the configured recipients)"
+		return m, nil
 
 	// --- Inline capture (read-only commands) ---
 	case chezmoiCmdStatus:
@@ -71,20 +76,12 @@ func (m Model) executeChezmoiCommand(id chezmoiCommandID) (tea.Model, tea.Cmd) {
 			output, err := m.service.CatConfig()
 			return chezmoiSourceContentMsg{path: "chezmoi cat-config", content: output, err: err}
 		}
+
+	// --- Streamed into the output pane ---
 	case chezmoiCmdDoctor:
-		m.ui.busyAction = true
-		return m, func() tea.Msg {
-			output, err := m.service.Doctor()
-			return chezmoiSourceContentMsg{path: "chezmoi doctor", content: output, err: err}
-		}
+		return m, m.enqueueCommandJob("doctor", chezmoiActionNone, jobScope{readOnly: true}, m.service.StreamDoctor)
 	case chezmoiCmdVerify:
-		m.ui.busyAction = true
-		return m, func() tea.Msg {
-			if err := m.service.Verify(); err != nil {
-				return chezmoiActionDoneMsg{action: chezmoiActionNone, err: err}
-			}
-			return chezmoiActionDoneMsg{action: chezmoiActionNone, message: "verify: all files match source state"}
-		}
+		return m, m.enqueueCommandJob("verify", chezmoiActionNone, jobScope{readOnly: true}, m.verifyJob)
 	case chezmoiCmdData:
 		m.ui.busyAction = true
 		return m, func() tea.Msg {
@@ -98,6 +95,27 @@ func (m Model) executeChezmoiCommand(id chezmoiCommandID) (tea.Model, tea.Cmd) {
 			return chezmoiSourceContentMsg{path: "chezmoi git log", content: output, err: err}
 		}
 
+	case chezmoiCmdApplyPlan:
+		return m.openApplyPlan()
+	case chezmoiCmdBackups:
+		return m.openBackups()
+	case chezmoiCmdUndoUpdate:
+		return m.openUndoUpdate()
+	case chezmoiCmdTimeTravel:
+		return m.openTimeTravel()
+	case chezmoiCmdSandboxApply:
+		return m.openSandboxApply()
+	case chezmoiCmdWhatIf:
+		return m.openWhatIf()
+	case chezmoiCmdLintTemplates:
+		return m.openTemplateLint()
+	case chezmoiCmdDataDeps:
+		return m.openDataDeps()
+	case chezmoiCmdScripts:
+		return m.openScripts()
+	case chezmoiCmdExternals:
+		return m.openExternals()
+
 	// --- Confirm-gated archive ---
 	case chezmoiCmdArchive:
 		m.view = ConfirmScreen
@@ -105,16 +123,16 @@ func (m Model) executeChezmoiCommand(id chezmoiCommandID) (tea.Model, tea.Cmd) {
 		m.overlays.confirmLabel = fmt.Sprintf("create backup archive in %s", m.service.ArchiveOutputDir())
 		return m, nil
 
-	// --- Editor shell-out commands ---
+	// --- Editor commands, run in the terminal pane ---
 	case chezmoiCmdEditSource:
 		cmd := m.service.EditSourceCmd()
-		return m, execCmdOrUnsupported(chezmoiActionEditSource, cmd, "chezmoi: edit not supported")
+		return m, m.terminalExecCmd(chezmoiActionEditSource, "chezmoi edit", cmd, cmd, "chezmoi: edit not supported")
 	case chezmoiCmdEditConfig:
 		cmd := m.service.EditConfigCmd()
-		return m, execCmdOrUnsupported(chezmoiActionEditSource, cmd, "chezmoi: config editing not supported")
+		return m, m.terminalExecCmd(chezmoiActionEditSource, "chezmoi edit-config", cmd, cmd, "chezmoi: config editing not supported")
 	case chezmoiCmdEditConfigTemplate:
 		cmd := m.service.EditConfigTemplateCmd()
-		return m, execCmdOrUnsupported(chezmoiActionEditSource, cmd, "chezmoi: config template not found")
+		return m, m.terminalExecCmd(chezmoiActionEditSource, "chezmoi edit-config-template", cmd, cmd, "chezmoi: config template not found")
 	}
 	return m, nil
 }
@@ -129,18 +147,16 @@ func (m Model) executeDryRun(id chezmoiCommandID) (tea.Model, tea.Cmd) {
 	default:
 		return m, nil
 	}
-	wrapped := wrapWithPressEnter(cmd)
-	if wrapped == nil {
+	if cmd == nil {
 		m.ui.message = "chezmoi: dry-run not supported"
 		return m, nil
 	}
-	return m, tea.ExecProcess(wrapped, func(err error) tea.Msg {
-		return chezmoiActionDoneMsg{action: chezmoiActionNone, err: err}
-	})
+	return m, m.terminalExecCmd(chezmoiActionNone, "chezmoi apply --dry-run", cmd, wrapWithPressEnter(cmd), "chezmoi: dry-run not supported")
 }
 
 // wrapWithPressEnter wraps an exec.Cmd so output stays visible and user
-// presses Enter to return to the TUI.
+// presses Enter to return to the TUI. It is the fallback where the
+// terminal pane is unavailable.
 func wrapWithPressEnter(cmd *exec.Cmd) *exec.Cmd {
 	if cmd == nil {
 		return nil
@@ -181,6 +197,8 @@ func commandIDFromLabel(label string) chezmoiCommandID {
 		return chezmoiCmdRefreshExternals
 	case "Re-Add All":
 		return chezmoiCmdReAddAll
+	case "Re-Encrypt All":
+		return chezmoiCmdReencryptAll
 	case "Init":
 		return chezmoiCmdInit
 	case "Edit Source":
@@ -205,6 +223,26 @@ func commandIDFromLabel(label string) chezmoiCommandID {
 		return chezmoiCmdData
 	case "Archive":
 		return chezmoiCmdArchive
+	case "Apply Plan":
+		return chezmoiCmdApplyPlan
+	case "Backups":
+		return chezmoiCmdBackups
+	case "Undo Update":
+		return chezmoiCmdUndoUpdate
+	case "Time Travel":
+		return chezmoiCmdTimeTravel
+	case "Sandbox Apply":
+		return chezmoiCmdSandboxApply
+	case "What-If Data":
+		return chezmoiCmdWhatIf
+	case "Lint Templates":
+		return chezmoiCmdLintTemplates
+	case "Data Dependencies":
+		return chezmoiCmdDataDeps
+	case "Scripts":
+		return chezmoiCmdScripts
+	case "Externals":
+		return chezmoiCmdExternals
 	default:
 		return 0
 	}
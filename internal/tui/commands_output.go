@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// enqueueCommandJob queues a job and, when started from the Commands tab,
+// shows its output there as it streams in.
+func (m *Model) enqueueCommandJob(label string, action chezmoiAction, scope jobScope, run jobFunc) tea.Cmd {
+	cmd := m.enqueueJob(label, action, scope, run)
+	if m.activeTabName() == "Commands" {
+		m.cmds.outputJob = m.jobs.nextID
+		m.cmds.output = viewport.New()
+		m.syncCommandOutput()
+	}
+	return cmd
+}
+
+// verifyJob runs chezmoi verify, reporting success as output since verify
+// itself prints nothing.
+func (m Model) verifyJob(ctx context.Context, out chan<- chezmoi.OutputLine) error {
+	if err := m.service.StreamVerify(ctx, out); err != nil {
+		return err
+	}
+	out <- chezmoi.OutputLine{Text: "all files match source state"}
+	return nil
+}
+
+// commandOutputJob returns the job shown in the Commands tab output pane.
+func (m Model) commandOutputJob() (job, bool) {
+	if m.cmds.outputJob == 0 {
+		return job{}, false
+	}
+	i := m.jobs.index(m.cmds.outputJob)
+	if i < 0 {
+		return job{}, false
+	}
+	return m.jobs.jobs[i], true
+}
+
+// syncCommandOutput sizes the output viewport and refreshes its content,
+// following new output while it is scrolled to the bottom.
+func (m *Model) syncCommandOutput() {
+	j, ok := m.commandOutputJob()
+	if !ok {
+		return
+	}
+	follow := m.cmds.output.AtBottom()
+	m.cmds.output.SetWidth(m.effectiveWidth())
+	m.cmds.output.SetHeight(m.commandOutputHeight())
+
+	lines := make([]string, len(j.output))
+	for i, line := range j.output {
+		text := visualTruncate(line.Text, m.effectiveWidth()-2)
+		if line.Stderr {
+			text = activeTheme.WarningFg.Render(text)
+		}
+		lines[i] = "  " + text
+	}
+	if j.err != nil && !j.canceled() {
+		lines = append(lines, "  "+activeTheme.DangerFg.Render(j.err.Error()))
+	}
+	m.cmds.output.SetContent(strings.Join(lines, "\n"))
+	if follow {
+		m.cmds.output.GotoBottom()
+	}
+}
+
+func (m Model) handleCommandOutputKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		m.cmds.outputJob = 0
+		return m, nil
+	case key.Matches(msg, ChezJobsKeys.Cancel):
+		return m, m.cancelJob(m.cmds.outputJob)
+	}
+	scrollViewport(&m.cmds.output, msg)
+	return m, nil
+}
+
+func (m Model) renderCommandOutput(j job) string {
+	status := j.state.String()
+	if j.canceled() {
+		status = "canceled"
+	}
+	if d := j.elapsed(time.Now()); d > 0 {
+		status += " " + formatJobElapsed(d)
+	}
+	icon := ""
+	if j.state == jobRunning {
+		icon = m.ui.loadingSpinner.View() + " "
+	}
+	header := fmt.Sprintf("  %s%s · %s", icon, j.label, status)
+
+	var b strings.Builder
+	b.WriteString(activeTheme.AccentFg.Render(visualTruncate(header, m.effectiveWidth()-2)))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	if len(j.output) == 0 && j.err == nil {
+		b.WriteString(activeTheme.DimText.Render("  waiting for output..."))
+		return b.String()
+	}
+	b.WriteString(m.cmds.output.View())
+	return b.String()
+}
+
+func (m Model) commandOutputHelp(j job) string {
+	help := "↑/↓ scroll | ^d/^u half | g/G top/bottom"
+	if j.state == jobRunning || j.state == jobQueued {
+		help += " | c cancel"
+	}
+	return help + " | esc back to commands"
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var testOutputCommands = []chezmoiCommandItem{
+	{label: "Doctor", id: chezmoiCmdDoctor, available: true},
+	{label: "Re-Add All", id: chezmoiCmdReAddAll, available: true},
+}
+
+func TestCommandsDoctorStreamsIntoOutputPane(t *testing.T) {
+	m := newTestModel(WithTab(3), WithSize(100, 30), WithCommandItems(testOutputCommands))
+
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEnter))
+	if cmd == nil || len(m.jobs.jobs) != 1 {
+		t.Fatalf("expected doctor job, got %d jobs", len(m.jobs.jobs))
+	}
+	id := m.jobs.jobs[0].id
+	if m.cmds.outputJob != id {
+		t.Fatal("expected the output pane to follow the doctor job")
+	}
+
+	m, _ = sendMsg(t, m, jobOutputMsg{id: id, lines: outputLines("ok  version  v2.60.0", "warning  editor  vi")})
+	rendered := ansi.Strip(m.renderCommandsTabContent())
+	for _, want := range []string{"doctor · running", "version  v2.60.0", "editor  vi"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected pane to contain %q, got:\n%s", want, rendered)
+		}
+	}
+	if help := ansi.Strip(m.renderCommandsStatusBar()); !strings.Contains(help, "c cancel") {
+		t.Fatalf("expected cancel hint while running, got %q", help)
+	}
+
+	m, _ = sendMsg(t, m, jobDoneMsg{id: id})
+	rendered = ansi.Strip(m.renderCommandsTabContent())
+	if !strings.Contains(rendered, "doctor · done") {
+		t.Fatalf("expected finished header, got:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.cmds.outputJob != 0 {
+		t.Fatal("expected esc to return to the command list")
+	}
+	if !strings.Contains(ansi.Strip(m.renderCommandsTabContent()), "Re-Add All") {
+		t.Fatal("expected command list after esc")
+	}
+}
+
+func TestCommandsOutputPaneCancelKey(t *testing.T) {
+	m := newTestModel(WithTab(3), WithSize(100, 30), WithCommandItems(testOutputCommands))
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	canceled := false
+	m.jobs.jobs[0].cancel = func() { canceled = true }
+
+	m, _ = sendKey(t, m, runeKey("c"))
+	if !canceled {
+		t.Fatal("expected c to cancel the command's context")
+	}
+}
+
+func TestCommandsOutputPaneShowsFailure(t *testing.T) {
+	m := newTestModel(WithTab(3), WithSize(100, 30), WithCommandItems(testOutputCommands))
+	m.cmds.cursor = 1
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	if m.view != ConfirmScreen {
+		t.Fatalf("expected re-add confirm, got %v", m.view)
+	}
+	m, _ = sendKey(t, m, runeKey("y"))
+	if len(m.jobs.jobs) != 1 || m.cmds.outputJob != m.jobs.jobs[0].id {
+		t.Fatal("expected confirmed re-add to stream into the output pane")
+	}
+
+	m, _ = sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id, err: errors.New("chezmoi re-add: exit status 1")})
+	rendered := ansi.Strip(m.renderCommandsTabContent())
+	if !strings.Contains(rendered, "re-add all · failed") || !strings.Contains(rendered, "exit status 1") {
+		t.Fatalf("expected failure in pane, got:\n%s", rendered)
+	}
+}
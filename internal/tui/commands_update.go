@@ -11,6 +11,9 @@ func (m Model) handleCommandsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	if msg.IsRepeat && (key.Matches(msg, ChezCommandKeys.Run) || key.Matches(msg, ChezCommandKeys.DryRun)) {
 		return m, nil
 	}
+	if _, ok := m.commandOutputJob(); ok {
+		return m.handleCommandOutputKeys(msg)
+	}
 
 	switch {
 	case key.Matches(msg, ChezSharedKeys.Back):
@@ -33,6 +36,8 @@ func (m Model) handleCommandsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		if m.cmds.cursor < len(m.cmds.items) && m.cmds.items[m.cmds.cursor].supportsDryRun {
 			return m.executeDryRun(m.cmds.items[m.cmds.cursor].id)
 		}
+	case key.Matches(msg, ChezCommandKeys.LastOutput):
+		return m.openTerminalHistory()
 	}
 	return m, nil
 }
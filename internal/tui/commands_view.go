@@ -11,6 +11,10 @@ const (
 )
 
 func (m Model) renderCommandsTabContent() string {
+	if j, ok := m.commandOutputJob(); ok {
+		return m.renderCommandOutput(j)
+	}
+
 	var b strings.Builder
 
 	maxWidth := m.effectiveWidth() - 2
@@ -103,10 +107,16 @@ func (m Model) renderCommandsStatusBar() string {
 		status = " " + m.ui.message + " "
 	}
 	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	if j, ok := m.commandOutputJob(); ok {
+		return statusBar + "\n" + m.helpHint(m.commandOutputHelp(j))
+	}
 	helpText := "↑/↓ navigate | enter run"
 	if m.cmds.cursor < len(m.cmds.items) && m.cmds.items[m.cmds.cursor].supportsDryRun {
 		helpText += " | d dry run"
 	}
+	if m.term.session != nil {
+		helpText += " | o last output"
+	}
 	helpText += " | tab switch | ? keys | esc quit"
 	help := m.helpHint(helpText)
 	return statusBar + "\n" + help
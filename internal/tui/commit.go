@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -145,3 +146,28 @@ func (m Model) renderCommitScreen() string {
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(formView))
 }
+
+// secretFindingsShown caps the findings listed in the commit confirm.
+const secretFindingsShown = 8
+
+// handleCommitSecretsFound asks before committing staged changes the
+// secret scan flagged, listing the matching lines.
+func (m Model) handleCommitSecretsFound(msg commitSecretsFoundMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	detail := []string{"Staged changes look like they hold secrets:"}
+	for i, f := range msg.findings {
+		if i == secretFindingsShown {
+			detail = append(detail, fmt.Sprintf("… and %d more", len(msg.findings)-i))
+			break
+		}
+		detail = append(detail, "  "+f.String())
+	}
+	detail = append(detail, "", "Add encrypted or move the values into template data,",
+		"or allow them with secret_allowlist in the chezit config.")
+	m.view = ConfirmScreen
+	m.overlays.confirmAction = chezmoiActionCommitAllowSecrets
+	m.overlays.confirmLabel = "commit anyway"
+	m.overlays.confirmPath = msg.message
+	m.overlays.confirmDetail = detail
+	return m, nil
+}
@@ -437,10 +437,10 @@ func TestApplyConfirmExecCmd(t *testing.T) {
 		force    bool
 		wantArgs string
 	}{
-		{"force_apply_all", chezmoiActionApplyAll, "", true, "apply --force"},
-		{"interactive_apply_all", chezmoiActionApplyAll, "", false, "apply"},
-		{"force_managed", chezmoiActionApplyManaged, "/home/test/.bashrc", true, "apply --force /home/test/.bashrc"},
-		{"interactive_managed", chezmoiActionApplyManaged, "/home/test/.bashrc", false, "apply /home/test/.bashrc"},
+		{"force_apply_all", chezmoiActionApplyAll, "", true, "apply --verbose --force"},
+		{"interactive_apply_all", chezmoiActionApplyAll, "", false, "apply --verbose"},
+		{"force_managed", chezmoiActionApplyManaged, "/home/test/.bashrc", true, "apply --verbose --force /home/test/.bashrc"},
+		{"interactive_managed", chezmoiActionApplyManaged, "/home/test/.bashrc", false, "apply --verbose /home/test/.bashrc"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
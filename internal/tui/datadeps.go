@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// dataDepsState holds Data Dependencies: every data key and secret-manager
+// function the templates use, and which templates use each.
+type dataDepsState struct {
+	index   chezmoi.DataIndex
+	entries []chezmoi.DataIndexEntry
+	loaded  bool // an index has come back
+	cursor  int
+}
+
+func (m Model) openDataDeps() (tea.Model, tea.Cmd) {
+	m.actions.show = false
+	m.view = DataDepsScreen
+	m.dataDeps = dataDepsState{}
+	m.ui.busyAction = true
+	m.ui.message = "scanning templates..."
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadDataIndexCmd(true))
+}
+
+// loadDataIndexCmd scans the template sources in the background. view
+// marks a scan the Data Dependencies view is waiting for.
+func (m Model) loadDataIndexCmd(view bool) tea.Cmd {
+	return func() tea.Msg {
+		index, err := m.service.TemplateDataIndex()
+		return dataIndexLoadedMsg{index: index, err: err, view: view}
+	}
+}
+
+func (m Model) handleDataIndexLoaded(msg dataIndexLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err == nil {
+		m.status.dataIndex = msg.index
+	}
+	if !msg.view {
+		// Background scans only feed the panel header; errors are not shown.
+		return m, nil
+	}
+	m.ui.busyAction = false
+	if m.view != DataDepsScreen {
+		return m, nil
+	}
+	if msg.err != nil {
+		if !m.dataDeps.loaded {
+			updated, cmd := m.closeDataDeps()
+			m = updated.(Model)
+			m.ui.message = "Error: " + msg.err.Error()
+			return m, cmd
+		}
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m.ui.message = ""
+	m.dataDeps.index = msg.index
+	m.dataDeps.entries = msg.index.Entries()
+	m.dataDeps.loaded = true
+	m.dataDeps.cursor = min(m.dataDeps.cursor, max(0, len(m.dataDeps.entries)-1))
+	return m, nil
+}
+
+func (m Model) closeDataDeps() (tea.Model, tea.Cmd) {
+	m.view = StatusScreen
+	m.dataDeps = dataDepsState{}
+	m.ui.message = ""
+	return m, nil
+}
+
+// selectedDataIndexEntry returns the key or function under the cursor.
+func (s dataDepsState) selectedDataIndexEntry() (chezmoi.DataIndexEntry, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.entries) {
+		return chezmoi.DataIndexEntry{}, false
+	}
+	return s.entries[s.cursor], true
+}
+
+func (m Model) handleDataDepsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.ui.busyAction {
+		return m, nil
+	}
+	entries := m.dataDeps.entries
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		return m.closeDataDeps()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.dataDeps.cursor = moveCursorUp(m.dataDeps.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.dataDeps.cursor = moveCursorDown(m.dataDeps.cursor, len(entries), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.dataDeps.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.dataDeps.cursor = max(0, len(entries)-1)
+	case key.Matches(msg, ChezDataDepsKeys.Reload):
+		m.ui.busyAction = true
+		m.ui.message = "scanning templates..."
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadDataIndexCmd(true))
+	}
+	return m, nil
+}
+
+// dataDepsSummary counts what the scan found, e.g.
+// "14 templates · 23 data keys · 2 secret functions".
+func (m Model) dataDepsSummary() string {
+	var keys, secrets, failed int
+	for _, e := range m.dataDeps.entries {
+		if e.Secret {
+			secrets++
+		} else {
+			keys++
+		}
+	}
+	for _, t := range m.dataDeps.index.Templates {
+		if t.Err != nil {
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("%d templates · %d data keys · %d secret functions", len(m.dataDeps.index.Templates), keys, secrets)
+	if failed > 0 {
+		summary += fmt.Sprintf(" · %d could not be parsed", failed)
+	}
+	return summary
+}
+
+// dataIndexEntryLabel is how an entry is written: ".email" for a data key
+// and "bitwarden()" for a secret-manager function.
+func dataIndexEntryLabel(e chezmoi.DataIndexEntry) string {
+	if e.Secret {
+		return e.Key + "()"
+	}
+	return e.Key
+}
+
+// templateDepsSummary lists what a template reads for the panel header,
+// e.g. "uses .email, .git.name · bitwarden()".
+func templateDepsSummary(deps chezmoi.TemplateDeps) string {
+	if deps.Err != nil {
+		return "dependencies unknown: template does not parse"
+	}
+	var parts []string
+	if len(deps.Keys) > 0 {
+		parts = append(parts, strings.Join(deps.Keys, ", "))
+	}
+	if len(deps.Secrets) > 0 {
+		parts = append(parts, strings.Join(deps.Secrets, "(), ")+"()")
+	}
+	if len(parts) == 0 {
+		return "uses no template data"
+	}
+	return "uses " + strings.Join(parts, " · ")
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+var testDataIndexLoaded = dataIndexLoadedMsg{view: true, index: chezmoi.DataIndex{Templates: []chezmoi.TemplateDeps{
+	{Target: "/home/test/.gitconfig", Keys: []string{".email", ".git.name"}},
+	{Target: "/home/test/.ssh/config", Keys: []string{".git"}, Secrets: []string{"bitwarden"}},
+	{Target: "/home/test/.zshrc", Err: errors.New("unexpected EOF")},
+}}}
+
+func TestDataDepsListsKeysAndTheirTemplates(t *testing.T) {
+	m := newTestModel(WithView(DataDepsScreen), WithSize(120, 30), WithLoaded(testDataIndexLoaded))
+
+	rendered := ansi.Strip(m.renderDataDepsScreen())
+	for _, want := range []string{
+		"Data Dependencies",
+		"3 templates · 3 data keys · 1 secret functions · 1 could not be parsed",
+		".email  1",
+		"bitwarden()  1",
+		"Used by .email",
+		"~/.gitconfig",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, "~/.ssh/config") {
+		t.Fatalf("expected only .gitconfig to use .email:\n%s", rendered)
+	}
+
+	// .git.name is inside .git, so .ssh/config changes with it too.
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	rendered = ansi.Strip(m.renderDataDepsScreen())
+	if !strings.Contains(rendered, "Used by .git.name") || !strings.Contains(rendered, "~/.ssh/config  via .git") {
+		t.Fatalf("expected .ssh/config listed via .git:\n%s", rendered)
+	}
+
+	m, cmd := sendKey(t, m, runeKey("r"))
+	if !m.ui.busyAction || cmd == nil {
+		t.Fatal("expected r to scan the templates again")
+	}
+	m, _ = sendMsg(t, m, dataIndexLoadedMsg{view: true})
+	if rendered := ansi.Strip(m.renderDataDepsScreen()); !strings.Contains(rendered, "No template reads data") {
+		t.Fatalf("expected the empty message:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected esc to leave, got %v", m.view)
+	}
+}
+
+func TestDataIndexBackgroundScanFeedsPanelOnly(t *testing.T) {
+	m := newTestModel(WithSize(120, 30))
+	m.ui.busyAction = true
+	index := chezmoi.DataIndex{Templates: []chezmoi.TemplateDeps{{Target: "/home/test/.gitconfig", Keys: []string{".email"}}}}
+	m, _ = sendMsg(t, m, dataIndexLoadedMsg{index: index})
+	if !m.ui.busyAction || len(m.status.dataIndex.Templates) != 1 {
+		t.Fatal("expected a background scan to store the index and leave other work alone")
+	}
+
+	m, _ = sendMsg(t, m, dataIndexLoadedMsg{err: errors.New("boom")})
+	if len(m.status.dataIndex.Templates) != 1 || strings.Contains(m.ui.message, "boom") {
+		t.Fatal("expected a failed background scan to keep the last index quietly")
+	}
+}
+
+func TestDataDepsErrorBeforeIndexCloses(t *testing.T) {
+	m := newTestModel(WithSize(120, 30))
+	m.view = DataDepsScreen
+	m, _ = sendMsg(t, m, dataIndexLoadedMsg{view: true, err: errors.New("chezmoi managed: boom")})
+	if m.view != StatusScreen || !strings.Contains(m.ui.message, "boom") {
+		t.Fatalf("expected the error on the Commands tab, got view %v message %q", m.view, m.ui.message)
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+func (m Model) renderDataDepsScreen() string {
+	var b strings.Builder
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Data Dependencies")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	switch {
+	case !m.dataDeps.loaded:
+		b.WriteString(activeTheme.DimText.Render("  Scanning templates..."))
+	case len(m.dataDeps.entries) == 0:
+		b.WriteString(activeTheme.DimText.Render("  " + m.dataDepsSummary()))
+		b.WriteString("\n\n")
+		b.WriteString(activeTheme.DimText.Render("  No template reads data or calls a secret manager"))
+	default:
+		b.WriteString(activeTheme.DimText.Render("  " + m.dataDepsSummary()))
+		b.WriteString("\n\n")
+		width := m.effectiveWidth()
+		listW := max(width*2/5, 24)
+		list := lipgloss.NewStyle().Width(listW).Render(m.renderDataDepsList(listW - 2))
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderDataDepsUsers(width-listW-2)))
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderDataDepsStatusBar())
+}
+
+// renderDataDepsList lists the keys and functions with how many templates
+// read each as written.
+func (m Model) renderDataDepsList(maxWidth int) string {
+	entries := m.dataDeps.entries
+	var b strings.Builder
+	start, end := visibleRange(len(entries), m.dataDeps.cursor, m.dataDepsListHeight())
+	for i := start; i < end; i++ {
+		selected := i == m.dataDeps.cursor
+		e := entries[i]
+		label := dataIndexEntryLabel(e)
+		if e.Secret && !selected {
+			label = activeTheme.WarningFg.Render(label)
+		}
+		count := "  " + strconv.Itoa(len(e.Targets))
+		if !selected {
+			count = activeTheme.DimText.Render(count)
+		}
+		content := visualTruncate("  "+label+count, maxWidth)
+		if selected {
+			content = activeTheme.Selected.Width(maxWidth).Render(content)
+		}
+		b.WriteString(content)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderDataDepsUsers lists the templates that change with the selected
+// entry, noting the key when a template reads a map containing it or a
+// key inside it.
+func (m Model) renderDataDepsUsers(maxWidth int) string {
+	entry, ok := m.dataDeps.selectedDataIndexEntry()
+	if !ok {
+		return ""
+	}
+	users := m.dataDeps.index.Users(entry)
+	lines := []string{activeTheme.BoldPrimary.Render("Used by " + dataIndexEntryLabel(entry))}
+	height := m.dataDepsListHeight() - 1
+	for i, u := range users {
+		if i == height-1 && len(users) > height {
+			lines = append(lines, activeTheme.DimText.Render("  … "+strconv.Itoa(len(users)-i)+" more"))
+			break
+		}
+		line := "  " + shortenPath(u.Target, m.targetPath)
+		if !u.Direct(entry) {
+			line += activeTheme.DimText.Render("  via " + strings.Join(u.Via, ", "))
+		}
+		lines = append(lines, visualTruncate(line, maxWidth))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderDataDepsStatusBar() string {
+	status := " Data Dependencies "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | r rescan | esc back")
+	return statusBar + "\n" + help
+}
@@ -18,9 +18,14 @@ func (m Model) logMsg(msg tea.Msg) {
 	if _, ok := msg.(spinner.TickMsg); ok {
 		return
 	}
+	detail := formatMsgDetail(msg)
+	// Keys typed into a running command may be passwords or passphrases.
+	if _, ok := msg.(tea.KeyPressMsg); ok && m.view == TerminalScreen && m.term.running {
+		detail = "(forwarded to terminal)"
+	}
 	m.debugLog.Info("msg",
 		"type", fmt.Sprintf("%T", msg),
-		"detail", formatMsgDetail(msg),
+		"detail", detail,
 	)
 }
 
@@ -53,11 +58,13 @@ func formatMsgDetail(msg tea.Msg) string {
 	case chezmoiGitActionDoneMsg:
 		return actionErr(msg.action, msg.err, msg.message)
 	case chezmoiGitCommitsLoadedMsg:
-		return genErr(msg.gen, msg.err, fmt.Sprintf("unpushed=%d incoming=%d", len(msg.unpushed), len(msg.incoming)))
-	case chezmoiGitFetchDoneMsg:
-		return genErr(msg.gen, msg.err, "")
+		return genErr(msg.gen, msg.err, fmt.Sprintf("unpushed=%d incoming=%d files=%d files_err=%v", len(msg.unpushed), len(msg.incoming), len(msg.files), msg.filesErr))
 	case templatePathsLoadedMsg:
 		return genErr(msg.gen, nil, fmt.Sprintf("paths=%d", len(msg.paths)))
+	case encryptedPathsLoadedMsg:
+		return genErr(msg.gen, nil, fmt.Sprintf("paths=%d", len(msg.paths)))
+	case panelRevealExpiredMsg:
+		return fmt.Sprintf("seq=%d", msg.seq)
 
 	// Files tab
 	case chezmoiManagedLoadedMsg:
@@ -74,24 +81,116 @@ func formatMsgDetail(msg tea.Msg) string {
 	// Info tab
 	case infoContentLoadedMsg:
 		return genErr(msg.gen, msg.err, fmt.Sprintf("view=%d len=%d", msg.view, len(msg.content)))
+	case infoStateLoadedMsg:
+		return genErr(msg.gen, msg.err, fmt.Sprintf("buckets=%d", len(msg.state)))
+	case stateDeletedMsg:
+		return fmt.Sprintf("bucket=%q key=%q err=%v", msg.bucket, msg.key, msg.err)
+
+	// Apply plan
+	case applyPlanLoadedMsg:
+		return genErr(msg.gen, msg.err, fmt.Sprintf("entries=%d", len(msg.entries)))
+
+	// Backups
+	case applyBackupDoneMsg:
+		return fmt.Sprintf("run=%q files=%d err=%v", msg.run.ID, len(msg.run.Files), msg.err)
+	case backupsLoadedMsg:
+		return fmt.Sprintf("runs=%d err=%v", len(msg.runs), msg.err)
+	case backupDiffLoadedMsg:
+		return pathErr(msg.path, msg.err)
+	case backupRestoredMsg:
+		return pathErr(msg.path, msg.err)
+
+	// Update undo
+	case updateOriginRecordedMsg:
+		return fmt.Sprintf("err=%v", msg.err)
+	case updateOriginLoadedMsg:
+		return fmt.Sprintf("commit=%q reflog=%v err=%v", msg.origin.Commit, msg.origin.FromReflog, msg.err)
+	case updateUndoneMsg:
+		return fmt.Sprintf("commit=%q err=%v", msg.commit, msg.err)
+
+	// Time travel
+	case timeTravelCommitsLoadedMsg:
+		return fmt.Sprintf("commits=%d err=%v", len(msg.commits), msg.err)
+
+	// Destination preview
+	case destPreviewLoadedMsg:
+		return fmt.Sprintf("commit=%q entries=%d err=%v", msg.preview.Commit, len(msg.preview.Entries), msg.err)
+	case destPreviewDiffLoadedMsg:
+		return pathErr(msg.path, msg.err)
+
+	// What-if data
+	case whatIfRenderedMsg:
+		return fmt.Sprintf("label=%q renders=%d err=%v", msg.label, len(msg.renders), msg.err)
+
+	// Template lint
+	case templateLintLoadedMsg:
+		return fmt.Sprintf("checked=%d issues=%d err=%v", msg.report.Checked, len(msg.report.Issues), msg.err)
+
+	// Template sub-view
+	case templateHistoryLoadedMsg:
+		return fmt.Sprintf("entries=%d err=%v", len(msg.history), msg.err)
+	case templateEvalTickMsg:
+		return fmt.Sprintf("seq=%d", msg.seq)
+	case templateEvaluatedMsg:
+		return fmt.Sprintf("seq=%d bytes=%d err=%v", msg.seq, len(msg.output), msg.err)
+
+	// Data dependencies
+	case dataIndexLoadedMsg:
+		return fmt.Sprintf("templates=%d view=%v err=%v", len(msg.index.Templates), msg.view, msg.err)
+
+	// Scripts
+	case scriptsLoadedMsg:
+		return fmt.Sprintf("scripts=%d err=%v", len(msg.scripts), msg.err)
+	case scriptStateResetMsg:
+		return fmt.Sprintf("name=%q err=%v", msg.name, msg.err)
+
+	// Externals
+	case externalsLoadedMsg:
+		return fmt.Sprintf("externals=%d err=%v", len(msg.externals), msg.err)
+
+	// Terminal pane
+	case terminalStartedMsg:
+		return fmt.Sprintf("title=%q", msg.session.title)
+	case terminalOutputMsg:
+		return fmt.Sprintf("bytes=%d", len(msg.data))
+	case terminalExitedMsg:
+		return actionErr(msg.session.action, msg.err, msg.session.title)
 
 	// Cross-cutting
 	case chezmoiDiffLoadedMsg:
 		return pathErr(msg.path, msg.err)
 	case chezmoiActionDoneMsg:
 		return actionErr(msg.action, msg.err, msg.message)
+	case commitSecretsFoundMsg:
+		return fmt.Sprintf("findings=%d", len(msg.findings))
 	case chezmoiExecDoneMsg:
 		return actionErr(msg.action, msg.err, "")
 	case chezmoiForgetDoneMsg:
 		return pathErr(msg.path, msg.err)
-	case chezmoiAddDoneMsg:
+	case addPreviewLoadedMsg:
+		return pathErr(msg.request.path, msg.err)
+	case addOptionsLoadedMsg:
 		return pathErr(msg.path, msg.err)
-	case chezmoiSourceContentMsg:
+	case addOptionsSavedMsg:
+		return fmt.Sprintf("err=%v", msg.err)
+	case sourceEntryLoadedMsg:
+		return pathErr(msg.path, msg.err)
+	case chattrPreviewLoadedMsg:
+		return pathErr(msg.request.entry.Target, msg.err)
+	case ignoreFormLoadedMsg:
+		return pathErr(msg.path, msg.err)
+	case ignoreAppendedMsg:
 		return pathErr(msg.path, msg.err)
-	case chezmoiCapturedOutputMsg:
-		return actionErr(msg.action, msg.err, msg.label)
-	case chezmoiArchiveDoneMsg:
+	case ignoreExplainedMsg:
+		return pathErr(msg.path, msg.err)
+	case chezmoiSourceContentMsg:
 		return pathErr(msg.path, msg.err)
+	case applyPlanAppliedMsg:
+		return fmt.Sprintf("paths=%d err=%v", len(msg.paths), msg.err)
+	case jobOutputMsg:
+		return fmt.Sprintf("id=%d lines=%d", msg.id, len(msg.lines))
+	case jobDoneMsg:
+		return fmt.Sprintf("id=%d err=%v", msg.id, msg.err)
 	case sourceDirResolvedMsg:
 		return pathErr(msg.path, msg.err)
 	case panelContentLoadedMsg:
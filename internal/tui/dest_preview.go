@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// destPreviewState holds a destination applied into a temporary directory
+// by Sandbox Apply or Time Travel, drawn with the Files tab's tree rows.
+type destPreviewState struct {
+	preview     chezmoi.DestPreview
+	open        bool
+	returnTo    Screen // screen Esc goes back to
+	changesOnly bool
+	tree        managedTree
+	rows        []flatTreeRow
+	cursor      int
+	// status maps a tree node's relPath to how it compares with the
+	// current target.
+	status map[string]chezmoi.DestPreviewStatus
+	// panelMode is the panel content mode to restore on close; the
+	// preview always shows file content.
+	panelMode panelContentMode
+}
+
+// rebuildTree lays out the preview's entries as a tree rooted at the
+// preview destination, so directory detection sees the preview's layout.
+func (s *destPreviewState) rebuildTree(targetPath string) {
+	s.status = make(map[string]chezmoi.DestPreviewStatus, len(s.preview.Entries))
+	paths := make([]string, 0, len(s.preview.Entries))
+	homePrefix := managedTreeHomePrefix(s.preview.DestDir)
+	for _, entry := range s.preview.Entries {
+		if s.changesOnly && entry.Status == chezmoi.PreviewSame {
+			continue
+		}
+		path, err := s.preview.PreviewPath(entry.Path, targetPath)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, path)
+		s.status[managedTreeRelativePath(path, homePrefix)] = entry.Status
+	}
+	s.tree = buildManagedTree(paths, s.preview.DestDir)
+	s.rows = flattenManagedTree(s.tree)
+	s.cursor = min(s.cursor, max(len(s.rows)-1, 0))
+}
+
+// selectedFile returns the file row under the cursor.
+func (s destPreviewState) selectedFile() (*managedTreeNode, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.rows) || s.rows[s.cursor].node.isDir {
+		return nil, false
+	}
+	return s.rows[s.cursor].node, true
+}
+
+func (m Model) openSandboxApply() (tea.Model, tea.Cmd) {
+	m.ui.busyAction = true
+	m.ui.message = "applying the source to a temporary destination..."
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+		preview, err := m.service.SandboxApply(context.Background())
+		return destPreviewLoadedMsg{preview: preview, returnTo: StatusScreen, err: err}
+	})
+}
+
+func (m Model) handleDestPreviewLoaded(msg destPreviewLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if m.view != msg.returnTo {
+		// Moved on while the preview was being built.
+		return m, m.closeDestPreviewCmd(msg.preview)
+	}
+	m.ui.message = ""
+	if msg.preview.Warnings != "" {
+		m.ui.message = "Warning: some targets failed to apply: " + lastOutputLine(msg.preview.Warnings)
+	}
+	m.actions.show = false
+	m.view = DestPreviewScreen
+	m.destPreview = destPreviewState{
+		preview:   msg.preview,
+		open:      true,
+		returnTo:  msg.returnTo,
+		panelMode: m.panel.contentMode,
+	}
+	m.destPreview.rebuildTree(m.targetPath)
+	m.panel.contentMode = panelModeContent
+	return m.destPreviewPanelLoad()
+}
+
+// lastOutputLine returns the last line of chezmoi output, where it prints
+// the error.
+func lastOutputLine(output string) string {
+	output = strings.TrimSpace(output)
+	if idx := strings.LastIndex(output, "\n"); idx >= 0 {
+		return output[idx+1:]
+	}
+	return output
+}
+
+// closeDestPreview leaves the preview for the screen it was opened from
+// and deletes its temporary files.
+func (m Model) closeDestPreview() (tea.Model, tea.Cmd) {
+	closeCmd := m.closeDestPreviewCmd(m.destPreview.preview)
+	m.view = m.destPreview.returnTo
+	m.panel.contentMode = m.destPreview.panelMode
+	m.panel.currentPath = ""
+	m.destPreview = destPreviewState{}
+	m.ui.message = ""
+	return m, closeCmd
+}
+
+// closeOpenDestPreviewCmd deletes the open preview's temporary files, if
+// any; used when quitting.
+func (m Model) closeOpenDestPreviewCmd() tea.Cmd {
+	if !m.destPreview.open {
+		return nil
+	}
+	return m.closeDestPreviewCmd(m.destPreview.preview)
+}
+
+func (m Model) closeDestPreviewCmd(preview chezmoi.DestPreview) tea.Cmd {
+	return func() tea.Msg {
+		_ = m.service.CloseDestPreview(preview)
+		return nil
+	}
+}
+
+func (m Model) handleDestPreviewKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	dp := &m.destPreview
+	prev := dp.cursor
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		return m.closeDestPreview()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		dp.cursor = moveCursorUp(dp.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		dp.cursor = moveCursorDown(dp.cursor, len(dp.rows), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		dp.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		dp.cursor = max(0, len(dp.rows)-1)
+	case key.Matches(msg, ChezPanelKeys.Toggle):
+		m.panel.toggle(m.width)
+		return m.destPreviewPanelLoad()
+	case key.Matches(msg, ChezDestPreviewKeys.ChangesOnly):
+		dp.changesOnly = !dp.changesOnly
+		dp.cursor = 0
+		dp.rebuildTree(m.targetPath)
+		return m.destPreviewPanelLoad()
+	case key.Matches(msg, ChezManagedKeys.Collapse):
+		if dp.cursor >= len(dp.rows) {
+			return m, nil
+		}
+		if node := dp.rows[dp.cursor].node; node.isDir && node.expanded {
+			node.expanded = false
+			dp.rows = flattenManagedTree(dp.tree)
+		} else if parent := findParentRow(dp.rows, dp.cursor); parent >= 0 {
+			dp.cursor = parent
+		}
+	case key.Matches(msg, ChezDestPreviewKeys.Diff), key.Matches(msg, ChezManagedKeys.Expand):
+		if dp.cursor >= len(dp.rows) {
+			return m, nil
+		}
+		if node := dp.rows[dp.cursor].node; node.isDir {
+			node.expanded = !node.expanded
+			dp.rows = flattenManagedTree(dp.tree)
+			return m, nil
+		}
+		return m.openDestPreviewDiff()
+	}
+	if dp.cursor != prev {
+		return m.destPreviewPanelLoad()
+	}
+	return m, nil
+}
+
+// destPreviewPanelLoad shows the selected file, as rendered into the
+// preview, in the preview panel.
+func (m Model) destPreviewPanelLoad() (tea.Model, tea.Cmd) {
+	if !m.panel.shouldShow(m.width) {
+		return m, nil
+	}
+	node, ok := m.destPreview.selectedFile()
+	if !ok {
+		m.panel.currentPath = ""
+		m.panel.pendingLoad = false
+		m = m.syncPanelViewportContent()
+		return m, nil
+	}
+	path := node.absPath
+	m.panel.currentPath = path
+	m.panel.currentSection = changesSectionDrift
+	if _, ok := m.panel.cacheGet(path, panelModeContent, changesSectionDrift); ok {
+		m = m.syncPanelViewportContent()
+		return m, nil
+	}
+	if m.panel.loading {
+		m.panel.pendingPath = path
+		m.panel.pendingMode = panelModeContent
+		m.panel.pendingSection = changesSectionDrift
+		m.panel.pendingLoad = true
+		m = m.syncPanelViewportContent()
+		return m, nil
+	}
+	m.panel.pendingLoad = false
+	m.panel.loading = true
+	m = m.syncPanelViewportContent()
+	return m, m.panelContentCmd(path, panelModeContent, changesSectionDrift)
+}
+
+func (m Model) openDestPreviewDiff() (tea.Model, tea.Cmd) {
+	node, ok := m.destPreview.selectedFile()
+	if !ok {
+		return m, nil
+	}
+	target := filepath.Join(m.targetPath, filepath.FromSlash(node.relPath))
+	preview := m.destPreview.preview
+	m.ui.busyAction = true
+	return m, func() tea.Msg {
+		content, err := m.service.DestPreviewDiff(preview, target)
+		return destPreviewDiffLoadedMsg{path: target, content: content, err: err}
+	}
+}
+
+// handleDestPreviewDiffLoaded shows a current-vs-preview diff in the diff
+// view.
+func (m Model) handleDestPreviewDiffLoaded(msg destPreviewDiffLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if strings.TrimSpace(msg.content) == "" {
+		m.ui.message = shortenPath(msg.path, m.targetPath) + " matches the current file"
+		return m, nil
+	}
+	m.view = DiffScreen
+	m.diff.fromDestPreview = true
+	m.diff.sourceSection = changesSectionDrift
+	m.diff.content = msg.content
+	m.diff.path = msg.path
+	m.diff.rawLines = strings.Split(msg.content, "\n")
+	m.diff.lines = m.diff.rawLines
+	m.diff.pagerApplied = false
+	m.diff.resetViewport()
+	return m, nil
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// newDestPreviewModel returns a model with a preview where .bashrc
+// differs, .config/git/config is new, .zshrc is removed, and .profile is
+// unchanged. commit is "" for a sandbox apply.
+func newDestPreviewModel(t *testing.T, width int, commit string) (Model, chezmoi.DestPreview) {
+	t.Helper()
+	target := t.TempDir()
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "home")
+	files := map[string]string{
+		filepath.Join(target, ".bashrc"):                "current bashrc\n",
+		filepath.Join(target, ".zshrc"):                 "zshrc\n",
+		filepath.Join(target, ".profile"):               "profile\n",
+		filepath.Join(dest, ".bashrc"):                  "old bashrc\n",
+		filepath.Join(dest, ".profile"):                 "profile\n",
+		filepath.Join(dest, ".config", "git", "config"): "[user]\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	preview := chezmoi.DestPreview{
+		Commit:  commit,
+		Dir:     dir,
+		DestDir: dest,
+		Entries: []chezmoi.DestPreviewEntry{
+			{Path: filepath.Join(target, ".bashrc"), Status: chezmoi.PreviewModified},
+			{Path: filepath.Join(target, ".config", "git", "config"), Status: chezmoi.PreviewAdded},
+			{Path: filepath.Join(target, ".profile"), Status: chezmoi.PreviewSame},
+			{Path: filepath.Join(target, ".zshrc"), Status: chezmoi.PreviewRemoved},
+		},
+	}
+	m := newTestModel(WithReadOnly(), WithTarget(target), WithSize(width, 30))
+	return m, preview
+}
+
+// moveToDestPreviewRow moves the cursor to the row named name.
+func moveToDestPreviewRow(t *testing.T, m Model, name string) Model {
+	t.Helper()
+	for range m.destPreview.rows {
+		if m.destPreview.rows[m.destPreview.cursor].node.name == name {
+			return m
+		}
+		m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	}
+	t.Fatalf("no row named %s", name)
+	return m
+}
+
+func TestSandboxApplyShowsRenderedFileInPanel(t *testing.T) {
+	m, preview := newDestPreviewModel(t, 140, "")
+	m.panel.contentMode = panelModeDiff
+
+	updated, cmd := m.openSandboxApply()
+	m = updated.(Model)
+	if cmd == nil || !m.ui.busyAction {
+		t.Fatal("expected the sandbox apply to start")
+	}
+	m, _ = sendMsg(t, m, destPreviewLoadedMsg{preview: preview, returnTo: StatusScreen})
+	if m.view != DestPreviewScreen {
+		t.Fatalf("expected DestPreviewScreen, got %v", m.view)
+	}
+	rendered := ansi.Strip(m.renderDestPreviewScreen())
+	if !strings.Contains(rendered, "Sandbox Apply") || !strings.Contains(rendered, "4 targets · 1 new · 1 changed in") {
+		t.Fatalf("unexpected header:\n%s", rendered)
+	}
+
+	m = moveToDestPreviewRow(t, m, ".bashrc")
+	if m.panel.currentPath != filepath.Join(preview.DestDir, ".bashrc") {
+		t.Fatalf("expected the panel on the sandbox file, got %q", m.panel.currentPath)
+	}
+	m, _ = sendMsg(t, m, m.panelContentCmd(m.panel.currentPath, panelModeContent, changesSectionDrift)())
+	if entry, ok := m.panel.cacheGet(m.panel.currentPath, panelModeContent, changesSectionDrift); !ok || !strings.Contains(entry.content, "old bashrc") {
+		t.Fatalf("expected the rendered file loaded in the panel, got %+v", entry)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen || m.panel.contentMode != panelModeDiff {
+		t.Fatalf("expected esc to leave with the panel mode restored, got view %v mode %v", m.view, m.panel.contentMode)
+	}
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func (m Model) renderDestPreviewScreen() string {
+	var b strings.Builder
+
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), m.destPreviewTitle()...)...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	b.WriteString(activeTheme.DimText.Render("  " + m.destPreviewSummary()))
+	b.WriteString("\n\n")
+
+	if m.panel.shouldShow(m.width) {
+		panelW := panelWidthFor(m.width)
+		listW := m.width - panelW - 1
+		list := lipgloss.NewStyle().Width(listW).Render(m.renderDestPreviewTree(listW - 2))
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderFilePanel(panelW)))
+	} else {
+		b.WriteString(m.renderDestPreviewTree(m.effectiveWidth() - 2))
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderDestPreviewStatusBar())
+}
+
+// destPreviewTitle returns the breadcrumb parts naming the preview.
+func (m Model) destPreviewTitle() []string {
+	if commit := m.destPreview.preview.Commit; commit != "" {
+		return []string{"Time Travel", shortHash(commit)}
+	}
+	return []string{"Sandbox Apply"}
+}
+
+// destPreviewSummary counts differences from the current targets, e.g.
+// "12 targets · 2 new · 3 changed · 1 removed in <dir>".
+func (m Model) destPreviewSummary() string {
+	preview := m.destPreview.preview
+	added, modified, removed := preview.Counts()
+	summary := fmt.Sprintf("%d targets", len(preview.Entries))
+	if added+modified+removed == 0 {
+		summary += ", same as now"
+	} else {
+		summary += fmt.Sprintf(" · %d new · %d changed", added, modified)
+		if preview.Commit != "" {
+			summary += fmt.Sprintf(" · %d removed", removed)
+		}
+	}
+	return summary + " in " + preview.DestDir
+}
+
+func (m Model) renderDestPreviewTree(maxWidth int) string {
+	rows := m.destPreview.rows
+	if len(rows) == 0 {
+		return activeTheme.DimText.Render("  No changes")
+	}
+	var b strings.Builder
+	start, end := visibleRange(len(rows), m.destPreview.cursor, m.destPreviewListHeight())
+	for i := start; i < end; i++ {
+		b.WriteString(m.renderDestPreviewRow(rows[i], i == m.destPreview.cursor, maxWidth))
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderDestPreviewRow draws a tree row like the Files tab, tagged with
+// how the target differs from the current one.
+func (m Model) renderDestPreviewRow(row flatTreeRow, selected bool, maxWidth int) string {
+	icon := renderFileIcon(row.node.name, row.node.isDir, selected, m.iconMode)
+	var nameStr string
+	if row.node.isDir {
+		nameStr = renderDirName(row.node, icon, selected)
+	} else {
+		nameStr = icon + row.node.name
+	}
+	content := "  " + treeRowPrefix(row) + nameStr + treeRowSuffix(row, false, false, selected)
+	if !row.node.isDir {
+		content += destPreviewStatusTag(m.destPreview.status[row.node.relPath], selected)
+	}
+	content = visualTruncate(content, maxWidth)
+	if selected {
+		return activeTheme.Selected.Width(maxWidth).Render(content)
+	}
+	return content
+}
+
+func destPreviewStatusTag(status chezmoi.DestPreviewStatus, selected bool) string {
+	var tag string
+	var style lipgloss.Style
+	switch status {
+	case chezmoi.PreviewAdded:
+		tag, style = " [new]", activeTheme.SuccessFg
+	case chezmoi.PreviewModified:
+		tag, style = " [changed]", activeTheme.WarningFg
+	case chezmoi.PreviewRemoved:
+		tag, style = " [removed]", activeTheme.DangerFg
+	default:
+		return ""
+	}
+	if selected {
+		return tag
+	}
+	return style.Render(tag)
+}
+
+func (m Model) renderDestPreviewStatusBar() string {
+	status := " " + strings.Join(m.destPreviewTitle(), " ") + " "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	back := "esc back"
+	if m.destPreview.returnTo == TimeTravelScreen {
+		back = "esc pick another commit"
+	}
+	help := m.helpHint("↑/↓ nav | enter/l expand or diff | h collapse | d diff against current | c changes only | p panel | " + back)
+	return statusBar + "\n" + help
+}
@@ -47,6 +47,24 @@ func diffLineStyle(line string) lipgloss.Style {
 	}
 }
 
+// whatIfDirectionHint labels the sides of a What-If Data diff.
+const whatIfDirectionHint = "- current data  + overlay"
+
+// diffViewDirection returns the direction hint and drift side label for
+// the full-screen diff.
+func (m Model) diffViewDirection() (hint, side string) {
+	if m.diff.fromWhatIf {
+		return whatIfDirectionHint, ""
+	}
+	if m.diff.previewAdd != nil {
+		return "- target  + source", ""
+	}
+	if m.diff.previewChattr != nil {
+		return "- current  + after chattr", ""
+	}
+	return diffDirectionHint(m.diff.sourceSection), m.driftSideLabel(m.diff.sourceSection, m.diff.path)
+}
+
 // diffDirectionHint returns a short label explaining what - and + mean
 // in the diff for the given section. Returns "" for commit sections.
 func diffDirectionHint(section changesSection) string {
@@ -57,6 +75,8 @@ func diffDirectionHint(section changesSection) string {
 		return "- committed  + working tree"
 	case changesSectionStaged:
 		return "- committed  + staged"
+	case changesSectionIncomingFiles:
+		return "- local HEAD  + upstream"
 	default:
 		return ""
 	}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// externalsState holds the Externals view: every entry defined in a
+// .chezmoiexternal file, with when it was last downloaded.
+type externalsState struct {
+	externals []chezmoi.External
+	loaded    bool // externals have come back
+	cursor    int
+}
+
+func (m Model) openExternals() (tea.Model, tea.Cmd) {
+	m.actions.show = false
+	m.view = ExternalsScreen
+	m.externals = externalsState{}
+	m.ui.busyAction = true
+	m.ui.message = "loading externals..."
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadExternalsCmd())
+}
+
+func (m Model) loadExternalsCmd() tea.Cmd {
+	return func() tea.Msg {
+		externals, err := m.service.Externals()
+		return externalsLoadedMsg{externals: externals, err: err}
+	}
+}
+
+func (m Model) handleExternalsLoaded(msg externalsLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.view != ExternalsScreen {
+		return m, nil
+	}
+	if m.ui.busyAction {
+		// A load started from this view rather than after a refresh job.
+		m.ui.busyAction = false
+		m.ui.message = ""
+	}
+	if msg.err != nil {
+		if !m.externals.loaded {
+			updated, cmd := m.closeExternals()
+			m = updated.(Model)
+			m.ui.message = "Error: " + msg.err.Error()
+			return m, cmd
+		}
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	// Keep the cursor on the same external across reloads.
+	selected, hadSelection := m.externals.selectedExternal()
+	m.externals.externals = msg.externals
+	m.externals.loaded = true
+	m.externals.cursor = min(m.externals.cursor, max(0, len(msg.externals)-1))
+	if hadSelection {
+		for i, e := range msg.externals {
+			if e.Target == selected.Target && e.Definition == selected.Definition {
+				m.externals.cursor = i
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) closeExternals() (tea.Model, tea.Cmd) {
+	m.view = StatusScreen
+	m.externals = externalsState{}
+	m.ui.message = ""
+	return m, nil
+}
+
+// selectedExternal returns the external under the cursor.
+func (s externalsState) selectedExternal() (chezmoi.External, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.externals) {
+		return chezmoi.External{}, false
+	}
+	return s.externals[s.cursor], true
+}
+
+func (m Model) handleExternalsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.ui.busyAction {
+		return m, nil
+	}
+	externals := m.externals.externals
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		return m.closeExternals()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.externals.cursor = moveCursorUp(m.externals.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.externals.cursor = moveCursorDown(m.externals.cursor, len(externals), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.externals.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.externals.cursor = max(0, len(externals)-1)
+	case key.Matches(msg, ChezExternalsKeys.Reload):
+		m.ui.busyAction = true
+		m.ui.message = "loading externals..."
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadExternalsCmd())
+	case key.Matches(msg, ChezExternalsKeys.Refresh):
+		return m.refreshExternal()
+	}
+	return m, nil
+}
+
+// refreshExternal re-downloads the selected external and applies its
+// target in the background.
+func (m Model) refreshExternal() (tea.Model, tea.Cmd) {
+	e, ok := m.externals.selectedExternal()
+	if !ok || e.Err != nil {
+		return m, nil
+	}
+	if m.service.IsReadOnly() {
+		m.ui.message = actionUnavailableMessage("read-only mode")
+		return m, nil
+	}
+	label := "refresh " + shortenPath(e.Target, m.targetPath)
+	scope := writerJobScope(e.Target)
+	return m, m.enqueueJob(label, chezmoiActionRefreshExternal, scope, func(ctx context.Context, out chan<- chezmoi.OutputLine) error {
+		return m.service.StreamRefreshExternal(ctx, out, e.Target)
+	})
+}
+
+// externalsSummary counts the externals and those due a refresh, e.g.
+// "5 externals · 2 due for refresh".
+func (m Model) externalsSummary(now time.Time) string {
+	due := 0
+	for _, e := range m.externals.externals {
+		if e.RefreshDue(now) {
+			due++
+		}
+	}
+	noun := "externals"
+	if len(m.externals.externals) == 1 {
+		noun = "external"
+	}
+	return fmt.Sprintf("%d %s · %d due for refresh", len(m.externals.externals), noun, due)
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+var testExternalsLoaded = externalsLoadedMsg{externals: []chezmoi.External{
+	{
+		Definition: "/home/test/.local/share/chezmoi/dot_config/.chezmoiexternal.json",
+		Err:        errors.New("invalid character 'n'"),
+	},
+	{
+		Target: "/home/test/.config/nvim", Type: "git-repo",
+		URL:         "https://github.com/example/nvim.git",
+		Definition:  "/home/test/.local/share/chezmoi/.chezmoiexternal.toml",
+		LastFetched: time.Now().Add(-time.Hour),
+	},
+	{
+		Target: "/home/test/.oh-my-zsh", Type: "archive",
+		URL:           "https://github.com/ohmyzsh/ohmyzsh/archive/master.tar.gz",
+		RefreshPeriod: 168 * time.Hour,
+		Definition:    "/home/test/.local/share/chezmoi/.chezmoiexternal.toml",
+		LastFetched:   time.Now().Add(-240 * time.Hour),
+	},
+}}
+
+func TestExternalsListShowsTypeURLAndFetchState(t *testing.T) {
+	m := newTestModel(WithView(ExternalsScreen), WithSize(140, 30), WithLoaded(testExternalsLoaded))
+
+	rendered := ansi.Strip(m.renderExternalsScreen())
+	for _, want := range []string{
+		"Externals",
+		"3 externals · 1 due for refresh",
+		"~/.local/share/chezmoi/dot_config/.chezmoiexternal.json  error",
+		"~/.config/nvim  git-repo  fetched",
+		"~/.oh-my-zsh  archive  refresh due",
+		"invalid character 'n'",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendKey(t, m, runeKey("G"))
+	rendered = ansi.Strip(m.renderExternalsScreen())
+	for _, want := range []string{
+		"archive · every 7d",
+		"https://github.com/ohmyzsh/ohmyzsh/archive/master.tar.gz",
+		"downloaded again on next apply",
+		"defined in ~/.local/share/chezmoi/.chezmoiexternal.toml",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected esc to leave, got %v", m.view)
+	}
+}
+
+func TestExternalsRefreshQueuesScopedJob(t *testing.T) {
+	m := newTestModel(WithView(ExternalsScreen), WithSize(140, 30), WithLoaded(testExternalsLoaded))
+	m, _ = sendKey(t, m, runeKey("G"))
+
+	m, _ = sendKey(t, m, runeKey("u"))
+	if len(m.jobs.jobs) != 1 {
+		t.Fatalf("expected a refresh job, got %d", len(m.jobs.jobs))
+	}
+	j := m.jobs.jobs[0]
+	if j.action != chezmoiActionRefreshExternal || j.label != "refresh ~/.oh-my-zsh" || j.scope.paths[0] != "/home/test/.oh-my-zsh" {
+		t.Fatalf("unexpected job %+v", j)
+	}
+}
+
+func TestExternalsRefreshIsDisabledInReadOnlyMode(t *testing.T) {
+	m := newTestModel(WithView(ExternalsScreen), WithSize(140, 30), WithLoaded(testExternalsLoaded), WithReadOnly())
+	m, _ = sendKey(t, m, runeKey("G"))
+
+	m, cmd := sendKey(t, m, runeKey("u"))
+	if cmd != nil || len(m.jobs.jobs) != 0 || !strings.Contains(m.ui.message, "read-only") {
+		t.Fatalf("expected the refresh blocked, got %q", m.ui.message)
+	}
+}
+
+func TestFormatRefreshPeriod(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                "no refresh period",
+		168 * time.Hour:  "every 7d",
+		2 * time.Hour:    "every 2h",
+		90 * time.Minute: "every 1h30m",
+		40 * time.Second: "every 40s",
+	} {
+		if got := formatRefreshPeriod(d); got != want {
+			t.Errorf("formatRefreshPeriod(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
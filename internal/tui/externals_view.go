@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func (m Model) renderExternalsScreen() string {
+	var b strings.Builder
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Externals")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	now := time.Now()
+	switch {
+	case !m.externals.loaded:
+		b.WriteString(activeTheme.DimText.Render("  Loading externals..."))
+	case len(m.externals.externals) == 0:
+		b.WriteString(activeTheme.DimText.Render("  No .chezmoiexternal entries in the source state"))
+	default:
+		b.WriteString(activeTheme.DimText.Render("  " + m.externalsSummary(now)))
+		b.WriteString("\n\n")
+		width := m.effectiveWidth()
+		listW := max(width/2, 40)
+		list := lipgloss.NewStyle().Width(listW).Render(m.renderExternalsList(listW-2, now))
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderExternalDetail(width-listW-2, now)))
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderExternalsStatusBar())
+}
+
+// formatRefreshPeriod renders a refresh period in days when it is whole
+// days, e.g. "every 7d".
+func formatRefreshPeriod(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "no refresh period"
+	case d%(24*time.Hour) == 0:
+		return "every " + strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+	s := d.String()
+	if trimmed, ok := strings.CutSuffix(s, "m0s"); ok {
+		s = trimmed + "m"
+	}
+	if trimmed, ok := strings.CutSuffix(s, "h0m"); ok {
+		s = trimmed + "h"
+	}
+	return "every " + s
+}
+
+// externalFetchLabel is the short fetch state shown in the list and the
+// style to show it in when not selected.
+func externalFetchLabel(e chezmoi.External, now time.Time) (string, lipgloss.Style) {
+	switch {
+	case e.Err != nil:
+		return "error", activeTheme.DangerFg
+	case e.LastFetched.IsZero():
+		return "not fetched", activeTheme.WarningFg
+	case e.RefreshDue(now):
+		return "refresh due", activeTheme.WarningFg
+	default:
+		return "fetched " + e.LastFetched.Local().Format(scriptTimeLayout), activeTheme.DimText
+	}
+}
+
+func (m Model) renderExternalsList(maxWidth int, now time.Time) string {
+	externals := m.externals.externals
+	var b strings.Builder
+	start, end := visibleRange(len(externals), m.externals.cursor, m.externalsListHeight())
+	for i := start; i < end; i++ {
+		selected := i == m.externals.cursor
+		e := externals[i]
+		name := shortenPath(e.Target, m.targetPath)
+		kind := "  " + e.Type
+		if e.Err != nil {
+			name = shortenPath(e.Definition, m.targetPath)
+			kind = ""
+		}
+		label, style := externalFetchLabel(e, now)
+		label = "  " + label
+		if !selected {
+			kind = activeTheme.DimText.Render(kind)
+			label = style.Render(label)
+		}
+		content := visualTruncate("  "+name+kind+label, maxWidth)
+		if selected {
+			content = activeTheme.Selected.Width(maxWidth).Render(content)
+		}
+		b.WriteString(content)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderExternalDetail shows where the selected external comes from and
+// when chezmoi downloads it.
+func (m Model) renderExternalDetail(maxWidth int, now time.Time) string {
+	e, ok := m.externals.selectedExternal()
+	if !ok {
+		return ""
+	}
+	definition := "defined in " + shortenPath(e.Definition, m.targetPath)
+	if e.Err != nil {
+		wrapped := strings.Split(lipgloss.NewStyle().Width(maxWidth).Render(e.Err.Error()), "\n")
+		lines := []string{activeTheme.BoldPrimary.Render(visualTruncate(definition, maxWidth)), ""}
+		for _, line := range wrapped {
+			lines = append(lines, activeTheme.DangerFg.Render(strings.TrimRight(line, " ")))
+		}
+		return strings.Join(lines, "\n")
+	}
+	fetched := "never fetched"
+	if !e.LastFetched.IsZero() {
+		fetched = "last fetched " + e.LastFetched.Local().Format(scriptTimeLayout)
+	}
+	switch {
+	case e.RefreshDue(now):
+		fetched = activeTheme.WarningFg.Render(fetched + " · downloaded again on next apply")
+	case e.RefreshPeriod == 0 && !e.LastFetched.IsZero():
+		fetched = activeTheme.DimText.Render(fetched + " · reused until refreshed")
+	default:
+		fetched = activeTheme.DimText.Render(fetched)
+	}
+	lines := []string{
+		activeTheme.BoldPrimary.Render(visualTruncate(shortenPath(e.Target, m.targetPath), maxWidth)),
+		visualTruncate(e.Type+" · "+formatRefreshPeriod(e.RefreshPeriod), maxWidth),
+		visualTruncate(e.URL, maxWidth),
+		visualTruncate(fetched, maxWidth),
+		activeTheme.DimText.Render(visualTruncate(definition, maxWidth)),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderExternalsStatusBar() string {
+	status := " Externals "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | u refresh external | r reload | J jobs | esc back")
+	return statusBar + "\n" + help
+}
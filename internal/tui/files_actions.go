@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -47,6 +50,10 @@ func (m *Model) openFilesIgnoredMenu() {
 		isDir = rows[m.filesTab.cursor].node.isDir
 	}
 
+	m.actions.managedItems = append(m.actions.managedItems,
+		explainIgnoreItem(),
+		chezmoiActionItem{label: "──────────", action: chezmoiActionNone},
+	)
 	if isDir {
 		m.actions.managedItems = appendActionItemWithCapability(m.actions.managedItems, "Open in File Manager", chezmoiActionOpenFileManager, fmCap)
 	} else {
@@ -55,6 +62,8 @@ func (m *Model) openFilesIgnoredMenu() {
 			chezmoiActionItem{label: "Edit .chezmoiignore ($EDITOR)", action: chezmoiActionEditIgnoreFile},
 			chezmoiActionItem{label: "──────────", action: chezmoiActionNone},
 		)
+		m.actions.managedItems = appendEncryptionItems(m.actions.managedItems, m.service.IsReadOnly())
+		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
 		m.actions.managedItems = appendActionItemWithCapability(m.actions.managedItems, "Open in File Manager", chezmoiActionOpenFileManager, fmCap)
 	}
 
@@ -85,6 +94,14 @@ func (m *Model) openFilesActionsMenu() {
 			"", !readOnly,
 			"read-only mode",
 		)
+		m.actions.managedItems = appendActionItem(
+			m.actions.managedItems,
+			"Edit Attributes...",
+			chezmoiActionEditAttributes,
+			"Toggle exact_, private_, and readonly_ on the source directory\ncmd: chezmoi chattr <modifiers> <path>", !readOnly,
+			"read-only mode",
+		)
+		m.actions.managedItems = appendIgnoreItems(m.actions.managedItems, readOnly)
 		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
 		m.actions.managedItems = appendActionItemWithCapability(m.actions.managedItems, "Open in File Manager", chezmoiActionOpenFileManager, fmCap)
 	} else {
@@ -117,6 +134,16 @@ func (m *Model) openFilesActionsMenu() {
 			"", !readOnly,
 			"read-only mode",
 		)
+		m.actions.managedItems = appendActionItem(
+			m.actions.managedItems,
+			"Edit Attributes...",
+			chezmoiActionEditAttributes,
+			"Toggle private_, executable_, .tmpl, encrypted_, and more on the source file\ncmd: chezmoi chattr <modifiers> <path>", !readOnly,
+			"read-only mode",
+		)
+		m.actions.managedItems = appendIgnoreItems(m.actions.managedItems, readOnly)
+		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
+		m.actions.managedItems = appendEncryptionItems(m.actions.managedItems, readOnly)
 		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
 		m.actions.managedItems = appendActionItemWithCapability(m.actions.managedItems, "Open in File Manager", chezmoiActionOpenFileManager, fmCap)
 	}
@@ -206,8 +233,7 @@ func (m Model) executeFilesAction(action chezmoiAction) (tea.Model, tea.Cmd) {
 		}
 		return m, m.editTargetCmd(absPath)
 
-	case chezmoiActionAdd, chezmoiActionAddEncrypt, chezmoiActionAddTemplate,
-		chezmoiActionAddAutoTemplate, chezmoiActionAddExact, chezmoiActionAddNoRecursive:
+	case chezmoiActionAdd, chezmoiActionAddWithOptions:
 		if m.service.IsReadOnly() {
 			m.ui.message = actionUnavailableMessage("read-only mode")
 			return m, nil
@@ -221,26 +247,109 @@ func (m Model) executeFilesAction(action chezmoiAction) (tea.Model, tea.Cmd) {
 			m.ui.message = "Error: " + err.Error()
 			return m, nil
 		}
-		var opts chezmoi.AddOptions
-		switch action {
-		case chezmoiActionAddEncrypt:
-			opts.Encrypt = true
-		case chezmoiActionAddTemplate:
-			opts.Template = true
-		case chezmoiActionAddAutoTemplate:
-			opts.AutoTemplate = true
-		case chezmoiActionAddExact:
-			opts.Exact = true
-		case chezmoiActionAddNoRecursive:
-			opts.NoRecursive = true
+		m.ui.busyAction = true
+		if action == chezmoiActionAddWithOptions {
+			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.addOptionsCmd(absPath))
+		}
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.addPreviewCmd(absPath, chezmoi.AddOptions{}))
+
+	case chezmoiActionAddMarked:
+		if m.service.IsReadOnly() {
+			m.ui.message = actionUnavailableMessage("read-only mode")
+			return m, nil
+		}
+		paths := slices.Sorted(maps.Keys(m.filesTab.marked))
+		if len(paths) == 0 {
+			m.ui.message = "No files marked"
+			return m, nil
+		}
+		for _, p := range paths {
+			if err := m.service.Policy().ValidateTargetPath(p); err != nil {
+				m.ui.message = "Error: " + err.Error()
+				return m, nil
+			}
+		}
+		m.filesTab.marked = nil
+		label := fmt.Sprintf("add %d files", len(paths))
+		return m, m.enqueueJob(label, chezmoiActionAdd, writerJobScope(paths...), m.addFilesJob(paths))
+
+	case chezmoiActionEditAttributes:
+		if m.service.IsReadOnly() {
+			m.ui.message = actionUnavailableMessage("read-only mode")
+			return m, nil
+		}
+		absPath := m.selectedManagedPathForOpen()
+		if absPath == "" {
+			m.ui.message = "Error: no file selected"
+			return m, nil
+		}
+		m.ui.busyAction = true
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.sourceEntryCmd(absPath))
+
+	case chezmoiActionExplainIgnore:
+		absPath := m.selectedManagedPathForOpen()
+		if absPath == "" {
+			m.ui.message = "Error: no file selected"
+			return m, nil
 		}
 		m.ui.busyAction = true
-		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.addFileCmd(absPath, opts))
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.explainIgnoreCmd(absPath))
+
+	case chezmoiActionIgnorePath, chezmoiActionIgnorePattern:
+		if m.service.IsReadOnly() {
+			m.ui.message = actionUnavailableMessage("read-only mode")
+			return m, nil
+		}
+		absPath := m.selectedManagedPathForOpen()
+		if absPath == "" {
+			m.ui.message = "Error: no file selected"
+			return m, nil
+		}
+		isDir := false
+		rows := m.activeTreeRows()
+		if m.filesTab.treeView && m.filesTab.cursor < len(rows) {
+			isDir = rows[m.filesTab.cursor].node.isDir
+		}
+		m.ui.busyAction = true
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.ignoreFormCmd(absPath, isDir, action == chezmoiActionIgnorePattern))
+
+	case chezmoiActionEncryptFile, chezmoiActionDecryptFile:
+		if m.service.IsReadOnly() {
+			m.ui.message = actionUnavailableMessage("read-only mode")
+			return m, nil
+		}
+		absPath := m.selectedManagedPathForOpen()
+		if absPath == "" {
+			m.ui.message = "Error: no file selected"
+			return m, nil
+		}
+		decrypt := action == chezmoiActionDecryptFile
+		label := "encrypt "
+		if decrypt {
+			label = "decrypt "
+		}
+		scope := writerJobScope(absPath)
+		return m, m.enqueueJob(label+shortenPath(absPath, m.targetPath), action, scope, m.encryptFileJob(absPath, decrypt))
 	}
 
 	return m, nil
 }
 
+// appendEncryptionItems adds the actions that write an encrypted or a
+// decrypted copy of a file next to it.
+func appendEncryptionItems(items []chezmoiActionItem, readOnly bool) []chezmoiActionItem {
+	items = appendActionItem(
+		items, "Encrypt Copy", chezmoiActionEncryptFile,
+		"Write the file encrypted for the configured recipients next to it, with the .age suffix\ncmd: chezmoi encrypt <path>",
+		!readOnly, "read-only mode",
+	)
+	return appendActionItem(
+		items, "Decrypt Copy", chezmoiActionDecryptFile,
+		"Write the file decrypted next to it, without the .age suffix\ncmd: chezmoi decrypt <path>",
+		!readOnly, "read-only mode",
+	)
+}
+
 // --- Unmanaged Actions Menu ---
 
 func (m *Model) openFilesUnmanagedMenu() {
@@ -258,20 +367,23 @@ func (m *Model) openFilesUnmanagedMenu() {
 		isDir = rows[m.filesTab.cursor].node.isDir
 	}
 
-	if isDir {
+	if n := len(m.filesTab.marked); n > 0 {
 		m.actions.managedItems = appendActionItem(
-			m.actions.managedItems, "Add", chezmoiActionAdd,
-			"Add directory and all contents recursively\ncmd: chezmoi add <path>",
+			m.actions.managedItems, fmt.Sprintf("Add Marked (%d)", n), chezmoiActionAddMarked,
+			"Add every marked file in one background job\ncmd: chezmoi add <path> for each",
 			canAdd, "read-only mode",
 		)
+		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
+	}
+	if isDir {
 		m.actions.managedItems = appendActionItem(
-			m.actions.managedItems, "Add (Exact)", chezmoiActionAddExact,
-			"Add directory exactly — untracked files inside will be removed on apply\ncmd: chezmoi add --exact <path>",
+			m.actions.managedItems, "Add", chezmoiActionAdd,
+			"Add directory and all contents recursively\ncmd: chezmoi add <path>",
 			canAdd, "read-only mode",
 		)
 		m.actions.managedItems = appendActionItem(
-			m.actions.managedItems, "Add (Shallow)", chezmoiActionAddNoRecursive,
-			"Add directory and immediate children only (non-recursive)\ncmd: chezmoi add --recursive=false <path>",
+			m.actions.managedItems, "Add With Options...", chezmoiActionAddWithOptions,
+			"Choose flags such as --exact, --recursive=false, --include, and --exclude; remembered per directory\ncmd: chezmoi add [flags] <path>",
 			canAdd, "read-only mode",
 		)
 	} else {
@@ -281,21 +393,13 @@ func (m *Model) openFilesUnmanagedMenu() {
 			canAdd, "read-only mode",
 		)
 		m.actions.managedItems = appendActionItem(
-			m.actions.managedItems, "Add (Encrypted)", chezmoiActionAddEncrypt,
-			"Add file encrypted with age/gpg\ncmd: chezmoi add --encrypt <path>",
-			canAdd, "read-only mode",
-		)
-		m.actions.managedItems = appendActionItem(
-			m.actions.managedItems, "Add (Template)", chezmoiActionAddTemplate,
-			"Add file as a chezmoi template\ncmd: chezmoi add --template <path>",
-			canAdd, "read-only mode",
-		)
-		m.actions.managedItems = appendActionItem(
-			m.actions.managedItems, "Add (Auto Template)", chezmoiActionAddAutoTemplate,
-			"Add file and auto-detect template variables\ncmd: chezmoi add --autotemplate <path>",
+			m.actions.managedItems, "Add With Options...", chezmoiActionAddWithOptions,
+			"Choose flags such as --encrypt, --template, --autotemplate, and --create; remembered per directory\ncmd: chezmoi add [flags] <path>",
 			canAdd, "read-only mode",
 		)
 		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
+		m.actions.managedItems = appendEncryptionItems(m.actions.managedItems, !canAdd)
+		m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
 		m.actions.managedItems = appendActionItem(
 			m.actions.managedItems, "Open in Editor ($EDITOR)", chezmoiActionEditTarget,
 			"Open file in your configured editor",
@@ -303,6 +407,8 @@ func (m *Model) openFilesUnmanagedMenu() {
 		)
 	}
 
+	m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
+	m.actions.managedItems = appendIgnoreItems(m.actions.managedItems, !canAdd)
 	m.actions.managedItems = append(m.actions.managedItems, chezmoiActionItem{label: "──────────", action: chezmoiActionNone})
 	m.actions.managedItems = appendActionItemWithCapability(
 		m.actions.managedItems, "Open in File Manager", chezmoiActionOpenFileManager, fmCap,
@@ -312,6 +418,31 @@ func (m *Model) openFilesUnmanagedMenu() {
 	m.actions.managedShow = true
 }
 
+// appendIgnoreItems adds the actions that append the row's path, or a
+// pattern like it, to .chezmoiignore, and the one explaining why it is
+// or is not ignored.
+func appendIgnoreItems(items []chezmoiActionItem, readOnly bool) []chezmoiActionItem {
+	items = appendActionItem(
+		items, "Ignore This Path", chezmoiActionIgnorePath,
+		"Append the escaped path to .chezmoiignore, optionally for this OS or host only",
+		!readOnly, "read-only mode",
+	)
+	items = appendActionItem(
+		items, "Ignore This Pattern...", chezmoiActionIgnorePattern,
+		"Append an editable glob, such as dir/*.ext or **/name, to .chezmoiignore",
+		!readOnly, "read-only mode",
+	)
+	return append(items, explainIgnoreItem())
+}
+
+func explainIgnoreItem() chezmoiActionItem {
+	return chezmoiActionItem{
+		label:       "Explain Ignore Status",
+		action:      chezmoiActionExplainIgnore,
+		description: "Show which .chezmoiignore lines match with the current data, and whether the entry filter hides it",
+	}
+}
+
 // --- Files state helpers ---
 
 // reflattenActiveTree re-flattens the tree for the current view mode and clamps the cursor.
@@ -442,6 +573,51 @@ func (m Model) selectedManagedPathForOpen() string {
 	return filepath.Join(m.targetPath, row.node.relPath)
 }
 
+// toggleFilesMark marks the selected unmanaged file for a bulk add, or
+// unmarks it, and moves to the next row.
+func (m *Model) toggleFilesMark() {
+	path := m.selectedUnmanagedFile()
+	if path == "" {
+		m.ui.message = "Only unmanaged files can be marked"
+		return
+	}
+	if m.filesTab.marked[path] {
+		delete(m.filesTab.marked, path)
+	} else {
+		if m.filesTab.marked == nil {
+			m.filesTab.marked = make(map[string]bool)
+		}
+		m.filesTab.marked[path] = true
+	}
+	total := len(m.activeFlatFiles())
+	if m.filesTab.treeView {
+		total = len(m.activeTreeRows())
+	}
+	m.filesTab.cursor = moveCursorDown(m.filesTab.cursor, total, 1)
+	m.ui.message = fmt.Sprintf("%d marked for add", len(m.filesTab.marked))
+}
+
+// selectedUnmanagedFile returns the absolute path of the selected row
+// when it is an unmanaged file, or "" otherwise.
+func (m Model) selectedUnmanagedFile() string {
+	if m.filesTab.treeView {
+		rows := m.activeTreeRows()
+		if m.filesTab.cursor < 0 || m.filesTab.cursor >= len(rows) || rows[m.filesTab.cursor].node.isDir {
+			return ""
+		}
+	}
+	path := m.selectedManagedPathForOpen()
+	switch {
+	case path == "":
+		return ""
+	case m.filesTab.viewMode == managedViewUnmanaged:
+		return path
+	case m.filesTab.viewMode == managedViewAll && m.classifyPath(path) == pathClassUnmanaged:
+		return path
+	}
+	return ""
+}
+
 // --- Files tab query helpers ---
 
 // activeTreeRows returns the tree rows for the current view mode.
@@ -2,6 +2,9 @@ package tui
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -33,8 +36,12 @@ func (m Model) loadManagedCmd() tea.Cmd {
 
 func (m Model) loadIgnoredCmd() tea.Cmd {
 	gen := m.gen
+	ignoredHere := slices.Clone(m.filesTab.ignoredHere)
 	return func() tea.Msg {
 		files, err := m.service.IgnoredFiles()
+		if err == nil && len(ignoredHere) > 0 {
+			files = mergeIgnored(files, existingPaths(ignoredHere))
+		}
 		return chezmoiIgnoredLoadedMsg{files: files, err: err, gen: gen}
 	}
 }
@@ -91,11 +98,77 @@ func (m Model) forgetFileCmd(path string) tea.Cmd {
 	}
 }
 
-func (m Model) addFileCmd(path string, opts chezmoi.AddOptions) tea.Cmd {
+// addFileJob adds path as a background job. The raw chezmoi error is kept
+// as job output; the returned error is the short form for the status bar.
+func (m Model) addFileJob(path string, opts chezmoi.AddOptions) jobFunc {
 	mgr := m.service
-	return func() tea.Msg {
-		err := mgr.Add(path, opts)
-		return chezmoiAddDoneMsg{path: path, err: err}
+	return func(_ context.Context, out chan<- chezmoi.OutputLine) error {
+		if err := mgr.Add(path, opts); err != nil {
+			writeAddError(out, err)
+			return errors.New(mapAddError(err))
+		}
+		return nil
+	}
+}
+
+// addFilesJob adds each of paths in turn as one background job. A failed
+// add is reported and the rest still run; cancelling stops before the
+// next path.
+func (m Model) addFilesJob(paths []string) jobFunc {
+	mgr := m.service
+	targetPath := m.targetPath
+	return func(ctx context.Context, out chan<- chezmoi.OutputLine) error {
+		failed := 0
+		for _, path := range paths {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			display := shortenPath(path, targetPath)
+			if err := mgr.Add(path, chezmoi.AddOptions{}); err != nil {
+				failed++
+				out <- chezmoi.OutputLine{Text: display + ": " + mapAddError(err), Stderr: true}
+				writeAddError(out, err)
+				continue
+			}
+			out <- chezmoi.OutputLine{Text: "added " + display}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d adds failed", failed, len(paths))
+		}
+		return nil
+	}
+}
+
+// writeAddError sends the raw add error, or each secret finding that
+// blocked the add, to the job output.
+func writeAddError(out chan<- chezmoi.OutputLine, err error) {
+	var found *chezmoi.SecretsFoundError
+	if errors.As(err, &found) {
+		for _, f := range found.Findings {
+			out <- chezmoi.OutputLine{Text: f.String(), Stderr: true}
+		}
+		out <- chezmoi.OutputLine{Text: "add encrypted, move the values into template data, or allow them with secret_allowlist"}
+		return
+	}
+	out <- chezmoi.OutputLine{Text: err.Error(), Stderr: true}
+}
+
+// encryptFileJob writes an encrypted copy of path next to it, or a
+// decrypted one when decrypt is set.
+func (m Model) encryptFileJob(path string, decrypt bool) jobFunc {
+	mgr := m.service
+	targetPath := m.targetPath
+	return func(_ context.Context, out chan<- chezmoi.OutputLine) error {
+		write := mgr.EncryptFile
+		if decrypt {
+			write = mgr.DecryptFile
+		}
+		written, err := write(path)
+		if err != nil {
+			return err
+		}
+		out <- chezmoi.OutputLine{Text: "wrote " + shortenPath(written, targetPath)}
+		return nil
 	}
 }
 
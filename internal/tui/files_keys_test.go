@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestHandleManagedTreeKeysEditShortcutNoOp(t *testing.T) {
@@ -657,3 +658,33 @@ func newOpaqueTreeModelForTest(rootAbs string) Model {
 	m.filesTab.cursor = 0
 	return m
 }
+
+func TestMarkedUnmanagedFilesAddInOneJob(t *testing.T) {
+	home := t.TempDir()
+	bashrc := filepath.Join(home, ".bashrc")
+	zshrc := filepath.Join(home, ".zshrc")
+	m := newTestModel(WithTab(1), WithTarget(home))
+	m.filesTab.treeView = false
+	m.filesTab.viewMode = managedViewUnmanaged
+	m.filesTab.views[managedViewUnmanaged].files = []string{bashrc, zshrc}
+	m.filesTab.views[managedViewUnmanaged].filteredFiles = []string{bashrc, zshrc}
+
+	m, _ = sendKey(t, m, runeKey("x"))
+	m, _ = sendKey(t, m, runeKey("x"))
+	if len(m.filesTab.marked) != 2 || !strings.HasPrefix(ansi.Strip(m.renderManagedFlatView(80)), "● ") {
+		t.Fatalf("expected both files marked, got %v", m.filesTab.marked)
+	}
+
+	m, _ = sendKey(t, m, runeKey("a"))
+	if len(m.actions.managedItems) == 0 || m.actions.managedItems[0].label != "Add Marked (2)" {
+		t.Fatalf("expected the bulk add first in the menu, got %+v", m.actions.managedItems)
+	}
+	next, _ := m.executeFilesAction(chezmoiActionAddMarked)
+	m = next.(Model)
+	if len(m.jobs.jobs) != 1 || m.jobs.jobs[0].label != "add 2 files" || m.filesTab.marked != nil {
+		t.Fatalf("expected one add job and marks cleared, got %+v", m.jobs.jobs)
+	}
+	if scope := m.jobs.jobs[0].scope; !scope.conflicts(writerJobScope(filepath.Join(home, ".profile"))) {
+		t.Fatal("expected the bulk add to serialize with other writers")
+	}
+}
@@ -357,6 +357,9 @@ func (m Model) handleFilesMainKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd, boo
 	case key.Matches(msg, ChezManagedKeys.Refresh):
 		next, cmd := m.refreshFilesViewMode()
 		return next, cmd, true
+	case key.Matches(msg, ChezManagedKeys.Mark):
+		m.toggleFilesMark()
+		return m, nil, true
 	default:
 		return m, nil, false
 	}
@@ -124,7 +124,7 @@ func (m Model) renderTreeRow(row flatTreeRow, selected bool, maxWidth int) strin
 		nameStr = icon + row.node.name
 	}
 
-	content := "  " + treeRowPrefix(row) + nameStr + treeRowSuffix(row, isIgnored, isUnmanaged, selected)
+	content := m.markGutter(row.node.absPath, "  ") + treeRowPrefix(row) + nameStr + treeRowSuffix(row, isIgnored, isUnmanaged, selected)
 	content = visualTruncate(content, maxWidth)
 
 	if selected {
@@ -136,6 +136,15 @@ func (m Model) renderTreeRow(row flatTreeRow, selected bool, maxWidth int) strin
 	return content
 }
 
+// markGutter returns the row gutter, replaced by a marker when path is
+// marked for a bulk add.
+func (m Model) markGutter(path, gutter string) string {
+	if m.filesTab.marked[path] {
+		return "● "
+	}
+	return gutter
+}
+
 // treeRowPrefix builds the tree connector string for a row based on its depth
 // and sibling position. Returns an empty string for root-level rows.
 func treeRowPrefix(row flatTreeRow) string {
@@ -260,6 +269,7 @@ func (m Model) renderManagedFlatView(maxWidth int) string {
 		} else if isIgnored || isUnmanaged {
 			style = activeTheme.DimText
 		}
+		cursor = m.markGutter(path, cursor)
 
 		icon := renderFileIcon(filepath.Base(path), false, isSelected, m.iconMode)
 		displayPath := shortenPath(path, m.targetPath)
@@ -411,10 +421,14 @@ func (m Model) renderManagedStatusBar() string {
 		if m.filesTab.treeView && strings.TrimSpace(m.filterInput.Value()) != "" && !m.filterInput.Focused() {
 			clearHint = " | c clear search"
 		}
+		markHint := ""
+		if m.filesTab.viewMode == managedViewUnmanaged || m.filesTab.viewMode == managedViewAll {
+			markHint = " | x mark"
+		}
 		if m.filesTab.treeView {
-			help = m.helpHint("↑/↓ nav | enter open/toggle | a actions | t flat | f view/filter | r refresh" + clearHint + panelHint + " | ? keys | esc back")
+			help = m.helpHint("↑/↓ nav | enter open/toggle | a actions" + markHint + " | t flat | f view/filter | r refresh" + clearHint + panelHint + " | ? keys | esc back")
 		} else {
-			help = m.helpHint("↑/↓ nav | enter actions | a actions | t tree | f view/filter | r refresh" + panelHint + " | ? keys | esc back")
+			help = m.helpHint("↑/↓ nav | enter actions | a actions" + markHint + " | t tree | f view/filter | r refresh" + panelHint + " | ? keys | esc back")
 		}
 	}
 	return statusBar + "\n" + help
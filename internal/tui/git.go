@@ -74,3 +74,44 @@ func (m Model) renderGitInfoHeader() string {
 
 	return "  " + strings.Join(parts, " · ")
 }
+
+// renderIncomingFileRow renders an upstream file change mapped to its target
+// path, flagging targets that also have local drift.
+func (m Model) renderIncomingFileRow(f chezmoi.IncomingFile, selected bool, maxWidth int) string {
+	cursor := "    "
+	if selected {
+		cursor = "  > "
+	}
+
+	display := f.SourcePath
+	if f.TargetPath != "" {
+		display = shortenPath(f.TargetPath, m.targetPath)
+	}
+	icon := renderFileIcon(filepath.Base(display), false, selected, m.iconMode)
+	drift := m.incomingFileHasDrift(f)
+
+	if selected {
+		content := cursor + f.StatusCode + " " + icon + display
+		if drift {
+			content += " [drift]"
+		}
+		content = visualTruncate(content, maxWidth)
+		return activeTheme.Selected.Width(maxWidth).Render(content)
+	}
+
+	statusStyle := activeTheme.PrimaryFg
+	switch f.StatusCode {
+	case "A":
+		statusStyle = activeTheme.SuccessFg
+	case "D":
+		statusStyle = activeTheme.DangerFg
+	}
+	content := cursor + statusStyle.Render(f.StatusCode) + " " + icon + display
+	if f.TargetPath == "" {
+		content += activeTheme.DimText.Render(" (source only)")
+	}
+	if drift {
+		content += activeTheme.DangerFg.Render(" [drift]")
+	}
+	return visualTruncate(content, maxWidth)
+}
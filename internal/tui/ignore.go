@@ -0,0 +1,328 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// ignoreFormState holds the form that appends a row's path, or a pattern
+// like it, to .chezmoiignore.
+type ignoreFormState struct {
+	form      *huh.Form
+	path      string
+	rel       string // path relative to the destination directory, slash-separated
+	editable  bool   // pattern mode: the pattern can be changed
+	pattern   *string
+	condition *chezmoi.IgnoreCondition
+	machine   chezmoi.IgnoreMachine
+}
+
+// ignoreFormCmd works out the pattern for path and the current machine's
+// values for the condition choices.
+func (m Model) ignoreFormCmd(path string, isDir, editable bool) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		pattern, err := svc.IgnorePathPattern(path)
+		if err != nil {
+			return ignoreFormLoadedMsg{path: path, err: err}
+		}
+		rel, _ := filepath.Rel(svc.TargetPath(), path) // checked by IgnorePathPattern
+		rel = filepath.ToSlash(rel)
+		if editable {
+			pattern = chezmoi.SuggestIgnorePattern(rel, isDir)
+		}
+		machine, err := svc.IgnoreMachine()
+		return ignoreFormLoadedMsg{path: path, rel: rel, pattern: pattern, editable: editable, machine: machine, err: err}
+	}
+}
+
+func (m Model) handleIgnoreFormLoaded(msg ignoreFormLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.pattern == "" {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if msg.err != nil {
+		// Without template data only "everywhere" is offered.
+		m.ui.message = "Error: " + msg.err.Error()
+	}
+	pattern := msg.pattern
+	m.view = IgnoreFormScreen
+	m.actions.show = false
+	m.ignoreForm = ignoreFormState{
+		path:      msg.path,
+		rel:       msg.rel,
+		editable:  msg.editable,
+		pattern:   &pattern,
+		condition: &chezmoi.IgnoreCondition{},
+		machine:   msg.machine,
+	}
+	m.ignoreForm.form = m.buildIgnoreForm()
+	return m, m.ignoreForm.form.Init()
+}
+
+// ignoreConditions lists the conditionals offered for the current
+// machine's OS and hostname, "everywhere" first.
+func ignoreConditions(machine chezmoi.IgnoreMachine) []huh.Option[chezmoi.IgnoreCondition] {
+	opts := []huh.Option[chezmoi.IgnoreCondition]{huh.NewOption("Everywhere", chezmoi.IgnoreCondition{})}
+	for _, c := range []struct{ variable, value, noun string }{
+		{"chezmoi.os", machine.OS, "OS"},
+		{"chezmoi.hostname", machine.Hostname, "host"},
+	} {
+		if c.value == "" {
+			continue
+		}
+		opts = append(opts,
+			huh.NewOption(fmt.Sprintf("Only on %s %s", c.noun, c.value), chezmoi.IgnoreCondition{Variable: c.variable, Value: c.value}),
+			huh.NewOption(fmt.Sprintf("Everywhere except %s %s", c.noun, c.value), chezmoi.IgnoreCondition{Variable: c.variable, Value: c.value, Negate: true}),
+		)
+	}
+	return opts
+}
+
+func (m Model) buildIgnoreForm() *huh.Form {
+	s := m.ignoreForm
+	var fields []huh.Field
+	if s.editable {
+		fields = append(fields, huh.NewInput().
+			Title("Ignore pattern").
+			Description("Glob relative to the destination directory").
+			Value(s.pattern).
+			Validate(chezmoi.ValidateIgnorePattern))
+	} else {
+		fields = append(fields, huh.NewNote().
+			Title("Ignore path").
+			Description(*s.pattern))
+	}
+	conditions := ignoreConditions(s.machine)
+	fields = append(fields, huh.NewSelect[chezmoi.IgnoreCondition]().
+		Title("Where").
+		Options(conditions...).
+		Height(len(conditions)+1).
+		Value(s.condition))
+
+	return huh.NewForm(huh.NewGroup(fields...)).
+		WithTheme(huh.ThemeFunc(huh.ThemeCatppuccin)).
+		WithKeyMap(whatIfFormKeyMap()).
+		WithWidth(56).
+		WithShowHelp(false)
+}
+
+// handleIgnoreFormUpdate routes messages to the ignore form and appends
+// the pattern on submit.
+func (m Model) handleIgnoreFormUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.ignoreForm.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.ignoreForm.form = f
+	}
+
+	switch m.ignoreForm.form.State {
+	case huh.StateCompleted:
+		s := m.ignoreForm
+		m.ignoreForm = ignoreFormState{}
+		m.view = StatusScreen
+		m.ui.busyAction = true
+		// Conditions are built from this machine's values, so only a
+		// negated one fails to match here.
+		appliesHere := !s.condition.Negate && chezmoi.MatchIgnorePattern(*s.pattern, s.rel)
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, m.appendIgnoreCmd(s.path, *s.pattern, *s.condition, appliesHere))
+	case huh.StateAborted:
+		m.ignoreForm = ignoreFormState{}
+		m.view = StatusScreen
+		return m, nil
+	}
+	return m, cmd
+}
+
+func (m Model) appendIgnoreCmd(path, pattern string, cond chezmoi.IgnoreCondition, appliesHere bool) tea.Cmd {
+	svc := m.service
+	return func() tea.Msg {
+		err := svc.AppendIgnorePattern(pattern, cond)
+		return ignoreAppendedMsg{path: path, pattern: pattern, appliesHere: appliesHere, err: err}
+	}
+}
+
+// handleIgnoreAppended moves the row to the Ignored view at once if the
+// pattern ignores it on this machine, then reloads the file lists.
+func (m Model) handleIgnoreAppended(msg ignoreAppendedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m.ui.message = "Added " + msg.pattern + " to .chezmoiignore"
+	if msg.appliesHere {
+		m.markIgnored(msg.path)
+	}
+
+	m.panel.clearCache()
+	cmds := []tea.Cmd{m.postActionReloadCmds(), sendRefreshMsg()}
+	if m.filesTab.views[managedViewIgnored].files != nil {
+		cmds = append(cmds, m.loadIgnoredCmd())
+	}
+	if m.filesTab.views[managedViewUnmanaged].files != nil {
+		cmds = append(cmds, m.loadUnmanagedCmd())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// markIgnored moves path, and everything under it, from the managed and
+// unmanaged views to the Ignored view. chezmoi ignored only reports
+// entries in the source state, so unmanaged paths ignored from here are
+// remembered and merged into later loads.
+func (m *Model) markIgnored(path string) {
+	inPath := func(p string) bool { return p == path || hasPathPrefix(p, path) }
+	for _, mode := range []managedViewMode{managedViewManaged, managedViewUnmanaged} {
+		fv := &m.filesTab.views[mode]
+		if fv.files == nil {
+			continue
+		}
+		fv.files = slices.DeleteFunc(slices.Clone(fv.files), inPath)
+		fv.filteredFiles = fv.files
+		m.rebuildFileViewTree(mode)
+	}
+	if !slices.Contains(m.filesTab.ignoredHere, path) {
+		m.filesTab.ignoredHere = append(m.filesTab.ignoredHere, path)
+	}
+	ignored := &m.filesTab.views[managedViewIgnored]
+	ignored.files = mergeIgnored(ignored.files, []string{path})
+	ignored.filteredFiles = ignored.files
+	m.rebuildFileViewTree(managedViewIgnored)
+	m.rebuildDatasetAndAllView()
+	m.applyManagedFilter()
+}
+
+// mergeIgnored returns files with extra added, sorted and without
+// duplicates.
+func mergeIgnored(files, extra []string) []string {
+	merged := append(slices.Clone(files), extra...)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+// existingPaths returns the paths that still exist.
+func existingPaths(paths []string) []string {
+	var existing []string
+	for _, p := range paths {
+		if _, err := os.Lstat(p); err == nil {
+			existing = append(existing, p)
+		}
+	}
+	return existing
+}
+
+// renderIgnoreFormScreen wraps the ignore form in a centered box.
+func (m Model) renderIgnoreFormScreen() string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.Primary).
+		Padding(1, 2).
+		Width(60)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(m.ignoreForm.form.View()))
+}
+
+// explainIgnoreCmd evaluates the ignore rules, and the Files tab's entry
+// filter, against path.
+func (m Model) explainIgnoreCmd(path string) tea.Cmd {
+	svc := m.service
+	filter := m.filesTab.entryFilter
+	return func() tea.Msg {
+		e, err := svc.ExplainIgnore(path, filter)
+		return ignoreExplainedMsg{path: path, explanation: e, err: err}
+	}
+}
+
+func (m Model) handleIgnoreExplained(msg ignoreExplainedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	raw, styled := m.ignoreExplanationLines(msg.explanation)
+	m.view = DiffScreen
+	m.diff.fromIgnoreExplain = true
+	m.diff.content = strings.Join(raw, "\n")
+	m.diff.path = msg.path
+	m.diff.rawLines = raw
+	m.diff.lines = styled
+	m.diff.pagerApplied = true // lines are styled here
+	m.diff.resetViewport()
+	m.actions.show = false
+	return m, nil
+}
+
+// ignoreExplainLegend labels the line markers of an ignore explanation.
+const ignoreExplainLegend = "▶ ignores  ! keeps  · skipped with current data"
+
+// ignoreExplanationLines lays out the verdict, then each .chezmoiignore
+// with the lines that matched highlighted. It returns plain and styled
+// lines.
+func (m Model) ignoreExplanationLines(e chezmoi.IgnoreExplanation) (raw, styled []string) {
+	add := func(style lipgloss.Style, line string) {
+		raw = append(raw, line)
+		styled = append(styled, "  "+style.Render(line))
+	}
+
+	add(activeTheme.BoldPrimary, "Path: "+shortenPath(e.Target, m.targetPath))
+	switch {
+	case e.Ignored() && e.IgnoredAt == e.Rel:
+		add(activeTheme.BoldWarning, "Ignored: a pattern below matches it and no \"!\" pattern does")
+	case e.Ignored():
+		add(activeTheme.BoldWarning, "Ignored: its parent "+e.IgnoredAt+" is ignored")
+	case len(e.Files) == 0:
+		add(activeTheme.BoldSuccess, "Not ignored: there is no .chezmoiignore")
+	default:
+		add(activeTheme.BoldSuccess, "Not ignored by .chezmoiignore")
+	}
+	if !e.Filter.IsZero() {
+		switch {
+		case e.Hidden > 0:
+			add(activeTheme.BoldWarning, fmt.Sprintf("Entry filter: hides %d of %d managed entries (%s)", e.Hidden, e.Managed, e.Filter))
+		case e.Managed > 0:
+			add(activeTheme.DimText, "Entry filter: shows it")
+		}
+	}
+
+	for _, f := range e.Files {
+		add(activeTheme.Normal, "")
+		title := "── " + shortenPath(f.Path, m.targetPath)
+		if f.Dir != "" {
+			title += " (patterns under " + f.Dir + "/)"
+		}
+		add(activeTheme.BoldPrimary, title)
+		if f.RenderError != "" {
+			add(activeTheme.DangerFg, "Does not render: "+f.RenderError)
+		}
+		for _, line := range f.Lines {
+			mark, style, note := " ", activeTheme.Normal, ""
+			switch line.State {
+			case chezmoi.IgnoreLineMatch:
+				mark, style, note = "▶", activeTheme.BoldWarning, "ignores "+line.Matched
+			case chezmoi.IgnoreLineNegated:
+				mark, style, note = "!", activeTheme.BoldSuccess, "keeps "+line.Matched
+			case chezmoi.IgnoreLineSkipped:
+				mark, style = "·", activeTheme.DimText
+			}
+			if strings.Contains(line.Text, "{{") && len(line.Patterns) > 0 {
+				note = strings.TrimSpace("renders " + strings.Join(line.Patterns, ", ") + "; " + note)
+				note = strings.TrimSuffix(note, ";")
+			}
+			text := fmt.Sprintf("%s %4d  %s", mark, line.Number, line.Text)
+			if note != "" {
+				text += "    ← " + note
+			}
+			add(style, text)
+		}
+	}
+	return raw, styled
+}
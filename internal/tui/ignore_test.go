@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// submitIgnoreForm presses Enter through the form's fields, feeding it its
+// own commands, until it closes.
+func submitIgnoreForm(t *testing.T, m Model) (Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for range 4 {
+		m, cmd = sendKey(t, m, specialKey(tea.KeyEnter))
+		for cmd != nil && m.view == IgnoreFormScreen {
+			msg := cmd()
+			if _, ok := msg.(tea.BatchMsg); ok {
+				break
+			}
+			m, cmd = sendMsg(t, m, msg)
+		}
+		if m.view != IgnoreFormScreen {
+			break
+		}
+	}
+	return m, cmd
+}
+
+func TestIgnorePatternFormOffersMachineConditions(t *testing.T) {
+	m := newTestModel(WithSize(120, 40))
+	m, _ = sendMsg(t, m, ignoreFormLoadedMsg{
+		path:     "/home/test/.cache/app/log.txt",
+		rel:      ".cache/app/log.txt",
+		pattern:  ".cache/app/*.txt",
+		editable: true,
+		machine:  chezmoi.IgnoreMachine{OS: "darwin", Hostname: "laptop"},
+	})
+	if m.view != IgnoreFormScreen {
+		t.Fatalf("expected the ignore form, got view %v", m.view)
+	}
+	screen := ansi.Strip(m.renderIgnoreFormScreen())
+	for _, want := range []string{"Ignore pattern", ".cache/app/*.txt", "Everywhere", "Only on OS darwin", "Everywhere except host laptop"} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("expected %q in the form, got:\n%s", want, screen)
+		}
+	}
+
+	m, cmd := submitIgnoreForm(t, m)
+	if m.view != StatusScreen || !m.ui.busyAction || cmd == nil {
+		t.Fatalf("expected the pattern appended on submit, got view %v", m.view)
+	}
+}
+
+func TestIgnoreAppendedMovesRowToIgnoredView(t *testing.T) {
+	home := t.TempDir()
+	cache := filepath.Join(home, ".cache")
+	if err := os.MkdirAll(filepath.Join(cache, "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	notes := filepath.Join(home, "notes.txt")
+	if err := os.WriteFile(notes, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(WithTarget(home), WithSize(120, 40))
+	m.targetPath = home
+	m.filesTab.viewMode = managedViewUnmanaged
+	m.filesTab.views[managedViewManaged].files = []string{}
+	m.filesTab.views[managedViewIgnored].files = []string{}
+	m.filesTab.views[managedViewUnmanaged].files = []string{cache, filepath.Join(cache, "app"), notes}
+	m.filesTab.dataset = rebuildDataset(&m.filesTab)
+	m.rebuildFileViewTree(managedViewUnmanaged)
+
+	m, _ = sendMsg(t, m, ignoreAppendedMsg{path: cache, pattern: ".cache", appliesHere: true})
+	if m.ui.message != "Added .cache to .chezmoiignore" {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+	if got := m.filesTab.views[managedViewUnmanaged].files; !slices.Equal(got, []string{notes}) {
+		t.Fatalf("expected .cache and its children removed from unmanaged, got %q", got)
+	}
+	if got := m.filesTab.views[managedViewIgnored].files; !slices.Equal(got, []string{cache}) {
+		t.Fatalf("expected .cache in the ignored view, got %q", got)
+	}
+	if m.classifyPath(cache) != pathClassIgnored {
+		t.Fatal("expected the dataset to classify .cache as ignored")
+	}
+
+	// Later loads of chezmoi ignored keep the unmanaged path.
+	if got := mergeIgnored(nil, existingPaths(m.filesTab.ignoredHere)); !slices.Equal(got, []string{cache}) {
+		t.Fatalf("expected .cache remembered, got %q", got)
+	}
+
+	m, _ = sendMsg(t, m, ignoreAppendedMsg{path: notes, pattern: "notes.txt"})
+	if got := m.filesTab.views[managedViewUnmanaged].files; !slices.Equal(got, []string{notes}) {
+		t.Fatalf("expected a pattern not applying here to leave the row, got %q", got)
+	}
+}
+
+func TestIgnoreExplanationHighlightsMatchingLines(t *testing.T) {
+	m := newTestModel(WithSize(120, 40))
+	m.targetPath = "/home/test"
+	m.filesTab.viewMode = managedViewIgnored
+	m.filesTab.views[managedViewIgnored].files = []string{"/home/test/.cache/keep/x"}
+	m.filesTab.views[managedViewIgnored].filteredFiles = m.filesTab.views[managedViewIgnored].files
+	m.rebuildFileViewTree(managedViewIgnored)
+	m.openFilesActiveMenu()
+	if len(m.actions.managedItems) == 0 || m.actions.managedItems[0].action != chezmoiActionExplainIgnore {
+		t.Fatalf("expected Explain first in the ignored menu, got %+v", m.actions.managedItems)
+	}
+
+	m, _ = sendMsg(t, m, ignoreExplainedMsg{path: "/home/test/.cache/keep/x", explanation: chezmoi.IgnoreExplanation{
+		Target:    "/home/test/.cache/keep/x",
+		Rel:       ".cache/keep/x",
+		IgnoredAt: ".cache",
+		Files: []chezmoi.IgnoreRuleFile{{
+			Path: "/src/.chezmoiignore",
+			Lines: []chezmoi.IgnoreRuleLine{
+				{Number: 1, Text: ".cache", Patterns: []string{".cache"}, State: chezmoi.IgnoreLineMatch, Matched: ".cache"},
+				{Number: 2, Text: `{{ if ne .chezmoi.os "linux" }}`, State: chezmoi.IgnoreLineInert},
+				{Number: 3, Text: ".config/linux-only", State: chezmoi.IgnoreLineSkipped},
+				{Number: 4, Text: "{{ end }}", State: chezmoi.IgnoreLineInert},
+				{Number: 5, Text: "!.cache/keep", Patterns: []string{"!.cache/keep"}, State: chezmoi.IgnoreLineNegated, Matched: ".cache/keep"},
+			},
+		}},
+		Filter:  chezmoi.EntryFilter{Include: []chezmoi.EntryType{chezmoi.EntryTemplates}},
+		Managed: 2,
+		Hidden:  2,
+	}})
+	if m.view != DiffScreen || !m.diff.fromIgnoreExplain {
+		t.Fatalf("expected the explanation, got view %v", m.view)
+	}
+	screen := ansi.Strip(m.renderDiffView())
+	for _, want := range []string{
+		"Ignored: its parent .cache is ignored",
+		"Entry filter: hides 2 of 2 managed entries (--include=templates)",
+		"▶    1  .cache    ← ignores .cache",
+		"·    3  .config/linux-only",
+		"!    5  !.cache/keep    ← keeps .cache/keep",
+		"esc back",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("expected %q in the explanation, got:\n%s", want, screen)
+		}
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen || m.diff.fromIgnoreExplain {
+		t.Fatalf("expected Esc to close the explanation, got view %v", m.view)
+	}
+}
@@ -9,6 +9,13 @@ func (m Model) loadInfoSubViewCmd(view int) tea.Cmd {
 	mgr := m.service
 	format := m.info.format
 	gen := m.gen
+	if view == infoViewTemplate {
+		// The Template sub-view has no content to fetch, only its history.
+		return m.loadTemplateHistoryCmd()
+	}
+	if view == infoViewState {
+		return m.loadStateDumpCmd()
+	}
 	return func() tea.Msg {
 		var content string
 		var err error
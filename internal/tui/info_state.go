@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+)
+
+// stateBrowserState holds the Info tab's State sub-view: chezmoi's
+// persistent state as a tree of buckets, their keys, and the keys' values.
+type stateBrowserState struct {
+	state    map[string]map[string]json.RawMessage
+	expanded map[string]bool // open buckets, and open keys by stateKeyID
+	cursor   int
+	pending  stateRow // what the delete confirmation removes
+}
+
+// stateRow is one line of the State tree: a bucket, a key in it, or a
+// line of a key's value.
+type stateRow struct {
+	bucket string
+	key    string // "" on bucket rows
+	value  string // set on value lines
+}
+
+// isBucket reports whether r is a bucket row.
+func (r stateRow) isBucket() bool { return r.key == "" }
+
+// isValue reports whether r is a line of a key's value.
+func (r stateRow) isValue() bool { return r.value != "" }
+
+func stateKeyID(bucket, key string) string { return bucket + "\x00" + key }
+
+// rows flattens the open parts of the tree, buckets and keys sorted.
+func (s stateBrowserState) rows() []stateRow {
+	var rows []stateRow
+	for _, bucket := range slices.Sorted(maps.Keys(s.state)) {
+		rows = append(rows, stateRow{bucket: bucket})
+		if !s.expanded[bucket] {
+			continue
+		}
+		for _, k := range slices.Sorted(maps.Keys(s.state[bucket])) {
+			rows = append(rows, stateRow{bucket: bucket, key: k})
+			if !s.expanded[stateKeyID(bucket, k)] {
+				continue
+			}
+			for _, line := range stateValueLines(s.state[bucket][k]) {
+				rows = append(rows, stateRow{bucket: bucket, key: k, value: line})
+			}
+		}
+	}
+	return rows
+}
+
+// selectedRow returns the row under the cursor.
+func (s stateBrowserState) selectedRow() (stateRow, bool) {
+	rows := s.rows()
+	if s.cursor < 0 || s.cursor >= len(rows) {
+		return stateRow{}, false
+	}
+	return rows[s.cursor], true
+}
+
+// stateValueLines pretty-prints a state value.
+func stateValueLines(raw json.RawMessage) []string {
+	var out bytes.Buffer
+	if json.Indent(&out, raw, "", "  ") != nil {
+		return []string{string(raw)}
+	}
+	return strings.Split(out.String(), "\n")
+}
+
+// stateValueSummary is a state value compacted onto one line.
+func stateValueSummary(raw json.RawMessage) string {
+	var out bytes.Buffer
+	if json.Compact(&out, raw) != nil {
+		return string(raw)
+	}
+	return out.String()
+}
+
+func (m Model) loadStateDumpCmd() tea.Cmd {
+	gen := m.gen
+	return func() tea.Msg {
+		state, err := m.service.StateDump()
+		return infoStateLoadedMsg{state: state, err: err, gen: gen}
+	}
+}
+
+func (m Model) handleInfoStateLoaded(msg infoStateLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.gen {
+		return m, nil
+	}
+	view := &m.info.views[infoViewState]
+	view.loading = false
+	view.loaded = true
+	view.lines = nil
+	if msg.err != nil {
+		m.info.state.state = nil
+		view.lines = []string{"Error: " + msg.err.Error()}
+		return m, nil
+	}
+	// Keep the cursor on the same row across reloads.
+	selected, hadSelection := m.info.state.selectedRow()
+	m.info.state.state = msg.state
+	if m.info.state.expanded == nil {
+		m.info.state.expanded = make(map[string]bool)
+	}
+	rows := m.info.state.rows()
+	m.info.state.cursor = min(m.info.state.cursor, max(0, len(rows)-1))
+	if hadSelection {
+		if i := slices.Index(rows, selected); i >= 0 {
+			m.info.state.cursor = i
+		}
+	}
+	return m, nil
+}
+
+// handleInfoStateKeys handles the State sub-view's tree keys. Keys it does
+// not use fall through to the Info tab's.
+func (m Model) handleInfoStateKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd, bool) {
+	rows := m.info.state.rows()
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.info.state.cursor = moveCursorUp(m.info.state.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.info.state.cursor = moveCursorDown(m.info.state.cursor, len(rows), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.info.state.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.info.state.cursor = max(0, len(rows)-1)
+	case key.Matches(msg, ChezInfoKeys.Toggle):
+		m.toggleStateRow()
+	case key.Matches(msg, ChezInfoKeys.Delete):
+		updated, cmd := m.confirmStateDelete()
+		return updated, cmd, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// toggleStateRow opens or closes the bucket or key under the cursor. On a
+// value line it closes the key the value belongs to.
+func (m *Model) toggleStateRow() {
+	row, ok := m.info.state.selectedRow()
+	if !ok {
+		return
+	}
+	id := row.bucket
+	if !row.isBucket() {
+		id = stateKeyID(row.bucket, row.key)
+	}
+	m.info.state.expanded[id] = !m.info.state.expanded[id]
+	if !m.info.state.expanded[id] {
+		// Land on the row that was closed.
+		m.info.state.cursor = slices.Index(m.info.state.rows(), stateRow{bucket: row.bucket, key: row.key})
+	}
+}
+
+// confirmStateDelete asks before deleting the key or bucket under the
+// cursor.
+func (m Model) confirmStateDelete() (tea.Model, tea.Cmd) {
+	row, ok := m.info.state.selectedRow()
+	if !ok {
+		return m, nil
+	}
+	if m.service.IsReadOnly() {
+		m.ui.message = actionUnavailableMessage("read-only mode")
+		return m, nil
+	}
+	row.value = ""
+	m.info.state.pending = row
+	if row.isBucket() {
+		n := len(m.info.state.state[row.bucket])
+		noun := "keys"
+		if n == 1 {
+			noun = "key"
+		}
+		return m.showConfirmScreen(chezmoiActionDeleteStateBucket,
+			"delete state bucket "+row.bucket+" and its "+strconv.Itoa(n)+" "+noun), nil
+	}
+	return m.showConfirmScreen(chezmoiActionDeleteStateKey,
+		"delete "+row.key+" from state bucket "+row.bucket), nil
+}
+
+// deleteStateCmd deletes the confirmed key or bucket.
+func (m Model) deleteStateCmd() tea.Cmd {
+	row := m.info.state.pending
+	return func() tea.Msg {
+		var err error
+		if row.isBucket() {
+			err = m.service.DeleteStateBucket(row.bucket)
+		} else {
+			err = m.service.DeleteStateKey(row.bucket, row.key)
+		}
+		return stateDeletedMsg{bucket: row.bucket, key: row.key, err: err}
+	}
+}
+
+func (m Model) handleStateDeleted(msg stateDeletedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	m.info.state.pending = stateRow{}
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if msg.key == "" {
+		m.ui.message = "deleted state bucket " + msg.bucket
+	} else {
+		m.ui.message = "deleted " + msg.key + " from " + msg.bucket
+	}
+	// Status depends on the entry state, so reload it along with the tree.
+	reload := m.postActionReloadCmds()
+	m.info.views[infoViewState].loading = true
+	return m, tea.Batch(reload, m.loadStateDumpCmd(), sendRefreshMsg())
+}
+
+// stateSummary counts the buckets and keys, e.g. "3 buckets · 41 keys".
+func (s stateBrowserState) stateSummary() string {
+	keys := 0
+	for _, bucket := range s.state {
+		keys += len(bucket)
+	}
+	buckets, noun := "buckets", "keys"
+	if len(s.state) == 1 {
+		buckets = "bucket"
+	}
+	if keys == 1 {
+		noun = "key"
+	}
+	return fmt.Sprintf("%d %s · %d %s", len(s.state), buckets, keys, noun)
+}
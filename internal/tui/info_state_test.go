@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var testInfoStateLoaded = infoStateLoadedMsg{state: map[string]map[string]json.RawMessage{
+	"entryState": {
+		"/home/test/.bashrc": json.RawMessage(`{"type":"file","contentsSHA256":"abc"}`),
+		"/home/test/.zshrc":  json.RawMessage(`{"type":"file"}`),
+	},
+	"scriptState": {
+		"f00d": json.RawMessage(`{"name":"setup.sh","runAt":"2026-03-01T10:00:00Z"}`),
+	},
+}}
+
+func TestStateBrowserExpandsBucketsAndKeys(t *testing.T) {
+	m := newTestModel(WithInfoView(infoViewState), WithSize(120, 30), WithLoaded(testInfoStateLoaded))
+
+	rendered := ansi.Strip(m.renderInfoTabContent())
+	for _, want := range []string{"[State]", "2 buckets · 3 keys", "▸ entryState  2 keys", "▸ scriptState  1 key"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, ".bashrc") {
+		t.Fatalf("expected buckets collapsed:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	m, _ = sendKey(t, m, runeKey("j"))
+	rendered = ansi.Strip(m.renderInfoTabContent())
+	if !strings.Contains(rendered, `▸ /home/test/.bashrc  {"type":"file","contentsSHA256":"abc"}`) {
+		t.Fatalf("expected the bucket's keys with their values:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	rendered = ansi.Strip(m.renderInfoTabContent())
+	if !strings.Contains(rendered, `"contentsSHA256": "abc"`) {
+		t.Fatalf("expected the key's value pretty-printed:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, runeKey("j"))
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	if row, _ := m.info.state.selectedRow(); row.key != "/home/test/.bashrc" || row.isValue() {
+		t.Fatalf("expected enter on a value to close its key, got %+v", row)
+	}
+}
+
+func TestStateBrowserDeletesAfterConfirm(t *testing.T) {
+	m := newTestModel(WithInfoView(infoViewState), WithSize(120, 30), WithLoaded(testInfoStateLoaded))
+	m, _ = sendKey(t, m, runeKey("G"))
+
+	m, _ = sendKey(t, m, runeKey("d"))
+	if m.view != ConfirmScreen || m.overlays.confirmAction != chezmoiActionDeleteStateBucket {
+		t.Fatalf("expected a confirmation, got view %v", m.view)
+	}
+	if !strings.Contains(m.overlays.confirmLabel, "scriptState and its 1 key") {
+		t.Fatalf("unexpected label %q", m.overlays.confirmLabel)
+	}
+
+	m, cmd := sendKey(t, m, runeKey("y"))
+	if !m.ui.busyAction || cmd == nil {
+		t.Fatal("expected y to delete the bucket")
+	}
+	m, cmd = sendMsg(t, m, stateDeletedMsg{bucket: "scriptState"})
+	if cmd == nil || !m.info.views[infoViewState].loading {
+		t.Fatal("expected the state to reload after the delete")
+	}
+	if m.ui.message != "deleted state bucket scriptState" {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+}
+
+func TestStateBrowserDeleteIsDisabledInReadOnlyMode(t *testing.T) {
+	m := newTestModel(WithInfoView(infoViewState), WithSize(120, 30), WithLoaded(testInfoStateLoaded), WithReadOnly())
+
+	m, cmd := sendKey(t, m, runeKey("d"))
+	if cmd != nil || m.view == ConfirmScreen || !strings.Contains(m.ui.message, "read-only") {
+		t.Fatalf("expected the delete blocked, got %q", m.ui.message)
+	}
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// renderStateBrowser renders the State sub-view: a summary line and the
+// tree of buckets, keys, and open values.
+func (m Model) renderStateBrowser() string {
+	view := m.info.views[infoViewState]
+	if len(view.lines) > 0 {
+		return activeTheme.DangerFg.Render("  " + view.lines[0])
+	}
+	s := m.info.state
+	if len(s.state) == 0 {
+		return activeTheme.DimText.Render("  No persistent state recorded")
+	}
+
+	var b strings.Builder
+	b.WriteString(activeTheme.DimText.Render("  " + s.stateSummary()))
+	b.WriteString("\n")
+
+	maxWidth := m.effectiveWidth() - 4
+	rows := s.rows()
+	start, end := visibleRange(len(rows), s.cursor, m.infoViewHeight()-1)
+	for i := start; i < end; i++ {
+		selected := i == s.cursor
+		content := visualTruncate(m.renderStateRow(rows[i], selected), maxWidth)
+		if selected {
+			content = activeTheme.Selected.Width(maxWidth).Render(content)
+		}
+		b.WriteString("  " + content)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderStateRow renders a tree row, styled unless selected.
+func (m Model) renderStateRow(row stateRow, selected bool) string {
+	s := m.info.state
+	style := func(text string) string {
+		if selected {
+			return text
+		}
+		return activeTheme.DimText.Render(text)
+	}
+	switch {
+	case row.isValue():
+		return "      " + style(row.value)
+	case row.isBucket():
+		marker := "▸ "
+		if s.expanded[row.bucket] {
+			marker = "▾ "
+		}
+		name := row.bucket
+		if !selected {
+			name = activeTheme.BoldPrimary.Render(name)
+		}
+		noun := " keys"
+		if len(s.state[row.bucket]) == 1 {
+			noun = " key"
+		}
+		return marker + name + style("  "+strconv.Itoa(len(s.state[row.bucket]))+noun)
+	default:
+		raw := s.state[row.bucket][row.key]
+		if s.expanded[stateKeyID(row.bucket, row.key)] {
+			return "  ▾ " + row.key
+		}
+		return "  ▸ " + row.key + style("  "+stateValueSummary(raw))
+	}
+}
+
+// stateBrowserStatus summarizes the State sub-view for the status bar.
+func (m Model) stateBrowserStatus() string {
+	rows := m.info.state.rows()
+	if len(rows) == 0 {
+		return "empty"
+	}
+	return "row " + strconv.Itoa(m.info.state.cursor+1) + "/" + strconv.Itoa(len(rows))
+}
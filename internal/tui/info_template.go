@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+)
+
+const (
+	// templateEvalDebounce is how long typing must pause before the
+	// Template sub-view evaluates the input.
+	templateEvalDebounce = 300 * time.Millisecond
+	// templateInputLines is the height of the template input box.
+	templateInputLines = 5
+)
+
+// templateREPLState holds the Info tab's Template sub-view: a template typed
+// into input and evaluated with `chezmoi execute-template` as it changes.
+// The output lines live in the sub-view's infoSubViewState like the other
+// sub-views' content.
+type templateREPLState struct {
+	input      textarea.Model
+	seq        int    // bumped on every edit; stale evaluations are dropped
+	evaluating bool   // an evaluation of the current input is running
+	evaluated  string // input the shown output belongs to
+	failed     bool   // the shown output is an error
+	history    []string
+	historyPos int    // index into history being shown; -1 for the typed input
+	draft      string // typed input kept while browsing the history
+	// saveOnEval saves the input to the history once its pending
+	// evaluation succeeds; set when editing ends before it has run.
+	saveOnEval bool
+}
+
+func newTemplateInput() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "{{ .chezmoi.hostname }} on {{ .chezmoi.os }}"
+	ta.ShowLineNumbers = false
+	ta.Prompt = ""
+	// ctrl+p/ctrl+n walk the history instead of moving between lines.
+	ta.KeyMap.LinePrevious = key.NewBinding(key.WithKeys("up"))
+	ta.KeyMap.LineNext = key.NewBinding(key.WithKeys("down"))
+	ta.SetHeight(templateInputLines)
+	return ta
+}
+
+// templateInputFocused reports whether keys go to the template input.
+func (m Model) templateInputFocused() bool {
+	return m.view == StatusScreen && m.activeTabName() == "Info" &&
+		m.info.activeView == infoViewTemplate && m.info.template.input.Focused()
+}
+
+func (m Model) loadTemplateHistoryCmd() tea.Cmd {
+	return func() tea.Msg {
+		history, err := m.service.TemplateHistory()
+		return templateHistoryLoadedMsg{history: history, err: err}
+	}
+}
+
+func (m Model) handleTemplateHistoryLoaded(msg templateHistoryLoadedMsg) (tea.Model, tea.Cmd) {
+	view := &m.info.views[infoViewTemplate]
+	view.loading = false
+	view.loaded = true
+	m.info.template.historyPos = -1
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m.info.template.history = msg.history
+	return m, nil
+}
+
+// handleTemplateInputKeys routes keys to the focused template input.
+func (m Model) handleTemplateInputKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ChezTemplateREPLKeys.Done):
+		m.info.template.input.Blur()
+		if m.info.template.evaluated != m.info.template.input.Value() {
+			m.info.template.saveOnEval = true
+			m.info.template.seq++
+			return m.evaluateTemplate()
+		}
+		return m.saveTemplateHistory()
+	case key.Matches(msg, ChezTemplateREPLKeys.HistoryPrev):
+		return m.recallTemplateHistory(1)
+	case key.Matches(msg, ChezTemplateREPLKeys.HistoryNext):
+		return m.recallTemplateHistory(-1)
+	}
+	before := m.info.template.input.Value()
+	m.info.template.saveOnEval = false
+	var cmd tea.Cmd
+	m.info.template.input, cmd = m.info.template.input.Update(msg)
+	if m.info.template.input.Value() == before {
+		return m, cmd
+	}
+	m.info.template.historyPos = -1
+	return m, tea.Batch(cmd, m.scheduleTemplateEval())
+}
+
+// focusTemplateInput starts editing the template.
+func (m Model) focusTemplateInput() (tea.Model, tea.Cmd) {
+	m.info.template.input.SetWidth(max(m.effectiveWidth()-8, 20))
+	cmd := m.info.template.input.Focus()
+	m.ui.message = ""
+	return m, cmd
+}
+
+// recallTemplateHistory replaces the input with an older (step 1) or newer
+// (step -1) history entry. Stepping past the newest restores what was typed.
+func (m Model) recallTemplateHistory(step int) (tea.Model, tea.Cmd) {
+	t := &m.info.template
+	pos := t.historyPos + step
+	if pos < -1 || pos >= len(t.history) {
+		return m, nil
+	}
+	if t.historyPos == -1 {
+		t.draft = t.input.Value()
+	}
+	t.historyPos = pos
+	if pos == -1 {
+		t.input.SetValue(t.draft)
+	} else {
+		t.input.SetValue(t.history[pos])
+	}
+	return m, m.scheduleTemplateEval()
+}
+
+// scheduleTemplateEval evaluates the input once typing pauses for
+// templateEvalDebounce.
+func (m *Model) scheduleTemplateEval() tea.Cmd {
+	m.info.template.seq++
+	seq := m.info.template.seq
+	return tea.Tick(templateEvalDebounce, func(time.Time) tea.Msg {
+		return templateEvalTickMsg{seq: seq}
+	})
+}
+
+func (m Model) handleTemplateEvalTick(msg templateEvalTickMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.info.template.seq {
+		return m, nil
+	}
+	return m.evaluateTemplate()
+}
+
+// evaluateTemplate runs the current input through chezmoi execute-template.
+func (m Model) evaluateTemplate() (tea.Model, tea.Cmd) {
+	text := m.info.template.input.Value()
+	seq := m.info.template.seq
+	if strings.TrimSpace(text) == "" {
+		m.info.template.saveOnEval = false
+		m.info.template.evaluating = false
+		m.info.template.evaluated = ""
+		m.info.template.failed = false
+		m.info.views[infoViewTemplate].lines = nil
+		return m, nil
+	}
+	m.info.template.evaluating = true
+	return m, func() tea.Msg {
+		output, err := m.service.ExecuteTemplate(text)
+		return templateEvaluatedMsg{seq: seq, input: text, output: output, err: err}
+	}
+}
+
+func (m Model) handleTemplateEvaluated(msg templateEvaluatedMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.info.template.seq {
+		return m, nil
+	}
+	t := &m.info.template
+	t.evaluating = false
+	t.evaluated = msg.input
+	t.failed = msg.err != nil
+	save := t.saveOnEval
+	t.saveOnEval = false
+
+	view := &m.info.views[infoViewTemplate]
+	if msg.err != nil {
+		view.lines = nil
+		for line := range strings.SplitSeq(msg.err.Error(), "\n") {
+			view.lines = append(view.lines, activeTheme.DangerFg.Render(line))
+		}
+	} else {
+		view.lines = strings.Split(strings.TrimSuffix(msg.output, "\n"), "\n")
+	}
+	if view.viewportReady {
+		view.viewport.GotoTop()
+	}
+	if save {
+		return m.saveTemplateHistory()
+	}
+	return m, nil
+}
+
+// saveTemplateHistory keeps the input in the history once it has rendered
+// without error.
+func (m Model) saveTemplateHistory() (tea.Model, tea.Cmd) {
+	t := m.info.template
+	text := t.input.Value()
+	if t.failed || t.evaluating || t.evaluated != text || strings.TrimSpace(text) == "" {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		history, err := m.service.AddTemplateHistory(text)
+		return templateHistoryLoadedMsg{history: history, err: err}
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// typeTemplate types text into the focused template input.
+func typeTemplate(t *testing.T, m Model, text string) (Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, r := range text {
+		m, cmd = sendKey(t, m, runeKey(string(r)))
+	}
+	return m, cmd
+}
+
+func TestTemplateREPLEvaluatesAfterTypingPauses(t *testing.T) {
+	m := newTestModel(WithReadOnly(), WithServiceOptions(chezmoi.WithDataDir(t.TempDir())), WithInfoView(infoViewTemplate))
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	if !m.templateInputFocused() {
+		t.Fatal("expected enter to focus the template input")
+	}
+	m, _ = typeTemplate(t, m, "{{ .chezmoi.os }}")
+	if m.info.activeView != infoViewTemplate || m.info.template.input.Value() != "{{ .chezmoi.os }}" {
+		t.Fatalf("expected keys typed into the input, got %q", m.info.template.input.Value())
+	}
+
+	m, cmd := sendMsg(t, m, templateEvalTickMsg{seq: m.info.template.seq - 1})
+	if cmd != nil || m.info.template.evaluating {
+		t.Fatal("expected a tick from an earlier edit to be ignored")
+	}
+	m, cmd = sendMsg(t, m, templateEvalTickMsg{seq: m.info.template.seq})
+	if cmd == nil || !m.info.template.evaluating {
+		t.Fatal("expected the latest tick to evaluate the template")
+	}
+
+	m, _ = sendMsg(t, m, templateEvaluatedMsg{seq: m.info.template.seq - 1, input: "{{", err: errors.New("stale")})
+	if !m.info.template.evaluating {
+		t.Fatal("expected a stale result to be ignored")
+	}
+	m, _ = sendMsg(t, m, templateEvaluatedMsg{seq: m.info.template.seq, input: "{{ .chezmoi.os }}", output: "linux\n"})
+	rendered := ansi.Strip(m.renderInfoTabContent())
+	for _, want := range []string{"Template", "{{ .chezmoi.os }}", "Output", "linux"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendMsg(t, m, templateEvaluatedMsg{seq: m.info.template.seq, input: "{{ .nope }}", err: errors.New(`map has no entry for key "nope"`)})
+	rendered = ansi.Strip(m.renderInfoTabContent())
+	if !strings.Contains(rendered, "Error") || !strings.Contains(rendered, "no entry for key") {
+		t.Fatalf("expected the error shown:\n%s", rendered)
+	}
+}
+
+func TestTemplateREPLSavesHistoryWhenEditingEnds(t *testing.T) {
+	m := newTestModel(WithReadOnly(), WithServiceOptions(chezmoi.WithDataDir(t.TempDir())), WithInfoView(infoViewTemplate))
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	m, _ = typeTemplate(t, m, "{{ .email }}")
+
+	// Editing ends before the debounce fired: evaluate now, then save.
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.templateInputFocused() || !m.info.template.saveOnEval || cmd == nil {
+		t.Fatal("expected esc to stop editing and evaluate the pending input")
+	}
+	m, cmd = sendMsg(t, m, cmd())
+	if cmd == nil {
+		t.Fatal("expected a successful evaluation to save the expression")
+	}
+	m, _ = sendMsg(t, m, cmd())
+	if !slices.Equal(m.info.template.history, []string{"{{ .email }}"}) {
+		t.Fatalf("expected the expression saved, got %q", m.info.template.history)
+	}
+	history, err := m.service.TemplateHistory()
+	if err != nil || !slices.Equal(history, []string{"{{ .email }}"}) {
+		t.Fatalf("expected the history persisted, got %q, %v", history, err)
+	}
+}
+
+func TestTemplateREPLHistoryRecall(t *testing.T) {
+	m := newTestModel(WithReadOnly(), WithServiceOptions(chezmoi.WithDataDir(t.TempDir())), WithInfoView(infoViewTemplate))
+	m.info.template.history = []string{"{{ .newer }}", "{{ .older }}"}
+	m, _ = sendKey(t, m, specialKey(tea.KeyEnter))
+	m, _ = typeTemplate(t, m, "draft")
+
+	ctrl := func(r rune) tea.KeyPressMsg { return tea.KeyPressMsg{Code: r, Mod: tea.ModCtrl} }
+	for _, want := range []string{"{{ .newer }}", "{{ .older }}", "{{ .older }}"} {
+		m, _ = sendKey(t, m, ctrl('p'))
+		if got := m.info.template.input.Value(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+	for _, want := range []string{"{{ .newer }}", "draft"} {
+		m, _ = sendKey(t, m, ctrl('n'))
+		if got := m.info.template.input.Value(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+}
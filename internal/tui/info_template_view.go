@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// renderTemplateREPL renders the Template sub-view: the input box and the
+// output of the last evaluation below it.
+func (m Model) renderTemplateREPL() string {
+	t := m.info.template
+	width := m.effectiveWidth()
+
+	input := t.input
+	input.SetWidth(max(width-8, 20)) // margin + border + padding
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.Primary).
+		Padding(0, 1).
+		MarginLeft(2)
+	if !input.Focused() {
+		box = box.BorderForeground(activeTheme.Dim)
+	}
+
+	var b strings.Builder
+	b.WriteString(box.Render(input.View()))
+	b.WriteString("\n")
+
+	view := &m.info.views[infoViewTemplate]
+	switch {
+	case t.evaluating:
+		b.WriteString(activeTheme.DimText.Render("  " + m.ui.loadingSpinner.View() + " Evaluating..."))
+		return b.String()
+	case len(view.lines) == 0:
+		hint := "  Press enter to type a template; it is evaluated as you type"
+		if input.Focused() || strings.TrimSpace(input.Value()) != "" {
+			hint = "  Output appears here"
+		}
+		b.WriteString(activeTheme.DimText.Render(hint))
+		return b.String()
+	}
+
+	label := "Output"
+	if t.failed {
+		label = "Error"
+	}
+	b.WriteString(activeTheme.DimText.Render("  " + label))
+	b.WriteString("\n")
+
+	maxWidth := width - 4
+	view.ensureViewport(width, m.templateREPLOutputHeight())
+	content := m.preRenderInfoContent(maxWidth)
+	offset := view.viewport.YOffset()
+	view.viewport.SetContent(content)
+	view.viewport.SetYOffset(offset)
+	b.WriteString(view.viewport.View())
+	return b.String()
+}
+
+// templateREPLStatus summarizes the Template sub-view for the status bar.
+func (m Model) templateREPLStatus() string {
+	t := m.info.template
+	switch {
+	case t.evaluating:
+		return "evaluating"
+	case t.failed:
+		return "error"
+	case t.historyPos >= 0:
+		return fmt.Sprintf("history %d/%d", t.historyPos+1, len(t.history))
+	case t.evaluated != "":
+		return fmt.Sprintf("%d lines", len(m.info.views[infoViewTemplate].lines))
+	}
+	return fmt.Sprintf("%d saved", len(t.history))
+}
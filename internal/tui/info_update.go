@@ -82,6 +82,21 @@ func (m Model) handleInfoContentLoaded(msg infoContentLoadedMsg) (tea.Model, tea
 // --- Info tab key handler ---
 
 func (m Model) handleInfoKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.info.activeView == infoViewTemplate {
+		switch {
+		case key.Matches(msg, ChezTemplateREPLKeys.Edit):
+			return m.focusTemplateInput()
+		case key.Matches(msg, ChezInfoKeys.Refresh):
+			m.info.template.seq++
+			return m.evaluateTemplate()
+		}
+	}
+	if m.info.activeView == infoViewState {
+		if updated, cmd, handled := m.handleInfoStateKeys(msg); handled {
+			return updated, cmd
+		}
+	}
+
 	switch {
 	case key.Matches(msg, ChezSharedKeys.Back):
 		return m.escCmd()
@@ -155,6 +170,9 @@ func (m Model) syncInfoViewportContent() Model {
 		return m
 	}
 	listHeight := m.infoViewHeight()
+	if m.info.activeView == infoViewTemplate {
+		listHeight = m.templateREPLOutputHeight()
+	}
 	view.ensureViewport(m.effectiveWidth(), listHeight)
 	content := m.preRenderInfoContent(m.effectiveWidth() - 4)
 	currentOffset := view.viewport.YOffset()
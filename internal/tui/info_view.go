@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-// --- Tab 2: Info (sub-views: Config, Full, Data, Doctor) ---
+// --- Tab 2: Info (sub-views: Config, Full, Data, Template, State, Doctor) ---
 
 func (m Model) renderInfoTabContent() string {
 	var b strings.Builder
@@ -16,12 +16,22 @@ func (m Model) renderInfoTabContent() string {
 
 	view := &m.info.views[m.info.activeView]
 
+	if m.info.activeView == infoViewTemplate {
+		b.WriteString(m.renderTemplateREPL())
+		return b.String()
+	}
+
 	if view.loading {
 		spinnerView := m.ui.loadingSpinner.View()
 		fmt.Fprintf(&b, "  %s Loading...", spinnerView)
 		return b.String()
 	}
 
+	if m.info.activeView == infoViewState {
+		b.WriteString(m.renderStateBrowser())
+		return b.String()
+	}
+
 	if len(view.lines) == 0 {
 		b.WriteString(activeTheme.DimText.Render("  No data"))
 		return b.String()
@@ -95,6 +105,12 @@ func (m Model) renderInfoLine(line string, maxWidth int) string {
 		return "  " + truncated
 	}
 
+	// Template output is plain text (errors are pre-styled) and may be
+	// arbitrarily wide.
+	if m.info.activeView == infoViewTemplate {
+		return "  " + visualTruncate(line, maxWidth)
+	}
+
 	// For highlighted content (Config, Full, Data), lines are pre-highlighted with ANSI codes
 	return "  " + line
 }
@@ -111,11 +127,30 @@ func (m Model) renderInfoStatusBar() string {
 	if m.info.activeView == infoViewFull || m.info.activeView == infoViewData {
 		status = fmt.Sprintf(" %s (%s) | line %d/%d ", viewName, m.info.format, view.viewport.YOffset()+1, max(len(view.lines), 1))
 	}
+	if m.info.activeView == infoViewTemplate {
+		status = " " + viewName + " | " + m.templateREPLStatus() + " "
+	}
+	if m.info.activeView == infoViewState {
+		status = " " + viewName + " | " + m.stateBrowserStatus() + " "
+	}
 	if m.ui.message != "" {
 		status = " " + m.ui.message + " "
 	}
 	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
 
+	if m.info.activeView == infoViewTemplate {
+		helpText := "h/l switch | enter/i edit | ↑/↓ scroll output | r evaluate | tab switch | ? keys | esc quit"
+		if m.info.template.input.Focused() {
+			helpText = "type a template | ^j/enter new line | ^p/^n history | esc done"
+		}
+		return statusBar + "\n" + m.helpHint(helpText)
+	}
+
+	if m.info.activeView == infoViewState {
+		helpText := "h/l switch | ↑/↓ nav | enter expand | d delete | r refresh | tab switch | ? keys | esc quit"
+		return statusBar + "\n" + m.helpHint(helpText)
+	}
+
 	helpText := "h/l switch | ↑/↓ scroll | ^d/^u half-page"
 	if m.info.activeView == infoViewFull || m.info.activeView == infoViewData {
 		helpText += " | f format"
@@ -0,0 +1,436 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// jobState is the lifecycle stage of a background job.
+type jobState int
+
+const (
+	jobQueued jobState = iota
+	jobRunning
+	jobSucceeded
+	jobFailed
+)
+
+func (s jobState) String() string {
+	switch s {
+	case jobRunning:
+		return "running"
+	case jobSucceeded:
+		return "done"
+	case jobFailed:
+		return "failed"
+	default:
+		return "queued"
+	}
+}
+
+// jobScope describes what a job touches so conflicting jobs run one at a
+// time. Paths are absolute target paths, or a pseudo-path such as
+// jobScopeGit for state outside the target tree; nil paths cover
+// everything.
+type jobScope struct {
+	paths    []string
+	readOnly bool
+}
+
+// Pseudo-paths for state outside the target tree.
+const (
+	// jobScopeGit stands for the source repository's git state.
+	jobScopeGit = "git:"
+	// jobScopeSource stands for the source directory and chezmoi's
+	// persistent state, which every mutating chezmoi process writes.
+	jobScopeSource = "source:"
+)
+
+// writerJobScope is the scope of a job that starts a mutating chezmoi
+// process. Besides the target paths it touches, it claims the source
+// state, so no two writers ever run at once, and the git state, since
+// autoCommit and autoPush can commit from any of them.
+func writerJobScope(paths ...string) jobScope {
+	return jobScope{paths: append(paths, jobScopeSource, jobScopeGit)}
+}
+
+// conflicts reports whether two jobs must not run at the same time.
+// Readers never conflict with each other.
+func (s jobScope) conflicts(other jobScope) bool {
+	if s.readOnly && other.readOnly {
+		return false
+	}
+	if s.paths == nil || other.paths == nil {
+		return true
+	}
+	for _, a := range s.paths {
+		for _, b := range other.paths {
+			if jobPathsOverlap(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jobPathsOverlap reports whether a and b are the same path or one
+// contains the other.
+func jobPathsOverlap(a, b string) bool {
+	a = strings.TrimSuffix(a, "/")
+	b = strings.TrimSuffix(b, "/")
+	return a == b || strings.HasPrefix(b, a+"/") || strings.HasPrefix(a, b+"/")
+}
+
+// jobFunc runs a job's work, sending output lines to out as they are
+// produced. It must return once ctx is cancelled.
+type jobFunc func(ctx context.Context, out chan<- chezmoi.OutputLine) error
+
+// capturedJob adapts an operation that returns its output on exit. run
+// must kill whatever it started once ctx is cancelled.
+func capturedJob(run func(ctx context.Context) (string, error)) jobFunc {
+	return func(ctx context.Context, out chan<- chezmoi.OutputLine) error {
+		output, err := run(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if output = strings.TrimRight(output, "\n"); output != "" {
+			for line := range strings.SplitSeq(output, "\n") {
+				out <- chezmoi.OutputLine{Text: line}
+			}
+		}
+		return err
+	}
+}
+
+const (
+	// jobOutputLimit caps the output lines kept per job.
+	jobOutputLimit = 10000
+	// jobOutputBatch caps how many buffered lines one jobOutputMsg carries.
+	jobOutputBatch = 256
+)
+
+// job is one non-interactive Service operation run in the background.
+type job struct {
+	id       int
+	label    string
+	action   chezmoiAction
+	scope    jobScope
+	state    jobState
+	started  time.Time
+	finished time.Time
+	output   []chezmoi.OutputLine
+	err      error
+	run      jobFunc
+
+	// Set while running.
+	cancel context.CancelFunc
+	lines  chan chezmoi.OutputLine
+	done   chan error
+}
+
+// outputText joins the job's output lines.
+func (j job) outputText() string {
+	texts := make([]string, len(j.output))
+	for i, line := range j.output {
+		texts[i] = line.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// canceled reports whether the job ended because it was cancelled.
+func (j job) canceled() bool {
+	return errors.Is(j.err, context.Canceled)
+}
+
+// elapsed is how long the job ran, or has been running so far.
+func (j job) elapsed(now time.Time) time.Duration {
+	switch {
+	case j.started.IsZero():
+		return 0
+	case j.finished.IsZero():
+		return now.Sub(j.started)
+	default:
+		return j.finished.Sub(j.started)
+	}
+}
+
+// jobQueue holds every job of the session, oldest first, plus the jobs
+// overlay selection.
+type jobQueue struct {
+	jobs   []job
+	nextID int
+	cursor int
+}
+
+func (q jobQueue) count(state jobState) int {
+	n := 0
+	for _, j := range q.jobs {
+		if j.state == state {
+			n++
+		}
+	}
+	return n
+}
+
+// active reports whether any job is queued or running.
+func (q jobQueue) active() bool {
+	return q.count(jobQueued)+q.count(jobRunning) > 0
+}
+
+func (q jobQueue) index(id int) int {
+	for i, j := range q.jobs {
+		if j.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// enqueueJob adds a job and starts it right away unless it conflicts with
+// a running job or an earlier queued one.
+func (m *Model) enqueueJob(label string, action chezmoiAction, scope jobScope, run jobFunc) tea.Cmd {
+	m.jobs.nextID++
+	m.jobs.jobs = append(m.jobs.jobs, job{
+		id:     m.jobs.nextID,
+		label:  label,
+		action: action,
+		scope:  scope,
+		run:    run,
+	})
+	m.ui.message = label + " queued — J for jobs"
+	if cmd := m.startReadyJobs(); cmd != nil {
+		return tea.Batch(m.ui.loadingSpinner.Tick, cmd)
+	}
+	return nil
+}
+
+// startReadyJobs starts every queued job that conflicts with neither a
+// running job nor a job queued ahead of it, so conflicting jobs keep their
+// submission order.
+func (m *Model) startReadyJobs() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.jobs.jobs {
+		j := &m.jobs.jobs[i]
+		if j.state != jobQueued || m.jobBlocked(i) {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		j.state = jobRunning
+		j.started = time.Now()
+		j.cancel = cancel
+		j.lines = make(chan chezmoi.OutputLine, jobOutputBatch)
+		j.done = make(chan error, 1)
+		cmds = append(cmds, runJobCmd(ctx, *j))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) jobBlocked(i int) bool {
+	scope := m.jobs.jobs[i].scope
+	for k, other := range m.jobs.jobs {
+		if k == i {
+			continue
+		}
+		ahead := other.state == jobQueued && k < i
+		if (other.state == jobRunning || ahead) && scope.conflicts(other.scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// runJobCmd starts j.run in its own goroutine and waits for its first
+// output. The goroutine closes j.lines before reporting the result on
+// j.done, so waitJobOutput sees every line before the jobDoneMsg.
+func runJobCmd(ctx context.Context, j job) tea.Cmd {
+	return func() tea.Msg {
+		go func() {
+			err := j.run(ctx, j.lines)
+			close(j.lines)
+			j.done <- err
+		}()
+		return waitJobOutput(j.id, j.lines, j.done)
+	}
+}
+
+func waitJobOutputCmd(j job) tea.Cmd {
+	return func() tea.Msg {
+		return waitJobOutput(j.id, j.lines, j.done)
+	}
+}
+
+// waitJobOutput blocks for the next output line, then batches whatever
+// else is already buffered so chatty commands do not flood Update.
+func waitJobOutput(id int, lines <-chan chezmoi.OutputLine, done <-chan error) tea.Msg {
+	line, ok := <-lines
+	if !ok {
+		return jobDoneMsg{id: id, err: <-done}
+	}
+	batch := []chezmoi.OutputLine{line}
+	for len(batch) < jobOutputBatch {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return jobOutputMsg{id: id, lines: batch}
+			}
+			batch = append(batch, line)
+		default:
+			return jobOutputMsg{id: id, lines: batch}
+		}
+	}
+	return jobOutputMsg{id: id, lines: batch}
+}
+
+func (m Model) handleJobOutput(msg jobOutputMsg) (tea.Model, tea.Cmd) {
+	i := m.jobs.index(msg.id)
+	if i < 0 {
+		return m, nil
+	}
+	j := &m.jobs.jobs[i]
+	j.output = append(j.output, msg.lines...)
+	if over := len(j.output) - jobOutputLimit; over > 0 {
+		j.output = append(j.output[:0], j.output[over:]...)
+	}
+	if m.cmds.outputJob == j.id {
+		m.syncCommandOutput()
+	}
+	if j.lines == nil {
+		return m, nil
+	}
+	return m, waitJobOutputCmd(*j)
+}
+
+func (m Model) handleJobDone(msg jobDoneMsg) (tea.Model, tea.Cmd) {
+	i := m.jobs.index(msg.id)
+	if i < 0 {
+		return m, nil
+	}
+	j := &m.jobs.jobs[i]
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.cancel, j.lines, j.done = nil, nil, nil
+	j.finished = time.Now()
+	j.err = msg.err
+	switch {
+	case j.canceled():
+		j.state = jobFailed
+		m.ui.message = j.label + " canceled"
+	case msg.err != nil:
+		j.state = jobFailed
+		m.ui.message = fmt.Sprintf("%s failed: %s", j.label, msg.err.Error())
+	default:
+		j.state = jobSucceeded
+		output := strings.TrimSpace(j.outputText())
+		switch {
+		case output == "":
+			m.ui.message = j.label + " done"
+		case !strings.Contains(output, "\n"):
+			m.ui.message = j.label + ": " + output
+		default:
+			m.ui.message = j.label + " done — J for output"
+		}
+	}
+	if m.cmds.outputJob == j.id {
+		m.syncCommandOutput()
+	}
+
+	cmds := []tea.Cmd{m.startReadyJobs(), m.jobFollowUpCmd(*j)}
+	return m, tea.Batch(cmds...)
+}
+
+// cancelJob stops a running job through its context, or drops a queued
+// one before it starts.
+func (m *Model) cancelJob(id int) tea.Cmd {
+	i := m.jobs.index(id)
+	if i < 0 {
+		return nil
+	}
+	j := &m.jobs.jobs[i]
+	switch j.state {
+	case jobRunning:
+		if j.cancel != nil {
+			j.cancel()
+		}
+		m.ui.message = "canceling " + j.label + "..."
+	case jobQueued:
+		j.state = jobFailed
+		j.err = context.Canceled
+		j.finished = time.Now()
+		m.ui.message = j.label + " canceled"
+		if m.cmds.outputJob == j.id {
+			m.syncCommandOutput()
+		}
+		return m.startReadyJobs()
+	}
+	return nil
+}
+
+// jobFollowUpCmd refreshes whatever state the finished job may have changed.
+func (m *Model) jobFollowUpCmd(j job) tea.Cmd {
+	switch j.action {
+	case chezmoiActionArchive, chezmoiActionNone:
+		return nil
+	case chezmoiActionFetch:
+		m.status.fetchInProgress = false
+		if j.err != nil {
+			return nil
+		}
+		m.status.lastFetchTime = j.finished
+		return tea.Batch(m.loadGitCommitsCmd(), m.loadGitStatusCmd())
+	}
+	m.panel.clearCache()
+	cmds := []tea.Cmd{m.postActionReloadCmds(), sendRefreshMsg()}
+	if j.action == chezmoiActionRefreshExternal && m.view == ExternalsScreen {
+		// Show the new fetch time.
+		cmds = append(cmds, m.loadExternalsCmd())
+	}
+	writesFile := j.action == chezmoiActionAdd || j.action == chezmoiActionEncryptFile || j.action == chezmoiActionDecryptFile
+	if writesFile && m.filesTab.views[managedViewUnmanaged].files != nil {
+		m.filesTab.views[managedViewUnmanaged].loading = true
+		cmds = append(cmds, m.loadUnmanagedCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// clearFinishedJobs drops succeeded and failed jobs from the overlay.
+func (m *Model) clearFinishedJobs() {
+	kept := m.jobs.jobs[:0]
+	for _, j := range m.jobs.jobs {
+		if j.state == jobQueued || j.state == jobRunning {
+			kept = append(kept, j)
+		}
+	}
+	m.jobs.jobs = kept
+	m.jobs.cursor = min(m.jobs.cursor, max(0, len(kept)-1))
+}
+
+func (m Model) handleJobsOverlayKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ChezJobsKeys.Close):
+		m.overlays.showJobs = false
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.jobs.cursor = moveCursorUp(m.jobs.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.jobs.cursor = moveCursorDown(m.jobs.cursor, len(m.jobs.jobs), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.jobs.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.jobs.cursor = max(0, len(m.jobs.jobs)-1)
+	case key.Matches(msg, ChezJobsKeys.Clear):
+		m.clearFinishedJobs()
+	case key.Matches(msg, ChezJobsKeys.Cancel):
+		if m.jobs.cursor < len(m.jobs.jobs) {
+			return m, m.cancelJob(m.jobs.jobs[m.jobs.cursor].id)
+		}
+	}
+	return m, nil
+}
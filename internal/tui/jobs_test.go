@@ -0,0 +1,297 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+	"github.com/daptify14/chezit/internal/config"
+)
+
+func noopJob(context.Context, chan<- chezmoi.OutputLine) error { return nil }
+
+func outputLines(texts ...string) []chezmoi.OutputLine {
+	lines := make([]chezmoi.OutputLine, len(texts))
+	for i, text := range texts {
+		lines[i] = chezmoi.OutputLine{Text: text}
+	}
+	return lines
+}
+
+func TestJobScopeConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b jobScope
+		want bool
+	}{
+		{"same path", jobScope{paths: []string{"/home/test/.bashrc"}}, jobScope{paths: []string{"/home/test/.bashrc"}}, true},
+		{"ancestor", jobScope{paths: []string{"/home/test/.config"}}, jobScope{paths: []string{"/home/test/.config/nvim/init.lua"}}, true},
+		{"sibling prefix", jobScope{paths: []string{"/home/test/.config"}}, jobScope{paths: []string{"/home/test/.config2"}}, false},
+		{"disjoint", jobScope{paths: []string{"/home/test/.bashrc"}}, jobScope{paths: []string{"/home/test/.zshrc"}}, false},
+		{"git vs target", jobScope{paths: []string{jobScopeGit}}, jobScope{paths: []string{"/home/test"}}, false},
+		{"unscoped", jobScope{}, jobScope{paths: []string{"/home/test/.zshrc"}}, true},
+		{"two readers", jobScope{readOnly: true}, jobScope{paths: []string{"/home/test"}, readOnly: true}, false},
+		{"reader and writer", jobScope{paths: []string{"/home/test"}, readOnly: true}, jobScope{paths: []string{"/home/test/.zshrc"}}, true},
+		{"writers on disjoint paths", writerJobScope("/home/test/.bashrc"), writerJobScope("/home/test/.zshrc"), true},
+		{"writer and git fetch", writerJobScope("/home/test/.bashrc"), jobScope{paths: []string{jobScopeGit}}, true},
+		{"reader and git fetch", jobScope{paths: []string{"/home/test"}, readOnly: true}, jobScope{paths: []string{jobScopeGit}}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.conflicts(tc.b); got != tc.want {
+				t.Fatalf("conflicts = %v, want %v", got, tc.want)
+			}
+			if got := tc.b.conflicts(tc.a); got != tc.want {
+				t.Fatalf("conflicts (reversed) = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueJobRunsDisjointJobsInParallel(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("archive", chezmoiActionArchive, jobScope{paths: []string{"/home/test"}, readOnly: true}, noopJob)
+	m.enqueueJob("git fetch", chezmoiActionFetch, jobScope{paths: []string{jobScopeGit}}, noopJob)
+
+	if got := m.jobs.count(jobRunning); got != 2 {
+		t.Fatalf("expected 2 running jobs, got %d", got)
+	}
+}
+
+func TestEnqueueJobSerializesWriters(t *testing.T) {
+	m := newTestModel()
+	// Different targets, but both write the source state.
+	m.enqueueJob("add ~/.bashrc", chezmoiActionAdd, writerJobScope("/home/test/.bashrc"), noopJob)
+	m.enqueueJob("add ~/.zshrc", chezmoiActionAdd, writerJobScope("/home/test/.zshrc"), noopJob)
+
+	if m.jobs.jobs[0].state != jobRunning || m.jobs.jobs[1].state != jobQueued {
+		t.Fatalf("expected the second add to wait, got %s / %s", m.jobs.jobs[0].state, m.jobs.jobs[1].state)
+	}
+	m, _ = sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id})
+	if m.jobs.jobs[1].state != jobRunning {
+		t.Fatalf("expected the second add to start, got %s", m.jobs.jobs[1].state)
+	}
+}
+
+func TestEnqueueJobSerializesConflictingJobs(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("re-add all", chezmoiActionReAdd, jobScope{paths: []string{"/home/test"}}, noopJob)
+	m.enqueueJob("add ~/.bashrc", chezmoiActionAdd, jobScope{paths: []string{"/home/test/.bashrc"}}, noopJob)
+	// Disjoint from both, so it starts immediately.
+	m.enqueueJob("git fetch", chezmoiActionFetch, jobScope{paths: []string{jobScopeGit}}, noopJob)
+	// Conflicts only with the queued add, so it waits behind it.
+	m.enqueueJob("add ~/.bashrc again", chezmoiActionAdd, jobScope{paths: []string{"/home/test/.bashrc"}}, noopJob)
+
+	states := []jobState{jobRunning, jobQueued, jobRunning, jobQueued}
+	for i, want := range states {
+		if got := m.jobs.jobs[i].state; got != want {
+			t.Fatalf("job %d (%s) state = %s, want %s", i, m.jobs.jobs[i].label, got, want)
+		}
+	}
+
+	m, _ = sendMsg(t, m, jobOutputMsg{id: m.jobs.jobs[0].id, lines: outputLines("re-added")})
+	m, _ = sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id})
+	if m.jobs.jobs[0].state != jobSucceeded {
+		t.Fatalf("expected first job done, got %s", m.jobs.jobs[0].state)
+	}
+	if m.jobs.jobs[1].state != jobRunning || m.jobs.jobs[3].state != jobQueued {
+		t.Fatalf("expected only the first queued add to start, got %s / %s", m.jobs.jobs[1].state, m.jobs.jobs[3].state)
+	}
+	if m.ui.message != "re-add all: re-added" {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+}
+
+func TestJobDoneRecordsFailure(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("archive", chezmoiActionArchive, jobScope{readOnly: true}, noopJob)
+
+	m, _ = sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id, err: errors.New("disk full")})
+	j := m.jobs.jobs[0]
+	if j.state != jobFailed || j.finished.IsZero() {
+		t.Fatalf("expected failed job with finish time, got %s", j.state)
+	}
+	if !strings.Contains(m.ui.message, "archive failed: disk full") {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+}
+
+func TestFetchJobUpdatesFetchState(t *testing.T) {
+	m := newTestModel()
+	m.status.fetchInProgress = true
+	m.enqueueJob("git fetch", chezmoiActionFetch, jobScope{paths: []string{jobScopeGit}}, noopJob)
+
+	m, cmd := sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id})
+	if m.status.fetchInProgress || m.status.lastFetchTime.IsZero() {
+		t.Fatal("expected fetch to be marked complete")
+	}
+	if cmd == nil {
+		t.Fatal("expected git reload cmds")
+	}
+}
+
+func TestJobsOverlayShowsOutputAndClears(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("re-add all", chezmoiActionReAdd, jobScope{paths: []string{"/home/test"}}, noopJob)
+	m.enqueueJob("add ~/.bashrc", chezmoiActionAdd, jobScope{paths: []string{"/home/test/.bashrc"}}, noopJob)
+	m, _ = sendMsg(t, m, jobOutputMsg{id: m.jobs.jobs[0].id, lines: outputLines("line one", "line two")})
+	m, _ = sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id})
+
+	m, _ = sendKey(t, m, runeKey("J"))
+	if !m.overlays.showJobs {
+		t.Fatal("expected J to open the jobs overlay")
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyUp))
+	rendered := ansi.Strip(m.renderJobsOverlay())
+	for _, want := range []string{"1 running", "done", "re-add all", "running", "add ~/.bashrc", "line two"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected overlay to contain %q, got:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendKey(t, m, runeKey("x"))
+	if len(m.jobs.jobs) != 1 || m.jobs.jobs[0].label != "add ~/.bashrc" {
+		t.Fatalf("expected only the running job to remain, got %+v", m.jobs.jobs)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.overlays.showJobs {
+		t.Fatal("expected esc to close the overlay")
+	}
+}
+
+func TestArchiveConfirmQueuesJob(t *testing.T) {
+	m := newTestModel()
+	m = m.showConfirmScreen(chezmoiActionArchive, "create backup archive")
+
+	m, cmd := sendKey(t, m, runeKey("y"))
+	if cmd == nil || len(m.jobs.jobs) != 1 {
+		t.Fatalf("expected archive job, got %d jobs", len(m.jobs.jobs))
+	}
+	if m.jobs.jobs[0].state != jobRunning || !m.jobs.jobs[0].scope.readOnly {
+		t.Fatal("expected running read-only archive job")
+	}
+}
+
+func TestRunJobCmdStreamsOutputBeforeDone(t *testing.T) {
+	m := newTestModel()
+	run := func(_ context.Context, out chan<- chezmoi.OutputLine) error {
+		out <- chezmoi.OutputLine{Text: "one"}
+		out <- chezmoi.OutputLine{Text: "two", Stderr: true}
+		return nil
+	}
+	cmd := m.enqueueJob("re-add all", chezmoiActionReAdd, jobScope{}, run)
+	if cmd == nil {
+		t.Fatal("expected job to start")
+	}
+	id := m.jobs.jobs[0].id
+
+	var msgs []tea.Msg
+	next := runJobCmd(context.Background(), m.jobs.jobs[0])
+	for next != nil {
+		msg := next()
+		msgs = append(msgs, msg)
+		var model tea.Model
+		model, next = m.Update(msg)
+		m = model.(Model)
+		if _, done := msg.(jobDoneMsg); done {
+			break
+		}
+	}
+
+	if _, ok := msgs[len(msgs)-1].(jobDoneMsg); !ok {
+		t.Fatalf("expected jobDoneMsg last, got %T", msgs[len(msgs)-1])
+	}
+	j := m.jobs.jobs[m.jobs.index(id)]
+	if j.state != jobSucceeded {
+		t.Fatalf("expected job done, got %s", j.state)
+	}
+	if got := j.outputText(); got != "one\ntwo" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if !j.output[1].Stderr {
+		t.Fatal("expected stderr flag to be kept")
+	}
+}
+
+func TestJobOutputIsCapped(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("re-add all", chezmoiActionReAdd, jobScope{}, noopJob)
+	texts := make([]string, jobOutputLimit+5)
+	for i := range texts {
+		texts[i] = "line"
+	}
+	texts[len(texts)-1] = "last"
+	m, _ = sendMsg(t, m, jobOutputMsg{id: m.jobs.jobs[0].id, lines: outputLines(texts...)})
+
+	j := m.jobs.jobs[0]
+	if len(j.output) != jobOutputLimit || j.output[len(j.output)-1].Text != "last" {
+		t.Fatalf("expected %d lines ending in last, got %d", jobOutputLimit, len(j.output))
+	}
+}
+
+func TestCancelQueuedJobStartsNext(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("re-add all", chezmoiActionReAdd, jobScope{paths: []string{"/home/test"}}, noopJob)
+	m.enqueueJob("add ~/.bashrc", chezmoiActionAdd, jobScope{paths: []string{"/home/test/.bashrc"}}, noopJob)
+	m.enqueueJob("add ~/.bashrc again", chezmoiActionAdd, jobScope{paths: []string{"/home/test/.bashrc"}}, noopJob)
+
+	m.cancelJob(m.jobs.jobs[1].id)
+	if !m.jobs.jobs[1].canceled() || m.jobs.jobs[1].state != jobFailed {
+		t.Fatalf("expected queued job canceled, got %s", m.jobs.jobs[1].state)
+	}
+	// Still blocked by the running re-add.
+	if m.jobs.jobs[2].state != jobQueued {
+		t.Fatalf("expected third job still queued, got %s", m.jobs.jobs[2].state)
+	}
+}
+
+func TestCancelRunningJobCancelsContext(t *testing.T) {
+	m := newTestModel()
+	m.enqueueJob("re-add all", chezmoiActionReAdd, jobScope{}, noopJob)
+	canceled := false
+	m.jobs.jobs[0].cancel = func() { canceled = true }
+
+	m.overlays.showJobs = true
+	m, _ = sendKey(t, m, runeKey("c"))
+	if !canceled {
+		t.Fatal("expected c to cancel the running job")
+	}
+
+	m, _ = sendMsg(t, m, jobDoneMsg{id: m.jobs.jobs[0].id, err: context.Canceled})
+	if m.ui.message != "re-add all canceled" {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+	if !strings.Contains(ansi.Strip(m.renderJobsOverlay()), "canceled") {
+		t.Fatal("expected overlay to show canceled")
+	}
+}
+
+func TestCapturedJobStopsOnCancel(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "chezmoi")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	svc := chezmoi.NewService(chezmoi.New(chezmoi.WithBinaryPath(binary)), config.ModeWrite, "/home/test")
+	m := newTestModel(WithService(svc))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- capturedJob(m.gitFetchJob)(ctx, make(chan chezmoi.OutputLine, 1)) }()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the job to report cancellation, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the job to stop once cancelled")
+	}
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+)
+
+// jobsOverlayOutputLines caps how much captured output the overlay shows.
+const jobsOverlayOutputLines = 12
+
+func (m Model) renderJobsOverlay() string {
+	now := time.Now()
+	width := min(max(m.effectiveWidth()-8, 40), 100)
+
+	var b strings.Builder
+	b.WriteString("\n  Background Jobs")
+	if summary := m.jobsSummary(); summary != "" {
+		b.WriteString(activeTheme.DimText.Render("  " + summary))
+	}
+	b.WriteString("\n  " + strings.Repeat("─", width-4) + "\n")
+
+	if len(m.jobs.jobs) == 0 {
+		b.WriteString(activeTheme.DimText.Render("  No jobs yet"))
+		b.WriteString("\n")
+	}
+	for i, j := range m.jobs.jobs {
+		b.WriteString(m.renderJobRow(j, i == m.jobs.cursor, now, width))
+		b.WriteString("\n")
+	}
+
+	if m.jobs.cursor < len(m.jobs.jobs) {
+		b.WriteString(m.renderJobOutput(m.jobs.jobs[m.jobs.cursor], width))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(activeTheme.HintText.Render("  ↑/↓ select | c cancel | x clear finished | esc close"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, activeTheme.Filter.Render(b.String()))
+}
+
+// jobsSummary counts unfinished jobs, e.g. "1 running · 2 queued".
+func (m Model) jobsSummary() string {
+	var parts []string
+	if n := m.jobs.count(jobRunning); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d running", n))
+	}
+	if n := m.jobs.count(jobQueued); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d queued", n))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m Model) renderJobRow(j job, selected bool, now time.Time, width int) string {
+	cursor := "  "
+	if selected {
+		cursor = "> "
+	}
+	icon := "◌"
+	switch j.state {
+	case jobRunning:
+		icon = m.ui.loadingSpinner.View()
+	case jobSucceeded:
+		icon = "✓"
+	case jobFailed:
+		icon = "✗"
+	}
+	elapsed := ""
+	if d := j.elapsed(now); d > 0 {
+		elapsed = formatJobElapsed(d)
+	}
+	state := j.state.String()
+	if j.canceled() {
+		state = "canceled"
+	}
+	label := visualTruncate(j.label, max(width-28, 10))
+	line := fmt.Sprintf("%s%s %-8s %s", cursor, icon, state, label)
+	line = visualPad(line, max(width-10, 0)) + " " + elapsed
+
+	switch {
+	case selected:
+		return activeTheme.Selected.Render(line)
+	case j.state == jobFailed:
+		return activeTheme.DangerFg.Render(line)
+	case j.state == jobSucceeded:
+		return activeTheme.DimText.Render(line)
+	default:
+		return line
+	}
+}
+
+// renderJobOutput shows the tail of the selected job's captured output.
+func (m Model) renderJobOutput(j job, width int) string {
+	text := strings.TrimSpace(j.outputText())
+	if j.err != nil && !j.canceled() && !strings.Contains(text, j.err.Error()) {
+		text = strings.TrimSpace(text + "\n" + j.err.Error())
+	}
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(activeTheme.DimText.Render("  ── output ──"))
+	b.WriteString("\n")
+	lines := strings.Split(text, "\n")
+	if len(lines) > jobsOverlayOutputLines {
+		lines = lines[len(lines)-jobsOverlayOutputLines:]
+	}
+	for _, line := range lines {
+		b.WriteString("  " + visualTruncate(line, width-4) + "\n")
+	}
+	return b.String()
+}
+
+func formatJobElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
@@ -149,6 +149,19 @@ var ChezHelpOverlayKeys = ChezHelpOverlayKeyMap{
 	),
 }
 
+// ── Apply Result Overlay Bindings ──────────────────────────────────
+
+type ChezApplyResultKeyMap struct {
+	Close key.Binding
+}
+
+var ChezApplyResultKeys = ChezApplyResultKeyMap{
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q", "enter"),
+		key.WithHelp("esc", "Close"),
+	),
+}
+
 // ── Confirm Dialog Bindings ────────────────────────────────────────
 
 type ChezConfirmKeyMap struct {
@@ -277,6 +290,7 @@ type ChezManagedKeyMap struct {
 	ClearSearch   key.Binding
 	Actions       key.Binding
 	Refresh       key.Binding
+	Mark          key.Binding
 	Expand        key.Binding
 	Collapse      key.Binding
 }
@@ -306,6 +320,10 @@ var ChezManagedKeys = ChezManagedKeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "Refresh"),
 	),
+	Mark: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "Mark for add"),
+	),
 	Expand: key.NewBinding(
 		key.WithKeys("l", "enter", "space"),
 		key.WithHelp("l", "Expand"),
@@ -362,11 +380,242 @@ var ChezDiffKeys = ChezDiffKeyMap{
 	),
 }
 
+// ── Apply Plan Bindings ────────────────────────────────────────────
+
+type ChezApplyPlanKeyMap struct {
+	Toggle    key.Binding
+	ToggleAll key.Binding
+	Diff      key.Binding
+	Apply     key.Binding
+}
+
+var ChezApplyPlanKeys = ChezApplyPlanKeyMap{
+	Toggle: key.NewBinding(
+		key.WithKeys("space"),
+		key.WithHelp("space", "Include/exclude"),
+	),
+	ToggleAll: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "Toggle all"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("Enter", "View diff"),
+	),
+	Apply: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "Apply selected"),
+	),
+}
+
+// ── Backups Bindings ───────────────────────────────────────────────
+
+type ChezBackupsKeyMap struct {
+	Diff    key.Binding
+	Restore key.Binding
+}
+
+var ChezBackupsKeys = ChezBackupsKeyMap{
+	Diff: key.NewBinding(
+		key.WithKeys("enter", "d"),
+		key.WithHelp("Enter/d", "Diff backup against current"),
+	),
+	Restore: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "Restore backup"),
+	),
+}
+
+// ── Time Travel Bindings ───────────────────────────────────────────
+
+type ChezTimeTravelKeyMap struct {
+	Preview key.Binding
+}
+
+var ChezTimeTravelKeys = ChezTimeTravelKeyMap{
+	Preview: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("Enter", "Preview commit"),
+	),
+}
+
+// ── Destination Preview Bindings ───────────────────────────────────
+
+type ChezDestPreviewKeyMap struct {
+	Diff        key.Binding
+	ChangesOnly key.Binding
+}
+
+var ChezDestPreviewKeys = ChezDestPreviewKeyMap{
+	Diff: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "Diff current against preview"),
+	),
+	ChangesOnly: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "Show changes only"),
+	),
+}
+
+// ── What-If Data Bindings ──────────────────────────────────────────
+
+type ChezWhatIfKeyMap struct {
+	Diff    key.Binding
+	Overlay key.Binding
+}
+
+var ChezWhatIfKeys = ChezWhatIfKeyMap{
+	Diff: key.NewBinding(
+		key.WithKeys("d", "enter"),
+		key.WithHelp("d/enter", "Open diff full screen"),
+	),
+	Overlay: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "Choose another overlay"),
+	),
+}
+
+// ── Template Lint Bindings ─────────────────────────────────────────
+
+type ChezTemplateLintKeyMap struct {
+	Edit  key.Binding
+	Rerun key.Binding
+}
+
+var ChezTemplateLintKeys = ChezTemplateLintKeyMap{
+	Edit: key.NewBinding(
+		key.WithKeys("enter", "e"),
+		key.WithHelp("enter/e", "Edit at the failing line"),
+	),
+	Rerun: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "Render templates again"),
+	),
+}
+
+// ── Data Dependencies Bindings ─────────────────────────────────────
+
+type ChezDataDepsKeyMap struct {
+	Reload key.Binding
+}
+
+var ChezDataDepsKeys = ChezDataDepsKeyMap{
+	Reload: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "Scan templates again"),
+	),
+}
+
+// ── Scripts Bindings ───────────────────────────────────────────────
+
+type ChezScriptsKeyMap struct {
+	Reset      key.Binding
+	Edit       key.Binding
+	Reload     key.Binding
+	ScrollUp   key.Binding
+	ScrollDown key.Binding
+}
+
+var ChezScriptsKeys = ChezScriptsKeyMap{
+	Reset: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "Reset run state so the script runs again"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "Edit script source"),
+	),
+	Reload: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "Reload scripts"),
+	),
+	ScrollUp: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "Scroll preview up"),
+	),
+	ScrollDown: key.NewBinding(
+		key.WithKeys("ctrl+d"),
+		key.WithHelp("ctrl+d", "Scroll preview down"),
+	),
+}
+
+// ── Externals Bindings ─────────────────────────────────────────────
+
+type ChezExternalsKeyMap struct {
+	Refresh key.Binding
+	Reload  key.Binding
+}
+
+var ChezExternalsKeys = ChezExternalsKeyMap{
+	Refresh: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "Re-download and apply the external"),
+	),
+	Reload: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "Reload externals"),
+	),
+}
+
+// ── Jobs Overlay Bindings ──────────────────────────────────────────
+
+type ChezJobsKeyMap struct {
+	Open   key.Binding
+	Close  key.Binding
+	Clear  key.Binding
+	Cancel key.Binding
+}
+
+var ChezJobsKeys = ChezJobsKeyMap{
+	Open: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "Background jobs"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("J", "esc", "q"),
+		key.WithHelp("esc", "Close"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "Clear finished"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "Cancel job"),
+	),
+}
+
+// ── Terminal Pane Bindings ─────────────────────────────────────────
+
+// ChezTerminalKeys apply on the terminal pane. While a command runs, every
+// key other than the scroll keys is forwarded to it.
+type ChezTerminalKeyMap struct {
+	ScrollUp   key.Binding
+	ScrollDown key.Binding
+	Close      key.Binding
+}
+
+var ChezTerminalKeys = ChezTerminalKeyMap{
+	ScrollUp: key.NewBinding(
+		key.WithKeys("pgup", "shift+up"),
+		key.WithHelp("PgUp", "Scroll back"),
+	),
+	ScrollDown: key.NewBinding(
+		key.WithKeys("pgdown", "shift+down"),
+		key.WithHelp("PgDn", "Scroll forward"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q", "enter"),
+		key.WithHelp("esc", "Close (after exit)"),
+	),
+}
+
 // ── Command Tab Bindings ───────────────────────────────────────────
 
 type ChezCommandKeyMap struct {
-	Run    key.Binding
-	DryRun key.Binding
+	Run        key.Binding
+	DryRun     key.Binding
+	LastOutput key.Binding
 }
 
 var ChezCommandKeys = ChezCommandKeyMap{
@@ -378,6 +627,10 @@ var ChezCommandKeys = ChezCommandKeyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "Dry run"),
 	),
+	LastOutput: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "Last output"),
+	),
 }
 
 // ── Filter Overlay Bindings ────────────────────────────────────────
@@ -461,6 +714,8 @@ type ChezInfoKeyMap struct {
 	Right   key.Binding
 	Format  key.Binding
 	Refresh key.Binding
+	Toggle  key.Binding
+	Delete  key.Binding
 }
 
 var ChezInfoKeys = ChezInfoKeyMap{
@@ -480,6 +735,42 @@ var ChezInfoKeys = ChezInfoKeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "Refresh"),
 	),
+	Toggle: key.NewBinding(
+		key.WithKeys("enter", "space"),
+		key.WithHelp("enter/space", "Expand/collapse"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "Delete key/bucket"),
+	),
+}
+
+// ── Info Template Sub-view Bindings ───────────────────────────────
+
+type ChezTemplateREPLKeyMap struct {
+	Edit        key.Binding
+	Done        key.Binding
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+}
+
+var ChezTemplateREPLKeys = ChezTemplateREPLKeyMap{
+	Edit: key.NewBinding(
+		key.WithKeys("enter", "i"),
+		key.WithHelp("enter/i", "Edit template"),
+	),
+	Done: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "Stop editing"),
+	),
+	HistoryPrev: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("^p", "Older expression"),
+	),
+	HistoryNext: key.NewBinding(
+		key.WithKeys("ctrl+n"),
+		key.WithHelp("^n", "Newer expression"),
+	),
 }
 
 // ── Panel Bindings ──────────────────────────────────────────────────
@@ -489,6 +780,7 @@ type ChezPanelKeyMap struct {
 	FocusPanel  key.Binding
 	FocusList   key.Binding
 	ContentMode key.Binding
+	Reveal      key.Binding
 	ScrollDown  key.Binding
 	ScrollUp    key.Binding
 	HalfDown    key.Binding
@@ -514,6 +806,10 @@ var ChezPanelKeys = ChezPanelKeyMap{
 		key.WithKeys("v"),
 		key.WithHelp("v", "Panel view"),
 	),
+	Reveal: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "Reveal encrypted file"),
+	),
 	ScrollDown: key.NewBinding(
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "Scroll down"),
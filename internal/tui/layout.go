@@ -63,6 +63,14 @@ func (m Model) infoViewHeight() int {
 	return clampListHeight(m.height - headerLines - subViewBar - statusFilesFooterLines)
 }
 
+// templateREPLOutputHeight is the room left for output under the Template
+// sub-view's input box.
+func (m Model) templateREPLOutputHeight() int {
+	inputBox := templateInputLines + 2 // rounded border
+	outputLabel := 1
+	return max(m.infoViewHeight()-inputBox-outputLabel, 3)
+}
+
 func (m Model) chezmoiCommandsListHeight() int {
 	if m.height == 0 {
 		return 0
@@ -71,6 +79,15 @@ func (m Model) chezmoiCommandsListHeight() int {
 	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
 }
 
+// commandOutputHeight is the output viewport height below the header and
+// separator of the Commands tab output pane.
+func (m Model) commandOutputHeight() int {
+	if m.height == 0 {
+		return 20
+	}
+	return max(m.chezmoiCommandsListHeight()-2, 1)
+}
+
 func (m Model) chezmoiDiffViewHeight() int {
 	if m.height == 0 {
 		return 0
@@ -83,6 +100,97 @@ func (m Model) chezmoiDiffViewHeight() int {
 	return clampListHeight(m.height - headerLines - statusFilesFooterLines - actionsLines)
 }
 
+func (m Model) backupsListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) destPreviewListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) whatIfListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) templateLintListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4                            // breadcrumb + separator + summary + blank line
+	detailLines := 2 + templateLintMessageLines // blank line + location + message
+	return clampListHeight(m.height - headerLines - detailLines - statusFilesFooterLines)
+}
+
+func (m Model) dataDepsListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) scriptsListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) externalsListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) timeTravelListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+func (m Model) applyPlanListHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 4 // breadcrumb + separator + summary + blank line
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+// terminalPaneHeight is the number of output rows shown in the terminal pane.
+func (m Model) terminalPaneHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	headerLines := 2 // breadcrumb + separator
+	return clampListHeight(m.height - headerLines - statusFilesFooterLines)
+}
+
+// terminalPaneSize is the pseudo-terminal window size matching the pane.
+func (m Model) terminalPaneSize() (cols, rows int) {
+	rows = m.terminalPaneHeight()
+	if rows == 0 {
+		rows = 24
+	}
+	return m.effectiveWidth(), rows
+}
+
 func (m Model) effectiveWidth() int {
 	if m.width == 0 {
 		return 80
@@ -1,6 +1,12 @@
 package tui
 
-import "github.com/daptify14/chezit/internal/chezmoi"
+import (
+	"encoding/json"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
 
 // ExitMsg is sent when the user wants to exit the TUI.
 // EscQuit and EscBack both produce this message; the caller decides final handling.
@@ -32,6 +38,13 @@ type chezmoiActionDoneMsg struct {
 	err     error
 }
 
+// commitSecretsFoundMsg reports a commit stopped by the secret scan, so
+// that it can be confirmed anyway.
+type commitSecretsFoundMsg struct {
+	message  string
+	findings []chezmoi.SecretFinding
+}
+
 type chezmoiExecDoneMsg struct {
 	action chezmoiAction
 	err    error
@@ -60,6 +73,69 @@ type chezmoiForgetDoneMsg struct {
 	err  error
 }
 
+// addPreviewLoadedMsg carries what an add would create, shown before it
+// runs.
+type addPreviewLoadedMsg struct {
+	request addPreviewRequest
+	preview chezmoi.AddPreview
+	err     error
+}
+
+// addOptionsLoadedMsg carries the options remembered for the directory of
+// a file about to be added with options.
+type addOptionsLoadedMsg struct {
+	path  string
+	isDir bool
+	opts  chezmoi.AddOptions
+	err   error
+}
+
+type addOptionsSavedMsg struct {
+	err error
+}
+
+// sourceEntryLoadedMsg carries the decoded source name of a managed target
+// for the attributes form.
+type sourceEntryLoadedMsg struct {
+	path  string
+	entry chezmoi.SourceEntry
+	err   error
+}
+
+// chattrPreviewLoadedMsg carries how a template attribute change alters
+// the target, shown before it runs.
+type chattrPreviewLoadedMsg struct {
+	request chattrRequest
+	diff    string
+	err     error
+}
+
+// ignoreFormLoadedMsg carries the pattern to offer for a row and the
+// machine values its conditions are built from.
+type ignoreFormLoadedMsg struct {
+	path     string
+	rel      string
+	pattern  string
+	editable bool
+	machine  chezmoi.IgnoreMachine
+	err      error
+}
+
+type ignoreAppendedMsg struct {
+	path        string
+	pattern     string
+	appliesHere bool // the pattern ignores path on this machine
+	err         error
+}
+
+// ignoreExplainedMsg carries how the ignore rules and entry filter treat
+// a path.
+type ignoreExplainedMsg struct {
+	path        string
+	explanation chezmoi.IgnoreExplanation
+	err         error
+}
+
 type chezmoiSourceContentMsg struct {
 	path         string
 	content      string // raw content
@@ -68,13 +144,6 @@ type chezmoiSourceContentMsg struct {
 	err          error
 }
 
-type chezmoiCapturedOutputMsg struct {
-	action chezmoiAction
-	label  string // display name, e.g., "chezmoi re-add"
-	output string // captured stdout+stderr
-	err    error
-}
-
 // infoContentLoadedMsg delivers loaded content for an Info sub-view.
 type infoContentLoadedMsg struct {
 	view    int // which sub-view this is for (infoViewConfig, etc.)
@@ -97,11 +166,6 @@ type chezmoiGitActionDoneMsg struct {
 	err     error
 }
 
-type chezmoiAddDoneMsg struct {
-	path string
-	err  error
-}
-
 type filesSearchDebouncedMsg struct {
 	requestID uint64
 }
@@ -115,12 +179,6 @@ type filesSearchCompletedMsg struct {
 	err       error
 }
 
-type chezmoiArchiveDoneMsg struct {
-	path string
-	size int64
-	err  error
-}
-
 type sourceDirResolvedMsg struct {
 	path   string
 	action chezmoiAction
@@ -139,16 +197,197 @@ type opaqueDirPopulatedMsg struct {
 // landingStatsReadyMsg is sent after all initial stats have loaded and debounced.
 type landingStatsReadyMsg struct{}
 
+type applyPlanLoadedMsg struct {
+	entries []chezmoi.ApplyPlanEntry
+	err     error
+	gen     uint64
+}
+
+type terminalStartedMsg struct {
+	session *terminalSession
+}
+
+type terminalOutputMsg struct {
+	session *terminalSession
+	data    []byte
+}
+
+type terminalExitedMsg struct {
+	session *terminalSession
+	err     error
+}
+
 type chezmoiGitCommitsLoadedMsg struct {
 	unpushed []chezmoi.GitCommit
 	incoming []chezmoi.GitCommit
+	files    []chezmoi.IncomingFile
+	filesErr error // incoming files failed to load; the commits did not
 	err      error
 	gen      uint64
 }
 
-type chezmoiGitFetchDoneMsg struct {
+// applyPlanAppliedMsg reports the verbose output of applying the targets
+// kept in the apply plan.
+type applyPlanAppliedMsg struct {
+	paths   []string
+	scripts []string
+	output  string
+	// backedUp is how many files were saved before applying.
+	backedUp int
+	err      error
+}
+
+// applyBackupDoneMsg reports the pre-apply snapshot. next is the apply
+// itself, run only when the backup succeeded.
+type applyBackupDoneMsg struct {
+	run  chezmoi.BackupRun
+	err  error
+	next tea.Cmd
+}
+
+type backupsLoadedMsg struct {
+	runs []chezmoi.BackupRun
+	err  error
+}
+
+type backupDiffLoadedMsg struct {
+	path    string
+	content string
+	err     error
+}
+
+// backupRestoredMsg reports a restore; before is the snapshot of the file
+// it replaced.
+type backupRestoredMsg struct {
+	path   string
+	before chezmoi.BackupRun
+	err    error
+}
+
+// updateOriginRecordedMsg reports recording the source HEAD before an
+// update or pull. next is the update itself.
+type updateOriginRecordedMsg struct {
+	err  error
+	next tea.Cmd
+}
+
+type updateOriginLoadedMsg struct {
+	origin chezmoi.UpdateOrigin
+	err    error
+}
+
+type updateUndoneMsg struct {
+	commit string
+	err    error
+}
+
+type timeTravelCommitsLoadedMsg struct {
+	commits []chezmoi.GitCommit
+	err     error
+}
+
+// destPreviewLoadedMsg carries a sandbox or time-travel preview; returnTo
+// is the screen it was started from.
+type destPreviewLoadedMsg struct {
+	preview  chezmoi.DestPreview
+	returnTo Screen
+	err      error
+}
+
+type destPreviewDiffLoadedMsg struct {
+	path    string
+	content string
+	err     error
+}
+
+// whatIfRenderedMsg carries template targets rendered with and without
+// the overlay named label.
+type whatIfRenderedMsg struct {
+	label   string
+	renders []chezmoi.WhatIfRender
+	err     error
+}
+
+// templateLintLoadedMsg carries the result of rendering every template.
+type templateLintLoadedMsg struct {
+	report chezmoi.TemplateLintReport
+	err    error
+}
+
+// templateHistoryLoadedMsg carries the saved Template sub-view expressions.
+type templateHistoryLoadedMsg struct {
+	history []string
+	err     error
+}
+
+// templateEvalTickMsg fires templateEvalDebounce after an edit; seq
+// identifies the edit.
+type templateEvalTickMsg struct {
+	seq int
+}
+
+// templateEvaluatedMsg carries the execute-template result for input.
+type templateEvaluatedMsg struct {
+	seq    int
+	input  string
+	output string
+	err    error
+}
+
+// dataIndexLoadedMsg carries the template data dependency index. view is
+// set when the Data Dependencies view asked for it.
+type dataIndexLoadedMsg struct {
+	index chezmoi.DataIndex
+	err   error
+	view  bool
+}
+
+// scriptsLoadedMsg carries the run_ scripts and their run state.
+type scriptsLoadedMsg struct {
+	scripts []chezmoi.Script
+	err     error
+}
+
+// infoStateLoadedMsg carries chezmoi's persistent state for the Info tab's
+// State sub-view.
+type infoStateLoadedMsg struct {
+	state map[string]map[string]json.RawMessage
+	err   error
+	gen   uint64
+}
+
+// stateDeletedMsg reports that a persistent state key, or a whole bucket
+// when key is empty, was deleted.
+type stateDeletedMsg struct {
+	bucket string
+	key    string
+	err    error
+}
+
+// scriptStateResetMsg reports that a script's run state was deleted.
+type scriptStateResetMsg struct {
+	name string
+	err  error
+}
+
+// externalsLoadedMsg carries the .chezmoiexternal entries.
+type externalsLoadedMsg struct {
+	externals []chezmoi.External
+	err       error
+}
+
+// applyHighlightExpiredMsg ends the post-apply highlight of resolved rows.
+type applyHighlightExpiredMsg struct{}
+
+// jobOutputMsg carries output lines a running job has written so far.
+type jobOutputMsg struct {
+	id    int
+	lines []chezmoi.OutputLine
+}
+
+type jobDoneMsg struct {
+	id  int
 	err error
-	gen uint64
 }
 
 type templatePathsLoadedMsg struct {
@@ -156,6 +395,17 @@ type templatePathsLoadedMsg struct {
 	gen   uint64
 }
 
+type encryptedPathsLoadedMsg struct {
+	paths map[string]bool
+	gen   uint64
+}
+
+// panelRevealExpiredMsg hides a revealed encrypted file again unless it
+// was revealed anew since.
+type panelRevealExpiredMsg struct {
+	seq int
+}
+
 // panelContentLoadedMsg is sent when async panel content loading completes.
 type panelContentLoadedMsg struct {
 	path         string
@@ -163,6 +413,7 @@ type panelContentLoadedMsg struct {
 	section      changesSection
 	content      string // raw content
 	rendered     string // pager-rendered output for diff mode (empty if no pager)
+	side         string // rendered target output for template mode
 	pagerApplied bool
 	err          error
 }
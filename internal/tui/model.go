@@ -46,6 +46,36 @@ type Model struct {
 
 	commit commitState
 
+	addForm addFormState
+
+	attrForm attrFormState
+
+	ignoreForm ignoreFormState
+
+	plan applyPlanState
+
+	backups backupsState
+
+	timeTravel timeTravelState
+
+	destPreview destPreviewState
+
+	whatIf whatIfState
+
+	templateLint templateLintState
+
+	dataDeps dataDepsState
+
+	scripts scriptsState
+
+	externals externalsState
+
+	applyResult applyResultState
+
+	term terminalState
+
+	jobs jobQueue
+
 	filterInput textinput.Model
 
 	panel filePanel
@@ -189,8 +219,9 @@ func NewModel(opts Options) Model {
 		},
 		panel: panel,
 		info: infoTab{
-			viewNames: []string{"Config", "Full", "Data", "Doctor"},
+			viewNames: []string{"Config", "Full", "Data", "Template", "State", "Doctor"},
 			format:    "yaml",
+			template:  templateREPLState{input: newTemplateInput(), historyPos: -1},
 		},
 	}
 	if !managedDeferred {
@@ -210,7 +241,8 @@ func (m Model) Init() tea.Cmd {
 		return nil
 	}
 
-	cmds := []tea.Cmd{m.ui.loadingSpinner.Tick, tea.RequestBackgroundColor}
+	// Encrypted paths are needed by the preview panel on every tab.
+	cmds := []tea.Cmd{m.ui.loadingSpinner.Tick, tea.RequestBackgroundColor, m.loadEncryptedPathsCmd()}
 
 	tab := strings.ToLower(m.opts.InitialTab)
 
@@ -283,6 +315,7 @@ func (m Model) isAnyLoading() bool {
 		m.ui.busyAction ||
 		m.status.loadingGit ||
 		m.status.fetchInProgress ||
+		m.jobs.count(jobRunning) > 0 ||
 		m.filesTab.views[managedViewManaged].loading ||
 		m.filesTab.views[managedViewIgnored].loading ||
 		m.filesTab.views[managedViewUnmanaged].loading ||
@@ -3,6 +3,7 @@ package tui
 import (
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -52,6 +53,19 @@ func TestNewModelReadOnlyStripsMutatingCommands(t *testing.T) {
 	if strings.Contains(joined, "Apply") || strings.Contains(joined, "Update") || strings.Contains(joined, "Re-Add All") || strings.Contains(joined, "Init") || strings.Contains(joined, "Edit Source") {
 		t.Fatalf("unexpected mutating commands in read-only mode: %v", labels)
 	}
+	// Later commands that write the source, destination, or chezmoi state,
+	// including previews that run hooks or add a git worktree.
+	for _, mutating := range []string{"Apply Plan", "Undo Update", "Refresh Externals", "Re-Encrypt All", "Backups", "Time Travel", "Sandbox Apply"} {
+		if slices.Contains(labels, mutating) {
+			t.Fatalf("unexpected mutating command %q in read-only mode: %v", mutating, labels)
+		}
+	}
+	// Read-only info commands stay available.
+	for _, info := range []string{"What-If Data", "Lint Templates", "Data Dependencies", "Scripts", "Externals"} {
+		if !slices.Contains(labels, info) {
+			t.Fatalf("expected %q in read-only mode, got %v", info, labels)
+		}
+	}
 }
 
 func TestInitStatusIncludesGitCommitsLoad(t *testing.T) {
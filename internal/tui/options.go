@@ -33,6 +33,10 @@ type Options struct {
 	// These appear as quick-select options in the commit message input.
 	CommitPresets []string
 
+	// DataProfiles are named template data overlays offered by What-If
+	// Data, keyed by profile name.
+	DataProfiles map[string]map[string]any
+
 	// Editor overrides the $EDITOR environment variable for file editing.
 	// Supports binary with arguments (e.g., "code --wait").
 	// Resolution order: Editor > $EDITOR > "vi".
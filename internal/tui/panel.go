@@ -2,12 +2,14 @@ package tui
 
 import "charm.land/bubbles/v2/viewport"
 
-// panelContentMode distinguishes between diff and file content display.
+// panelContentMode distinguishes between diff, file content, and template
+// source display.
 type panelContentMode int
 
 const (
 	panelModeDiff panelContentMode = iota
 	panelModeContent
+	panelModeTemplate // template source next to its rendered output
 )
 
 // panelFocusZone tracks which zone has keyboard focus.
@@ -30,6 +32,7 @@ type panelCacheEntry struct {
 	content      string
 	lines        []string // rendered lines for viewport (pager-colored or raw)
 	rawLines     []string // canonical raw diff lines; nil for non-diff content
+	sideLines    []string // template mode: rendered output shown next to lines
 	pagerApplied bool
 	err          error
 }
@@ -62,6 +65,11 @@ type filePanel struct {
 
 	cache map[panelCacheKey]panelCacheEntry
 
+	// revealedPath is the encrypted target whose plaintext is shown until
+	// the reveal numbered revealSeq times out.
+	revealedPath string
+	revealSeq    int
+
 	// Layout invalidation tracking.
 	lastWidth int
 }
@@ -170,5 +178,16 @@ func (p *filePanel) resetForTab(tabName string) {
 	p.pendingMode = p.contentMode
 	p.pendingSection = changesSectionDrift
 	p.pendingLoad = false
+	p.revealedPath = ""
 	p.clearCache()
 }
+
+// cacheDeletePlaintext drops everything cached for path that can show an
+// encrypted target's plaintext, in every section.
+func (p *filePanel) cacheDeletePlaintext(path string) {
+	for k := range p.cache {
+		if k.path == path && panelModeDecrypts(k.mode, k.section) {
+			delete(p.cache, k)
+		}
+	}
+}
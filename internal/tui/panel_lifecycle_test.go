@@ -387,6 +387,37 @@ func TestPanelModeKeysFromListToggleBackToDiff(t *testing.T) {
 	}
 }
 
+func TestPanelModeKeysCycleThroughTemplateForTemplateTargets(t *testing.T) {
+	m := NewModel(Options{Service: testService()})
+	m.view = StatusScreen
+	m.width = 140
+	m.height = 40
+	m.activeTab = 0 // Status
+	m.status.files = []chezmoi.FileStatus{
+		{Path: "/home/user/.gitconfig", SourceStatus: 'M', DestStatus: ' '},
+	}
+	m.status.filteredFiles = m.status.files
+	m.status.templatePaths = map[string]bool{"/home/user/.gitconfig": true}
+	m.buildChangesRows()
+	m.status.changesCursor = 2
+	m.panel.focusZone = panelFocusList
+	m.panel.contentMode = panelModeContent
+	m.panel.currentPath = "/home/user/.gitconfig"
+	m.panel.currentSection = changesSectionDrift
+
+	for _, want := range []panelContentMode{panelModeTemplate, panelModeDiff} {
+		updatedModel, cmd := m.handleKeyMsg(tea.KeyPressMsg{Code: 'v', Text: "v"})
+		m = updatedModel.(Model)
+		if m.panel.contentMode != want {
+			t.Fatalf("expected mode %d, got %d", want, m.panel.contentMode)
+		}
+		if cmd == nil {
+			t.Fatal("expected panel reload command after toggling panel mode")
+		}
+		m.panel.loading = false
+	}
+}
+
 func TestPanelFocusedArrowScrollChangesViewportOffset(t *testing.T) {
 	m := NewModel(Options{Service: testService()})
 	m.view = StatusScreen
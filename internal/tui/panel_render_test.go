@@ -79,6 +79,46 @@ func TestRenderFilePanelContentMode(t *testing.T) {
 	}
 }
 
+func TestRenderFilePanelTemplateMode(t *testing.T) {
+	m := NewModel(Options{Service: testService()})
+	m.width = 200
+	m.height = 40
+
+	m.panel.currentPath = "/home/user/.gitconfig"
+	m.panel.contentMode = panelModeTemplate
+	m.panel.cachePut("/home/user/.gitconfig", panelModeTemplate, changesSectionDrift, panelCacheEntry{
+		lines:     []string{"[user]", "  email = {{ .email }}", "{{- if eq .chezmoi.os \"darwin\" }}", "  helper = osxkeychain", "{{- end }}"},
+		sideLines: []string{"[user]", "  email = me@example.com", "  helper = osxkeychain"},
+	})
+
+	output := ansi.Strip(m.renderFilePanel(panelWidthFor(m.width)))
+	for _, want := range []string{".gitconfig [template]", "template", "rendered", "email = {{ .email }}", "me@example.com"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in panel:\n%s", want, output)
+		}
+	}
+	var row string
+	for line := range strings.SplitSeq(output, "\n") {
+		if strings.Contains(line, "{{ .email }}") {
+			row = line
+		}
+	}
+	if !strings.Contains(row, "me@example.com") {
+		t.Fatalf("expected source and rendered lines side by side, got %q", row)
+	}
+}
+
+func TestHighlightTemplateActionsTracksMultilineActions(t *testing.T) {
+	_, inAction := highlightTemplateActions("text {{ if", false)
+	if !inAction {
+		t.Fatal("expected an unclosed action to carry over")
+	}
+	out, inAction := highlightTemplateActions(`  .x }} tail`, true)
+	if inAction || ansi.Strip(out) != "  .x }} tail" {
+		t.Fatalf("expected the action closed with text kept, got %q in=%v", out, inAction)
+	}
+}
+
 func TestRenderPanelFileContentUsesStableLineNumberColumn(t *testing.T) {
 	short := renderPanelFileContent([]string{"alpha"}, 80, "test.txt")
 	longLines := make([]string, 120)
@@ -326,3 +366,22 @@ func TestRenderChezmoiDiffStatusIncludesDriftSubtypeLabel(t *testing.T) {
 		t.Fatalf("expected diff status line to include drift subtype, got: %q", got)
 	}
 }
+
+func TestRenderPanelTitleBarListsTemplateDependencies(t *testing.T) {
+	m := NewModel(Options{Service: testService()})
+	m.panel.currentPath = "/home/test/.gitconfig"
+	m.panel.contentMode = panelModeContent
+	m.status.dataIndex = chezmoi.DataIndex{Templates: []chezmoi.TemplateDeps{
+		{Target: "/home/test/.gitconfig", Keys: []string{".email", ".git.name"}, Secrets: []string{"bitwarden"}},
+	}}
+
+	got := ansi.Strip(m.renderPanelTitleBar(80))
+	if !strings.Contains(got, "\n  uses .email, .git.name · bitwarden()") {
+		t.Fatalf("expected the template dependencies on the detail line, got: %q", got)
+	}
+
+	got = ansi.Strip(m.renderPanelTitleBar(30))
+	if lines := strings.Split(got, "\n"); len(lines) != 2 || ansi.StringWidth(lines[1]) > 30 {
+		t.Fatalf("expected the dependencies truncated to one line, got: %q", got)
+	}
+}
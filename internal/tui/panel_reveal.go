@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// panelRevealTimeout is how long a revealed encrypted file stays shown.
+const panelRevealTimeout = 30 * time.Second
+
+// panelEncryptedError stands in for the content of an encrypted target
+// until it is revealed.
+func panelEncryptedError() error {
+	return newPanelPreviewError("Encrypted file (press " + ChezPanelKeys.Reveal.Help().Key + " to reveal)")
+}
+
+// panelHidesContent reports whether the content of path stays hidden
+// because it is an encrypted target that is not revealed.
+func (m Model) panelHidesContent(path string) bool {
+	return m.status.encryptedPaths[path] && m.panel.revealedPath != path
+}
+
+// panelModeDecrypts reports whether mode shows an encrypted target's
+// plaintext in section: its content, its chezmoi diff, or its template.
+// Git diffs show the source, which stays encrypted.
+func panelModeDecrypts(mode panelContentMode, section changesSection) bool {
+	switch mode {
+	case panelModeContent, panelModeTemplate:
+		return true
+	case panelModeDiff:
+		switch section {
+		case changesSectionUnstaged, changesSectionStaged, changesSectionUnpushed,
+			changesSectionIncoming, changesSectionIncomingFiles:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func (m Model) handleEncryptedPathsLoaded(msg encryptedPathsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.gen {
+		return m, nil
+	}
+	m.status.encryptedPaths = msg.paths
+	m.updateCommandAvailability()
+
+	// Content cached before the paths were known shows the plaintext.
+	stale := false
+	for k := range m.panel.cache {
+		if panelModeDecrypts(k.mode, k.section) && m.panelHidesContent(k.path) {
+			delete(m.panel.cache, k)
+			stale = stale || k.path == m.panel.currentPath
+		}
+	}
+	if stale {
+		return m.panelReloadCurrent()
+	}
+	return m, nil
+}
+
+// togglePanelReveal shows the decrypted content, diff, or template of the
+// encrypted file in the panel, or hides it again. The reveal times out
+// after panelRevealTimeout.
+func (m Model) togglePanelReveal() (Model, tea.Cmd, bool) {
+	path := m.panel.currentPath
+	if !panelModeDecrypts(m.panel.contentMode, m.panel.currentSection) || !m.status.encryptedPaths[path] {
+		return m, nil, false
+	}
+	if m.panel.revealedPath == path {
+		updated, cmd := m.hidePanelReveal()
+		return updated, cmd, true
+	}
+	if m.panel.revealedPath != "" {
+		m.panel.cacheDeletePlaintext(m.panel.revealedPath)
+	}
+	m.panel.revealedPath = path
+	m.panel.revealSeq++
+	m.panel.cacheDeletePlaintext(path)
+	seq := m.panel.revealSeq
+	expire := tea.Tick(panelRevealTimeout, func(time.Time) tea.Msg {
+		return panelRevealExpiredMsg{seq: seq}
+	})
+	m, cmd := m.panelReloadCurrent()
+	return m, tea.Batch(cmd, expire), true
+}
+
+func (m Model) handlePanelRevealExpired(msg panelRevealExpiredMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.panel.revealSeq || m.panel.revealedPath == "" {
+		return m, nil
+	}
+	return m.hidePanelReveal()
+}
+
+// hidePanelReveal drops the revealed plaintext and shows the encrypted
+// placeholder again.
+func (m Model) hidePanelReveal() (Model, tea.Cmd) {
+	path := m.panel.revealedPath
+	m.panel.revealedPath = ""
+	m.panel.cacheDeletePlaintext(path)
+	if path != m.panel.currentPath {
+		return m, nil
+	}
+	return m.panelReloadCurrent()
+}
+
+// panelReloadCurrent reloads the panel for the selected row after its
+// cache entry was dropped. Nothing loads while another screen is shown.
+func (m Model) panelReloadCurrent() (Model, tea.Cmd) {
+	if m.view != StatusScreen {
+		return m, nil
+	}
+	return m.panelLoadForCurrentTab()
+}
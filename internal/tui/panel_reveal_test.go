@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const testEncryptedPath = "/home/test/.netrc"
+
+// testRevealOptions open the Files tab on an encrypted target with its
+// panel loaded.
+func testRevealOptions(opts ...TestModelOption) []TestModelOption {
+	return append([]TestModelOption{
+		WithTab(1), // Files tab
+		WithSize(140, 40),
+		WithManagedFiles([]string{testEncryptedPath}),
+		WithPanelVisible(),
+		WithLoaded(encryptedPathsLoadedMsg{paths: map[string]bool{testEncryptedPath: true}}),
+		WithPanelLoaded(),
+	}, opts...)
+}
+
+func TestPanelHidesEncryptedFileUntilRevealed(t *testing.T) {
+	m := newTestModel(testRevealOptions()...)
+	panel := ansi.Strip(m.renderFilePanel(60))
+	if !strings.Contains(panel, "Encrypted file (press R to reveal)") || !strings.Contains(panel, "encrypted") {
+		t.Fatalf("expected the placeholder, got:\n%s", panel)
+	}
+
+	m, cmd := sendKey(t, m, runeKey("R"))
+	if cmd == nil || m.panel.revealedPath != testEncryptedPath {
+		t.Fatalf("expected the file revealed, got %q", m.panel.revealedPath)
+	}
+	m, _ = sendMsg(t, m, panelContentLoadedMsg{
+		path: testEncryptedPath, mode: panelModeContent, section: changesSectionDrift,
+		content: "machine example.com",
+	})
+	panel = ansi.Strip(m.renderFilePanel(60))
+	if !strings.Contains(panel, "machine example.com") || !strings.Contains(panel, "decrypted · hides after 30s") {
+		t.Fatalf("expected the decrypted content, got:\n%s", panel)
+	}
+
+	// An expiry from an earlier reveal leaves this one shown.
+	m, _ = sendMsg(t, m, panelRevealExpiredMsg{seq: m.panel.revealSeq - 1})
+	if m.panel.revealedPath == "" {
+		t.Fatal("expected a stale expiry ignored")
+	}
+
+	m, _ = sendMsg(t, m, panelRevealExpiredMsg{seq: m.panel.revealSeq})
+	if m.panel.revealedPath != "" {
+		t.Fatal("expected the reveal to expire")
+	}
+	if _, ok := m.panel.cacheGet(testEncryptedPath, panelModeContent, changesSectionDrift); ok {
+		t.Fatal("expected the plaintext dropped from the cache")
+	}
+}
+
+func TestPanelDropsPlaintextLoadedAfterRevealEnds(t *testing.T) {
+	m := newTestModel(testRevealOptions()...)
+	m, _ = sendKey(t, m, runeKey("R"))
+	m, _ = sendKey(t, m, runeKey("R"))
+	if m.panel.revealedPath != "" {
+		t.Fatal("expected R to hide the file again")
+	}
+
+	m, _ = sendMsg(t, m, panelContentLoadedMsg{
+		path: testEncryptedPath, mode: panelModeContent, section: changesSectionDrift,
+		content: "machine example.com",
+	})
+	entry, ok := m.panel.cacheGet(testEncryptedPath, panelModeContent, changesSectionDrift)
+	if !ok || entry.content != "" || entry.err == nil {
+		t.Fatalf("expected the placeholder cached, got %+v", entry)
+	}
+}
+
+func TestPanelHidesEncryptedDiffAndTemplate(t *testing.T) {
+	m := newTestModel(testRevealOptions()...)
+	m.status.templatePaths = map[string]bool{testEncryptedPath: true}
+	for _, mode := range []panelContentMode{panelModeDiff, panelModeTemplate} {
+		msg, ok := m.panelContentCmd(testEncryptedPath, mode, changesSectionDrift)().(panelContentLoadedMsg)
+		if !ok || msg.err == nil || !strings.Contains(msg.err.Error(), "Encrypted file") {
+			t.Fatalf("expected mode %v hidden, got %+v", mode, msg)
+		}
+	}
+	// Git diffs show the source, which is still encrypted.
+	if panelModeDecrypts(panelModeDiff, changesSectionStaged) {
+		t.Fatal("expected staged diffs shown")
+	}
+
+	m.panel.contentMode = panelModeDiff
+	m, cmd := sendKey(t, m, runeKey("R"))
+	if cmd == nil || m.panel.revealedPath != testEncryptedPath {
+		t.Fatalf("expected R to reveal the diff, got %q", m.panel.revealedPath)
+	}
+	m, _ = sendKey(t, m, runeKey("R"))
+	m, _ = sendMsg(t, m, panelContentLoadedMsg{
+		path: testEncryptedPath, mode: panelModeDiff, section: changesSectionDrift,
+		content: "+machine example.com",
+	})
+	entry, ok := m.panel.cacheGet(testEncryptedPath, panelModeDiff, changesSectionDrift)
+	if !ok || entry.content != "" || entry.err == nil {
+		t.Fatalf("expected the diff placeholder cached, got %+v", entry)
+	}
+}
+
+func TestFilesMenuEncryptCopyQueuesJob(t *testing.T) {
+	m := newTestModel(testRevealOptions()...)
+	m.openFilesActionsMenu()
+	var labels []string
+	for _, item := range m.actions.managedItems {
+		labels = append(labels, item.label)
+	}
+	if !slices.Contains(labels, "Encrypt Copy") || !slices.Contains(labels, "Decrypt Copy") {
+		t.Fatalf("expected encryption actions, got %v", labels)
+	}
+
+	updated, _ := m.executeFilesAction(chezmoiActionEncryptFile)
+	m = updated.(Model)
+	if len(m.jobs.jobs) != 1 || m.jobs.jobs[0].label != "encrypt ~/.netrc" {
+		t.Fatalf("expected an encrypt job, got %+v", m.jobs.jobs)
+	}
+
+	readOnly := newTestModel(testRevealOptions(WithReadOnly())...)
+	readOnly.openFilesActionsMenu()
+	for _, item := range readOnly.actions.managedItems {
+		if item.action == chezmoiActionDecryptFile && !item.disabled {
+			t.Fatal("expected Decrypt Copy disabled in read-only mode")
+		}
+	}
+}
+
+func TestReencryptAllNeedsEncryptedFiles(t *testing.T) {
+	m := newTestModel()
+	m.cmds.items = []chezmoiCommandItem{{label: "Re-Encrypt All", id: chezmoiCmdReencryptAll}}
+	m.updateCommandAvailability()
+	if m.cmds.items[0].available {
+		t.Fatal("expected Re-Encrypt All unavailable without encrypted files")
+	}
+	m, _ = sendMsg(t, m, encryptedPathsLoadedMsg{paths: map[string]bool{testEncryptedPath: true}})
+	if !m.cmds.items[0].available {
+		t.Fatal("expected Re-Encrypt All available")
+	}
+}
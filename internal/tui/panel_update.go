@@ -28,14 +28,13 @@ func (m Model) handlePanelKeys(msg tea.KeyPressMsg) (Model, tea.Cmd, bool) {
 		return m, nil, true
 
 	case key.Matches(msg, ChezPanelKeys.ContentMode):
-		if m.panel.contentMode == panelModeDiff {
-			m.panel.contentMode = panelModeContent
-		} else {
-			m.panel.contentMode = panelModeDiff
-		}
+		m.panel.contentMode = m.nextPanelContentMode()
 		updated, cmd := m.panelLoadForCurrentTab()
 		return updated, cmd, true
 
+	case key.Matches(msg, ChezPanelKeys.Reveal):
+		return m.togglePanelReveal()
+
 	case key.Matches(msg, ChezPanelKeys.ScrollDown):
 		m = m.syncPanelViewportContent()
 		m.panel.viewport.ScrollDown(navigationStepForKey(msg))
@@ -70,6 +69,26 @@ func (m Model) handlePanelKeys(msg tea.KeyPressMsg) (Model, tea.Cmd, bool) {
 	return m, nil, false
 }
 
+// nextPanelContentMode cycles diff → file → template → diff. The template
+// view is only offered for template-managed targets.
+func (m Model) nextPanelContentMode() panelContentMode {
+	switch m.panel.contentMode {
+	case panelModeDiff:
+		return panelModeContent
+	case panelModeContent:
+		if m.panelShowsTemplate(m.panel.currentPath, m.panel.currentSection) {
+			return panelModeTemplate
+		}
+	}
+	return panelModeDiff
+}
+
+// panelShowsTemplate reports whether path is a template target the panel
+// can show as source and rendered output.
+func (m Model) panelShowsTemplate(path string, section changesSection) bool {
+	return section == changesSectionDrift && m.status.templatePaths[path]
+}
+
 // panelLoadForCurrentTab dispatches a panel load based on the active tab.
 func (m Model) panelLoadForCurrentTab() (Model, tea.Cmd) {
 	switch m.activeTabName() {
@@ -104,6 +123,9 @@ func (m Model) panelLoadForChanges() (Model, tea.Cmd) {
 	case row.commit != nil:
 		path = row.commit.Hash
 		section = row.section
+	case row.incomingFile != nil:
+		path = row.incomingFile.SourcePath
+		section = row.section
 	default:
 		return m, nil
 	}
@@ -197,6 +219,9 @@ func (m Model) panelContentCmd(path string, mode panelContentMode, section chang
 
 		switch mode {
 		case panelModeDiff:
+			if panelModeDecrypts(mode, section) && m.panelHidesContent(path) {
+				return panelContentLoadedMsg{path: path, mode: mode, section: section, err: panelEncryptedError()}
+			}
 			switch section {
 			case changesSectionDrift:
 				content, err = m.service.Diff(path)
@@ -210,6 +235,8 @@ func (m Model) panelContentCmd(path string, mode panelContentMode, section chang
 				}
 			case changesSectionUnpushed, changesSectionIncoming:
 				content, err = m.service.GitShow(path)
+			case changesSectionIncomingFiles:
+				content, err = m.service.GitDiffIncoming(path)
 			default:
 				content, err = m.service.Diff(path)
 			}
@@ -221,7 +248,37 @@ func (m Model) panelContentCmd(path string, mode panelContentMode, section chang
 					err: newPanelPreviewError("Use [diff] view to see commit changes"),
 				}
 			}
-			content, err = m.panelLoadContentPreview(path, section)
+			if section == changesSectionIncomingFiles {
+				return panelContentLoadedMsg{
+					path: path, mode: mode, section: section,
+					err: newPanelPreviewError("Use [diff] view to see incoming changes"),
+				}
+			}
+			switch {
+			case m.panelHidesContent(path):
+				return panelContentLoadedMsg{path: path, mode: mode, section: section, err: panelEncryptedError()}
+			case m.status.encryptedPaths[path]:
+				// Revealed: the decrypted target state, whatever the tab.
+				content, err = m.panelReadTargetFile(path)
+			default:
+				content, err = m.panelLoadContentPreview(path, section)
+			}
+
+		case panelModeTemplate:
+			if !m.panelShowsTemplate(path, section) {
+				return panelContentLoadedMsg{
+					path: path, mode: mode, section: section,
+					err: newPanelPreviewError("Not a template (use [diff] or [file] view)"),
+				}
+			}
+			if m.panelHidesContent(path) {
+				return panelContentLoadedMsg{path: path, mode: mode, section: section, err: panelEncryptedError()}
+			}
+			var side string
+			content, side, err = m.panelLoadTemplatePreview(path)
+			if err == nil {
+				return panelContentLoadedMsg{path: path, mode: mode, section: section, content: content, side: side}
+			}
 		}
 
 		msg := panelContentLoadedMsg{
@@ -245,6 +302,12 @@ func (m Model) panelContentCmd(path string, mode panelContentMode, section chang
 func (m Model) handlePanelContentLoaded(msg panelContentLoadedMsg) (Model, tea.Cmd) {
 	m.panel.loading = false
 
+	// Plaintext whose reveal ended while it loaded is not kept.
+	if panelModeDecrypts(msg.mode, msg.section) && msg.err == nil && m.panelHidesContent(msg.path) {
+		msg.content = ""
+		msg.err = panelEncryptedError()
+	}
+
 	// Cache the result regardless of staleness
 	rawLines := strings.Split(msg.content, "\n")
 	var lines []string
@@ -260,8 +323,11 @@ func (m Model) handlePanelContentLoaded(msg panelContentLoadedMsg) (Model, tea.C
 		err:          msg.err,
 		pagerApplied: msg.pagerApplied,
 	}
-	if msg.mode == panelModeDiff {
+	switch msg.mode {
+	case panelModeDiff:
 		entry.rawLines = rawLines
+	case panelModeTemplate:
+		entry.sideLines = strings.Split(msg.side, "\n")
 	}
 	m.panel.cachePut(msg.path, msg.mode, msg.section, entry)
 
@@ -309,10 +375,14 @@ func (m Model) panelViewportDimensions() (contentWidth, viewportHeight int, ok b
 	contentWidth = max(panelW-4, 20) // border + padding
 
 	var panelH int
-	switch m.activeTabName() {
-	case "Status":
+	switch {
+	case m.view == DestPreviewScreen:
+		panelH = m.destPreviewListHeight()
+	case m.view == WhatIfScreen:
+		panelH = m.whatIfListHeight()
+	case m.activeTabName() == "Status":
 		panelH = m.chezmoiChangesListHeight() + 4
-	case "Files":
+	case m.activeTabName() == "Files":
 		panelH = m.chezmoiManagedListHeight() + 4
 	default:
 		panelH = max(m.height-2, 8)
@@ -335,6 +405,9 @@ func (m Model) panelViewportContentForWidth(contentWidth int) string {
 		if entry.err != nil {
 			return activeTheme.DimText.Render("  " + panelErrorText(entry.err))
 		}
+		if m.panel.contentMode == panelModeTemplate {
+			return renderPanelTemplate(entry.lines, entry.sideLines, contentWidth)
+		}
 		return m.renderPanelViewportContent(entry.lines, contentWidth, entry.pagerApplied)
 	}
 }
@@ -385,6 +458,11 @@ func panelErrorText(err error) string {
 }
 
 func (m Model) panelLoadContentPreview(path string, section changesSection) (string, error) {
+	// Destination previews show the file as rendered into the temporary
+	// destination.
+	if m.view == DestPreviewScreen {
+		return readPanelLocalFileWithNotFoundMessage(path, "Not in the preview")
+	}
 	switch section {
 	case changesSectionUnstaged, changesSectionStaged:
 		return m.panelReadSourceFile(path)
@@ -398,6 +476,30 @@ func (m Model) panelLoadContentPreview(path string, section changesSection) (str
 	}
 }
 
+// panelLoadTemplatePreview returns a template target's source and its
+// rendered output.
+func (m Model) panelLoadTemplatePreview(path string) (source, rendered string, err error) {
+	tmpl, err := m.service.TemplateSourceOf(path)
+	if err != nil {
+		return "", "", mapPanelTargetPreviewError(err)
+	}
+	if strings.HasPrefix(filepath.Base(tmpl.Path), "encrypted_") {
+		return "", "", newPanelPreviewError("Encrypted template (source preview disabled)")
+	}
+	for _, text := range []string{tmpl.Source, tmpl.Rendered} {
+		data := []byte(text)
+		if len(data) > panelPreviewMaxBytes {
+			return "", "", newPanelPreviewError(
+				fmt.Sprintf("File too large to preview (%s > %s)", panelFormatBytes(uint64(len(data))), panelFormatBytes(panelPreviewMaxBytes)),
+			)
+		}
+		if isLikelyBinaryContent(data) {
+			return "", "", newPanelPreviewError("Binary file (preview disabled)")
+		}
+	}
+	return strings.TrimSuffix(tmpl.Source, "\n"), strings.TrimSuffix(tmpl.Rendered, "\n"), nil
+}
+
 func (m Model) panelReadTargetFile(path string) (string, error) {
 	content, err := m.service.CatTarget(path)
 	if err != nil {
@@ -77,6 +77,11 @@ func (m Model) renderPanelTitleBar(width int) string {
 	}
 
 	name := filepath.Base(m.panel.currentPath)
+	if m.panel.currentSection == changesSectionIncomingFiles {
+		if f := m.incomingFileBySourcePath(m.panel.currentPath); f != nil && f.TargetPath != "" {
+			name = filepath.Base(f.TargetPath)
+		}
+	}
 
 	badgeText := " [file]"
 	switch m.panel.contentMode {
@@ -88,6 +93,8 @@ func (m Model) renderPanelTitleBar(width int) string {
 		}
 	case panelModeContent:
 		badgeText = panelContentModeBadge(m.panel.currentPath)
+	case panelModeTemplate:
+		badgeText = " [template]"
 	}
 	badge := activeTheme.DimText.Render(badgeText)
 
@@ -95,6 +102,7 @@ func (m Model) renderPanelTitleBar(width int) string {
 	title := activeTheme.BoldPrimary.Render(visualTruncate(name, titleWidth)) + badge
 
 	// Add diff summary, direction hint, and side qualifier if in diff mode
+	var detailParts []string
 	if m.panel.contentMode == panelModeDiff {
 		if entry, ok := m.panel.cacheGet(m.panel.currentPath, panelModeDiff, m.panel.currentSection); ok && entry.err == nil {
 			summaryLines := entry.lines
@@ -105,17 +113,29 @@ func (m Model) renderPanelTitleBar(width int) string {
 			summaryStr := activeTheme.DimText.Render("  " + summary)
 			title += summaryStr
 		}
-		var detailParts []string
-		if hint := diffDirectionHint(m.panel.currentSection); hint != "" {
+		hint, side := m.panelDiffDirection()
+		if hint != "" {
 			detailParts = append(detailParts, hint)
 		}
-		if side := m.panelDriftSideLabel(); side != "" {
+		if side != "" {
 			detailParts = append(detailParts, side)
 		}
-		if len(detailParts) > 0 {
-			title += "\n" + activeTheme.DimText.Render("  "+strings.Join(detailParts, " · "))
+	}
+	if panelModeDecrypts(m.panel.contentMode, m.panel.currentSection) && m.status.encryptedPaths[m.panel.currentPath] {
+		if m.panel.revealedPath == m.panel.currentPath {
+			detailParts = append(detailParts, "decrypted · hides after "+panelRevealTimeout.String())
+		} else {
+			detailParts = append(detailParts, "encrypted")
 		}
 	}
+	// Templates also list the data keys and secret managers they read.
+	if deps, ok := m.status.dataIndex.DepsOf(m.panel.currentPath); ok {
+		detailParts = append(detailParts, templateDepsSummary(deps))
+	}
+	if len(detailParts) > 0 {
+		detail := visualTruncate(strings.Join(detailParts, " · "), max(width-2, 1))
+		title += "\n" + activeTheme.DimText.Render("  "+detail)
+	}
 
 	return title
 }
@@ -173,6 +193,12 @@ func (m Model) renderPanelDiff(lines []string, width int, pagerApplied bool) str
 }
 
 func (m Model) emptyPanelDiffMessage() string {
+	if m.view == WhatIfScreen {
+		if r, ok := m.whatIf.selectedWhatIf(); ok && r.Skipped {
+			return "Encrypted template; not rendered"
+		}
+		return "Renders the same with this overlay"
+	}
 	switch m.panel.currentSection {
 	case changesSectionUnstaged:
 		if m.panel.currentPath != "" {
@@ -185,6 +211,8 @@ func (m Model) emptyPanelDiffMessage() string {
 		return "No staged changes"
 	case changesSectionUnpushed, changesSectionIncoming:
 		return "No commit diff available"
+	case changesSectionIncomingFiles:
+		return "No incoming changes for this file"
 	default:
 		return "No changes (file matches source state)"
 	}
@@ -208,6 +236,15 @@ func (m Model) lookupPanelGitFile(section changesSection, path string) (chezmoi.
 	return chezmoi.GitFile{}, false
 }
 
+// panelDiffDirection returns the direction hint and drift side label for
+// the panel's diff.
+func (m Model) panelDiffDirection() (hint, side string) {
+	if m.view == WhatIfScreen {
+		return whatIfDirectionHint, ""
+	}
+	return diffDirectionHint(m.panel.currentSection), m.panelDriftSideLabel()
+}
+
 // panelDriftSideLabel returns the SideLabel for the current panel file
 // when it is a drift entry, or "" otherwise.
 func (m Model) panelDriftSideLabel() string {
@@ -216,6 +253,12 @@ func (m Model) panelDriftSideLabel() string {
 
 // driftSideLabel returns the SideLabel for a drift file, or "" if not applicable.
 func (m Model) driftSideLabel(section changesSection, path string) string {
+	if section == changesSectionIncomingFiles && path != "" {
+		if f := m.incomingFileBySourcePath(path); f != nil && m.incomingFileHasDrift(*f) {
+			return "update overwrites local drift"
+		}
+		return ""
+	}
 	if section != changesSectionDrift || path == "" {
 		return ""
 	}
@@ -262,3 +305,82 @@ func renderPanelFileContent(lines []string, width int, filename string) string {
 	}
 	return b.String()
 }
+
+// renderPanelTemplate renders a template's source and its rendered output in
+// two columns. Template actions in the source and rendered lines that do not
+// appear literally in the source are highlighted, so output that comes from
+// data stands out from copied text.
+func renderPanelTemplate(source, rendered []string, width int) string {
+	colWidth := max((width-5)/2, 8) // padding + " │ " separator
+	separator := activeTheme.DimText.Render(" │ ")
+
+	literal := make(map[string]bool, len(source))
+	inAction := false
+	for _, line := range source {
+		if !inAction && !strings.Contains(line, "{{") {
+			literal[line] = true
+		}
+		_, inAction = highlightTemplateActions(line, inAction)
+	}
+
+	var b strings.Builder
+	b.WriteString("  ")
+	b.WriteString(visualPad(activeTheme.DimText.Render("template"), colWidth))
+	b.WriteString(separator)
+	b.WriteString(activeTheme.DimText.Render("rendered"))
+
+	inAction = false
+	for i := range max(len(source), len(rendered)) {
+		var left, right string
+		if i < len(source) {
+			line := expandPanelTabs(source[i])
+			left, _ = highlightTemplateActions(visualTruncate(line, colWidth), inAction)
+			_, inAction = highlightTemplateActions(line, inAction)
+		}
+		if i < len(rendered) {
+			right = visualTruncate(expandPanelTabs(rendered[i]), colWidth)
+			if !literal[rendered[i]] && strings.TrimSpace(rendered[i]) != "" {
+				right = activeTheme.AccentFg.Render(right)
+			}
+		}
+		b.WriteString("\n  ")
+		b.WriteString(visualPad(left, colWidth))
+		b.WriteString(separator)
+		b.WriteString(right)
+	}
+	return b.String()
+}
+
+// highlightTemplateActions styles the {{ ... }} actions in line. inAction
+// reports whether line starts inside an action opened on an earlier line;
+// the returned bool is the same for the line after.
+func highlightTemplateActions(line string, inAction bool) (string, bool) {
+	var b strings.Builder
+	for line != "" {
+		if inAction {
+			end := strings.Index(line, "}}")
+			if end < 0 {
+				b.WriteString(activeTheme.AccentFg.Render(line))
+				return b.String(), true
+			}
+			b.WriteString(activeTheme.AccentFg.Render(line[:end+2]))
+			line = line[end+2:]
+			inAction = false
+			continue
+		}
+		start := strings.Index(line, "{{")
+		if start < 0 {
+			b.WriteString(line)
+			break
+		}
+		b.WriteString(line[:start])
+		line = line[start:]
+		inAction = true
+	}
+	return b.String(), inAction
+}
+
+// expandPanelTabs replaces tabs so the template columns stay aligned.
+func expandPanelTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", "    ")
+}
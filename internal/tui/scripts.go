@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// scriptsState holds the Scripts view: every run_ script in the order
+// apply runs it, with a rendered preview of the selected one.
+type scriptsState struct {
+	scripts    []chezmoi.Script
+	loaded     bool // scripts have come back
+	cursor     int
+	previewTop int // first preview line shown
+}
+
+func (m Model) openScripts() (tea.Model, tea.Cmd) {
+	m.actions.show = false
+	m.view = ScriptsScreen
+	m.scripts = scriptsState{}
+	return m.reloadScripts()
+}
+
+// reloadScripts lists and renders the scripts in the background.
+func (m Model) reloadScripts() (tea.Model, tea.Cmd) {
+	m.ui.busyAction = true
+	m.ui.message = "loading scripts..."
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+		scripts, err := m.service.Scripts()
+		return scriptsLoadedMsg{scripts: scripts, err: err}
+	})
+}
+
+func (m Model) handleScriptsLoaded(msg scriptsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if m.view != ScriptsScreen {
+		return m, nil
+	}
+	if msg.err != nil {
+		if !m.scripts.loaded {
+			updated, cmd := m.closeScripts()
+			m = updated.(Model)
+			m.ui.message = "Error: " + msg.err.Error()
+			return m, cmd
+		}
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	// Keep the cursor on the same script across reloads.
+	selected, hadSelection := m.scripts.selectedScript()
+	m.ui.message = ""
+	m.scripts.scripts = msg.scripts
+	m.scripts.loaded = true
+	m.scripts.cursor = min(m.scripts.cursor, max(0, len(msg.scripts)-1))
+	if hadSelection {
+		for i, sc := range msg.scripts {
+			if sc.Target == selected.Target {
+				m.scripts.cursor = i
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) closeScripts() (tea.Model, tea.Cmd) {
+	m.view = StatusScreen
+	m.scripts = scriptsState{}
+	m.ui.message = ""
+	return m, nil
+}
+
+// selectedScript returns the script under the cursor.
+func (s scriptsState) selectedScript() (chezmoi.Script, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.scripts) {
+		return chezmoi.Script{}, false
+	}
+	return s.scripts[s.cursor], true
+}
+
+func (m Model) handleScriptsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.ui.busyAction {
+		return m, nil
+	}
+	scripts := m.scripts.scripts
+	cursor := m.scripts.cursor
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		return m.closeScripts()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.scripts.cursor = moveCursorUp(m.scripts.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.scripts.cursor = moveCursorDown(m.scripts.cursor, len(scripts), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.scripts.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.scripts.cursor = max(0, len(scripts)-1)
+	case key.Matches(msg, ChezScriptsKeys.ScrollDown):
+		m.scripts.previewTop = min(m.scripts.previewTop+m.scriptsListHeight()/2, max(0, m.scriptPreviewLineCount()-1))
+	case key.Matches(msg, ChezScriptsKeys.ScrollUp):
+		m.scripts.previewTop = max(0, m.scripts.previewTop-m.scriptsListHeight()/2)
+	case key.Matches(msg, ChezScriptsKeys.Reload):
+		return m.reloadScripts()
+	case key.Matches(msg, ChezScriptsKeys.Edit):
+		sc, ok := m.scripts.selectedScript()
+		if !ok {
+			return m, nil
+		}
+		if m.service.IsReadOnly() {
+			m.ui.message = actionUnavailableMessage("read-only mode")
+			return m, nil
+		}
+		return m, tea.ExecProcess(m.editorAtLineCmd(sc.Source, 0), func(err error) tea.Msg {
+			return chezmoiExecDoneMsg{action: chezmoiActionEditScript, err: err}
+		})
+	case key.Matches(msg, ChezScriptsKeys.Reset):
+		return m.resetScriptState()
+	}
+	if m.scripts.cursor != cursor {
+		m.scripts.previewTop = 0
+	}
+	return m, nil
+}
+
+// resetScriptState deletes the selected script's run state so the next
+// apply runs it again. Only run_once and run_onchange scripts have any.
+func (m Model) resetScriptState() (tea.Model, tea.Cmd) {
+	sc, ok := m.scripts.selectedScript()
+	if !ok {
+		return m, nil
+	}
+	if m.service.IsReadOnly() {
+		m.ui.message = actionUnavailableMessage("read-only mode")
+		return m, nil
+	}
+	if sc.Kind() == "always" {
+		m.ui.message = sc.Name + " runs on every apply; there is no run state to reset"
+		return m, nil
+	}
+	if !sc.Current && sc.LastRun.IsZero() {
+		m.ui.message = sc.Name + " already runs on the next apply"
+		return m, nil
+	}
+	m.ui.busyAction = true
+	m.ui.message = ""
+	return m, func() tea.Msg {
+		return scriptStateResetMsg{name: sc.Name, err: m.service.ResetScriptState(sc)}
+	}
+}
+
+func (m Model) handleScriptStateReset(msg scriptStateResetMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	for i := range m.scripts.scripts {
+		if m.scripts.scripts[i].Name == msg.name {
+			m.scripts.scripts[i].Current = false
+			m.scripts.scripts[i].LastRun = time.Time{}
+		}
+	}
+	m.ui.message = msg.name + " will run on the next apply"
+	m.panel.clearCache()
+	return m, tea.Batch(m.postActionReloadCmds(), sendRefreshMsg())
+}
+
+// scriptsSummary counts the scripts apply would run, e.g.
+// "6 scripts · 4 run on next apply".
+func (m Model) scriptsSummary() string {
+	pending := 0
+	for _, sc := range m.scripts.scripts {
+		if !sc.Current {
+			pending++
+		}
+	}
+	noun := "scripts"
+	if len(m.scripts.scripts) == 1 {
+		noun = "script"
+	}
+	return fmt.Sprintf("%d %s · %d run on next apply", len(m.scripts.scripts), noun, pending)
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+var testScriptsLoaded = scriptsLoadedMsg{scripts: []chezmoi.Script{
+	{
+		Target: "/home/test/setup.sh", Name: "setup.sh", Order: 1,
+		Source:   "/home/test/.local/share/chezmoi/.chezmoiscripts/run_once_before_setup.sh",
+		Attrs:    chezmoi.SourceName{Kind: chezmoi.SourceKindScript, Once: true, Before: true},
+		Rendered: "#!/bin/sh\necho setup\n",
+		LastRun:  time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local),
+		Current:  true,
+	},
+	{
+		Target: "/home/test/brew.sh", Name: "brew.sh", Order: 2,
+		Source:   "/home/test/.local/share/chezmoi/.chezmoiscripts/run_onchange_brew.sh.tmpl",
+		Attrs:    chezmoi.SourceName{Kind: chezmoi.SourceKindScript, OnChange: true, Template: true},
+		Rendered: "#!/bin/sh\nbrew bundle\n",
+	},
+	{
+		Target: "/home/test/cleanup.sh", Name: "cleanup.sh", Order: 3,
+		Source: "/home/test/.local/share/chezmoi/.chezmoiscripts/run_after_cleanup.sh",
+		Attrs:  chezmoi.SourceName{Kind: chezmoi.SourceKindScript, After: true},
+		Err:    errors.New(`map has no entry for key "work"`),
+	},
+}}
+
+func TestScriptsListShowsOrderKindAndRunState(t *testing.T) {
+	m := newTestModel(WithView(ScriptsScreen), WithSize(140, 30), WithLoaded(testScriptsLoaded))
+
+	rendered := ansi.Strip(m.renderScriptsScreen())
+	for _, want := range []string{
+		"Scripts",
+		"3 scripts · 2 run on next apply",
+		"1. setup.sh  once, before files  ran 2026-03-01 10:00",
+		"2. brew.sh  onchange  runs next apply",
+		"3. cleanup.sh  always, after files  error",
+		"~/.local/share/chezmoi/.chezmoiscripts/run_once_before_setup.sh",
+		"last ran 2026-03-01 10:00 · skipped by apply until reset",
+		"echo setup",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendKey(t, m, runeKey("G"))
+	if rendered := ansi.Strip(m.renderScriptsScreen()); !strings.Contains(rendered, `map has no entry for key "work"`) {
+		t.Fatalf("expected the render error in the preview:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected esc to leave, got %v", m.view)
+	}
+}
+
+func TestScriptsResetRunState(t *testing.T) {
+	m := newTestModel(WithView(ScriptsScreen), WithSize(140, 30), WithLoaded(testScriptsLoaded))
+
+	m, cmd := sendKey(t, m, runeKey("x"))
+	if !m.ui.busyAction || cmd == nil {
+		t.Fatal("expected x to reset the run state")
+	}
+	m, _ = sendMsg(t, m, scriptStateResetMsg{name: "setup.sh"})
+	if sc := m.scripts.scripts[0]; sc.Current || !sc.LastRun.IsZero() {
+		t.Fatalf("expected setup.sh pending after the reset, got %+v", sc)
+	}
+	if !strings.Contains(m.ui.message, "setup.sh will run on the next apply") {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+
+	m, _ = sendKey(t, m, runeKey("G"))
+	m, cmd = sendKey(t, m, runeKey("x"))
+	if cmd != nil || !strings.Contains(m.ui.message, "runs on every apply") {
+		t.Fatalf("expected no reset for a run_ script without once or onchange, got %q", m.ui.message)
+	}
+}
+
+func TestScriptsResetIsDisabledInReadOnlyMode(t *testing.T) {
+	m := newTestModel(WithView(ScriptsScreen), WithSize(140, 30), WithLoaded(testScriptsLoaded), WithReadOnly())
+
+	m, cmd := sendKey(t, m, runeKey("x"))
+	if cmd != nil || !strings.Contains(m.ui.message, "read-only") {
+		t.Fatalf("expected the reset blocked, got %q", m.ui.message)
+	}
+}
+
+func TestScriptsErrorBeforeListCloses(t *testing.T) {
+	m := newTestModel(WithSize(120, 30))
+	m.view = ScriptsScreen
+	m, _ = sendMsg(t, m, scriptsLoadedMsg{err: errors.New("chezmoi state dump: boom")})
+	if m.view != StatusScreen || !strings.Contains(m.ui.message, "boom") {
+		t.Fatalf("expected the error on the Commands tab, got view %v message %q", m.view, m.ui.message)
+	}
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+const scriptTimeLayout = "2006-01-02 15:04"
+
+func (m Model) renderScriptsScreen() string {
+	var b strings.Builder
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Scripts")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	switch {
+	case !m.scripts.loaded:
+		b.WriteString(activeTheme.DimText.Render("  Loading scripts..."))
+	case len(m.scripts.scripts) == 0:
+		b.WriteString(activeTheme.DimText.Render("  No run_ scripts in the source state"))
+	default:
+		b.WriteString(activeTheme.DimText.Render("  " + m.scriptsSummary()))
+		b.WriteString("\n\n")
+		width := m.effectiveWidth()
+		listW := max(width/2, 40)
+		list := lipgloss.NewStyle().Width(listW).Render(m.renderScriptsList(listW - 2))
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderScriptPreview(width-listW-2)))
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderScriptsStatusBar())
+}
+
+// scriptTiming describes when apply runs sc, e.g. "once, before files".
+func scriptTiming(sc chezmoi.Script) string {
+	switch sc.Phase() {
+	case "before":
+		return sc.Kind() + ", before files"
+	case "after":
+		return sc.Kind() + ", after files"
+	default:
+		return sc.Kind()
+	}
+}
+
+// scriptRunLabel is the short run state shown in the list and the style
+// to show it in when not selected.
+func scriptRunLabel(sc chezmoi.Script) (string, lipgloss.Style) {
+	switch {
+	case sc.Err != nil:
+		return "error", activeTheme.DangerFg
+	case sc.Kind() == "always":
+		return "every apply", activeTheme.DimText
+	case sc.Current && !sc.LastRun.IsZero():
+		return "ran " + sc.LastRun.Local().Format(scriptTimeLayout), activeTheme.DimText
+	case sc.Current:
+		return "ran", activeTheme.DimText
+	default:
+		return "runs next apply", activeTheme.WarningFg
+	}
+}
+
+func (m Model) renderScriptsList(maxWidth int) string {
+	scripts := m.scripts.scripts
+	var b strings.Builder
+	start, end := visibleRange(len(scripts), m.scripts.cursor, m.scriptsListHeight())
+	for i := start; i < end; i++ {
+		selected := i == m.scripts.cursor
+		sc := scripts[i]
+		timing := "  " + scriptTiming(sc)
+		label, style := scriptRunLabel(sc)
+		label = "  " + label
+		if !selected {
+			timing = activeTheme.DimText.Render(timing)
+			label = style.Render(label)
+		}
+		content := visualTruncate("  "+strconv.Itoa(sc.Order)+". "+sc.Name+timing+label, maxWidth)
+		if selected {
+			content = activeTheme.Selected.Width(maxWidth).Render(content)
+		}
+		b.WriteString(content)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// scriptPreviewHeaderLines is the number of lines above the script text in
+// the preview: source, timing, run state, and a blank line.
+const scriptPreviewHeaderLines = 4
+
+// renderScriptPreview shows where the selected script comes from, its run
+// state, and the script as apply would run it.
+func (m Model) renderScriptPreview(maxWidth int) string {
+	sc, ok := m.scripts.selectedScript()
+	if !ok {
+		return ""
+	}
+	lines := []string{
+		activeTheme.BoldPrimary.Render(visualTruncate(shortenPath(sc.Source, m.targetPath), maxWidth)),
+		activeTheme.DimText.Render(visualTruncate("run "+scriptTiming(sc)+" · "+strconv.Itoa(sc.Order)+" of "+strconv.Itoa(len(m.scripts.scripts)), maxWidth)),
+		visualTruncate(scriptRunDetail(sc), maxWidth),
+		"",
+	}
+	height := max(m.scriptsListHeight()-scriptPreviewHeaderLines, 1)
+	if sc.Err != nil {
+		wrapped := strings.Split(lipgloss.NewStyle().Width(maxWidth).Render(sc.Err.Error()), "\n")
+		for _, line := range wrapped[:min(len(wrapped), height)] {
+			lines = append(lines, activeTheme.DangerFg.Render(strings.TrimRight(line, " ")))
+		}
+		return strings.Join(lines, "\n")
+	}
+	body := scriptPreviewLines(sc)
+	top := min(m.scripts.previewTop, max(0, len(body)-1))
+	for _, line := range body[top:min(len(body), top+height)] {
+		lines = append(lines, visualTruncate(line, maxWidth))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scriptRunDetail spells out what the persistent state says about sc.
+func scriptRunDetail(sc chezmoi.Script) string {
+	last := "never ran"
+	if !sc.LastRun.IsZero() {
+		last = "last ran " + sc.LastRun.Local().Format(scriptTimeLayout)
+	}
+	switch {
+	case sc.Err != nil:
+		return activeTheme.DangerFg.Render("failed to render")
+	case sc.Kind() == "always":
+		return activeTheme.DimText.Render("runs on every apply")
+	case sc.Current && sc.Kind() == "once":
+		return activeTheme.DimText.Render(last + " · skipped by apply until reset")
+	case sc.Current:
+		return activeTheme.DimText.Render(last + " · skipped by apply until its contents change")
+	case sc.Kind() == "onchange" && sc.LastRun.IsZero():
+		return activeTheme.WarningFg.Render("no run recorded for these contents · runs on next apply")
+	default:
+		return activeTheme.WarningFg.Render(last + " · runs on next apply")
+	}
+}
+
+// scriptPreviewLines returns the selected script's text, highlighted.
+func scriptPreviewLines(sc chezmoi.Script) []string {
+	text := strings.TrimRight(expandPanelTabs(sc.Rendered), "\n")
+	return strings.Split(highlightCode(text, sc.Name), "\n")
+}
+
+// scriptPreviewLineCount is the length of the selected script's preview.
+func (m Model) scriptPreviewLineCount() int {
+	sc, ok := m.scripts.selectedScript()
+	if !ok {
+		return 0
+	}
+	return strings.Count(strings.TrimRight(sc.Rendered, "\n"), "\n") + 1
+}
+
+func (m Model) renderScriptsStatusBar() string {
+	status := " Scripts "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | ^d/^u scroll script | x reset run state | e edit | r reload | esc back")
+	return statusBar + "\n" + help
+}
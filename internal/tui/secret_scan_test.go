@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func TestCommitSecretsFoundAsksBeforeCommitting(t *testing.T) {
+	m := newTestModel(WithSize(140, 40))
+	m.ui.busyAction = true
+	m, _ = sendMsg(t, m, commitSecretsFoundMsg{
+		message: "add env",
+		findings: []chezmoi.SecretFinding{
+			{Path: "dot_env", Line: 1, Rule: "github-token", Text: "GITHUB_TOKEN=ghp_****"},
+		},
+	})
+	if m.view != ConfirmScreen || m.overlays.confirmAction != chezmoiActionCommitAllowSecrets || m.ui.busyAction {
+		t.Fatalf("expected the commit confirm, got view %v action %v", m.view, m.overlays.confirmAction)
+	}
+	screen := ansi.Strip(m.renderConfirmScreen())
+	for _, want := range []string{"dot_env:1: github-token: GITHUB_TOKEN=ghp_****", "secret_allowlist", "commit anyway"} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("expected %q in the confirm, got:\n%s", want, screen)
+		}
+	}
+
+	m, cmd := sendKey(t, m, runeKey("y"))
+	if cmd == nil || !m.ui.busyAction || m.view != StatusScreen {
+		t.Fatal("expected confirming to commit")
+	}
+	if m.overlays.confirmDetail != nil || m.overlays.confirmPath != "" {
+		t.Fatal("expected the confirm state cleared")
+	}
+}
+
+func TestAddFileJobReportsSecrets(t *testing.T) {
+	target := t.TempDir()
+	path := filepath.Join(target, ".npmrc")
+	if err := os.WriteFile(path, []byte("//registry.npmjs.org/:_authToken=npm_"+strings.Repeat("a1B2", 9)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(WithTarget(target))
+
+	out := make(chan chezmoi.OutputLine, 8)
+	err := m.addFileJob(path, chezmoi.AddOptions{})(context.Background(), out)
+	close(out)
+	if err == nil || !strings.Contains(err.Error(), "Skip chezit secret scan") {
+		t.Fatalf("expected the secret scan to stop the add, got %v", err)
+	}
+	var lines []string
+	for line := range out {
+		lines = append(lines, line.Text)
+	}
+	if len(lines) != 2 || !strings.Contains(lines[0], ".npmrc:1: npm-token") || !strings.Contains(lines[1], "add encrypted") {
+		t.Fatalf("unexpected job output: %q", lines)
+	}
+
+	if err := m.addFileJob(path, chezmoi.AddOptions{AllowSecrets: true})(context.Background(), make(chan chezmoi.OutputLine, 8)); err != nil {
+		t.Fatalf("expected the add allowed, got %v", err)
+	}
+}
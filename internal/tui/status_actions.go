@@ -37,6 +37,33 @@ func (m Model) driftFileByPath(path string) *chezmoi.FileStatus {
 	return nil
 }
 
+// incomingFileHasDrift reports whether an incoming file's target also has
+// local drift, meaning `chezmoi update` would overwrite local changes.
+func (m Model) incomingFileHasDrift(f chezmoi.IncomingFile) bool {
+	return f.TargetPath != "" && m.driftFileByPath(f.TargetPath) != nil
+}
+
+// incomingDriftCount returns how many incoming files overlap local drift.
+func (m Model) incomingDriftCount() int {
+	count := 0
+	for _, f := range m.status.incomingFiles {
+		if m.incomingFileHasDrift(f) {
+			count++
+		}
+	}
+	return count
+}
+
+// incomingFileBySourcePath returns the incoming file with the given source path.
+func (m Model) incomingFileBySourcePath(path string) *chezmoi.IncomingFile {
+	for i := range m.status.incomingFiles {
+		if m.status.incomingFiles[i].SourcePath == path {
+			return &m.status.incomingFiles[i]
+		}
+	}
+	return nil
+}
+
 func (m *Model) openStatusActionsMenu() {
 	if m.status.selectionActive {
 		m.openStatusSelectionActionsMenu()
@@ -83,6 +110,13 @@ func (m *Model) openStatusActionsMenu() {
 			"", !m.service.IsReadOnly(),
 			"read-only mode",
 		)
+		m.actions.items = appendActionItem(
+			m.actions.items,
+			"Review Apply Plan",
+			chezmoiActionApplyPlan,
+			"", !m.service.IsReadOnly(),
+			"read-only mode",
+		)
 		m.actions.items = appendActionItem(
 			m.actions.items,
 			"Update (pull + apply)",
@@ -305,6 +339,9 @@ func (m Model) executeStatusAction(action chezmoiAction) (tea.Model, tea.Cmd) {
 	case chezmoiActionApplyAll:
 		return m.showConfirmScreen(chezmoiActionApplyAll, "apply all changes to destination"), nil
 
+	case chezmoiActionApplyPlan:
+		return m.openApplyPlan()
+
 	case chezmoiActionUpdate:
 		return m.showConfirmScreen(chezmoiActionUpdate, "update (pull from remote and apply)"), nil
 
@@ -378,6 +415,7 @@ func actionRequiresWrite(action chezmoiAction) bool {
 		chezmoiActionGitUnstage,
 		chezmoiActionApplyFile,
 		chezmoiActionApplyAll,
+		chezmoiActionApplyPlan,
 		chezmoiActionUpdate,
 		chezmoiActionGitDiscard,
 		chezmoiActionGitDiscardSelected,
@@ -415,13 +453,16 @@ func (m Model) executeBulkAction(paths []string, emptyMsg string, cmd tea.Cmd) (
 
 // updateCommandAvailability updates the available flag on commands based on current state.
 // Apply is always available (chezmoi also runs scripts that don't appear in drift).
-// Re-Add All is only available when there's file drift.
+// Re-Add All is only available when there's file drift, and Re-Encrypt All
+// when there are encrypted files.
 func (m *Model) updateCommandAvailability() {
 	hasDrift := len(m.status.filteredFiles) > 0
 	for i := range m.cmds.items {
 		switch m.cmds.items[i].id {
 		case chezmoiCmdReAddAll:
 			m.cmds.items[i].available = hasDrift
+		case chezmoiCmdReencryptAll:
+			m.cmds.items[i].available = len(m.status.encryptedPaths) > 0
 		default:
 			m.cmds.items[i].available = true
 		}
@@ -442,6 +483,19 @@ func (m *Model) buildChangesRows() {
 		}
 	}
 
+	// Incoming files only appear once there is something to pull.
+	if len(m.status.incomingFiles) > 0 {
+		m.status.changesRows = append(m.status.changesRows, changesRow{isHeader: true, section: changesSectionIncomingFiles})
+		if !m.status.sectionCollapsed[changesSectionIncomingFiles] {
+			for i := range m.status.incomingFiles {
+				m.status.changesRows = append(m.status.changesRows, changesRow{
+					section:      changesSectionIncomingFiles,
+					incomingFile: &m.status.incomingFiles[i],
+				})
+			}
+		}
+	}
+
 	// Local work pipeline: drift → unstaged → staged
 	m.status.changesRows = append(m.status.changesRows, changesRow{isHeader: true, section: changesSectionDrift})
 	if !m.status.sectionCollapsed[changesSectionDrift] {
@@ -451,6 +505,7 @@ func (m *Model) buildChangesRows() {
 				driftFile: &m.status.filteredFiles[i],
 			})
 		}
+		m.status.changesRows = append(m.status.changesRows, m.resolvedRows()...)
 	}
 
 	m.status.changesRows = append(m.status.changesRows, changesRow{isHeader: true, section: changesSectionUnstaged})
@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -40,6 +43,24 @@ func (m Model) loadTemplatePathsCmd() tea.Cmd {
 	}
 }
 
+func (m Model) loadEncryptedPathsCmd() tea.Cmd {
+	gen := m.gen
+	return func() tea.Msg {
+		files, err := m.service.ManagedFilesWithFilter(chezmoi.EntryFilter{
+			Include: []chezmoi.EntryType{chezmoi.EntryEncrypted},
+		})
+		if err != nil {
+			// Non-fatal: encrypted files then preview like any other
+			return encryptedPathsLoadedMsg{gen: gen}
+		}
+		paths := make(map[string]bool, len(files))
+		for _, f := range files {
+			paths[f] = true
+		}
+		return encryptedPathsLoadedMsg{paths: paths, gen: gen}
+	}
+}
+
 func (m *Model) annotateTemplateFiles() {
 	if m.status.templatePaths == nil {
 		return
@@ -91,17 +112,36 @@ func (m Model) reAddSelectionCmd(paths []string) tea.Cmd {
 
 func (m Model) applyAllCmd() tea.Cmd {
 	cmd := m.service.ApplyAllCmd()
-	return execCmdOrUnsupported(chezmoiActionApplyAll, cmd, "chezmoi: apply not supported")
+	return m.terminalExecCmd(chezmoiActionApplyAll, "chezmoi apply", cmd, cmd, "chezmoi: apply not supported")
 }
 
 func (m Model) updateCmd() tea.Cmd {
 	cmd := m.service.UpdateCmd()
-	return execCmdOrUnsupported(chezmoiActionUpdate, cmd, "chezmoi: update not supported")
+	run := m.terminalExecCmd(chezmoiActionUpdate, "chezmoi update", cmd, cmd, "chezmoi: update not supported")
+	if cmd == nil {
+		return run
+	}
+	return m.recordUpdateOriginCmd(run)
 }
 
 func (m Model) commitWithMsgCmd(message string) tea.Cmd {
 	return func() tea.Msg {
 		if err := m.service.GitCommit(message); err != nil {
+			var found *chezmoi.SecretsFoundError
+			if errors.As(err, &found) {
+				return commitSecretsFoundMsg{message: message, findings: found.Findings}
+			}
+			return chezmoiActionDoneMsg{action: chezmoiActionCommit, err: err}
+		}
+		return chezmoiActionDoneMsg{action: chezmoiActionCommit, message: "committed: " + message}
+	}
+}
+
+// commitAllowingSecretsCmd commits after the user confirmed the secret
+// scan findings.
+func (m Model) commitAllowingSecretsCmd(message string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.service.GitCommitAllowingSecrets(message); err != nil {
 			return chezmoiActionDoneMsg{action: chezmoiActionCommit, err: err}
 		}
 		return chezmoiActionDoneMsg{action: chezmoiActionCommit, message: "committed: " + message}
@@ -128,32 +168,52 @@ func (m Model) loadGitCommitsCmd() tea.Cmd {
 		if err != nil {
 			return chezmoiGitCommitsLoadedMsg{err: err, gen: gen}
 		}
+		incoming := chezmoi.ParseGitLogOneline(incomingRaw)
+		var files []chezmoi.IncomingFile
+		var filesErr error
+		if len(incoming) > 0 {
+			// The commit lists stand on their own; a failure here only
+			// loses the per-file rows.
+			files, filesErr = m.service.IncomingFiles()
+		}
 		return chezmoiGitCommitsLoadedMsg{
 			unpushed: chezmoi.ParseGitLogOneline(unpushedRaw),
-			incoming: chezmoi.ParseGitLogOneline(incomingRaw),
+			incoming: incoming,
+			files:    files,
+			filesErr: filesErr,
 			gen:      gen,
 		}
 	}
 }
 
-func (m Model) gitFetchCmd() tea.Cmd {
-	gen := m.gen
-	return func() tea.Msg {
-		err := m.service.GitFetch()
-		return chezmoiGitFetchDoneMsg{err: err, gen: gen}
+// gitFetchJob runs git fetch as a background job.
+func (m Model) gitFetchJob(ctx context.Context) (string, error) {
+	return "", m.service.GitFetch(ctx)
+}
+
+// archiveJob creates a backup archive as a background job and reports
+// where it was written.
+func (m Model) archiveJob(ctx context.Context) (string, error) {
+	outputPath, err := m.service.Archive(ctx)
+	if err != nil {
+		return "", err
+	}
+	if info, statErr := os.Stat(outputPath); statErr == nil {
+		return fmt.Sprintf("created %s (%s)", outputPath, humanSize(info.Size())), nil
 	}
+	return "created " + outputPath, nil
 }
 
-// gitPullCmd runs git pull. Returns chezmoiActionDoneMsg (no gen field)
-// because the action handler calls postActionReloadCmds which increments
-// gen and starts fresh data loads.
+// gitPullCmd records the source HEAD, then runs git pull. Returns
+// chezmoiActionDoneMsg (no gen field) because the action handler calls
+// postActionReloadCmds which increments gen and starts fresh data loads.
 func (m Model) gitPullCmd() tea.Cmd {
-	return func() tea.Msg {
+	return m.recordUpdateOriginCmd(func() tea.Msg {
 		if err := m.service.GitPull(); err != nil {
 			return chezmoiActionDoneMsg{action: chezmoiActionPull, err: err}
 		}
 		return chezmoiActionDoneMsg{action: chezmoiActionPull, message: "pulled from remote"}
-	}
+	})
 }
 
 func (m Model) loadGitStatusCmd() tea.Cmd {
@@ -302,17 +362,30 @@ func (m Model) loadGitDiffCmd(path string, staged bool) tea.Cmd {
 	}
 }
 
+func (m Model) loadIncomingDiffCmd(sourcePath string) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := m.service.GitDiffIncoming(sourcePath)
+		if err != nil {
+			return chezmoiDiffLoadedMsg{path: sourcePath, diff: diff, err: err}
+		}
+		rendered, ok := m.renderDiffWithPager(diff)
+		return chezmoiDiffLoadedMsg{path: sourcePath, diff: diff, renderedDiff: rendered, pagerApplied: ok}
+	}
+}
+
 func (m *Model) reloadStatusAndGitCmds() []tea.Cmd {
-	cmds := []tea.Cmd{m.loadStatusCmd(), m.loadTemplatePathsCmd()}
+	cmds := []tea.Cmd{m.loadStatusCmd(), m.loadTemplatePathsCmd(), m.loadEncryptedPathsCmd()}
 	cmds = append(cmds, m.loadGitStatusCmd(), m.loadGitCommitsCmd())
 	return cmds
 }
 
 // --- Editor command factories ---
 
+// editSourceCmd runs chezmoi edit in the terminal pane, where the editor
+// draws on the pane's alternate screen.
 func (m Model) editSourceCmd(path string) tea.Cmd {
 	cmd := m.service.EditCmd(path)
-	return execCmdOrUnsupported(chezmoiActionEditSource, cmd, "chezmoi: edit not supported")
+	return m.terminalExecCmd(chezmoiActionEditSource, "chezmoi edit", cmd, cmd, "chezmoi: edit not supported")
 }
 
 // loadIgnoreFileContentCmd reads the .chezmoiignore file from the source directory.
@@ -354,6 +427,30 @@ func (m Model) editorCmd(filePath string) *exec.Cmd {
 	return exec.Command(parts[0], append(parts[1:], filePath)...)
 }
 
+// editorAtLineCmd is like editorCmd but opens filePath at line, when
+// known, with the editor's own syntax for it.
+func (m Model) editorAtLineCmd(filePath string, line int) *exec.Cmd {
+	cmd := m.editorCmd(filePath)
+	if line > 0 {
+		cmd.Args = append(cmd.Args[:len(cmd.Args)-1], editorLineArgs(cmd.Args[0], filePath, line)...)
+	}
+	return cmd
+}
+
+// editorLineArgs returns the arguments opening filePath at line: "+line"
+// for vi, nano, emacs and most terminal editors, file:line for the rest.
+func editorLineArgs(editor, filePath string, line int) []string {
+	at := filePath + ":" + strconv.Itoa(line)
+	switch filepath.Base(editor) {
+	case "code", "code-insiders", "codium", "cursor":
+		return []string{"--goto", at}
+	case "subl", "zed", "hx", "helix":
+		return []string{at}
+	default:
+		return []string{"+" + strconv.Itoa(line), filePath}
+	}
+}
+
 // editTargetCmd opens a target-path file in the configured editor.
 func (m Model) editTargetCmd(filePath string) tea.Cmd {
 	cmd := m.editorCmd(filePath)
@@ -2,7 +2,6 @@ package tui
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
 	"time"
 
@@ -17,9 +16,11 @@ func (m Model) handleStatusLoaded(msg chezmoiStatusLoadedMsg) (tea.Model, tea.Cm
 		return m, nil
 	}
 	m.ui.loading = false
+	var highlightCmd tea.Cmd
 	if msg.err != nil {
 		m.ui.message = "Error: " + msg.err.Error()
 	} else {
+		highlightCmd = m.startApplyHighlight()
 		m.status.files = msg.files
 		m.status.filteredFiles = msg.files
 		m.applyChezmoiFilter()
@@ -28,14 +29,14 @@ func (m Model) handleStatusLoaded(msg chezmoiStatusLoadedMsg) (tea.Model, tea.Cm
 		m.updateCommandAvailability()
 	}
 	if m.allLandingStatsLoaded() && !m.landing.statsReady {
-		return m, tea.Batch(nil, debounceLandingReadyCmd())
+		return m, tea.Batch(highlightCmd, debounceLandingReadyCmd())
 	}
 	if m.panel.shouldShow(m.width) && m.activeTabName() == "Status" {
 		var cmd tea.Cmd
 		m, cmd = m.panelLoadForChanges()
-		return m, cmd
+		return m, tea.Batch(highlightCmd, cmd)
 	}
-	return m, nil
+	return m, highlightCmd
 }
 
 func (m Model) handleGitStatusLoaded(msg chezmoiGitStatusLoadedMsg) (tea.Model, tea.Cmd) {
@@ -89,21 +90,14 @@ func (m Model) handleGitCommitsLoaded(msg chezmoiGitCommitsLoadedMsg) (tea.Model
 	}
 	m.status.unpushedCommits = msg.unpushed
 	m.status.incomingCommits = msg.incoming
+	m.status.incomingFiles = msg.files
+	if msg.filesErr != nil {
+		m.ui.message = "Error: incoming files: " + msg.filesErr.Error()
+	}
 	m.buildChangesRows()
 	return m, nil
 }
 
-func (m Model) handleGitFetchDone(msg chezmoiGitFetchDoneMsg) (tea.Model, tea.Cmd) {
-	m.status.fetchInProgress = false
-	if msg.err != nil {
-		m.ui.message = "Fetch error: " + msg.err.Error()
-		return m, nil
-	}
-	m.status.lastFetchTime = time.Now()
-	m.ui.message = "fetch complete"
-	return m, tea.Batch(m.loadGitCommitsCmd(), m.loadGitStatusCmd())
-}
-
 func (m Model) handleTemplatePathsLoaded(msg templatePathsLoadedMsg) (tea.Model, tea.Cmd) {
 	if msg.gen != m.gen {
 		return m, nil
@@ -111,7 +105,11 @@ func (m Model) handleTemplatePathsLoaded(msg templatePathsLoadedMsg) (tea.Model,
 	m.status.templatePaths = msg.paths
 	m.annotateTemplateFiles()
 	m.buildChangesRows()
-	return m, nil
+	if len(msg.paths) == 0 {
+		m.status.dataIndex.Templates = nil
+		return m, nil
+	}
+	return m, m.loadDataIndexCmd(false)
 }
 
 // --- Status tab key handlers ---
@@ -236,6 +234,13 @@ func (m Model) handleStatusEnter(row changesRow) (tea.Model, tea.Cmd) {
 			m.ui.message = ""
 			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadGitDiffCmd(row.gitFile.Path, true))
 		}
+	case changesSectionIncomingFiles:
+		if row.incomingFile != nil {
+			m.diff.sourceSection = changesSectionIncomingFiles
+			m.ui.busyAction = true
+			m.ui.message = ""
+			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.loadIncomingDiffCmd(row.incomingFile.SourcePath))
+		}
 	case changesSectionUnpushed, changesSectionIncoming:
 		if row.commit != nil {
 			m.diff.sourceSection = row.section
@@ -385,7 +390,7 @@ func (m Model) handleStatusPush() (tea.Model, tea.Cmd) {
 
 func (m Model) handleStatusFetch(row changesRow) (tea.Model, tea.Cmd) {
 	m.clearStatusSelection()
-	if row.section == changesSectionIncoming {
+	if isIncomingSection(row.section) {
 		switch {
 		case m.status.fetchInProgress:
 			m.ui.message = "fetch already in progress"
@@ -394,16 +399,21 @@ func (m Model) handleStatusFetch(row changesRow) (tea.Model, tea.Cmd) {
 			m.ui.message = fmt.Sprintf("fetch cooldown (last fetch %s ago)", elapsed)
 		default:
 			m.status.fetchInProgress = true
-			m.ui.message = "fetching..."
-			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.gitFetchCmd())
+			cmd := m.enqueueJob("git fetch", chezmoiActionFetch, jobScope{paths: []string{jobScopeGit}}, capturedJob(m.gitFetchJob))
+			return m, cmd
 		}
 	}
 	return m, nil
 }
 
+// isIncomingSection reports whether fetch/pull keys apply to the section.
+func isIncomingSection(section changesSection) bool {
+	return section == changesSectionIncoming || section == changesSectionIncomingFiles
+}
+
 func (m Model) handleStatusPull(row changesRow) (tea.Model, tea.Cmd) {
 	m.clearStatusSelection()
-	if row.section == changesSectionIncoming && !m.service.IsReadOnly() {
+	if isIncomingSection(row.section) && !m.service.IsReadOnly() {
 		m.overlays.confirmAction = chezmoiActionPull
 		m.overlays.confirmLabel = "pull changes from remote"
 		m.view = ConfirmScreen
@@ -482,6 +492,7 @@ func (m Model) handleConfirmKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.overlays.confirmLabel = ""
 		m.overlays.confirmPath = ""
 		m.overlays.confirmPaths = nil
+		m.overlays.confirmDetail = nil
 		m.overlays.applyForce = false
 		m.overlays.applyWrapTTY = false
 		switch action {
@@ -492,12 +503,20 @@ func (m Model) handleConfirmKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 				m.ui.busyAction = true
 				return m, tea.Batch(m.ui.loadingSpinner.Tick, m.forgetFileCmd(savedPath))
 			}
+		case chezmoiActionCommitAllowSecrets:
+			m.ui.busyAction = true
+			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.commitAllowingSecretsCmd(savedPath))
 		case chezmoiActionPush:
 			m.ui.busyAction = true
 			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.pushCmd())
 		case chezmoiActionPull:
 			m.ui.busyAction = true
 			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.gitPullCmd())
+		case chezmoiActionUndoUpdate:
+			if savedPath != "" {
+				m.ui.busyAction = true
+				return m, tea.Batch(m.ui.loadingSpinner.Tick, m.undoUpdateCmd(savedPath))
+			}
 		case chezmoiActionGitStageAll:
 			m.ui.busyAction = true
 			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.gitAddAllCmd())
@@ -505,32 +524,20 @@ func (m Model) handleConfirmKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			m.ui.busyAction = true
 			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.gitResetAllCmd())
 		case chezmoiActionRefresh:
-			cmd := m.service.ApplyRefreshCmd()
-			wrapped := wrapWithPressEnter(cmd)
-			return m, execCmdOrUnsupported(chezmoiActionRefresh, wrapped, "chezmoi: refresh not supported")
+			scope := writerJobScope(m.targetPath)
+			return m, m.enqueueCommandJob("refresh externals", chezmoiActionRefresh, scope, m.service.StreamRefreshExternals)
 		case chezmoiActionInit:
 			cmd := m.service.InitCmd()
-			wrapped := wrapWithPressEnter(cmd)
-			return m, execCmdOrUnsupported(chezmoiActionInit, wrapped, "chezmoi: init not supported")
+			return m, m.terminalExecCmd(chezmoiActionInit, "chezmoi init", cmd, wrapWithPressEnter(cmd), "chezmoi: init not supported")
 		case chezmoiActionReAdd:
-			m.ui.busyAction = true
-			return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
-				output, err := m.service.ReAddAll()
-				return chezmoiCapturedOutputMsg{action: chezmoiActionReAdd, label: "chezmoi re-add", output: output, err: err}
-			})
+			scope := writerJobScope(m.targetPath)
+			return m, m.enqueueCommandJob("re-add all", chezmoiActionReAdd, scope, m.service.StreamReAddAll)
+		case chezmoiActionReencryptAll:
+			scope := writerJobScope(m.targetPath)
+			return m, m.enqueueCommandJob("re-encrypt all", chezmoiActionReencryptAll, scope, m.service.StreamReencryptAll)
 		case chezmoiActionArchive:
-			m.ui.busyAction = true
-			return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
-				outputPath, err := m.service.Archive()
-				if err != nil {
-					return chezmoiArchiveDoneMsg{path: outputPath, size: -1, err: err}
-				}
-				size := int64(-1)
-				if info, statErr := os.Stat(outputPath); statErr == nil {
-					size = info.Size()
-				}
-				return chezmoiArchiveDoneMsg{path: outputPath, size: size}
-			})
+			scope := jobScope{paths: []string{m.targetPath}, readOnly: true}
+			return m, m.enqueueCommandJob("archive", chezmoiActionArchive, scope, capturedJob(m.archiveJob))
 		case chezmoiActionGitDiscard:
 			if savedPath != "" {
 				m.ui.busyAction = true
@@ -544,6 +551,9 @@ func (m Model) handleConfirmKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		case chezmoiActionGitUndoCommit:
 			m.ui.busyAction = true
 			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.gitSoftResetCmd())
+		case chezmoiActionDeleteStateKey, chezmoiActionDeleteStateBucket:
+			m.ui.busyAction = true
+			return m, tea.Batch(m.ui.loadingSpinner.Tick, m.deleteStateCmd())
 		}
 		return m, nil
 	case key.Matches(msg, ChezConfirmKeys.Cancel):
@@ -552,6 +562,7 @@ func (m Model) handleConfirmKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.overlays.confirmLabel = ""
 		m.overlays.confirmPath = ""
 		m.overlays.confirmPaths = nil
+		m.overlays.confirmDetail = nil
 		m.overlays.applyForce = false
 		m.overlays.applyWrapTTY = false
 		return m, nil
@@ -580,6 +591,7 @@ func (m Model) handleApplyConfirmKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd)
 		m.overlays.confirmLabel = ""
 		m.overlays.confirmPath = ""
 		m.overlays.confirmPaths = nil
+		m.overlays.confirmDetail = nil
 		m.overlays.applyForce = false
 		m.overlays.applyWrapTTY = false
 		return m, nil
@@ -600,18 +612,35 @@ func (m Model) executeApplyConfirm() (tea.Model, tea.Cmd) {
 	m.overlays.confirmLabel = ""
 	m.overlays.confirmPath = ""
 	m.overlays.confirmPaths = nil
+	m.overlays.confirmDetail = nil
 	m.overlays.applyForce = false
 	m.overlays.applyWrapTTY = false
 
 	cmd := m.applyConfirmExecCmd(action, savedPath, force)
-	cmd = wrapApplyConfirmCmd(cmd, wrapTTY)
+	title := "chezmoi apply"
+	if force {
+		title = "chezmoi apply --force"
+	}
+	fallback := wrapApplyConfirmCmd(cmd, wrapTTY)
+	var run tea.Cmd
+	var scope []string
 	switch action {
 	case chezmoiActionApplyAll:
-		return m, execCmdOrUnsupported(chezmoiActionApplyAll, cmd, "chezmoi: apply not supported")
+		run = m.terminalExecCmd(chezmoiActionApplyAll, title, cmd, fallback, "chezmoi: apply not supported")
 	case chezmoiActionApplyFile, chezmoiActionApplyManaged:
-		return m, execCmdOrUnsupported(chezmoiActionApplyFile, cmd, "chezmoi: apply not supported")
+		run = m.terminalExecCmd(chezmoiActionApplyFile, title, cmd, fallback, "chezmoi: apply not supported")
+		scope = []string{savedPath}
+		if action == chezmoiActionApplyFile {
+			scope = []string{m.currentFilePath()}
+		}
+	default:
+		return m, nil
 	}
-	return m, nil
+	// Force apply overwrites local edits without asking, so save them first.
+	if force && cmd != nil {
+		return m, m.backupTargetsCmd(title, scope, run)
+	}
+	return m, run
 }
 
 func (m Model) applyConfirmExecCmd(action chezmoiAction, savedPath string, force bool) *exec.Cmd {
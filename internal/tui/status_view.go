@@ -60,6 +60,14 @@ func (m Model) renderChangesTabContentWidth(maxWidth int) string {
 				line = markStatusRangeRow(line)
 			}
 			b.WriteString(line)
+		case row.resolved != nil:
+			b.WriteString(m.renderResolvedRow(*row.resolved, isSelected, rowMaxWidth))
+		case row.incomingFile != nil:
+			line := m.renderIncomingFileRow(*row.incomingFile, isSelected, rowMaxWidth)
+			if isRangeSelected {
+				line = markStatusRangeRow(line)
+			}
+			b.WriteString(line)
 		case row.commit != nil:
 			line := m.renderCommitRow(*row.commit, isSelected, rowMaxWidth)
 			if isRangeSelected {
@@ -119,6 +127,15 @@ func (m Model) renderChangesSectionHeaderWidth(section changesSection, selected
 			label += " " + m.incomingSectionActionHint()
 		}
 		sectionColor = activeTheme.Primary
+	case changesSectionIncomingFiles:
+		label = "Incoming Files"
+		count = len(m.status.incomingFiles)
+		if drift := m.incomingDriftCount(); drift > 0 {
+			label += fmt.Sprintf(" [%d overwrite drift]", drift)
+			sectionColor = activeTheme.Danger
+		} else {
+			sectionColor = activeTheme.Primary
+		}
 	}
 
 	header := fmt.Sprintf("  %s %s (%d)", arrow, label, count)
@@ -288,6 +305,8 @@ func (m Model) renderChangesStatusBar() string {
 			help = m.helpHint("↑/↓ nav | enter diff | u unstage | U all | c commit | P push" + panelHint + " | esc quit")
 		case row.section == changesSectionUnpushed:
 			help = m.helpHint("↑/↓ nav | enter show | x undo commit | P push | r refresh" + panelHint + " | esc quit")
+		case row.section == changesSectionIncomingFiles:
+			help = m.helpHint("↑/↓ nav | enter diff | " + m.incomingRowActionHint() + " | r refresh" + panelHint + " | esc quit")
 		case row.section == changesSectionIncoming:
 			help = m.helpHint("↑/↓ nav | enter show | " + m.incomingRowActionHint() + " | r refresh" + panelHint + " | esc quit")
 		default:
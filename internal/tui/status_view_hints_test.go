@@ -83,3 +83,37 @@ func TestDriftRowHelpHintShowsReAddOnlyWhenAvailable(t *testing.T) {
 		}
 	})
 }
+
+func TestIncomingFilesSectionFlagsDrift(t *testing.T) {
+	m := newTestModel(
+		WithDriftFiles([]chezmoi.FileStatus{
+			{Path: "/home/test/.bashrc", SourceStatus: 'M', DestStatus: 'M'},
+		}),
+	)
+	m.status.loadingGit = false
+	m.status.incomingCommits = []chezmoi.GitCommit{{Hash: "abc123", Message: "incoming commit"}}
+	m.status.incomingFiles = []chezmoi.IncomingFile{
+		{SourcePath: "dot_bashrc", TargetPath: "/home/test/.bashrc", StatusCode: "M"},
+		{SourcePath: "dot_zshrc", TargetPath: "/home/test/.zshrc", StatusCode: "A"},
+	}
+	m.buildChangesRows()
+
+	row := findFirstSectionFileRow(t, m, changesSectionIncomingFiles)
+	if m.status.changesRows[row].incomingFile == nil {
+		t.Fatal("expected incoming file row")
+	}
+	if got := m.incomingDriftCount(); got != 1 {
+		t.Fatalf("expected 1 incoming file with drift, got %d", got)
+	}
+
+	rendered := ansi.Strip(m.renderChangesTabContent())
+	if !strings.Contains(rendered, "Incoming Files [1 overwrite drift] (2)") {
+		t.Fatalf("expected incoming files header with drift count, got:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "~/.bashrc [drift]") {
+		t.Fatalf("expected drift flag on .bashrc row, got:\n%s", rendered)
+	}
+	if strings.Contains(rendered, "~/.zshrc [drift]") {
+		t.Fatalf("did not expect drift flag on .zshrc row, got:\n%s", rendered)
+	}
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// templateLintState holds Lint Templates: every template rendered on its
+// own, with the ones that fail listed by position.
+type templateLintState struct {
+	report chezmoi.TemplateLintReport
+	loaded bool // a report has come back
+	cursor int
+}
+
+func (m Model) openTemplateLint() (tea.Model, tea.Cmd) {
+	m.actions.show = false
+	m.view = TemplateLintScreen
+	m.templateLint = templateLintState{}
+	return m.lintTemplates()
+}
+
+// lintTemplates renders every template in the background.
+func (m Model) lintTemplates() (tea.Model, tea.Cmd) {
+	m.ui.busyAction = true
+	m.ui.message = "rendering templates..."
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+		report, err := m.service.LintTemplates()
+		return templateLintLoadedMsg{report: report, err: err}
+	})
+}
+
+func (m Model) handleTemplateLintLoaded(msg templateLintLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if m.view != TemplateLintScreen {
+		return m, nil
+	}
+	if msg.err != nil {
+		if !m.templateLint.loaded {
+			updated, cmd := m.closeTemplateLint()
+			m = updated.(Model)
+			m.ui.message = "Error: " + msg.err.Error()
+			return m, cmd
+		}
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m.ui.message = ""
+	m.templateLint.report = msg.report
+	m.templateLint.loaded = true
+	m.templateLint.cursor = min(m.templateLint.cursor, max(0, len(msg.report.Issues)-1))
+	return m, nil
+}
+
+func (m Model) closeTemplateLint() (tea.Model, tea.Cmd) {
+	m.view = StatusScreen
+	m.templateLint = templateLintState{}
+	m.ui.message = ""
+	return m, nil
+}
+
+// selectedTemplateLintIssue returns the failure under the cursor.
+func (s templateLintState) selectedTemplateLintIssue() (chezmoi.TemplateLintIssue, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.report.Issues) {
+		return chezmoi.TemplateLintIssue{}, false
+	}
+	return s.report.Issues[s.cursor], true
+}
+
+func (m Model) handleTemplateLintKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.ui.busyAction {
+		return m, nil
+	}
+	issues := m.templateLint.report.Issues
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		return m.closeTemplateLint()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.templateLint.cursor = moveCursorUp(m.templateLint.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.templateLint.cursor = moveCursorDown(m.templateLint.cursor, len(issues), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.templateLint.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.templateLint.cursor = max(0, len(issues)-1)
+	case key.Matches(msg, ChezTemplateLintKeys.Rerun):
+		return m.lintTemplates()
+	case key.Matches(msg, ChezTemplateLintKeys.Edit):
+		issue, ok := m.templateLint.selectedTemplateLintIssue()
+		if !ok {
+			return m, nil
+		}
+		if m.service.IsReadOnly() {
+			m.ui.message = actionUnavailableMessage("read-only mode")
+			return m, nil
+		}
+		cmd := m.editorAtLineCmd(issue.Source, issue.Line)
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return chezmoiExecDoneMsg{action: chezmoiActionEditTemplate, err: err}
+		})
+	}
+	return m, nil
+}
+
+// templateLintSummary counts the failures, e.g. "2 of 14 templates failed".
+func (m Model) templateLintSummary() string {
+	report := m.templateLint.report
+	summary := fmt.Sprintf("%d of %d templates failed to render", len(report.Issues), report.Checked)
+	if len(report.Issues) == 0 {
+		summary = fmt.Sprintf("All %d templates render cleanly", report.Checked)
+	}
+	if n := len(report.Skipped); n > 0 {
+		summary += fmt.Sprintf(" · %d encrypted, skipped", n)
+	}
+	return summary
+}
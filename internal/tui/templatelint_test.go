@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+var testTemplateLintLoaded = templateLintLoadedMsg{report: chezmoi.TemplateLintReport{
+	Checked: 5,
+	Issues: []chezmoi.TemplateLintIssue{
+		{
+			Target: "/home/test/.gitconfig", Source: "/home/test/.local/share/chezmoi/dot_gitconfig.tmpl",
+			Line: 3, Column: 7, Message: `map has no entry for key "email"`,
+		},
+		{
+			Target: "/home/test/.zshrc", Source: "/home/test/.local/share/chezmoi/.chezmoitemplates/header",
+			Line: 1, Message: `function "nope" not defined`,
+		},
+	},
+}}
+
+func TestTemplateLintListsFailuresWithPosition(t *testing.T) {
+	m := newTestModel(WithView(TemplateLintScreen), WithSize(120, 30), WithLoaded(testTemplateLintLoaded))
+
+	rendered := ansi.Strip(m.renderTemplateLintScreen())
+	for _, want := range []string{
+		"Lint Templates",
+		"2 of 5 templates failed to render",
+		"dot_gitconfig.tmpl:3  ~/.gitconfig",
+		"header:1  ~/.zshrc",
+		"~/.local/share/chezmoi/dot_gitconfig.tmpl:3:7",
+		`map has no entry for key "email"`,
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	if rendered := ansi.Strip(m.renderTemplateLintScreen()); !strings.Contains(rendered, `function "nope" not defined`) {
+		t.Fatalf("expected the second failure's message:\n%s", rendered)
+	}
+
+	m, cmd := sendKey(t, m, runeKey("r"))
+	if !m.ui.busyAction || cmd == nil {
+		t.Fatal("expected r to render the templates again")
+	}
+	m, _ = sendMsg(t, m, templateLintLoadedMsg{report: chezmoi.TemplateLintReport{Checked: 5, Skipped: []string{"/home/test/.netrc"}}})
+	if rendered := ansi.Strip(m.renderTemplateLintScreen()); !strings.Contains(rendered, "All 5 templates render cleanly · 1 encrypted, skipped") {
+		t.Fatalf("expected the clean summary:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected esc to leave, got %v", m.view)
+	}
+}
+
+func TestTemplateLintErrorBeforeReportCloses(t *testing.T) {
+	m := newTestModel(WithSize(120, 30))
+	m.view = TemplateLintScreen
+	m, _ = sendMsg(t, m, templateLintLoadedMsg{err: errors.New("chezmoi managed: boom")})
+	if m.view != StatusScreen || !strings.Contains(m.ui.message, "boom") {
+		t.Fatalf("expected the error on the Commands tab, got view %v message %q", m.view, m.ui.message)
+	}
+}
+
+func TestTemplateLintEditIsDisabledInReadOnlyMode(t *testing.T) {
+	m := newTestModel(WithView(TemplateLintScreen), WithSize(120, 30), WithLoaded(testTemplateLintLoaded), WithReadOnly())
+
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEnter))
+	if cmd != nil || !strings.Contains(m.ui.message, "read-only") {
+		t.Fatalf("expected editing blocked, got %q", m.ui.message)
+	}
+}
+
+func TestTemplateLintRerunsAfterEditorCloses(t *testing.T) {
+	m := newTestModel(WithView(TemplateLintScreen), WithSize(120, 30), WithLoaded(testTemplateLintLoaded))
+
+	m, cmd := sendMsg(t, m, chezmoiExecDoneMsg{action: chezmoiActionEditTemplate})
+	if m.view != TemplateLintScreen || !m.ui.busyAction || cmd == nil {
+		t.Fatalf("expected the templates rendered again, got view %v busy %v", m.view, m.ui.busyAction)
+	}
+}
+
+func TestEditorLineArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{editor: "nvim", want: []string{"+12", "/src/dot_zshrc.tmpl"}},
+		{editor: "/usr/bin/vi", want: []string{"+12", "/src/dot_zshrc.tmpl"}},
+		{editor: "code", want: []string{"--goto", "/src/dot_zshrc.tmpl:12"}},
+		{editor: "hx", want: []string{"/src/dot_zshrc.tmpl:12"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.editor, func(t *testing.T) {
+			if got := editorLineArgs(tt.editor, "/src/dot_zshrc.tmpl", 12); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditorAtLineCmdKeepsEditorFlags(t *testing.T) {
+	m := newTestModel()
+	m.opts.Editor = "code --wait"
+	cmd := m.editorAtLineCmd("/src/dot_zshrc.tmpl", 4)
+	want := []string{"code", "--wait", "--goto", "/src/dot_zshrc.tmpl:4"}
+	if !slices.Equal(cmd.Args, want) {
+		t.Fatalf("got %v, want %v", cmd.Args, want)
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func (m Model) renderTemplateLintScreen() string {
+	var b strings.Builder
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Lint Templates")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	switch {
+	case !m.templateLint.loaded:
+		b.WriteString(activeTheme.DimText.Render("  Rendering templates..."))
+	case len(m.templateLint.report.Issues) == 0:
+		b.WriteString(activeTheme.SuccessFg.Render("  " + m.templateLintSummary()))
+	default:
+		b.WriteString(activeTheme.DimText.Render("  " + m.templateLintSummary()))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderTemplateLintList(m.effectiveWidth() - 2))
+		if issue, ok := m.templateLint.selectedTemplateLintIssue(); ok {
+			b.WriteString("\n\n")
+			b.WriteString(m.renderTemplateLintDetail(issue, m.effectiveWidth()-2))
+		}
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderTemplateLintStatusBar())
+}
+
+// templateLintPosition is the short form of an issue position shown in the
+// list, e.g. "dot_gitconfig.tmpl:3".
+func templateLintPosition(issue chezmoi.TemplateLintIssue) string {
+	pos := filepath.Base(issue.Source)
+	if issue.Line > 0 {
+		pos += ":" + strconv.Itoa(issue.Line)
+	}
+	return pos
+}
+
+func (m Model) renderTemplateLintList(maxWidth int) string {
+	issues := m.templateLint.report.Issues
+	var b strings.Builder
+	start, end := visibleRange(len(issues), m.templateLint.cursor, m.templateLintListHeight())
+	for i := start; i < end; i++ {
+		selected := i == m.templateLint.cursor
+		issue := issues[i]
+		pos := templateLintPosition(issue)
+		if !selected {
+			pos = activeTheme.DangerFg.Render(pos)
+		}
+		content := "  " + pos + "  " + shortenPath(issue.Target, m.targetPath)
+		content = visualTruncate(content, maxWidth)
+		if selected {
+			content = activeTheme.Selected.Width(maxWidth).Render(content)
+		}
+		b.WriteString(content)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// templateLintMessageLines caps the wrapped error message under the list.
+const templateLintMessageLines = 3
+
+// renderTemplateLintDetail shows the selected failure's full position and
+// its message wrapped below the list.
+func (m Model) renderTemplateLintDetail(issue chezmoi.TemplateLintIssue, maxWidth int) string {
+	lines := []string{activeTheme.DimText.Render("  " + visualTruncate(shortenPath(issue.Location(), m.targetPath), maxWidth-2))}
+	wrapped := strings.Split(lipgloss.NewStyle().Width(maxWidth-2).Render(issue.Message), "\n")
+	if len(wrapped) > templateLintMessageLines {
+		wrapped = wrapped[:templateLintMessageLines]
+		wrapped[templateLintMessageLines-1] = visualTruncate(wrapped[templateLintMessageLines-1]+"…", maxWidth-2)
+	}
+	for _, line := range wrapped {
+		lines = append(lines, "  "+strings.TrimRight(line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderTemplateLintStatusBar() string {
+	status := " Lint Templates "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | enter/e edit at line | r re-run | esc back")
+	return statusBar + "\n" + help
+}
@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/pty"
+)
+
+// terminalSession is one command running (or finished) in the embedded
+// terminal pane. It is shared by pointer so stale reads from an earlier
+// session can be told apart from the current one.
+type terminalSession struct {
+	title  string
+	action chezmoiAction
+	cmd    *exec.Cmd
+	ptm    *os.File
+
+	// scripts are the status scripts when an apply started; the status
+	// may reload before the pane closes and the output is parsed.
+	scripts []string
+}
+
+// terminalState holds the terminal pane. The last session and its
+// scrollback stay around after exit so they can be reopened.
+type terminalState struct {
+	session *terminalSession
+	buf     terminalBuffer
+	running bool
+	exitErr error
+	offset  int // lines scrolled up from the bottom; 0 follows output
+
+	// unreported is the action whose result has not been surfaced yet;
+	// it is cleared when the pane is first closed after exit.
+	unreported chezmoiAction
+}
+
+// terminalExecCmd runs cmd in the terminal pane so interactive prompts
+// (overwrite confirmations, sudo, init questions) and editors stay inside
+// the layout. Platforms without pseudo-terminals suspend the TUI and run
+// fallback instead, which is usually cmd itself or cmd wrapped by
+// wrapWithPressEnter.
+func (m Model) terminalExecCmd(action chezmoiAction, title string, cmd, fallback *exec.Cmd, unsupported string) tea.Cmd {
+	if cmd == nil || !pty.Supported {
+		return execCmdOrUnsupported(action, fallback, unsupported)
+	}
+	cols, rows := m.terminalPaneSize()
+	var scripts []string
+	if isApplyAction(action) {
+		scripts = m.statusScripts()
+	}
+	return func() tea.Msg {
+		// xterm's alternate screen and cursor addressing are what the pane
+		// emulates; its colors are dropped.
+		cmd.Env = append(envOrEnviron(cmd.Env), "TERM=xterm")
+		ptm, err := pty.Start(cmd, cols, rows)
+		if err != nil {
+			return chezmoiExecDoneMsg{action: action, err: err}
+		}
+		return terminalStartedMsg{session: &terminalSession{title: title, action: action, cmd: cmd, ptm: ptm, scripts: scripts}}
+	}
+}
+
+func envOrEnviron(env []string) []string {
+	if env != nil {
+		return env
+	}
+	return os.Environ()
+}
+
+// readTerminalCmd waits for the next chunk of output. Once the command
+// exits and the terminal drains, it reaps the process instead.
+func readTerminalCmd(s *terminalSession) tea.Cmd {
+	return func() tea.Msg {
+		buf := make([]byte, 4096)
+		for {
+			n, err := s.ptm.Read(buf)
+			if n > 0 {
+				return terminalOutputMsg{session: s, data: buf[:n]}
+			}
+			if err != nil {
+				break
+			}
+		}
+		waitErr := s.cmd.Wait()
+		_ = s.ptm.Close()
+		return terminalExitedMsg{session: s, err: waitErr}
+	}
+}
+
+func (m Model) handleTerminalStarted(msg terminalStartedMsg) (tea.Model, tea.Cmd) {
+	m.term = terminalState{session: msg.session, running: true, unreported: msg.session.action}
+	m.term.buf.resize(m.terminalPaneSize())
+	m.actions.show = false
+	m.ui.message = ""
+	m.view = TerminalScreen
+	return m, readTerminalCmd(msg.session)
+}
+
+func (m Model) handleTerminalOutput(msg terminalOutputMsg) (tea.Model, tea.Cmd) {
+	if msg.session != m.term.session {
+		return m, nil
+	}
+	before := len(m.term.buf.lines)
+	m.term.buf.write(msg.data)
+	if replies := m.term.buf.takeReplies(); len(replies) > 0 {
+		if _, err := msg.session.ptm.Write(replies); err != nil {
+			m.ui.message = "Error: " + err.Error()
+		}
+	}
+	if m.term.buf.fullScreen() {
+		m.term.offset = 0
+	} else if m.term.offset > 0 {
+		// Keep the scrolled-back view anchored while output arrives.
+		m.term.offset += len(m.term.buf.lines) - before
+	}
+	return m, readTerminalCmd(msg.session)
+}
+
+func (m Model) handleTerminalExited(msg terminalExitedMsg) (tea.Model, tea.Cmd) {
+	if msg.session != m.term.session {
+		return m, nil
+	}
+	m.term.running = false
+	m.term.exitErr = msg.err
+	m.term.buf.appendLine("")
+	m.term.buf.appendLine(terminalExitBanner(msg.err))
+	if msg.err == nil && m.term.unreported == chezmoiActionEditSource && m.view == TerminalScreen {
+		// The editor's screen is gone; nothing is left worth reading.
+		return m.closeTerminal()
+	}
+	return m, nil
+}
+
+func terminalExitBanner(err error) string {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return "[process exited]"
+	case errors.As(err, &exitErr):
+		return fmt.Sprintf("[process exited with status %d]", exitErr.ExitCode())
+	default:
+		return "[process failed: " + err.Error() + "]"
+	}
+}
+
+// openTerminalHistory reopens the last session's scrollback.
+func (m Model) openTerminalHistory() (tea.Model, tea.Cmd) {
+	if m.term.session == nil {
+		m.ui.message = "No terminal output yet"
+		return m, nil
+	}
+	m.term.offset = 0
+	m.view = TerminalScreen
+	return m, nil
+}
+
+// closeTerminal leaves the pane. The first close after a command exits
+// reports the result and reloads state like any other shell-out.
+func (m Model) closeTerminal() (tea.Model, tea.Cmd) {
+	m.view = StatusScreen
+	action := m.term.unreported
+	if action == chezmoiActionNone {
+		return m, nil
+	}
+	m.term.unreported = chezmoiActionNone
+	if action == chezmoiActionApplyAll || action == chezmoiActionApplyFile {
+		m.recordApplyResult(strings.Join(m.term.buf.snapshot(), "\n"), m.term.session.scripts)
+	}
+	return m.handleExecDone(chezmoiExecDoneMsg{action: action, err: m.term.exitErr})
+}
+
+func (m Model) handleTerminalKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	page := max(1, m.terminalPaneHeight()-1)
+	maxOffset := max(0, len(m.term.buf.snapshot())-m.terminalPaneHeight())
+
+	// A full-screen program pages through its own content.
+	if !m.term.buf.fullScreen() {
+		switch {
+		case key.Matches(msg, ChezTerminalKeys.ScrollUp):
+			m.term.offset = min(maxOffset, m.term.offset+page)
+			return m, nil
+		case key.Matches(msg, ChezTerminalKeys.ScrollDown):
+			m.term.offset = max(0, m.term.offset-page)
+			return m, nil
+		}
+	}
+
+	if m.term.running {
+		if input := terminalKeyInput(msg, m.term.buf.appCursor); input != "" {
+			m.term.offset = 0
+			if _, err := m.term.session.ptm.WriteString(input); err != nil {
+				m.ui.message = "Error: " + err.Error()
+			}
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, ChezTerminalKeys.Close):
+		return m.closeTerminal()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.term.offset = min(maxOffset, m.term.offset+1)
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.term.offset = max(0, m.term.offset-1)
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.term.offset = maxOffset
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.term.offset = 0
+	}
+	return m, nil
+}
+
+// handleTerminalPaste forwards bracketed paste content to a running command.
+func (m Model) handleTerminalPaste(msg tea.PasteMsg) (tea.Model, tea.Cmd) {
+	if !m.term.running {
+		return m, nil
+	}
+	if _, err := m.term.session.ptm.WriteString(msg.Content); err != nil {
+		m.ui.message = "Error: " + err.Error()
+	}
+	return m, nil
+}
+
+// Close kills a command still running in the terminal pane, so it does
+// not outlive chezit. Call it on the final model once the program exits.
+func (m Model) Close() {
+	if m.term.running && m.term.session.cmd != nil {
+		_ = pty.Kill(m.term.session.cmd)
+	}
+}
+
+// resizeTerminal keeps the pseudo-terminal in step with the pane.
+func (m *Model) resizeTerminal() {
+	if !m.term.running {
+		return
+	}
+	cols, rows := m.terminalPaneSize()
+	m.term.buf.resize(cols, rows)
+	_ = pty.Resize(m.term.session.ptm, cols, rows)
+}
+
+// terminalKeyInput encodes a key press as the bytes a terminal would send.
+// appCursor selects the application sequences a full-screen program asks
+// for with the cursor keys mode.
+func terminalKeyInput(msg tea.KeyPressMsg, appCursor bool) string {
+	var seq string
+	switch msg.Code {
+	case tea.KeyEnter:
+		seq = "\r"
+	case tea.KeyBackspace:
+		seq = "\x7f"
+	case tea.KeyTab:
+		seq = "\t"
+	case tea.KeyEscape:
+		seq = "\x1b"
+	case tea.KeyUp:
+		seq = "\x1b[A"
+	case tea.KeyDown:
+		seq = "\x1b[B"
+	case tea.KeyRight:
+		seq = "\x1b[C"
+	case tea.KeyLeft:
+		seq = "\x1b[D"
+	case tea.KeyHome:
+		seq = "\x1b[H"
+	case tea.KeyEnd:
+		seq = "\x1b[F"
+	case tea.KeyDelete:
+		seq = "\x1b[3~"
+	case tea.KeyInsert:
+		seq = "\x1b[2~"
+	case tea.KeyPgUp:
+		seq = "\x1b[5~"
+	case tea.KeyPgDown:
+		seq = "\x1b[6~"
+	default:
+		switch {
+		case msg.Mod&tea.ModCtrl != 0 && msg.Code >= 'a' && msg.Code <= 'z':
+			seq = string(rune(msg.Code - 'a' + 1))
+		case msg.Text != "":
+			seq = msg.Text
+		case msg.Code == tea.KeySpace:
+			seq = " "
+		}
+	}
+	if appCursor && len(seq) == 3 && seq[1] == '[' {
+		// Arrows, Home, and End.
+		seq = "\x1bO" + seq[2:]
+	}
+	if seq != "" && msg.Mod&tea.ModAlt != 0 {
+		seq = "\x1b" + seq
+	}
+	return seq
+}
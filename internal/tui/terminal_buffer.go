@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// terminalScrollbackLimit caps how many finished lines the pane retains.
+const terminalScrollbackLimit = 10000
+
+// terminalBuffer emulates the terminal behind the pane. Ordinary output
+// goes to a line-oriented main screen with scrollback, which handles the
+// controls chezmoi prompts and script output rely on — carriage return,
+// backspace, tabs, line erase, and horizontal cursor moves. Full-screen
+// programs switch to the alternate screen, a grid with cursor addressing
+// and scroll regions. Colors and other attributes are dropped.
+type terminalBuffer struct {
+	lines   []string // finished lines, oldest first
+	cur     []rune   // line being written
+	col     int      // cursor column within cur
+	pending []byte   // incomplete escape or UTF-8 sequence from the last write
+
+	cols, rows   int             // pseudo-terminal size
+	alt          *terminalScreen // set while a program uses the alternate screen
+	appCursor    bool            // cursor keys send application sequences
+	cursorHidden bool
+	replies      []byte // answers to status queries, owed to the program
+}
+
+// resize records the pseudo-terminal size for the alternate screen.
+func (b *terminalBuffer) resize(cols, rows int) {
+	b.cols, b.rows = cols, rows
+	if b.alt != nil {
+		b.alt.resize(cols, rows)
+	}
+}
+
+// takeReplies returns and clears the pending answers to status queries.
+func (b *terminalBuffer) takeReplies() []byte {
+	replies := b.replies
+	b.replies = nil
+	return replies
+}
+
+// write feeds raw terminal output into the buffer.
+func (b *terminalBuffer) write(p []byte) {
+	data := p
+	if len(b.pending) > 0 {
+		data = append(b.pending, p...)
+		b.pending = nil
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == 0x1b:
+			n, ok := b.escape(data[i:])
+			if !ok {
+				b.pending = append([]byte(nil), data[i:]...)
+				return
+			}
+			i += n
+			continue
+		case b.alt != nil && c < 0x20:
+			b.alt.control(c)
+		case c == '\n':
+			b.newline()
+		case c == '\r':
+			b.col = 0
+		case c == '\b':
+			b.col = max(0, b.col-1)
+		case c == '\t':
+			b.col = (b.col/8 + 1) * 8
+			b.padTo(b.col)
+		case c < 0x20 || c == 0x7f:
+			// Bell and other controls have no visible effect.
+		default:
+			if !utf8.FullRune(data[i:]) {
+				b.pending = append([]byte(nil), data[i:]...)
+				return
+			}
+			r, size := utf8.DecodeRune(data[i:])
+			if b.alt != nil {
+				b.alt.put(r)
+			} else {
+				b.put(r)
+			}
+			i += size
+			continue
+		}
+		i++
+	}
+}
+
+// escape consumes one escape sequence at the start of data. It returns
+// false when the sequence is not yet complete.
+func (b *terminalBuffer) escape(data []byte) (int, bool) {
+	if len(data) < 2 {
+		return 0, false
+	}
+	switch data[1] {
+	case '[':
+		for j := 2; j < len(data); j++ {
+			if data[j] >= 0x40 && data[j] <= 0x7e {
+				b.csi(string(data[2:j]), data[j])
+				return j + 1, true
+			}
+		}
+		return 0, false
+	case ']', 'P', '_', '^':
+		// OSC/DCS/APC/PM strings end with BEL or ST (ESC \).
+		for j := 2; j < len(data); j++ {
+			if data[j] == 0x07 {
+				return j + 1, true
+			}
+			if data[j] == 0x1b && j+1 < len(data) && data[j+1] == '\\' {
+				return j + 2, true
+			}
+		}
+		return 0, false
+	case '(', ')', '*', '+', '#', '%', ' ':
+		// Character set and line size selections carry one more byte.
+		if len(data) < 3 {
+			return 0, false
+		}
+		return 3, true
+	case 'c':
+		b.reset()
+		return 2, true
+	default:
+		if b.alt != nil {
+			b.alt.escape(data[1])
+		}
+		return 2, true
+	}
+}
+
+// csi applies a control sequence: status queries and modes in either
+// screen, cursor addressing on the alternate screen, and the subset that
+// affects a single line on the main screen.
+func (b *terminalBuffer) csi(params string, final byte) {
+	var prefix byte
+	if params != "" && strings.IndexByte("<=>?", params[0]) >= 0 {
+		prefix, params = params[0], params[1:]
+	}
+	if strings.ContainsFunc(params, func(r rune) bool { return r < '0' || r > ';' }) {
+		// Intermediate bytes mark sequences chezit does not emulate.
+		return
+	}
+	var args []int
+	if params != "" {
+		for p := range strings.SplitSeq(params, ";") {
+			n, _ := strconv.Atoi(p)
+			args = append(args, n)
+		}
+	}
+
+	switch {
+	case final == 'h' || final == 'l':
+		if prefix == '?' {
+			for _, mode := range args {
+				b.setMode(mode, final == 'h')
+			}
+		}
+		return
+	case final == 'n' && prefix == 0:
+		b.statusReport(terminalArg(args, 0))
+		return
+	case final == 'c' && prefix == 0 && terminalArg(args, 0) == 0:
+		b.replies = append(b.replies, "\x1b[?1;2c"...)
+		return
+	case prefix != 0 || final == 'm':
+		return
+	case b.alt != nil:
+		b.alt.csi(final, args)
+		return
+	}
+
+	n := max(terminalArg(args, 0), 1)
+	switch final {
+	case 'K': // erase in line
+		switch terminalArg(args, 0) {
+		case 0:
+			if b.col < len(b.cur) {
+				b.cur = b.cur[:b.col]
+			}
+		case 1:
+			b.padTo(b.col)
+			for i := 0; i < b.col && i < len(b.cur); i++ {
+				b.cur[i] = ' '
+			}
+		case 2:
+			b.cur = b.cur[:0]
+		}
+	case 'C': // cursor forward
+		b.col += n
+	case 'D': // cursor back
+		b.col = max(0, b.col-n)
+	case 'G': // cursor to column
+		b.col = n - 1
+	}
+}
+
+// setMode applies a DEC private mode.
+func (b *terminalBuffer) setMode(mode int, on bool) {
+	switch mode {
+	case 1:
+		b.appCursor = on
+	case 25:
+		b.cursorHidden = !on
+	case 47, 1047, 1049:
+		switch {
+		case on && b.alt == nil:
+			b.alt = newTerminalScreen(b.screenSize())
+		case !on:
+			b.alt = nil
+		}
+	}
+}
+
+// statusReport answers a device status request; editors ask for the
+// cursor position to probe the terminal.
+func (b *terminalBuffer) statusReport(kind int) {
+	switch kind {
+	case 5:
+		b.replies = append(b.replies, "\x1b[0n"...)
+	case 6:
+		row, col := b.screenRows(), b.col
+		if b.alt != nil {
+			row, col = b.alt.row+1, b.alt.col
+		}
+		b.replies = append(b.replies, fmt.Sprintf("\x1b[%d;%dR", row, col+1)...)
+	}
+}
+
+func (b *terminalBuffer) screenSize() (cols, rows int) {
+	cols = b.cols
+	if cols <= 0 {
+		cols = 80
+	}
+	return cols, b.screenRows()
+}
+
+func (b *terminalBuffer) screenRows() int {
+	if b.rows <= 0 {
+		return 24
+	}
+	return b.rows
+}
+
+// reset returns the terminal to its initial modes, leaving the alternate
+// screen. Scrollback is kept.
+func (b *terminalBuffer) reset() {
+	b.alt = nil
+	b.appCursor = false
+	b.cursorHidden = false
+}
+
+// fullScreen reports whether a program is drawing on the alternate screen.
+func (b terminalBuffer) fullScreen() bool {
+	return b.alt != nil
+}
+
+// cursor returns the alternate screen's cursor position, and false when
+// the main screen is shown or the program hid the cursor.
+func (b terminalBuffer) cursor() (row, col int, ok bool) {
+	if b.alt == nil || b.cursorHidden {
+		return 0, 0, false
+	}
+	return b.alt.row, b.alt.col, true
+}
+
+func (b *terminalBuffer) put(r rune) {
+	b.padTo(b.col)
+	if b.col < len(b.cur) {
+		b.cur[b.col] = r
+	} else {
+		b.cur = append(b.cur, r)
+	}
+	b.col++
+}
+
+func (b *terminalBuffer) padTo(col int) {
+	for len(b.cur) < col {
+		b.cur = append(b.cur, ' ')
+	}
+}
+
+func (b *terminalBuffer) newline() {
+	b.lines = append(b.lines, strings.TrimRight(string(b.cur), " "))
+	if over := len(b.lines) - terminalScrollbackLimit; over > 0 {
+		b.lines = b.lines[over:]
+	}
+	b.cur = b.cur[:0]
+	b.col = 0
+}
+
+// appendLine adds a finished line of chezit's own text, such as an exit
+// banner, after whatever the command printed. A program that exited
+// without leaving the alternate screen is returned to the main one.
+func (b *terminalBuffer) appendLine(s string) {
+	b.reset()
+	if len(b.cur) > 0 {
+		b.newline()
+	}
+	b.lines = append(b.lines, s)
+}
+
+// snapshot returns every line including the partial line holding an
+// unanswered prompt, or the rows of the alternate screen while a program
+// draws on it.
+func (b terminalBuffer) snapshot() []string {
+	if b.alt != nil {
+		return b.alt.lines()
+	}
+	if len(b.cur) == 0 {
+		return b.lines
+	}
+	lines := make([]string, len(b.lines), len(b.lines)+1)
+	copy(lines, b.lines)
+	return append(lines, string(b.cur))
+}
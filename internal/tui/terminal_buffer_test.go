@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerminalBufferWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []string
+	}{
+		{"plain lines", []string{"one\ntwo\n"}, []string{"one", "two"}},
+		{"crlf", []string{"one\r\ntwo"}, []string{"one", "two"}},
+		{"carriage return overwrites", []string{"50%\r100%\n"}, []string{"100%"}},
+		{"erase line after return", []string{"downloading...\r\x1b[Kdone\n"}, []string{"done"}},
+		{"backspace", []string{"abx\bc\n"}, []string{"abc"}},
+		{"tab stops", []string{"a\tb\n"}, []string{"a       b"}},
+		{"colors dropped", []string{"\x1b[1;31merror\x1b[0m: bad\n"}, []string{"error: bad"}},
+		{"osc title dropped", []string{"\x1b]0;title\x07ok\n"}, []string{"ok"}},
+		{"escape split across writes", []string{"a\x1b[3", "1mb\n"}, []string{"ab"}},
+		{"utf-8 split across writes", []string{"\xe2\x86", "\x92 x\n"}, []string{"→ x"}},
+		{"prompt without newline", []string{"overwrite? [y,n] "}, []string{"overwrite? [y,n] "}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b terminalBuffer
+			for _, chunk := range tc.chunks {
+				b.write([]byte(chunk))
+			}
+			if got := b.snapshot(); !slices.Equal(got, tc.want) {
+				t.Fatalf("snapshot = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTerminalBufferScrollbackLimit(t *testing.T) {
+	var b terminalBuffer
+	for range terminalScrollbackLimit + 5 {
+		b.write([]byte("line\n"))
+	}
+	if got := len(b.snapshot()); got != terminalScrollbackLimit {
+		t.Fatalf("expected scrollback capped at %d, got %d", terminalScrollbackLimit, got)
+	}
+}
+
+func TestTerminalBufferAppendLineFinishesPrompt(t *testing.T) {
+	var b terminalBuffer
+	b.write([]byte("continue? "))
+	b.appendLine("[process exited]")
+
+	want := []string{"continue?", "[process exited]"}
+	if got := b.snapshot(); !slices.Equal(got, want) {
+		t.Fatalf("snapshot = %q, want %q", got, want)
+	}
+}
+
+func TestTerminalBufferAlternateScreen(t *testing.T) {
+	var b terminalBuffer
+	b.resize(10, 4)
+	b.write([]byte("$ chezmoi edit\r\n"))
+
+	// Enter the alternate screen, clear it, and draw like an editor.
+	b.write([]byte("\x1b[?1049h\x1b[H\x1b[2J"))
+	if !b.fullScreen() {
+		t.Fatal("expected the alternate screen")
+	}
+	b.write([]byte("line one\r\nline two\x1b[4;1H~\x1b[4;3Hend\x1b[1;6H\x1b[Kuno"))
+	want := []string{"line uno", "line two", "", "~ end"}
+	if got := b.snapshot(); !slices.Equal(got, want) {
+		t.Fatalf("snapshot = %q, want %q", got, want)
+	}
+	if row, col, ok := b.cursor(); !ok || row != 0 || col != 8 {
+		t.Fatalf("cursor = %d,%d,%v, want 0,8", row, col, ok)
+	}
+
+	// Delete the first line of a scroll region covering rows 1-3.
+	b.write([]byte("\x1b[1;3r\x1b[1;1H\x1b[M"))
+	want = []string{"line two", "", "", "~ end"}
+	if got := b.snapshot(); !slices.Equal(got, want) {
+		t.Fatalf("after delete line, snapshot = %q, want %q", got, want)
+	}
+
+	// Wrapping at the last column continues on the next row.
+	b.write([]byte("\x1b[r\x1b[2;1H0123456789ab"))
+	if got := b.snapshot()[1:3]; !slices.Equal(got, []string{"0123456789", "ab"}) {
+		t.Fatalf("expected the line to wrap, got %q", got)
+	}
+
+	b.write([]byte("\x1b[?1049l"))
+	if b.fullScreen() || !slices.Equal(b.snapshot(), []string{"$ chezmoi edit"}) {
+		t.Fatalf("expected the main screen back, got %q", b.snapshot())
+	}
+}
+
+func TestTerminalBufferAnswersStatusQueries(t *testing.T) {
+	var b terminalBuffer
+	b.resize(80, 24)
+	b.write([]byte("\x1b[?1049h\x1b[3;5H\x1b[6n\x1b[c\x1b[?1h"))
+	if got := string(b.takeReplies()); got != "\x1b[3;5R\x1b[?1;2c" {
+		t.Fatalf("replies = %q", got)
+	}
+	if !b.appCursor || b.takeReplies() != nil {
+		t.Fatal("expected cursor keys mode set and the replies taken")
+	}
+}
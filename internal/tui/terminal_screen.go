@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// terminalScreen is the alternate screen that full-screen programs such
+// as editors and pagers switch to: a fixed grid the program addresses by
+// cursor position. Like the line buffer, it drops colors and other
+// character attributes.
+type terminalScreen struct {
+	cells       [][]rune // rows of cells; 0 marks the second half of a wide rune
+	row, col    int
+	top, bottom int  // scroll region, inclusive rows
+	wrapNext    bool // the last column was written; the next rune wraps
+	savedRow    int
+	savedCol    int
+}
+
+func newTerminalScreen(cols, rows int) *terminalScreen {
+	s := &terminalScreen{}
+	s.resize(cols, rows)
+	return s
+}
+
+func (s *terminalScreen) rows() int { return len(s.cells) }
+
+func (s *terminalScreen) cols() int {
+	if len(s.cells) == 0 {
+		return 0
+	}
+	return len(s.cells[0])
+}
+
+// resize keeps the top-left of the grid, padding or cutting rows and
+// columns, and resets the scroll region as terminals do.
+func (s *terminalScreen) resize(cols, rows int) {
+	cols, rows = max(cols, 1), max(rows, 1)
+	cells := make([][]rune, rows)
+	for r := range cells {
+		cells[r] = blankTerminalRow(cols)
+		if r < len(s.cells) {
+			copy(cells[r], s.cells[r])
+		}
+	}
+	s.cells = cells
+	s.top, s.bottom = 0, rows-1
+	s.row, s.col = min(s.row, rows-1), min(s.col, cols-1)
+	s.savedRow, s.savedCol = min(s.savedRow, rows-1), min(s.savedCol, cols-1)
+	s.wrapNext = false
+}
+
+func blankTerminalRow(cols int) []rune {
+	row := make([]rune, cols)
+	for i := range row {
+		row[i] = ' '
+	}
+	return row
+}
+
+// put writes r at the cursor, wrapping to the next line first when the
+// previous rune filled the last column.
+func (s *terminalScreen) put(r rune) {
+	width := ansi.StringWidth(string(r))
+	if width == 0 {
+		return
+	}
+	if s.wrapNext || s.col+width > s.cols() {
+		s.col = 0
+		s.lineFeed()
+	}
+	s.cells[s.row][s.col] = r
+	if width == 2 {
+		s.cells[s.row][s.col+1] = 0
+	}
+	if s.col+width >= s.cols() {
+		s.col = s.cols() - 1
+		s.wrapNext = true
+		return
+	}
+	s.col += width
+}
+
+// control applies a C0 control character.
+func (s *terminalScreen) control(c byte) {
+	switch c {
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\r':
+		s.setCol(0)
+	case '\b':
+		s.setCol(s.col - 1)
+	case '\t':
+		s.setCol((s.col/8 + 1) * 8)
+	}
+}
+
+// escape applies a two-byte ESC sequence.
+func (s *terminalScreen) escape(c byte) {
+	switch c {
+	case '7':
+		s.savedRow, s.savedCol = s.row, s.col
+	case '8':
+		s.moveTo(s.savedRow, s.savedCol)
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.setCol(0)
+		s.lineFeed()
+	case 'M':
+		s.reverseIndex()
+	}
+}
+
+// csi applies a control sequence. args holds the numeric parameters,
+// with 0 standing for an omitted one.
+func (s *terminalScreen) csi(final byte, args []int) {
+	n := max(terminalArg(args, 0), 1)
+	switch final {
+	case 'A':
+		s.moveTo(s.row-n, s.col)
+	case 'B', 'e':
+		s.moveTo(s.row+n, s.col)
+	case 'C', 'a':
+		s.setCol(s.col + n)
+	case 'D':
+		s.setCol(s.col - n)
+	case 'E':
+		s.moveTo(s.row+n, 0)
+	case 'F':
+		s.moveTo(s.row-n, 0)
+	case 'G', '`':
+		s.setCol(n - 1)
+	case 'd':
+		s.moveTo(n-1, s.col)
+	case 'H', 'f':
+		s.moveTo(n-1, max(terminalArg(args, 1), 1)-1)
+	case 'J':
+		s.eraseInDisplay(terminalArg(args, 0))
+	case 'K':
+		s.eraseInLine(terminalArg(args, 0))
+	case 'L':
+		if s.row >= s.top && s.row <= s.bottom {
+			s.scrollDown(s.row, n)
+		}
+	case 'M':
+		if s.row >= s.top && s.row <= s.bottom {
+			s.scrollUp(s.row, n)
+		}
+	case '@':
+		line := s.cells[s.row]
+		n = min(n, len(line)-s.col)
+		copy(line[s.col+n:], line[s.col:])
+		s.blank(s.row, s.col, s.col+n)
+	case 'P':
+		line := s.cells[s.row]
+		n = min(n, len(line)-s.col)
+		copy(line[s.col:], line[s.col+n:])
+		s.blank(s.row, len(line)-n, len(line))
+	case 'X':
+		s.blank(s.row, s.col, min(s.col+n, s.cols()))
+	case 'S':
+		s.scrollUp(s.top, n)
+	case 'T':
+		s.scrollDown(s.top, n)
+	case 'r':
+		top := max(terminalArg(args, 0), 1) - 1
+		bottom := s.rows() - 1
+		if b := terminalArg(args, 1); b > 0 {
+			bottom = min(b, s.rows()) - 1
+		}
+		if top < bottom {
+			s.top, s.bottom = top, bottom
+			s.moveTo(0, 0)
+		}
+	case 's':
+		s.savedRow, s.savedCol = s.row, s.col
+	case 'u':
+		s.moveTo(s.savedRow, s.savedCol)
+	}
+}
+
+// terminalArg returns parameter i, or 0 when it was not given.
+func terminalArg(args []int, i int) int {
+	if i < len(args) {
+		return args[i]
+	}
+	return 0
+}
+
+func (s *terminalScreen) moveTo(row, col int) {
+	s.row = min(max(row, 0), s.rows()-1)
+	s.setCol(col)
+}
+
+func (s *terminalScreen) setCol(col int) {
+	s.col = min(max(col, 0), s.cols()-1)
+	s.wrapNext = false
+}
+
+// lineFeed moves down a row, scrolling the region at its bottom margin.
+func (s *terminalScreen) lineFeed() {
+	s.wrapNext = false
+	switch {
+	case s.row == s.bottom:
+		s.scrollUp(s.top, 1)
+	case s.row < s.rows()-1:
+		s.row++
+	}
+}
+
+// reverseIndex moves up a row, scrolling the region at its top margin.
+func (s *terminalScreen) reverseIndex() {
+	s.wrapNext = false
+	switch {
+	case s.row == s.top:
+		s.scrollDown(s.top, 1)
+	case s.row > 0:
+		s.row--
+	}
+}
+
+// scrollUp moves rows from..bottom up by n, blanking the rows it frees.
+func (s *terminalScreen) scrollUp(from, n int) {
+	n = min(n, s.bottom-from+1)
+	copy(s.cells[from:s.bottom+1], s.cells[from+n:s.bottom+1])
+	for r := s.bottom - n + 1; r <= s.bottom; r++ {
+		s.cells[r] = blankTerminalRow(s.cols())
+	}
+}
+
+// scrollDown moves rows from..bottom down by n, blanking the rows it frees.
+func (s *terminalScreen) scrollDown(from, n int) {
+	n = min(n, s.bottom-from+1)
+	copy(s.cells[from+n:s.bottom+1], s.cells[from:s.bottom+1-n])
+	for r := from; r < from+n; r++ {
+		s.cells[r] = blankTerminalRow(s.cols())
+	}
+}
+
+func (s *terminalScreen) eraseInDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.eraseInLine(0)
+		for r := s.row + 1; r < s.rows(); r++ {
+			s.blank(r, 0, s.cols())
+		}
+	case 1:
+		s.eraseInLine(1)
+		for r := range s.row {
+			s.blank(r, 0, s.cols())
+		}
+	case 2, 3:
+		for r := range s.rows() {
+			s.blank(r, 0, s.cols())
+		}
+	}
+}
+
+func (s *terminalScreen) eraseInLine(mode int) {
+	switch mode {
+	case 0:
+		s.blank(s.row, s.col, s.cols())
+	case 1:
+		s.blank(s.row, 0, s.col+1)
+	case 2:
+		s.blank(s.row, 0, s.cols())
+	}
+}
+
+func (s *terminalScreen) blank(row, from, to int) {
+	for i := from; i < to; i++ {
+		s.cells[row][i] = ' '
+	}
+}
+
+// lines renders every row of the grid.
+func (s *terminalScreen) lines() []string {
+	lines := make([]string, len(s.cells))
+	var b strings.Builder
+	for r, row := range s.cells {
+		b.Reset()
+		for _, c := range row {
+			if c != 0 {
+				b.WriteRune(c)
+			}
+		}
+		lines[r] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/pty"
+)
+
+// newPipeTerminalSession returns a session whose pty is a pipe, so tests
+// can read back whatever the pane forwards as keyboard input.
+func newPipeTerminalSession(t *testing.T) (*terminalSession, *os.File) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = r.Close()
+		_ = w.Close()
+	})
+	return &terminalSession{title: "chezmoi apply", action: chezmoiActionApplyAll, ptm: w}, r
+}
+
+func TestTerminalKeyInput(t *testing.T) {
+	tests := []struct {
+		name string
+		key  tea.KeyPressMsg
+		want string
+	}{
+		{"text", runeKey("y"), "y"},
+		{"enter", specialKey(tea.KeyEnter), "\r"},
+		{"backspace", specialKey(tea.KeyBackspace), "\x7f"},
+		{"escape", specialKey(tea.KeyEscape), "\x1b"},
+		{"arrow", specialKey(tea.KeyUp), "\x1b[A"},
+		{"ctrl+c", ctrlKey('c'), "\x03"},
+		{"alt+b", tea.KeyPressMsg{Code: 'b', Text: "b", Mod: tea.ModAlt}, "\x1bb"},
+		{"page down", specialKey(tea.KeyPgDown), "\x1b[6~"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := terminalKeyInput(tc.key, false); got != tc.want {
+				t.Fatalf("terminalKeyInput = %q, want %q", got, tc.want)
+			}
+		})
+	}
+	if got := terminalKeyInput(specialKey(tea.KeyUp), true); got != "\x1bOA" {
+		t.Fatalf("expected the application sequence in cursor keys mode, got %q", got)
+	}
+}
+
+func TestTerminalStartedOpensPane(t *testing.T) {
+	m := newTestModel()
+	session, _ := newPipeTerminalSession(t)
+
+	m, cmd := sendMsg(t, m, terminalStartedMsg{session: session})
+	if m.view != TerminalScreen || !m.term.running {
+		t.Fatalf("expected running TerminalScreen, got view=%v running=%v", m.view, m.term.running)
+	}
+	if cmd == nil {
+		t.Fatal("expected read cmd")
+	}
+}
+
+func TestTerminalForwardsKeysWhileRunning(t *testing.T) {
+	m := newTestModel()
+	session, input := newPipeTerminalSession(t)
+	m, _ = sendMsg(t, m, terminalStartedMsg{session: session})
+
+	// q and ? would quit or open help anywhere else.
+	for _, k := range []tea.KeyPressMsg{runeKey("q"), runeKey("?"), specialKey(tea.KeyEnter)} {
+		var cmd tea.Cmd
+		m, cmd = sendKey(t, m, k)
+		if isQuitCmd(cmd) {
+			t.Fatal("expected q to be forwarded, not quit")
+		}
+	}
+	_ = session.ptm.Close()
+
+	got, err := io.ReadAll(input)
+	if err != nil {
+		t.Fatalf("read forwarded input: %v", err)
+	}
+	if string(got) != "q?\r" {
+		t.Fatalf("forwarded input = %q, want %q", got, "q?\r")
+	}
+	if m.view != TerminalScreen || m.overlays.showHelp {
+		t.Fatal("expected pane to keep focus while running")
+	}
+}
+
+func TestTerminalIgnoresStaleSessionOutput(t *testing.T) {
+	m := newTestModel()
+	current, _ := newPipeTerminalSession(t)
+	stale, _ := newPipeTerminalSession(t)
+	m, _ = sendMsg(t, m, terminalStartedMsg{session: current})
+
+	m, cmd := sendMsg(t, m, terminalOutputMsg{session: stale, data: []byte("old\n")})
+	if cmd != nil || len(m.term.buf.snapshot()) != 0 {
+		t.Fatal("expected stale output to be dropped")
+	}
+	m, _ = sendMsg(t, m, terminalOutputMsg{session: current, data: []byte("new\n")})
+	if got := m.term.buf.snapshot(); !slices.Equal(got, []string{"new"}) {
+		t.Fatalf("snapshot = %q, want [new]", got)
+	}
+}
+
+func TestTerminalEditorDrawsFullScreenAndClosesOnExit(t *testing.T) {
+	m := newTestModel(WithSize(40, 12))
+	session, input := newPipeTerminalSession(t)
+	session.action = chezmoiActionEditSource
+	m, _ = sendMsg(t, m, terminalStartedMsg{session: session})
+	m, _ = sendMsg(t, m, terminalOutputMsg{session: session, data: []byte("\x1b[?1049h\x1b[?1h\x1b[H\x1b[2Jexport EDITOR=vi\x1b[6n")})
+
+	screen := m.renderTerminalScreen()
+	if !strings.Contains(screen, "export EDITOR=vi") || !strings.Contains(screen, "quit the program to return") {
+		t.Fatalf("expected the editor's screen, got:\n%s", screen)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyPgDown))
+	m, _ = sendKey(t, m, specialKey(tea.KeyUp))
+	_ = session.ptm.Close()
+	forwarded, _ := io.ReadAll(input)
+	if string(forwarded) != "\x1b[1;17R\x1b[6~\x1bOA" {
+		t.Fatalf("expected the cursor report and keys forwarded, got %q", forwarded)
+	}
+
+	m, cmd := sendMsg(t, m, terminalExitedMsg{session: session})
+	if m.view != StatusScreen || cmd == nil || m.term.unreported != chezmoiActionNone {
+		t.Fatalf("expected a clean editor exit to close the pane, got view %v", m.view)
+	}
+}
+
+func TestTerminalCloseAfterExitReloadsOnce(t *testing.T) {
+	m := newTestModel()
+	session, _ := newPipeTerminalSession(t)
+	m, _ = sendMsg(t, m, terminalStartedMsg{session: session})
+	m, _ = sendMsg(t, m, terminalOutputMsg{session: session, data: []byte("applied\n")})
+	m, _ = sendMsg(t, m, terminalExitedMsg{session: session})
+
+	if m.term.running {
+		t.Fatal("expected session to be finished")
+	}
+	if got := m.term.buf.snapshot(); got[len(got)-1] != "[process exited]" {
+		t.Fatalf("expected exit banner, got %q", got)
+	}
+
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected StatusScreen after close, got %v", m.view)
+	}
+	if cmd == nil || m.ui.message != "apply complete" {
+		t.Fatalf("expected reload and result message, got message=%q", m.ui.message)
+	}
+
+	// Reopening the scrollback keeps the output but does not reload again.
+	m, _ = sendKey(t, m, runeKey("4"))
+	m, _ = sendKey(t, m, runeKey("o"))
+	if m.view != TerminalScreen {
+		t.Fatalf("expected o to reopen the pane, got %v", m.view)
+	}
+	if !slices.Contains(m.term.buf.snapshot(), "applied") {
+		t.Fatal("expected scrollback to survive close")
+	}
+	m, cmd = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen || cmd != nil {
+		t.Fatal("expected second close to return without reloading")
+	}
+}
+
+func TestTerminalScrollback(t *testing.T) {
+	m := newTestModel(WithSize(80, 10))
+	session, _ := newPipeTerminalSession(t)
+	m, _ = sendMsg(t, m, terminalStartedMsg{session: session})
+	var out strings.Builder
+	for i := range 30 {
+		out.WriteString("line " + string(rune('a'+i%26)) + "\n")
+	}
+	m, _ = sendMsg(t, m, terminalOutputMsg{session: session, data: []byte(out.String())})
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyPgUp))
+	if m.term.offset == 0 {
+		t.Fatal("expected pgup to scroll back while running")
+	}
+	m, _ = sendMsg(t, m, terminalOutputMsg{session: session, data: []byte("more\n")})
+	before := m.term.offset
+	m, _ = sendKey(t, m, specialKey(tea.KeyPgDown))
+	if m.term.offset >= before {
+		t.Fatalf("expected pgdown to move toward the bottom, offset %d -> %d", before, m.term.offset)
+	}
+}
+
+func TestTerminalExecCmdRunsInPTY(t *testing.T) {
+	if !pty.Supported {
+		t.Skip("pseudo-terminals unsupported on this platform")
+	}
+	m := newTestModel()
+	cmd := exec.Command("sh", "-c", `test -t 0 && printf 'interactive %s\n' "$TERM"`)
+
+	msg := m.terminalExecCmd(chezmoiActionApplyAll, "chezmoi apply", cmd, cmd, "unsupported")()
+	started, ok := msg.(terminalStartedMsg)
+	if !ok {
+		t.Fatalf("expected terminalStartedMsg, got %T", msg)
+	}
+	m, next := sendMsg(t, m, started)
+	for next != nil {
+		m, next = sendMsg(t, m, next())
+		if !m.term.running {
+			break
+		}
+	}
+	if m.term.running {
+		t.Fatal("expected command to exit")
+	}
+	if !slices.Contains(m.term.buf.snapshot(), "interactive xterm") {
+		t.Fatalf("expected command to run attached to a terminal, got %q", m.term.buf.snapshot())
+	}
+}
+
+func TestTerminalCloseKillsRunningCommand(t *testing.T) {
+	if !pty.Supported {
+		t.Skip("pseudo-terminals unsupported on this platform")
+	}
+	m := newTestModel()
+	cmd := exec.Command("sleep", "30")
+
+	msg := m.terminalExecCmd(chezmoiActionApplyAll, "chezmoi apply", cmd, cmd, "unsupported")()
+	started, ok := msg.(terminalStartedMsg)
+	if !ok {
+		t.Fatalf("expected terminalStartedMsg, got %T", msg)
+	}
+	m, _ = sendMsg(t, m, started)
+	m.Close()
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- readTerminalCmd(started.session)() }()
+	select {
+	case msg := <-done:
+		exited, ok := msg.(terminalExitedMsg)
+		if !ok || exited.err == nil {
+			t.Fatalf("expected the command to be killed, got %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Close to stop the command")
+	}
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func (m Model) renderTerminalScreen() string {
+	var b strings.Builder
+
+	title := "Terminal"
+	if m.term.session != nil {
+		title = m.term.session.title
+	}
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), title)...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+
+	lines := m.term.buf.snapshot()
+	height := m.terminalPaneHeight()
+	end := max(0, len(lines)-m.term.offset)
+	start := max(0, end-height)
+	width := m.effectiveWidth()
+	cursorRow, cursorCol, showCursor := m.term.buf.cursor()
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if showCursor && m.term.running && i == cursorRow {
+			line = renderTerminalCursor(line, cursorCol)
+		}
+		b.WriteString(visualTruncate(line, width))
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderTerminalStatusBar())
+}
+
+// renderTerminalCursor shows the alternate screen's cursor as a
+// reverse-video cell at col.
+func renderTerminalCursor(line string, col int) string {
+	if pad := col + 1 - ansi.StringWidth(line); pad > 0 {
+		line += strings.Repeat(" ", pad)
+	}
+	cell := ansi.Cut(line, col, col+1)
+	return ansi.Truncate(line, col, "") + lipgloss.NewStyle().Reverse(true).Render(cell) + ansi.TruncateLeft(line, col+1, "")
+}
+
+func (m Model) renderTerminalStatusBar() string {
+	status := " running — keys are sent to chezmoi "
+	switch {
+	case m.ui.message != "":
+		status = " " + m.ui.message + " "
+	case !m.term.running && m.term.exitErr != nil:
+		status = " " + terminalExitBanner(m.term.exitErr) + " "
+	case !m.term.running:
+		status = " finished "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+
+	hint := "pgup/pgdn scroll | ctrl+c interrupt"
+	switch {
+	case m.term.running && m.term.buf.fullScreen():
+		hint = "quit the program to return"
+	case !m.term.running:
+		hint = "↑/↓ scroll | pgup/pgdn page | g/G top/bottom | esc close"
+	}
+	return statusBar + "\n" + m.helpHint(hint)
+}
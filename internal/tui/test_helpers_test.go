@@ -46,12 +46,16 @@ func testServiceWithTarget(targetPath string) *chezmoi.Service {
 type testModelConfig struct {
 	service  *chezmoi.Service
 	readOnly bool
+	target   string
+	svcOpts  []chezmoi.ServiceOption
 	view     Screen
 	tab      int
 	width    int
 	height   int
 	iconMode IconMode
 	postInit []func(*Model)
+	loaded   []tea.Msg
+	loadPane bool
 }
 
 // TestModelOption configures a test Model via testModelConfig.
@@ -73,6 +77,21 @@ func WithReadOnly() TestModelOption {
 	return func(c *testModelConfig) { c.readOnly = true }
 }
 
+func WithService(svc *chezmoi.Service) TestModelOption {
+	return func(c *testModelConfig) { c.service = svc }
+}
+
+// WithTarget sets the default service's target path.
+func WithTarget(path string) TestModelOption {
+	return func(c *testModelConfig) { c.target = path }
+}
+
+// WithServiceOptions passes opts to the default service, e.g. a data or
+// backup directory.
+func WithServiceOptions(opts ...chezmoi.ServiceOption) TestModelOption {
+	return func(c *testModelConfig) { c.svcOpts = append(c.svcOpts, opts...) }
+}
+
 func WithIconMode(mode IconMode) TestModelOption {
 	return func(c *testModelConfig) { c.iconMode = mode }
 }
@@ -103,6 +122,14 @@ func WithManagedFiles(files []string) TestModelOption {
 	}
 }
 
+func WithCommandItems(items []chezmoiCommandItem) TestModelOption {
+	return func(c *testModelConfig) {
+		c.postInit = append(c.postInit, func(m *Model) {
+			m.cmds.items = items
+		})
+	}
+}
+
 func WithPanelVisible() TestModelOption {
 	return func(c *testModelConfig) {
 		c.postInit = append(c.postInit, func(m *Model) {
@@ -112,6 +139,19 @@ func WithPanelVisible() TestModelOption {
 	}
 }
 
+// WithLoaded delivers msgs through Model.Update once the Model is built, as
+// a screen's loader would. Commands Update returns are dropped.
+func WithLoaded(msgs ...tea.Msg) TestModelOption {
+	return func(c *testModelConfig) { c.loaded = append(c.loaded, msgs...) }
+}
+
+// WithPanelLoaded loads the panel for the selected row of the active tab
+// after any WithLoaded messages, feeding the load command's result back
+// through Model.Update.
+func WithPanelLoaded() TestModelOption {
+	return func(c *testModelConfig) { c.loadPane = true }
+}
+
 func WithDiffContent(path, content string, lineCount int) TestModelOption {
 	return func(c *testModelConfig) {
 		c.view = DiffScreen
@@ -127,6 +167,14 @@ func WithDiffContent(path, content string, lineCount int) TestModelOption {
 	}
 }
 
+// WithInfoView opens the Info tab on subView.
+func WithInfoView(subView int) TestModelOption {
+	return func(c *testModelConfig) {
+		c.tab = 2
+		c.postInit = append(c.postInit, func(m *Model) { m.info.activeView = subView })
+	}
+}
+
 func WithInfoContent(subView int, content string, lineCount int) TestModelOption {
 	return func(c *testModelConfig) {
 		c.tab = 2
@@ -148,7 +196,8 @@ func WithInfoContent(subView int, content string, lineCount int) TestModelOption
 //
 // Options are order-independent: the config struct is populated first, then the
 // Model is built once from the final config. Post-construction mutations
-// (WithDriftFiles, WithPanelVisible, etc.) run after Model creation.
+// (WithDriftFiles, WithPanelVisible, etc.) run after Model creation, then
+// WithLoaded messages are delivered, then WithPanelLoaded loads the panel.
 func newTestModel(opts ...TestModelOption) Model {
 	cfg := &testModelConfig{
 		view:     StatusScreen,
@@ -156,6 +205,7 @@ func newTestModel(opts ...TestModelOption) Model {
 		width:    120,
 		height:   40,
 		iconMode: IconModeNone,
+		target:   "/home/test",
 	}
 
 	for _, opt := range opts {
@@ -164,11 +214,11 @@ func newTestModel(opts ...TestModelOption) Model {
 
 	svc := cfg.service
 	if svc == nil {
+		mode := config.ModeWrite
 		if cfg.readOnly {
-			svc = testServiceReadOnly()
-		} else {
-			svc = testService()
+			mode = config.ModeReadOnly
 		}
+		svc = chezmoi.NewService(chezmoi.New(chezmoi.WithBinaryPath("/bin/true")), mode, cfg.target, cfg.svcOpts...)
 	}
 
 	m := NewModel(Options{Service: svc, IconMode: cfg.iconMode})
@@ -181,6 +231,21 @@ func newTestModel(opts ...TestModelOption) Model {
 		fn(&m)
 	}
 
+	for _, msg := range cfg.loaded {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	if cfg.loadPane {
+		m.panel.resetForTab(m.activeTabName())
+		var cmd tea.Cmd
+		m, cmd = m.panelLoadForCurrentTab()
+		if cmd != nil {
+			updated, _ := m.Update(cmd())
+			m = updated.(Model)
+		}
+	}
+
 	return m
 }
 
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"context"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// timeTravelState holds the Time Travel commit picker. The chosen commit
+// opens in the destination preview.
+type timeTravelState struct {
+	commits []chezmoi.GitCommit
+	cursor  int
+}
+
+func (m Model) openTimeTravel() (tea.Model, tea.Cmd) {
+	m.ui.busyAction = true
+	m.ui.message = ""
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+		commits, err := m.service.TimeTravelCommits()
+		return timeTravelCommitsLoadedMsg{commits: commits, err: err}
+	})
+}
+
+func (m Model) handleTimeTravelCommitsLoaded(msg timeTravelCommitsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	if len(msg.commits) == 0 {
+		m.ui.message = "No commits in the source repo"
+		return m, nil
+	}
+	m.actions.show = false
+	m.view = TimeTravelScreen
+	m.timeTravel = timeTravelState{commits: msg.commits}
+	return m, nil
+}
+
+func (m Model) handleTimeTravelKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		m.view = StatusScreen
+		m.timeTravel = timeTravelState{}
+		return m, nil
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.timeTravel.cursor = moveCursorUp(m.timeTravel.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.timeTravel.cursor = moveCursorDown(m.timeTravel.cursor, len(m.timeTravel.commits), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.timeTravel.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.timeTravel.cursor = max(0, len(m.timeTravel.commits)-1)
+	case key.Matches(msg, ChezTimeTravelKeys.Preview):
+		if m.ui.busyAction || m.timeTravel.cursor >= len(m.timeTravel.commits) {
+			return m, nil
+		}
+		commit := m.timeTravel.commits[m.timeTravel.cursor].Hash
+		m.ui.busyAction = true
+		m.ui.message = "applying " + shortHash(commit) + " to a temporary destination..."
+		return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+			preview, err := m.service.TimeTravel(context.Background(), commit)
+			return destPreviewLoadedMsg{preview: preview, returnTo: TimeTravelScreen, err: err}
+		})
+	}
+	return m, nil
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// newTimeTravelModel returns a model on the Time Travel commit picker with
+// a preview of commit abc1234 (see newDestPreviewModel) ready to load.
+func newTimeTravelModel(t *testing.T) (Model, chezmoi.DestPreview) {
+	t.Helper()
+	m, preview := newDestPreviewModel(t, 80, "abc1234def")
+	m, _ = sendMsg(t, m, timeTravelCommitsLoadedMsg{commits: []chezmoi.GitCommit{
+		{Hash: "abc1234def", Message: "switch shell"},
+		{Hash: "0123456789", Message: "initial"},
+	}})
+	if m.view != TimeTravelScreen {
+		t.Fatalf("expected TimeTravelScreen, got %v", m.view)
+	}
+	return m, preview
+}
+
+func TestTimeTravelPickerPreviewsCommit(t *testing.T) {
+	m, preview := newTimeTravelModel(t)
+	rendered := ansi.Strip(m.renderTimeTravelScreen())
+	if !strings.Contains(rendered, "abc1234 switch shell") || !strings.Contains(rendered, "0123456 initial") {
+		t.Fatalf("expected commits listed:\n%s", rendered)
+	}
+
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEnter))
+	if cmd == nil || !m.ui.busyAction {
+		t.Fatal("expected enter to build the preview")
+	}
+
+	m, _ = sendMsg(t, m, destPreviewLoadedMsg{preview: preview, returnTo: TimeTravelScreen})
+	if m.view != DestPreviewScreen || !m.destPreview.open {
+		t.Fatalf("expected the preview open, got %v", m.view)
+	}
+	rendered = ansi.Strip(m.renderDestPreviewScreen())
+	for _, want := range []string{"Time Travel > abc1234", "1 new · 1 changed · 1 removed", ".bashrc [changed]", ".zshrc [removed]", ".config"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	if !strings.Contains(rendered, ".profile") || strings.Contains(rendered, ".profile [") {
+		t.Fatalf("expected .profile listed without a tag:\n%s", rendered)
+	}
+	m, _ = sendKey(t, m, runeKey("c"))
+	if rendered := ansi.Strip(m.renderDestPreviewScreen()); strings.Contains(rendered, ".profile") {
+		t.Fatalf("expected unchanged targets hidden:\n%s", rendered)
+	}
+}
+
+func TestTimeTravelDiffAgainstCurrent(t *testing.T) {
+	m, preview := newTimeTravelModel(t)
+	m, _ = sendMsg(t, m, destPreviewLoadedMsg{preview: preview, returnTo: TimeTravelScreen})
+
+	// Directories sort first, so move past .config to .bashrc.
+	m = moveToDestPreviewRow(t, m, ".bashrc")
+	m, cmd := sendKey(t, m, runeKey("d"))
+	if cmd == nil {
+		t.Fatal("expected a diff command")
+	}
+	m, _ = sendMsg(t, m, cmd())
+	if m.view != DiffScreen || !m.diff.fromDestPreview {
+		t.Fatalf("expected time-travel diff view, got %v", m.view)
+	}
+	if diff := strings.Join(m.diff.rawLines, "\n"); !strings.Contains(diff, "-current bashrc") || !strings.Contains(diff, "+old bashrc") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != DestPreviewScreen || !m.destPreview.open {
+		t.Fatalf("expected esc to return to the preview, got %v", m.view)
+	}
+}
+
+func TestTimeTravelEscClosesPreview(t *testing.T) {
+	m, preview := newTimeTravelModel(t)
+	m, _ = sendMsg(t, m, destPreviewLoadedMsg{preview: preview, returnTo: TimeTravelScreen})
+
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.destPreview.open || m.view != TimeTravelScreen {
+		t.Fatal("expected esc to return to the commit picker")
+	}
+	if cmd == nil {
+		t.Fatal("expected the preview to be cleaned up")
+	}
+	cmd()
+	if _, err := os.Stat(preview.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the preview directory removed, got %v", err)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen {
+		t.Fatalf("expected esc to leave the screen, got %v", m.view)
+	}
+}
+
+func TestTimeTravelPreviewFinishedAfterLeavingIsDiscarded(t *testing.T) {
+	m, preview := newTimeTravelModel(t)
+	m.view = StatusScreen
+
+	m, cmd := sendMsg(t, m, destPreviewLoadedMsg{preview: preview, returnTo: TimeTravelScreen})
+	if m.destPreview.open || cmd == nil {
+		t.Fatal("expected a late preview to be discarded")
+	}
+	cmd()
+	if _, err := os.Stat(preview.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the preview directory removed, got %v", err)
+	}
+}
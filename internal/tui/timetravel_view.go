@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+func (m Model) renderTimeTravelScreen() string {
+	var b strings.Builder
+
+	b.WriteString(renderBreadcrumb(append(m.breadcrumbParts(), "Time Travel")...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	b.WriteString(activeTheme.DimText.Render("  Pick a source commit to preview; nothing is written to the source or destination"))
+	b.WriteString("\n\n")
+
+	maxWidth := m.effectiveWidth() - 2
+	commits := m.timeTravel.commits
+	start, end := visibleRange(len(commits), m.timeTravel.cursor, m.timeTravelListHeight())
+	for i := start; i < end; i++ {
+		selected := i == m.timeTravel.cursor
+		cursor := "    "
+		if selected {
+			cursor = "  > "
+		}
+		hash := shortHash(commits[i].Hash)
+		if !selected {
+			hash = activeTheme.AccentFg.Render(hash)
+		}
+		line := visualTruncate(cursor+hash+" "+commits[i].Message, maxWidth)
+		if selected {
+			line = activeTheme.Selected.Width(maxWidth).Render(line)
+		}
+		b.WriteString(line)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderTimeTravelStatusBar())
+}
+
+func (m Model) renderTimeTravelStatusBar() string {
+	status := " Time Travel "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	help := m.helpHint("↑/↓ nav | enter preview commit | esc back")
+	return statusBar + "\n" + help
+}
@@ -21,6 +21,19 @@ const (
 	DiffScreen
 	ConfirmScreen
 	CommitScreen
+	ApplyPlanScreen
+	TerminalScreen
+	BackupsScreen
+	TimeTravelScreen
+	DestPreviewScreen
+	WhatIfScreen
+	TemplateLintScreen
+	DataDepsScreen
+	ScriptsScreen
+	ExternalsScreen
+	AddFormScreen
+	AttributesScreen
+	IgnoreFormScreen
 )
 
 type chezmoiAction int
@@ -33,6 +46,7 @@ const (
 	chezmoiActionApplyAll
 	chezmoiActionUpdate
 	chezmoiActionCommit
+	chezmoiActionCommitAllowSecrets
 	chezmoiActionPush
 	chezmoiActionRefresh
 	chezmoiActionInit
@@ -62,14 +76,25 @@ const (
 
 	// Unmanaged add actions
 	chezmoiActionAdd
-	chezmoiActionAddEncrypt
-	chezmoiActionAddTemplate
-	chezmoiActionAddAutoTemplate
-	chezmoiActionAddExact
-	chezmoiActionAddNoRecursive
+	chezmoiActionAddWithOptions
+	chezmoiActionAddMarked
+	chezmoiActionEditAttributes
+	chezmoiActionIgnorePath
+	chezmoiActionIgnorePattern
+	chezmoiActionExplainIgnore
 
 	// Command tab actions
 	chezmoiActionArchive
+	chezmoiActionApplyPlan
+	chezmoiActionUndoUpdate
+	chezmoiActionEditTemplate
+	chezmoiActionEditScript
+	chezmoiActionDeleteStateKey
+	chezmoiActionDeleteStateBucket
+	chezmoiActionRefreshExternal
+	chezmoiActionEncryptFile
+	chezmoiActionDecryptFile
+	chezmoiActionReencryptAll
 )
 
 type changesSection int
@@ -80,29 +105,40 @@ const (
 	changesSectionStaged
 	changesSectionUnpushed
 	changesSectionIncoming
+	changesSectionIncomingFiles
+	changesSectionCount
 )
 
 // Info sub-view indices.
 const (
-	infoViewConfig = iota // cat-config (user's config file)
-	infoViewFull          // dump-config (full computed config)
-	infoViewData          // template data
-	infoViewDoctor        // health check
+	infoViewConfig   = iota // cat-config (user's config file)
+	infoViewFull            // dump-config (full computed config)
+	infoViewData            // template data
+	infoViewTemplate        // execute-template playground
+	infoViewState           // persistent state browser
+	infoViewDoctor          // health check
 	infoViewCount
 )
 
 // infoTab manages the Info tab's own state.
 type infoTab struct {
-	activeView int                             // active sub-view index (0-3)
-	viewNames  []string                        // ["Config", "Full", "Data", "Doctor"]
+	activeView int                             // active sub-view index (0-5)
+	viewNames  []string                        // ["Config", "Full", "Data", "Template", "State", "Doctor"]
 	views      [infoViewCount]infoSubViewState // per-sub-view state
 	format     string                          // "yaml" or "json"
+	template   templateREPLState               // Template sub-view input and history
+	state      stateBrowserState               // State sub-view tree
 }
 
 // commandsTab manages the Commands tab's own state.
 type commandsTab struct {
 	items  []chezmoiCommandItem
 	cursor int
+
+	// outputJob is the job whose streamed output replaces the command
+	// list; 0 shows the list.
+	outputJob int
+	output    viewport.Model
 }
 
 // statusTab manages the Status/Changes tab's own state.
@@ -117,15 +153,18 @@ type statusTab struct {
 	changesCursor    int
 	selectionActive  bool // true when a range selection is active in the status list
 	selectionAnchor  int  // anchor row index for status range selection
-	sectionCollapsed [5]bool
+	sectionCollapsed [changesSectionCount]bool
 	statusDeferred   bool // true if status load was deferred at startup
 	gitDeferred      bool // true if git status load was deferred at startup
 
 	unpushedCommits []chezmoi.GitCommit
 	incomingCommits []chezmoi.GitCommit
+	incomingFiles   []chezmoi.IncomingFile
 	lastFetchTime   time.Time
 	fetchInProgress bool
 	templatePaths   map[string]bool // target paths of template-managed files
+	encryptedPaths  map[string]bool // target paths of encrypted files
+	dataIndex       chezmoi.DataIndex
 }
 
 // managedViewMode selects which data the Managed tab displays.
@@ -160,6 +199,15 @@ type filesTab struct {
 	dataset filesDataset
 
 	managedDeferred bool // true if managed load was deferred at startup
+
+	// ignoredHere are paths ignored from the Files tab this session.
+	// chezmoi ignored lists only source state entries, so unmanaged ones
+	// are merged into its output.
+	ignoredHere []string
+
+	// marked holds the unmanaged files marked for one bulk add, by
+	// absolute path.
+	marked map[string]bool
 }
 
 // landingState groups fields for the landing page view.
@@ -178,13 +226,15 @@ type uiState struct {
 }
 
 // changesRow is a union row in the Status tab's changes list.
-// Exactly one of driftFile, gitFile, or commit is non-nil; isHeader marks section headers.
+// Exactly one of driftFile, gitFile, commit, or incomingFile is non-nil; isHeader marks section headers.
 type changesRow struct {
-	isHeader  bool
-	section   changesSection
-	driftFile *chezmoi.FileStatus
-	gitFile   *chezmoi.GitFile
-	commit    *chezmoi.GitCommit
+	isHeader     bool
+	section      changesSection
+	driftFile    *chezmoi.FileStatus
+	gitFile      *chezmoi.GitFile
+	commit       *chezmoi.GitCommit
+	incomingFile *chezmoi.IncomingFile
+	resolved     *chezmoi.ApplyResultEntry // briefly shown after an apply resolves it
 }
 
 type chezmoiActionItem struct {
@@ -214,6 +264,17 @@ const (
 	chezmoiCmdGitLog
 	chezmoiCmdData
 	chezmoiCmdArchive
+	chezmoiCmdApplyPlan
+	chezmoiCmdBackups
+	chezmoiCmdUndoUpdate
+	chezmoiCmdTimeTravel
+	chezmoiCmdSandboxApply
+	chezmoiCmdWhatIf
+	chezmoiCmdLintTemplates
+	chezmoiCmdDataDeps
+	chezmoiCmdScripts
+	chezmoiCmdExternals
+	chezmoiCmdReencryptAll
 )
 
 type chezmoiCommandItem struct {
@@ -278,13 +339,16 @@ type overlayState struct {
 	showFilterOverlay bool
 	filterCategories  []filterCategory
 	filterCursor      int
+	// Jobs
+	showJobs bool
 	// Confirm dialog
 	confirmAction chezmoiAction
 	confirmLabel  string
 	confirmPath   string
 	confirmPaths  []string
-	applyForce    bool // true = Force Apply (default for apply actions)
-	applyWrapTTY  bool // true = keep apply output visible with wrapWithPressEnter
+	confirmDetail []string // lines shown above the question, e.g. secret scan findings
+	applyForce    bool     // true = Force Apply (default for apply actions)
+	applyWrapTTY  bool     // true = keep apply output visible with wrapWithPressEnter when the terminal pane is unavailable
 }
 
 // isApplyAction returns true for actions that use the two-option apply confirm selector.
@@ -296,16 +360,23 @@ func isApplyAction(action chezmoiAction) bool {
 
 // diffViewState groups fields for the full-screen diff overlay.
 type diffViewState struct {
-	content       string
-	path          string
-	lines         []string // rendered lines for viewport (pager-colored or raw)
-	rawLines      []string // canonical raw unified diff lines (for diffSummary)
-	pagerApplied  bool
-	sourceSection changesSection
-	previewApply  bool
-	viewport      viewport.Model
-	viewportReady bool
-	lastWidth     int
+	content           string
+	path              string
+	lines             []string // rendered lines for viewport (pager-colored or raw)
+	rawLines          []string // canonical raw unified diff lines (for diffSummary)
+	pagerApplied      bool
+	sourceSection     changesSection
+	previewApply      bool
+	previewAdd        *addPreviewRequest // Enter runs the previewed add
+	previewChattr     *chattrRequest     // Enter runs the previewed chattr
+	fromApplyPlan     bool               // Esc returns to the Apply Plan screen
+	fromBackups       bool               // Esc returns to the Backups screen
+	fromDestPreview   bool               // Esc returns to the destination preview
+	fromWhatIf        bool               // Esc returns to What-If Data
+	fromIgnoreExplain bool               // read-only ignore explanation
+	viewport          viewport.Model
+	viewportReady     bool
+	lastWidth         int
 }
 
 // ensureViewport creates or resizes the viewport to the given dimensions.
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// recordUpdateOriginCmd saves the source HEAD, then hands next back to run
+// the update or pull it precedes.
+func (m Model) recordUpdateOriginCmd(next tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		return updateOriginRecordedMsg{err: m.service.RecordUpdateOrigin(), next: next}
+	}
+}
+
+// handleUpdateOriginRecorded runs the update even when recording failed;
+// only the undo is lost, and the reflog may still cover it.
+func (m Model) handleUpdateOriginRecorded(msg updateOriginRecordedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.ui.message = "Warning: could not record source HEAD for undo: " + msg.err.Error()
+	}
+	return m, msg.next
+}
+
+func (m Model) openUndoUpdate() (tea.Model, tea.Cmd) {
+	if m.service.IsReadOnly() {
+		m.ui.message = actionUnavailableMessage("read-only mode")
+		return m, nil
+	}
+	m.ui.busyAction = true
+	m.ui.message = ""
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+		origin, err := m.service.LastUpdateOrigin()
+		return updateOriginLoadedMsg{origin: origin, err: err}
+	})
+}
+
+func (m Model) handleUpdateOriginLoaded(msg updateOriginLoadedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if errors.Is(msg.err, chezmoi.ErrNoUpdate) {
+		m.ui.message = "No update to undo"
+		return m, nil
+	}
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m = m.showConfirmScreen(chezmoiActionUndoUpdate, undoUpdateLabel(msg.origin))
+	m.overlays.confirmPath = msg.origin.Commit
+	return m, nil
+}
+
+// undoUpdateLabel describes the reset for the confirm screen, e.g. "reset
+// the source to abc1234 (recorded before the update at 15:04), dropping 2
+// commits, then review the apply".
+func undoUpdateLabel(origin chezmoi.UpdateOrigin) string {
+	source := "found in the reflog"
+	if !origin.FromReflog {
+		source = "recorded before the update at " + origin.Time.Local().Format("2006-01-02 15:04")
+	}
+	label := fmt.Sprintf("reset the source to %s (%s)", shortHash(origin.Commit), source)
+	switch {
+	case origin.Dropped == 1:
+		label += ", dropping 1 commit"
+	case origin.Dropped > 1:
+		label += fmt.Sprintf(", dropping %d commits", origin.Dropped)
+	}
+	return label + ", then review the apply"
+}
+
+func (m Model) undoUpdateCmd(commit string) tea.Cmd {
+	return func() tea.Msg {
+		return updateUndoneMsg{commit: commit, err: m.service.UndoUpdate(commit)}
+	}
+}
+
+// handleUpdateUndone reloads for the rolled-back source and opens the apply
+// plan, so the previous state can be previewed before it reaches the
+// destination.
+func (m Model) handleUpdateUndone(msg updateUndoneMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if errors.Is(msg.err, chezmoi.ErrSourceDirty) {
+		m.ui.message = "Error: source has uncommitted changes — commit or discard them before undoing"
+		return m, nil
+	}
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		return m, nil
+	}
+	m.panel.clearCache()
+	reload := m.postActionReloadCmds()
+	result, planCmd := m.openApplyPlan()
+	m = result.(Model)
+	m.ui.message = "source reset to " + shortHash(msg.commit)
+	return m, tea.Batch(reload, planCmd, sendRefreshMsg())
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
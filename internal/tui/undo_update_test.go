@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func TestUndoUpdateConfirmsRecordedOrigin(t *testing.T) {
+	m := newTestModel()
+	m.ui.busyAction = true
+
+	m, _ = sendMsg(t, m, updateOriginLoadedMsg{origin: chezmoi.UpdateOrigin{
+		Commit:  "aaa1111bbb2222ccc3333",
+		Time:    time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local),
+		Dropped: 2,
+	}})
+	if m.view != ConfirmScreen || m.overlays.confirmAction != chezmoiActionUndoUpdate {
+		t.Fatalf("expected undo confirm, got view %v action %v", m.view, m.overlays.confirmAction)
+	}
+	if m.overlays.confirmPath != "aaa1111bbb2222ccc3333" {
+		t.Fatalf("expected the commit kept for the confirm, got %q", m.overlays.confirmPath)
+	}
+	want := "reset the source to aaa1111 (recorded before the update at 2026-03-01 09:30), dropping 2 commits, then review the apply"
+	if m.overlays.confirmLabel != want {
+		t.Fatalf("unexpected label:\n got %q\nwant %q", m.overlays.confirmLabel, want)
+	}
+
+	m, cmd := sendKey(t, m, runeKey("y"))
+	if cmd == nil || !m.ui.busyAction {
+		t.Fatal("expected confirming to start the reset")
+	}
+}
+
+func TestUndoUpdateLabelFromReflog(t *testing.T) {
+	got := undoUpdateLabel(chezmoi.UpdateOrigin{Commit: "aaa1111", FromReflog: true})
+	if got != "reset the source to aaa1111 (found in the reflog), then review the apply" {
+		t.Fatalf("unexpected label %q", got)
+	}
+}
+
+func TestUndoUpdateWithoutUpdateShowsMessage(t *testing.T) {
+	m := newTestModel()
+	m, _ = sendMsg(t, m, updateOriginLoadedMsg{err: chezmoi.ErrNoUpdate})
+	if m.view == ConfirmScreen || m.ui.message != "No update to undo" {
+		t.Fatalf("expected no-update message, got view %v message %q", m.view, m.ui.message)
+	}
+}
+
+func TestUpdateUndoneOpensApplyPlan(t *testing.T) {
+	m := newTestModel()
+	m.ui.busyAction = true
+
+	m, cmd := sendMsg(t, m, updateUndoneMsg{commit: "aaa1111bbb2222"})
+	if cmd == nil || !m.ui.busyAction {
+		t.Fatal("expected the apply plan to load after the reset")
+	}
+	if m.ui.message != "source reset to aaa1111" {
+		t.Fatalf("unexpected message %q", m.ui.message)
+	}
+
+	m, _ = sendMsg(t, m, applyPlanLoadedMsg{
+		entries: []chezmoi.ApplyPlanEntry{{Path: "/home/test/.bashrc", Action: chezmoi.PlanModify}},
+		gen:     m.gen,
+	})
+	if m.view != ApplyPlanScreen {
+		t.Fatalf("expected the apply plan, got %v", m.view)
+	}
+}
+
+func TestUpdateUndoneRefusesDirtySource(t *testing.T) {
+	m := newTestModel()
+	m, cmd := sendMsg(t, m, updateUndoneMsg{commit: "aaa1111", err: chezmoi.ErrSourceDirty})
+	if cmd != nil || !strings.Contains(m.ui.message, "uncommitted changes") {
+		t.Fatalf("expected dirty-source error, got %q", m.ui.message)
+	}
+}
+
+func TestUpdateRunsEvenWhenRecordingFails(t *testing.T) {
+	m := newTestModel()
+	next := func() tea.Msg { return nil }
+	m, cmd := sendMsg(t, m, updateOriginRecordedMsg{err: errors.New("not a git repository"), next: next})
+	if cmd == nil {
+		t.Fatal("expected the update to run anyway")
+	}
+	if !strings.Contains(m.ui.message, "could not record source HEAD") {
+		t.Fatalf("expected a warning, got %q", m.ui.message)
+	}
+}
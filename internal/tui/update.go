@@ -43,6 +43,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.resizeTerminal()
+		m.syncCommandOutput()
 		tab := m.activeTabName()
 		if m.panel.shouldShow(m.width) && (tab == "Status" || tab == "Files") {
 			m = m.syncPanelViewportContent()
@@ -65,10 +67,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleGitActionDone(msg)
 	case chezmoiGitCommitsLoadedMsg:
 		return m.handleGitCommitsLoaded(msg)
-	case chezmoiGitFetchDoneMsg:
-		return m.handleGitFetchDone(msg)
 	case templatePathsLoadedMsg:
 		return m.handleTemplatePathsLoaded(msg)
+	case encryptedPathsLoadedMsg:
+		return m.handleEncryptedPathsLoaded(msg)
+	case panelRevealExpiredMsg:
+		return m.handlePanelRevealExpired(msg)
 
 	// Files tab messages
 	case chezmoiManagedLoadedMsg:
@@ -87,6 +91,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Info tab messages
 	case infoContentLoadedMsg:
 		return m.handleInfoContentLoaded(msg)
+	case infoStateLoadedMsg:
+		return m.handleInfoStateLoaded(msg)
+	case stateDeletedMsg:
+		return m.handleStateDeleted(msg)
 
 	// Landing
 	case landingStatsReadyMsg:
@@ -98,22 +106,92 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleDiffLoaded(msg)
 	case chezmoiActionDoneMsg:
 		return m.handleActionDone(msg)
+	case commitSecretsFoundMsg:
+		return m.handleCommitSecretsFound(msg)
 	case chezmoiForgetDoneMsg:
 		return m.handleForgetDone(msg)
-	case chezmoiAddDoneMsg:
-		return m.handleAddDone(msg)
 	case chezmoiSourceContentMsg:
 		return m.handleSourceContent(msg)
-	case chezmoiCapturedOutputMsg:
-		return m.handleCapturedOutput(msg)
-	case chezmoiArchiveDoneMsg:
-		return m.handleArchiveDone(msg)
+	case addPreviewLoadedMsg:
+		return m.handleAddPreviewLoaded(msg)
+	case addOptionsLoadedMsg:
+		return m.handleAddOptionsLoaded(msg)
+	case addOptionsSavedMsg:
+		return m.handleAddOptionsSaved(msg)
+	case sourceEntryLoadedMsg:
+		return m.handleSourceEntryLoaded(msg)
+	case chattrPreviewLoadedMsg:
+		return m.handleChattrPreviewLoaded(msg)
+	case ignoreFormLoadedMsg:
+		return m.handleIgnoreFormLoaded(msg)
+	case ignoreAppendedMsg:
+		return m.handleIgnoreAppended(msg)
+	case ignoreExplainedMsg:
+		return m.handleIgnoreExplained(msg)
+	case applyPlanAppliedMsg:
+		return m.handleApplyPlanApplied(msg)
+	case applyHighlightExpiredMsg:
+		return m.handleApplyHighlightExpired()
+	case jobOutputMsg:
+		return m.handleJobOutput(msg)
+	case jobDoneMsg:
+		return m.handleJobDone(msg)
 	case sourceDirResolvedMsg:
 		return m.handleSourceDirResolved(msg)
 	case chezmoiExecDoneMsg:
 		return m.handleExecDone(msg)
 	case panelContentLoadedMsg:
 		return m.handlePanelContentLoaded(msg)
+	case applyPlanLoadedMsg:
+		return m.handleApplyPlanLoaded(msg)
+	case applyBackupDoneMsg:
+		return m.handleApplyBackupDone(msg)
+	case backupsLoadedMsg:
+		return m.handleBackupsLoaded(msg)
+	case backupDiffLoadedMsg:
+		return m.handleBackupDiffLoaded(msg)
+	case backupRestoredMsg:
+		return m.handleBackupRestored(msg)
+	case updateOriginRecordedMsg:
+		return m.handleUpdateOriginRecorded(msg)
+	case updateOriginLoadedMsg:
+		return m.handleUpdateOriginLoaded(msg)
+	case updateUndoneMsg:
+		return m.handleUpdateUndone(msg)
+	case timeTravelCommitsLoadedMsg:
+		return m.handleTimeTravelCommitsLoaded(msg)
+	case destPreviewLoadedMsg:
+		return m.handleDestPreviewLoaded(msg)
+	case destPreviewDiffLoadedMsg:
+		return m.handleDestPreviewDiffLoaded(msg)
+	case whatIfRenderedMsg:
+		return m.handleWhatIfRendered(msg)
+	case templateLintLoadedMsg:
+		return m.handleTemplateLintLoaded(msg)
+	case dataIndexLoadedMsg:
+		return m.handleDataIndexLoaded(msg)
+	case scriptsLoadedMsg:
+		return m.handleScriptsLoaded(msg)
+	case scriptStateResetMsg:
+		return m.handleScriptStateReset(msg)
+	case externalsLoadedMsg:
+		return m.handleExternalsLoaded(msg)
+	case templateHistoryLoadedMsg:
+		return m.handleTemplateHistoryLoaded(msg)
+	case templateEvalTickMsg:
+		return m.handleTemplateEvalTick(msg)
+	case templateEvaluatedMsg:
+		return m.handleTemplateEvaluated(msg)
+	case terminalStartedMsg:
+		return m.handleTerminalStarted(msg)
+	case terminalOutputMsg:
+		return m.handleTerminalOutput(msg)
+	case terminalExitedMsg:
+		return m.handleTerminalExited(msg)
+	case tea.PasteMsg:
+		if m.view == TerminalScreen {
+			return m.handleTerminalPaste(msg)
+		}
 
 	// Input messages
 	case tea.MouseClickMsg:
@@ -124,12 +202,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKeyMsg(msg)
 	}
 
+	// Form-internal messages (cursor blink, field focus) go to an open
+	// What-If overlay form or one of the add, attributes, and ignore forms.
+	if m.view == WhatIfScreen && m.whatIf.form != nil {
+		return m.handleWhatIfFormUpdate(msg)
+	}
+	if m.view == AddFormScreen {
+		return m.handleAddFormUpdate(msg)
+	}
+	if m.view == AttributesScreen {
+		return m.handleAttrFormUpdate(msg)
+	}
+	if m.view == IgnoreFormScreen {
+		return m.handleIgnoreFormUpdate(msg)
+	}
+	// Cursor blink for the Template sub-view input.
+	if m.templateInputFocused() {
+		var cmd tea.Cmd
+		m.info.template.input, cmd = m.info.template.input.Update(msg)
+		return m, cmd
+	}
 	return m, nil
 }
 
 // --- Root key gate ---
 
 func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	// The terminal pane owns the keyboard so prompts receive every key,
+	// including ones chezit would otherwise treat as global.
+	if m.view == TerminalScreen {
+		return m.handleTerminalKeys(msg)
+	}
+
+	// The template input takes every key while it is being edited.
+	if m.templateInputFocused() {
+		return m.handleTemplateInputKeys(msg)
+	}
+
 	if !m.filterInput.Focused() && key.Matches(msg, ChezSharedKeys.Mouse) {
 		m.toggleMouseCapture()
 		return m, nil
@@ -143,6 +252,20 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.handleConfirmKeys(msg)
 	}
 
+	// The overlay form takes every key, including ones typed into YAML.
+	if m.view == WhatIfScreen && m.whatIf.form != nil {
+		return m.handleWhatIfFormUpdate(msg)
+	}
+	if m.view == AddFormScreen {
+		return m.handleAddFormUpdate(msg)
+	}
+	if m.view == AttributesScreen {
+		return m.handleAttrFormUpdate(msg)
+	}
+	if m.view == IgnoreFormScreen {
+		return m.handleIgnoreFormUpdate(msg)
+	}
+
 	if m.overlays.showHelp {
 		maxScroll := m.helpOverlayMaxScroll()
 		pageStep := helpOverlayPageStep(m.width, m.height)
@@ -166,6 +289,14 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.overlays.showJobs {
+		return m.handleJobsOverlayKeys(msg)
+	}
+
+	if m.applyResult.show {
+		return m.handleApplyResultKeys(msg)
+	}
+
 	if m.actions.show {
 		switch {
 		case key.Matches(msg, ChezActionMenuKeys.Close):
@@ -270,20 +401,60 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.overlays.showHelp = true
 		m.overlays.helpScroll = 0
 		return m, nil
+	case key.Matches(msg, ChezJobsKeys.Open):
+		m.overlays.showJobs = true
+		m.jobs.cursor = max(0, len(m.jobs.jobs)-1)
+		return m, nil
 	case key.Matches(msg, ChezSharedKeys.Quit):
-		return m, tea.Quit
+		return m, tea.Sequence(m.closeOpenDestPreviewCmd(), tea.Quit)
 	}
 
 	if m.view == DiffScreen {
 		return m.handleDiffKeys(msg)
 	}
 
+	if m.view == ApplyPlanScreen {
+		return m.handleApplyPlanKeys(msg)
+	}
+
+	if m.view == BackupsScreen {
+		return m.handleBackupsKeys(msg)
+	}
+
+	if m.view == TimeTravelScreen {
+		return m.handleTimeTravelKeys(msg)
+	}
+
+	if m.view == DestPreviewScreen {
+		return m.handleDestPreviewKeys(msg)
+	}
+
+	if m.view == WhatIfScreen {
+		return m.handleWhatIfKeys(msg)
+	}
+
+	if m.view == TemplateLintScreen {
+		return m.handleTemplateLintKeys(msg)
+	}
+
+	if m.view == DataDepsScreen {
+		return m.handleDataDepsKeys(msg)
+	}
+
+	if m.view == ScriptsScreen {
+		return m.handleScriptsKeys(msg)
+	}
+
+	if m.view == ExternalsScreen {
+		return m.handleExternalsKeys(msg)
+	}
+
 	// Panel toggle and focus switching (Status/Files tabs only)
 	tab = m.activeTabName()
 	if m.view == StatusScreen && (tab == "Status" || tab == "Files") {
 		if key.Matches(msg, ChezPanelKeys.Toggle) {
 			// "p" is used for Pull on incoming rows — let it fall through
-			if msg.String() == "p" && tab == "Status" && m.currentChangesRow().section == changesSectionIncoming {
+			if msg.String() == "p" && tab == "Status" && isIncomingSection(m.currentChangesRow().section) {
 				// fall through to handleStatusKeys
 			} else {
 				m.panel.toggle(m.width)
@@ -339,13 +510,11 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 func (m Model) handlePanelModeKeysFromList(msg tea.KeyPressMsg) (Model, tea.Cmd, bool) {
 	switch {
 	case key.Matches(msg, ChezPanelKeys.ContentMode):
-		if m.panel.contentMode == panelModeDiff {
-			m.panel.contentMode = panelModeContent
-		} else {
-			m.panel.contentMode = panelModeDiff
-		}
+		m.panel.contentMode = m.nextPanelContentMode()
 		updated, cmd := m.panelLoadForCurrentTab()
 		return updated, cmd, true
+	case key.Matches(msg, ChezPanelKeys.Reveal):
+		return m.togglePanelReveal()
 	default:
 		return m, nil, false
 	}
@@ -1,11 +1,12 @@
 package tui
 
 import (
-	"fmt"
-	"path/filepath"
+	"errors"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
 )
 
 // --- Cross-cutting message handlers ---
@@ -62,23 +63,6 @@ func (m Model) handleForgetDone(msg chezmoiForgetDoneMsg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(reloadCmds...)
 }
 
-func (m Model) handleAddDone(msg chezmoiAddDoneMsg) (tea.Model, tea.Cmd) {
-	m.ui.busyAction = false
-	if msg.err != nil {
-		m.ui.message = "Error: " + mapAddError(msg.err)
-		return m, nil
-	}
-	m.ui.message = "Added " + filepath.Base(msg.path)
-	m.nextGen()
-	reloadCmds := []tea.Cmd{m.loadManagedCmd()}
-	if m.filesTab.views[managedViewUnmanaged].files != nil {
-		m.filesTab.views[managedViewUnmanaged].loading = true
-		reloadCmds = append(reloadCmds, m.loadUnmanagedCmd())
-	}
-	reloadCmds = append(reloadCmds, m.reloadStatusAndGitCmds()...)
-	return m, tea.Batch(append(reloadCmds, sendRefreshMsg())...)
-}
-
 func (m Model) handleSourceContent(msg chezmoiSourceContentMsg) (tea.Model, tea.Cmd) {
 	m.ui.busyAction = false
 	if msg.err != nil {
@@ -107,47 +91,6 @@ func (m Model) handleSourceContent(msg chezmoiSourceContentMsg) (tea.Model, tea.
 	return m, nil
 }
 
-func (m Model) handleCapturedOutput(msg chezmoiCapturedOutputMsg) (tea.Model, tea.Cmd) {
-	m.ui.busyAction = false
-	m.diff.previewApply = false
-	if msg.err != nil {
-		m.ui.message = "Error: " + msg.err.Error()
-		m.panel.clearCache()
-		cmd := m.postActionReloadCmds()
-		return m, cmd
-	}
-	if strings.TrimSpace(msg.output) == "" {
-		m.ui.message = msg.label + ": no changes"
-		m.panel.clearCache()
-		reloadCmd := m.postActionReloadCmds()
-		return m, tea.Batch(reloadCmd, sendRefreshMsg())
-	}
-	m.view = DiffScreen
-	m.diff.content = msg.output
-	m.diff.path = msg.label
-	m.diff.rawLines = strings.Split(msg.output, "\n")
-	m.diff.lines = m.diff.rawLines
-	m.diff.pagerApplied = false
-	m.diff.resetViewport()
-	m.actions.show = false
-	m.panel.clearCache()
-	return m, tea.Batch(m.postActionReloadCmds(), sendRefreshMsg())
-}
-
-func (m Model) handleArchiveDone(msg chezmoiArchiveDoneMsg) (tea.Model, tea.Cmd) {
-	m.ui.busyAction = false
-	if msg.err != nil {
-		m.ui.message = "Archive failed: " + msg.err.Error()
-		return m, nil
-	}
-	sizeStr := ""
-	if msg.size >= 0 {
-		sizeStr = fmt.Sprintf(" (%s)", humanSize(msg.size))
-	}
-	m.ui.message = fmt.Sprintf("Archive created: %s%s", msg.path, sizeStr)
-	return m, nil
-}
-
 func (m Model) handleSourceDirResolved(msg sourceDirResolvedMsg) (tea.Model, tea.Cmd) {
 	m.ui.busyAction = false
 	if msg.err != nil {
@@ -178,6 +121,8 @@ func (m Model) handleExecDone(msg chezmoiExecDoneMsg) (tea.Model, tea.Cmd) {
 	}
 	reload := true
 	switch msg.action {
+	case chezmoiActionAdd:
+		m.ui.message = "add complete"
 	case chezmoiActionApplyFile:
 		m.ui.message = "applied file"
 	case chezmoiActionApplyAll:
@@ -188,6 +133,10 @@ func (m Model) handleExecDone(msg chezmoiExecDoneMsg) (tea.Model, tea.Cmd) {
 		m.ui.message = "edit complete"
 	case chezmoiActionEditIgnoreFile:
 		m.ui.message = "edit complete"
+	case chezmoiActionEditTemplate:
+		m.ui.message = "edit complete"
+	case chezmoiActionEditScript:
+		m.ui.message = "edit complete"
 	case chezmoiActionEditTarget:
 		m.ui.message = "editor closed"
 		reload = false
@@ -200,7 +149,19 @@ func (m Model) handleExecDone(msg chezmoiExecDoneMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	m.panel.clearCache()
-	return m, tea.Batch(m.postActionReloadCmds(), sendRefreshMsg())
+	cmds := []tea.Cmd{m.postActionReloadCmds(), sendRefreshMsg()}
+	if msg.action == chezmoiActionEditTemplate && m.view == TemplateLintScreen {
+		// Show whether the edit fixed the template.
+		updated, cmd := m.lintTemplates()
+		m = updated.(Model)
+		cmds = append(cmds, cmd)
+	}
+	if msg.action == chezmoiActionEditScript && m.view == ScriptsScreen {
+		updated, cmd := m.reloadScripts()
+		m = updated.(Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
 }
 
 // --- Cross-cutting reload helpers ---
@@ -212,12 +173,16 @@ func (m *Model) postActionReloadCmds() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-// mapAddError converts chezmoi add errors into concise user-friendly messages.
+// mapAddError converts chezmoi add and chattr errors into concise user-friendly messages.
 // Known encryption-related patterns are mapped to actionable hints.
 func mapAddError(err error) string {
 	if err == nil {
 		return ""
 	}
+	var found *chezmoi.SecretsFoundError
+	if errors.As(err, &found) {
+		return "Possible secrets found (see job output). Add encrypted, or use Add With Options > Skip chezit secret scan."
+	}
 	msg := err.Error()
 
 	// Known encryption misconfiguration patterns
@@ -55,6 +55,103 @@ func (m Model) handleDiffKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		// Fall through to scroll keys below
 	}
 
+	// Add preview: Esc cancels, Enter queues the add.
+	if m.diff.previewAdd != nil {
+		switch {
+		case key.Matches(msg, ChezSharedKeys.Back):
+			m.diff.previewAdd = nil
+			m.view = StatusScreen
+			m.diff.clear()
+			return m, nil
+		case key.Matches(msg, ChezCommandKeys.Run): // Enter
+			return m.confirmAddPreview()
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
+	// Attributes preview: Esc cancels, Enter applies the change.
+	if m.diff.previewChattr != nil {
+		switch {
+		case key.Matches(msg, ChezSharedKeys.Back):
+			m.diff.previewChattr = nil
+			m.view = StatusScreen
+			m.diff.clear()
+			return m, nil
+		case key.Matches(msg, ChezCommandKeys.Run): // Enter
+			return m.confirmChattrPreview()
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
+	// Backup diff: read-only view, Esc returns to the Backups screen.
+	if m.diff.fromBackups {
+		if key.Matches(msg, ChezSharedKeys.Back) {
+			m.diff.fromBackups = false
+			m.view = BackupsScreen
+			m.diff.clear()
+			return m, nil
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
+	// Destination preview diff: read-only view, Esc returns to the preview.
+	if m.diff.fromDestPreview {
+		if key.Matches(msg, ChezSharedKeys.Back) {
+			m.diff.fromDestPreview = false
+			m.view = DestPreviewScreen
+			m.diff.clear()
+			return m, nil
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
+	// What-If diff: read-only view, Esc returns to What-If Data.
+	if m.diff.fromWhatIf {
+		if key.Matches(msg, ChezSharedKeys.Back) {
+			m.diff.fromWhatIf = false
+			m.view = WhatIfScreen
+			m.diff.clear()
+			return m, nil
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
+	// Ignore explanation: read-only view, Esc returns to the Files tab.
+	if m.diff.fromIgnoreExplain {
+		if key.Matches(msg, ChezSharedKeys.Back) {
+			m.diff.fromIgnoreExplain = false
+			m.view = StatusScreen
+			m.diff.clear()
+			return m, nil
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
+	// Apply-plan entry diff: read-only view, Esc returns to the plan.
+	if m.diff.fromApplyPlan {
+		if key.Matches(msg, ChezSharedKeys.Back) {
+			m.diff.fromApplyPlan = false
+			m.view = ApplyPlanScreen
+			m.diff.clear()
+			return m, nil
+		}
+		m = m.syncDiffViewportContent()
+		scrollViewport(&m.diff.viewport, msg)
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, ChezSharedKeys.Back):
 		m.view = StatusScreen
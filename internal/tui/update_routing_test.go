@@ -444,6 +444,25 @@ func TestGitCommitsLoadedMsgRouting(t *testing.T) {
 		}
 	})
 
+	t.Run("incoming files error keeps the commits", func(t *testing.T) {
+		m := newTestModel()
+		msg := chezmoiGitCommitsLoadedMsg{
+			unpushed: testUnpushed,
+			incoming: testIncoming,
+			filesErr: errors.New("git diff failed"),
+			gen:      m.gen,
+		}
+		updated, _ := sendMsg(t, m, msg)
+
+		if len(updated.status.unpushedCommits) != 1 || len(updated.status.incomingCommits) != 1 {
+			t.Fatalf("expected the commit lists kept, got %d unpushed and %d incoming",
+				len(updated.status.unpushedCommits), len(updated.status.incomingCommits))
+		}
+		if !strings.Contains(updated.ui.message, "incoming files: git diff failed") {
+			t.Fatalf("expected the incoming files error reported, got %q", updated.ui.message)
+		}
+	})
+
 	t.Run("error is non-fatal and returns nil cmd", func(t *testing.T) {
 		m := newTestModel()
 		gen := m.gen
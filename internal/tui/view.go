@@ -35,6 +35,16 @@ func (m Model) View() tea.View {
 		return v
 	}
 
+	if m.overlays.showJobs {
+		v.Content = m.renderJobsOverlay()
+		return v
+	}
+
+	if m.applyResult.show {
+		v.Content = m.renderApplyResultOverlay()
+		return v
+	}
+
 	if m.overlays.showViewPicker {
 		v.Content = m.renderViewPickerMenu()
 		return v
@@ -60,6 +70,45 @@ func (m Model) View() tea.View {
 	case CommitScreen:
 		v.Content = m.renderCommitScreen()
 		return v
+	case AddFormScreen:
+		v.Content = m.renderAddFormScreen()
+		return v
+	case AttributesScreen:
+		v.Content = m.renderAttributesScreen()
+		return v
+	case IgnoreFormScreen:
+		v.Content = m.renderIgnoreFormScreen()
+		return v
+	case ApplyPlanScreen:
+		v.Content = m.renderApplyPlanScreen()
+		return v
+	case BackupsScreen:
+		v.Content = m.renderBackupsScreen()
+		return v
+	case TimeTravelScreen:
+		v.Content = m.renderTimeTravelScreen()
+		return v
+	case DestPreviewScreen:
+		v.Content = m.renderDestPreviewScreen()
+		return v
+	case WhatIfScreen:
+		v.Content = m.renderWhatIfScreen()
+		return v
+	case TemplateLintScreen:
+		v.Content = m.renderTemplateLintScreen()
+		return v
+	case DataDepsScreen:
+		v.Content = m.renderDataDepsScreen()
+		return v
+	case ScriptsScreen:
+		v.Content = m.renderScriptsScreen()
+		return v
+	case ExternalsScreen:
+		v.Content = m.renderExternalsScreen()
+		return v
+	case TerminalScreen:
+		v.Content = m.renderTerminalScreen()
+		return v
 	}
 
 	var b strings.Builder
@@ -90,7 +139,11 @@ func (m Model) View() tea.View {
 }
 
 func (m Model) renderChezmoiTabBar() string {
-	return renderTabs(m.tabNames, m.activeTab)
+	tabs := renderTabs(m.tabNames, m.activeTab)
+	if summary := m.jobsSummary(); summary != "" {
+		tabs += activeTheme.DimText.Render("   jobs: " + summary + " (J)")
+	}
+	return tabs
 }
 
 func (m Model) renderChezmoiLoading() string {
@@ -176,6 +229,7 @@ func (m Model) helpOverlayRows() [][]HelpSection {
 					{"1-4", "Jump to tab"},
 					{"?", "Open/close keys"},
 					{"m", m.mouseModeHelpLabel()},
+					{"J", "Background jobs"},
 					{"esc", "Back"},
 					{"q", "Quit"},
 				},
@@ -234,6 +288,7 @@ func (m Model) helpOverlayRows() [][]HelpSection {
 					{"t", "Tree/flat toggle"},
 					{"f", "View/filter overlay"},
 					{"r", "Refresh"},
+					{"x", "Mark for bulk add"},
 				},
 			},
 		})
@@ -251,6 +306,23 @@ func (m Model) helpOverlayRows() [][]HelpSection {
 					{"r", "Refresh"},
 				},
 			},
+			{
+				Title: "Template",
+				Entries: []HelpEntry{
+					{"enter/i", "Edit template"},
+					{"^p/^n", "Older / newer expression"},
+					{"esc", "Stop editing"},
+					{"r", "Evaluate again"},
+				},
+			},
+		}, []HelpSection{
+			{
+				Title: "State",
+				Entries: []HelpEntry{
+					{"enter/space", "Expand/collapse"},
+					{"d", "Delete key or bucket"},
+				},
+			},
 		})
 	case "Commands":
 		rows = append(rows, []HelpSection{
@@ -260,6 +332,8 @@ func (m Model) helpOverlayRows() [][]HelpSection {
 					{"↑/↓", "Navigate"},
 					{"enter", "Run command"},
 					{"d", "Dry run (if available)"},
+					{"o", "Reopen last output"},
+					{"c", "Cancel streamed command"},
 					{"g/G", "Top / Bottom"},
 				},
 			},
@@ -275,6 +349,7 @@ func (m Model) helpOverlayRows() [][]HelpSection {
 					{"l/→", "Focus preview"},
 					{"h/←", "Back to list"},
 					{"v", "Switch diff/content"},
+					{"R", "Reveal/hide encrypted file"},
 				},
 				Notes: []string{
 					"Preview appears when terminal is wide enough.",
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 var warningDialogBase = lipgloss.NewStyle().
@@ -26,10 +27,11 @@ func (m Model) renderDiffView() string {
 	b.WriteString("\n")
 
 	var detailParts []string
-	if hint := diffDirectionHint(m.diff.sourceSection); hint != "" {
+	hint, side := m.diffViewDirection()
+	if hint != "" {
 		detailParts = append(detailParts, hint)
 	}
-	if side := m.driftSideLabel(m.diff.sourceSection, m.diff.path); side != "" {
+	if side != "" {
 		detailParts = append(detailParts, side)
 	}
 	if len(detailParts) > 0 {
@@ -75,9 +77,20 @@ func (m Model) renderConfirmScreen() string {
 			box.Render(m.renderApplyConfirmContent(label)))
 	}
 
+	// Detail lines, such as secret findings, get a wider box and are cut
+	// to one line each.
+	var detail strings.Builder
+	if len(m.overlays.confirmDetail) > 0 {
+		width := max(60, min(m.width-4, 100))
+		box = box.Width(width)
+		for _, line := range m.overlays.confirmDetail {
+			detail.WriteString("\n  " + ansi.Truncate(line, width-8, "…"))
+		}
+		detail.WriteString("\n")
+	}
 	content := fmt.Sprintf(
-		"\n  Are you sure you want to %s?\n\n  Press y to confirm, n or Esc to cancel.\n",
-		label,
+		"%s\n  Are you sure you want to %s?\n\n  Press y to confirm, n or Esc to cancel.\n",
+		detail.String(), label,
 	)
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(content))
 }
@@ -129,10 +142,11 @@ func applyConfirmDescription(action chezmoiAction, force bool) string {
 
 func (m Model) renderChezmoiDiffStatus() string {
 	summary := diffSummary(m.diffRawLines())
-	if hint := diffDirectionHint(m.diff.sourceSection); hint != "" {
+	hint, side := m.diffViewDirection()
+	if hint != "" {
 		summary = summary + " | " + hint
 	}
-	if side := m.driftSideLabel(m.diff.sourceSection, m.diff.path); side != "" {
+	if side != "" {
 		summary = summary + " | " + side
 	}
 	scrollInfo := ""
@@ -143,6 +157,15 @@ func (m Model) renderChezmoiDiffStatus() string {
 	if m.diff.previewApply {
 		status = " Preview: chezmoi apply" + scrollInfo + " "
 	}
+	if m.diff.previewAdd != nil {
+		status = " Preview: chezmoi add" + scrollInfo + " "
+	}
+	if m.diff.previewChattr != nil {
+		status = " Preview: chezmoi chattr" + scrollInfo + " "
+	}
+	if m.diff.fromIgnoreExplain {
+		status = " Explain: .chezmoiignore | " + ignoreExplainLegend + scrollInfo + " "
+	}
 	if m.ui.message != "" {
 		status = " " + m.ui.message + " "
 	}
@@ -152,6 +175,20 @@ func (m Model) renderChezmoiDiffStatus() string {
 	switch {
 	case m.diff.previewApply:
 		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | enter choose mode | esc cancel")
+	case m.diff.previewAdd != nil:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | enter add | esc cancel")
+	case m.diff.previewChattr != nil:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | enter apply change | esc cancel")
+	case m.diff.fromApplyPlan:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | g top | G bottom | esc back to plan")
+	case m.diff.fromBackups:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | g top | G bottom | esc back to backups")
+	case m.diff.fromDestPreview:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | g top | G bottom | esc back to preview")
+	case m.diff.fromWhatIf:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | g top | G bottom | esc back to what-if")
+	case m.diff.fromIgnoreExplain:
+		help = m.helpHint("↑/↓ scroll | ^d/^u half-page | g top | G bottom | esc back")
 	case m.actions.show:
 		help = m.helpHint("↑/↓ navigate | enter select | esc back")
 	default:
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestViewEnablesAltScreenAndMouseCaptureByDefault(t *testing.T) {
@@ -42,3 +44,14 @@ func TestViewDoesNotForceBackgroundColor(t *testing.T) {
 		t.Fatalf("expected BackgroundColor=nil to inherit terminal background, got %v", v.BackgroundColor)
 	}
 }
+
+func TestViewRendersFullScreenViewsAlone(t *testing.T) {
+	m := newTestModel(WithSize(100, 30))
+	m.ui.loading = false
+	for _, screen := range []Screen{BackupsScreen, TimeTravelScreen, DestPreviewScreen, WhatIfScreen, TemplateLintScreen, DataDepsScreen, ScriptsScreen, ExternalsScreen} {
+		m.view = screen
+		if content := ansi.Strip(m.View().Content); strings.Contains(content, "Status") && strings.Contains(content, "Commands") {
+			t.Errorf("screen %v rendered with the tab bar:\n%s", screen, content)
+		}
+	}
+}
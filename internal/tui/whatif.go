@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// whatIfCustomKey is the sentinel value identifying the "Custom overlay..."
+// option in the profile select form.
+const whatIfCustomKey = "__custom__"
+
+// whatIfCustomLabel names a typed overlay in the breadcrumb.
+const whatIfCustomLabel = "custom overlay"
+
+// whatIfState holds What-If Data: a form choosing a template data overlay,
+// then every template target rendered with it and diffed against now.
+type whatIfState struct {
+	form    *huh.Form // profile select or overlay editor; nil once submitted
+	editing bool      // form is the overlay editor
+	overlay string    // last typed overlay, kept for the next edit
+	label   string    // overlay the renders were made with; "" before any
+	renders []chezmoi.WhatIfRender
+	cursor  int
+	// panelMode is the panel content mode to restore on close; What-If
+	// always shows diffs.
+	panelMode panelContentMode
+}
+
+func (m Model) openWhatIf() (tea.Model, tea.Cmd) {
+	m.actions.show = false
+	m.view = WhatIfScreen
+	m.whatIf = whatIfState{overlay: m.whatIf.overlay, panelMode: m.panel.contentMode}
+	m.panel.contentMode = panelModeDiff
+	cmd := m.showWhatIfForm()
+	return m, cmd
+}
+
+// showWhatIfForm opens the profile select, or the overlay editor when no
+// profiles are configured.
+func (m *Model) showWhatIfForm() tea.Cmd {
+	if len(m.opts.DataProfiles) == 0 {
+		m.whatIf.editing = true
+		m.whatIf.form = m.buildWhatIfOverlayForm()
+	} else {
+		m.whatIf.editing = false
+		m.whatIf.form = m.buildWhatIfProfileForm()
+	}
+	return m.whatIf.form.Init()
+}
+
+// buildWhatIfProfileForm creates a huh.Select of the configured data
+// profiles plus "Custom overlay...".
+func (m Model) buildWhatIfProfileForm() *huh.Form {
+	names := make([]string, 0, len(m.opts.DataProfiles))
+	for name := range m.opts.DataProfiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	opts := make([]huh.Option[string], 0, len(names)+1)
+	for _, name := range names {
+		opts = append(opts, huh.NewOption(name, name))
+	}
+	opts = append(opts, huh.NewOption("Custom overlay...", whatIfCustomKey))
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("profile").
+				Title("Render templates as").
+				Options(opts...),
+		),
+	).WithTheme(huh.ThemeFunc(huh.ThemeCatppuccin)).
+		WithKeyMap(whatIfFormKeyMap()).
+		WithWidth(56).
+		WithShowHelp(false)
+}
+
+// buildWhatIfOverlayForm creates a huh.Text for a YAML overlay, prefilled
+// with the last one typed.
+func (m Model) buildWhatIfOverlayForm() *huh.Form {
+	overlay := m.whatIf.overlay
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Key("overlay").
+				Title("Template data overlay (YAML)").
+				Description("Merged over chezmoi data · ctrl+j new line · enter render").
+				Placeholder("chezmoi:\n  os: darwin\n  hostname: work-laptop").
+				Lines(8).
+				Value(&overlay).
+				Validate(func(s string) error {
+					_, err := chezmoi.ParseDataOverlay(s)
+					return err
+				}),
+		),
+	).WithTheme(huh.ThemeFunc(huh.ThemeCatppuccin)).
+		WithKeyMap(whatIfFormKeyMap()).
+		WithWidth(56).
+		WithShowHelp(false)
+}
+
+// whatIfFormKeyMap lets Esc cancel the forms.
+func whatIfFormKeyMap() *huh.KeyMap {
+	km := huh.NewDefaultKeyMap()
+	km.Quit = key.NewBinding(key.WithKeys("esc", "ctrl+c"))
+	return km
+}
+
+// handleWhatIfFormUpdate routes messages to the open form and handles
+// completion/abort.
+func (m Model) handleWhatIfFormUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.whatIf.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.whatIf.form = f
+	}
+
+	switch m.whatIf.form.State {
+	case huh.StateCompleted:
+		if !m.whatIf.editing {
+			choice := m.whatIf.form.GetString("profile")
+			if choice == whatIfCustomKey {
+				m.whatIf.editing = true
+				m.whatIf.form = m.buildWhatIfOverlayForm()
+				return m, m.whatIf.form.Init()
+			}
+			return m.renderWhatIf(choice, m.opts.DataProfiles[choice])
+		}
+		m.whatIf.overlay = m.whatIf.form.GetString("overlay")
+		data, err := chezmoi.ParseDataOverlay(m.whatIf.overlay)
+		if err != nil {
+			m.ui.message = "Error: " + err.Error()
+			m.whatIf.form = m.buildWhatIfOverlayForm()
+			return m, m.whatIf.form.Init()
+		}
+		return m.renderWhatIf(whatIfCustomLabel, data)
+	case huh.StateAborted:
+		if m.whatIf.editing {
+			m.whatIf.overlay = m.whatIf.form.GetString("overlay")
+			if len(m.opts.DataProfiles) > 0 {
+				m.whatIf.editing = false
+				m.whatIf.form = m.buildWhatIfProfileForm()
+				return m, m.whatIf.form.Init()
+			}
+		}
+		if m.whatIf.label != "" {
+			// Back to the renders from the previous overlay.
+			m.whatIf.form = nil
+			return m, nil
+		}
+		return m.closeWhatIf()
+	}
+
+	return m, cmd
+}
+
+func (m Model) renderWhatIf(label string, data map[string]any) (tea.Model, tea.Cmd) {
+	m.whatIf.form = nil
+	m.ui.busyAction = true
+	m.ui.message = "rendering templates with " + label + "..."
+	return m, tea.Batch(m.ui.loadingSpinner.Tick, func() tea.Msg {
+		renders, err := m.service.WhatIfTemplates(data)
+		return whatIfRenderedMsg{label: label, renders: renders, err: err}
+	})
+}
+
+func (m Model) handleWhatIfRendered(msg whatIfRenderedMsg) (tea.Model, tea.Cmd) {
+	m.ui.busyAction = false
+	if m.view != WhatIfScreen {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.ui.message = "Error: " + msg.err.Error()
+		cmd := m.showWhatIfForm()
+		return m, cmd
+	}
+	if len(msg.renders) == 0 {
+		updated, cmd := m.closeWhatIf()
+		m = updated.(Model)
+		m.ui.message = "No templates to render"
+		return m, cmd
+	}
+	m.ui.message = ""
+	m.whatIf.label = msg.label
+	m.whatIf.renders = msg.renders
+	m.whatIf.cursor = 0
+	return m.whatIfPanelLoad()
+}
+
+// closeWhatIf returns to the Commands tab, dropping the renders but keeping
+// the typed overlay for next time.
+func (m Model) closeWhatIf() (tea.Model, tea.Cmd) {
+	m.view = StatusScreen
+	m.panel.contentMode = m.whatIf.panelMode
+	m.panel.currentPath = ""
+	// Renders were cached under target paths the Status tab also uses.
+	m.panel.clearCache()
+	m.whatIf = whatIfState{overlay: m.whatIf.overlay}
+	m.ui.message = ""
+	return m, nil
+}
+
+// selectedWhatIf returns the render under the cursor.
+func (s whatIfState) selectedWhatIf() (chezmoi.WhatIfRender, bool) {
+	if s.cursor < 0 || s.cursor >= len(s.renders) {
+		return chezmoi.WhatIfRender{}, false
+	}
+	return s.renders[s.cursor], true
+}
+
+func (m Model) handleWhatIfKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.ui.busyAction {
+		return m, nil
+	}
+	prev := m.whatIf.cursor
+	switch {
+	case key.Matches(msg, ChezSharedKeys.Back):
+		return m.closeWhatIf()
+	case key.Matches(msg, ChezSharedKeys.Up):
+		m.whatIf.cursor = moveCursorUp(m.whatIf.cursor, navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Down):
+		m.whatIf.cursor = moveCursorDown(m.whatIf.cursor, len(m.whatIf.renders), navigationStepForKey(msg))
+	case key.Matches(msg, ChezSharedKeys.Home):
+		m.whatIf.cursor = 0
+	case key.Matches(msg, ChezSharedKeys.End):
+		m.whatIf.cursor = max(0, len(m.whatIf.renders)-1)
+	case key.Matches(msg, ChezPanelKeys.Toggle):
+		m.panel.toggle(m.width)
+		return m.whatIfPanelLoad()
+	case key.Matches(msg, ChezWhatIfKeys.Overlay):
+		cmd := m.showWhatIfForm()
+		return m, cmd
+	case key.Matches(msg, ChezWhatIfKeys.Diff):
+		return m.openWhatIfDiff()
+	}
+	if m.whatIf.cursor != prev {
+		return m.whatIfPanelLoad()
+	}
+	return m, nil
+}
+
+// whatIfPanelLoad shows the selected target's diff in the preview panel.
+// Diffs are rendered up front, so the panel is filled without loading.
+func (m Model) whatIfPanelLoad() (tea.Model, tea.Cmd) {
+	if !m.panel.shouldShow(m.width) {
+		return m, nil
+	}
+	r, ok := m.whatIf.selectedWhatIf()
+	if !ok {
+		m.panel.currentPath = ""
+		m = m.syncPanelViewportContent()
+		return m, nil
+	}
+	lines := strings.Split(r.Diff, "\n")
+	m.panel.cachePut(r.Target, panelModeDiff, changesSectionDrift, panelCacheEntry{
+		content:  r.Diff,
+		lines:    lines,
+		rawLines: lines,
+		err:      r.Err,
+	})
+	m.panel.currentPath = r.Target
+	m.panel.currentSection = changesSectionDrift
+	m.panel.loading = false
+	m.panel.pendingLoad = false
+	if m.panel.viewportReady {
+		m.panel.viewport.GotoTop()
+	}
+	m = m.syncPanelViewportContent()
+	return m, nil
+}
+
+func (m Model) openWhatIfDiff() (tea.Model, tea.Cmd) {
+	r, ok := m.whatIf.selectedWhatIf()
+	if !ok {
+		return m, nil
+	}
+	if r.Err != nil {
+		m.ui.message = "Error: " + r.Err.Error()
+		return m, nil
+	}
+	if r.Skipped {
+		m.ui.message = shortenPath(r.Target, m.targetPath) + " is encrypted and was not rendered"
+		return m, nil
+	}
+	if r.Diff == "" {
+		m.ui.message = shortenPath(r.Target, m.targetPath) + " renders the same with " + m.whatIf.label
+		return m, nil
+	}
+	m.view = DiffScreen
+	m.diff.fromWhatIf = true
+	m.diff.sourceSection = changesSectionDrift
+	m.diff.content = r.Diff
+	m.diff.path = r.Target
+	m.diff.rawLines = strings.Split(r.Diff, "\n")
+	m.diff.lines = m.diff.rawLines
+	m.diff.pagerApplied = false
+	m.diff.resetViewport()
+	return m, nil
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+// submitWhatIfForm presses enter in the open form and feeds its follow-up
+// messages back until the form completes.
+func submitWhatIfForm(t *testing.T, m Model) (Model, tea.Cmd) {
+	t.Helper()
+	m, cmd := sendKey(t, m, specialKey(tea.KeyEnter))
+	for range 5 {
+		if m.whatIf.form == nil || cmd == nil {
+			break
+		}
+		msg := cmd()
+		if _, ok := msg.(tea.BatchMsg); ok {
+			break
+		}
+		m, cmd = sendMsg(t, m, msg)
+	}
+	return m, cmd
+}
+
+var testWhatIfRendered = whatIfRenderedMsg{label: "work-mac", renders: []chezmoi.WhatIfRender{
+	{
+		Target:  "/home/test/.gitconfig",
+		Current: "os = linux\n",
+		WhatIf:  "os = darwin\n",
+		Diff:    "--- current/.gitconfig\n+++ what-if/.gitconfig\n@@ -1 +1 @@\n-os = linux\n+os = darwin\n",
+	},
+	{Target: "/home/test/.zshrc", Current: "same\n", WhatIf: "same\n"},
+	{Target: "/home/test/.ssh/config", Err: errors.New(`map has no entry for key "work"`)},
+	{Target: "/home/test/.netrc", Skipped: true},
+}}
+
+// newWhatIfResultsModel opens What-If from the content panel and delivers
+// testWhatIfRendered, as choosing a profile would.
+func newWhatIfResultsModel(t *testing.T, width int) Model {
+	t.Helper()
+	m := newTestModel(WithSize(width, 30))
+	m.panel.contentMode = panelModeContent
+	updated, _ := m.openWhatIf()
+	m = updated.(Model)
+	m.whatIf.form = nil
+	m, _ = sendMsg(t, m, testWhatIfRendered)
+	return m
+}
+
+func TestWhatIfProfilePickerRendersWithProfileData(t *testing.T) {
+	m := newTestModel(WithSize(120, 30))
+	m.opts.DataProfiles = map[string]map[string]any{
+		"work-mac": {"chezmoi": map[string]any{"os": "darwin"}},
+		"server":   {"chezmoi": map[string]any{"os": "linux"}},
+	}
+	updated, _ := m.openWhatIf()
+	m = updated.(Model)
+	if m.view != WhatIfScreen || m.whatIf.form == nil || m.whatIf.editing {
+		t.Fatal("expected the profile picker to open")
+	}
+	rendered := ansi.Strip(m.renderWhatIfScreen())
+	for _, want := range []string{"server", "work-mac", "Custom overlay..."} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	m, cmd := submitWhatIfForm(t, m)
+	if m.whatIf.form != nil || !m.ui.busyAction || cmd == nil {
+		t.Fatal("expected choosing a profile to start rendering")
+	}
+	if !strings.Contains(m.ui.message, "server") {
+		t.Fatalf("expected the first profile rendered, got %q", m.ui.message)
+	}
+}
+
+func TestWhatIfWithoutProfilesOpensOverlayEditor(t *testing.T) {
+	m := newTestModel(WithSize(120, 30))
+	m.whatIf.overlay = "chezmoi:\n  os: darwin\n"
+	updated, _ := m.openWhatIf()
+	m = updated.(Model)
+	if m.whatIf.form == nil || !m.whatIf.editing {
+		t.Fatal("expected the overlay editor to open")
+	}
+	if rendered := ansi.Strip(m.renderWhatIfScreen()); !strings.Contains(rendered, "Template data overlay") {
+		t.Fatalf("expected the overlay editor:\n%s", rendered)
+	}
+
+	m, cmd := submitWhatIfForm(t, m)
+	if m.whatIf.form != nil || !m.ui.busyAction || cmd == nil {
+		t.Fatal("expected the kept overlay to be rendered")
+	}
+
+	m, _ = sendMsg(t, m, whatIfRenderedMsg{label: whatIfCustomLabel, err: chezmoi.ErrInvalidData})
+	if m.whatIf.form == nil || !strings.Contains(m.ui.message, "invalid template data overlay") {
+		t.Fatalf("expected the error with the form reopened, got %q", m.ui.message)
+	}
+}
+
+func TestWhatIfResultsShowDiffInPanel(t *testing.T) {
+	m := newWhatIfResultsModel(t, 140)
+
+	rendered := ansi.Strip(m.renderWhatIfScreen())
+	for _, want := range []string{"What-If Data > work-mac", "3 templates · 1 render differently · 1 failed · 1 encrypted, skipped", ".gitconfig [changed]", ".ssh/config [error]", ".netrc [encrypted, skipped]", "- current data  + overlay", "+os = darwin"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, ".zshrc [") {
+		t.Fatalf("expected .zshrc listed without a tag:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	if rendered := ansi.Strip(m.renderWhatIfScreen()); !strings.Contains(rendered, "Renders the same with this overlay") {
+		t.Fatalf("expected the unchanged message in the panel:\n%s", rendered)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	if rendered := ansi.Strip(m.renderWhatIfScreen()); !strings.Contains(rendered, "no entry for key") {
+		t.Fatalf("expected the render error in the panel:\n%s", rendered)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	if rendered := ansi.Strip(m.renderWhatIfScreen()); !strings.Contains(rendered, "Encrypted template; not rendered") {
+		t.Fatalf("expected the skipped template explained in the panel:\n%s", rendered)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != StatusScreen || m.panel.contentMode != panelModeContent {
+		t.Fatalf("expected esc to leave with the panel mode restored, got view %v mode %v", m.view, m.panel.contentMode)
+	}
+	if _, ok := m.panel.cacheGet("/home/test/.gitconfig", panelModeDiff, changesSectionDrift); ok {
+		t.Fatal("expected what-if diffs dropped from the panel cache")
+	}
+}
+
+func TestWhatIfDiffOpensFullScreen(t *testing.T) {
+	m := newWhatIfResultsModel(t, 80)
+
+	m, _ = sendKey(t, m, runeKey("d"))
+	if m.view != DiffScreen || !m.diff.fromWhatIf || m.diff.path != filepath.Join("/home/test", ".gitconfig") {
+		t.Fatalf("expected the what-if diff, got view %v", m.view)
+	}
+	if status := ansi.Strip(m.renderChezmoiDiffStatus()); !strings.Contains(status, "- current data  + overlay") {
+		t.Fatalf("expected the what-if direction hint, got %q", status)
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.view != WhatIfScreen {
+		t.Fatalf("expected esc to return to what-if, got %v", m.view)
+	}
+
+	m, _ = sendKey(t, m, specialKey(tea.KeyDown))
+	m, _ = sendKey(t, m, runeKey("d"))
+	if m.view != WhatIfScreen || !strings.Contains(m.ui.message, "renders the same with work-mac") {
+		t.Fatalf("expected no diff for an unchanged target, got %q", m.ui.message)
+	}
+
+	m, _ = sendKey(t, m, runeKey("o"))
+	if m.whatIf.form == nil {
+		t.Fatal("expected o to reopen the overlay form")
+	}
+	m, _ = sendKey(t, m, specialKey(tea.KeyEscape))
+	if m.whatIf.form != nil || m.view != WhatIfScreen || len(m.whatIf.renders) != 4 {
+		t.Fatal("expected esc in the form to return to the previous renders")
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/daptify14/chezit/internal/chezmoi"
+)
+
+func (m Model) renderWhatIfScreen() string {
+	if m.whatIf.form != nil {
+		box := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(activeTheme.Primary).
+			Padding(1, 2).
+			Width(60)
+		body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Center, lipgloss.Center, box.Render(m.whatIf.form.View()))
+		return lipgloss.JoinVertical(lipgloss.Top, body, m.renderWhatIfStatusBar())
+	}
+
+	var b strings.Builder
+	parts := append(m.breadcrumbParts(), "What-If Data")
+	if m.whatIf.label != "" {
+		parts = append(parts, m.whatIf.label)
+	}
+	b.WriteString(renderBreadcrumb(parts...))
+	b.WriteString("\n")
+	b.WriteString(renderSeparator(m.effectiveWidth()))
+	b.WriteString("\n")
+	if m.whatIf.label == "" {
+		b.WriteString(activeTheme.DimText.Render("  Rendering templates..."))
+	} else {
+		b.WriteString(activeTheme.DimText.Render("  " + m.whatIfSummary()))
+		b.WriteString("\n\n")
+		if m.panel.shouldShow(m.width) {
+			panelW := panelWidthFor(m.width)
+			listW := m.width - panelW - 1
+			list := lipgloss.NewStyle().Width(listW).Render(m.renderWhatIfList(listW - 2))
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderFilePanel(panelW)))
+		} else {
+			b.WriteString(m.renderWhatIfList(m.effectiveWidth() - 2))
+		}
+	}
+
+	body := lipgloss.Place(m.width, max(m.height-statusFilesFooterLines, 0), lipgloss.Left, lipgloss.Top, b.String())
+	return lipgloss.JoinVertical(lipgloss.Top, body, m.renderWhatIfStatusBar())
+}
+
+// whatIfSummary counts the targets the overlay changes, e.g.
+// "12 templates · 3 render differently · 1 failed · 1 encrypted, skipped".
+func (m Model) whatIfSummary() string {
+	var changed, failed, skipped int
+	for _, r := range m.whatIf.renders {
+		switch {
+		case r.Skipped:
+			skipped++
+		case r.Err != nil:
+			failed++
+		case r.Changed():
+			changed++
+		}
+	}
+	summary := fmt.Sprintf("%d templates", len(m.whatIf.renders)-skipped)
+	if changed == 0 {
+		summary += ", none render differently"
+	} else {
+		summary += fmt.Sprintf(" · %d render differently", changed)
+	}
+	if failed > 0 {
+		summary += fmt.Sprintf(" · %d failed", failed)
+	}
+	if skipped > 0 {
+		summary += fmt.Sprintf(" · %d encrypted, skipped", skipped)
+	}
+	return summary
+}
+
+func (m Model) renderWhatIfList(maxWidth int) string {
+	renders := m.whatIf.renders
+	var b strings.Builder
+	start, end := visibleRange(len(renders), m.whatIf.cursor, m.whatIfListHeight())
+	for i := start; i < end; i++ {
+		selected := i == m.whatIf.cursor
+		r := renders[i]
+		content := "  " + shortenPath(r.Target, m.targetPath) + whatIfRenderTag(r, selected)
+		content = visualTruncate(content, maxWidth)
+		if selected {
+			content = activeTheme.Selected.Width(maxWidth).Render(content)
+		}
+		b.WriteString(content)
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func whatIfRenderTag(r chezmoi.WhatIfRender, selected bool) string {
+	var tag string
+	var style lipgloss.Style
+	switch {
+	case r.Skipped:
+		tag, style = " [encrypted, skipped]", activeTheme.DimText
+	case r.Err != nil:
+		tag, style = " [error]", activeTheme.DangerFg
+	case r.Changed():
+		tag, style = " [changed]", activeTheme.WarningFg
+	default:
+		return ""
+	}
+	if selected {
+		return tag
+	}
+	return style.Render(tag)
+}
+
+func (m Model) renderWhatIfStatusBar() string {
+	status := " What-If Data "
+	if m.ui.busyAction {
+		status = " " + m.ui.loadingSpinner.View() + " working... "
+	}
+	if m.ui.message != "" {
+		status = " " + m.ui.message + " "
+	}
+	statusBar := activeTheme.StatusBar.Width(m.effectiveWidth()).Render(status)
+	var help string
+	if m.whatIf.form != nil {
+		help = m.helpHint("↑/↓ choose | enter render | esc cancel")
+	} else {
+		help = m.helpHint("↑/↓ nav | d/enter full diff | o another overlay | p panel | esc back")
+	}
+	return statusBar + "\n" + help
+}